@@ -9,4 +9,10 @@ import (
 type Cache interface {
 	Set(ctx context.Context, key string, value string, ttl time.Duration) error
 	Get(ctx context.Context, key string) (value string, err error)
+
+	// SetNX sets an item only when the key does not exist and returns false when the key already exists
+	SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error)
+
+	// Delete removes an item from the cache
+	Delete(ctx context.Context, key string) error
 }
@@ -50,3 +50,26 @@ func (cache *redisCache) Set(ctx context.Context, key string, value string, ttl
 	}
 	return nil
 }
+
+// SetNX sets an item in the redis cache only when the key does not exist
+func (cache *redisCache) SetNX(ctx context.Context, key string, value string, ttl time.Duration) (bool, error) {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	ok, err := cache.client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, cache.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot set item in redis with key [%s] if it does not exist", key))
+	}
+	return ok, nil
+}
+
+// Delete an item from the redis cache
+func (cache *redisCache) Delete(ctx context.Context, key string) error {
+	ctx, span := cache.tracer.Start(ctx)
+	defer span.End()
+
+	if err := cache.client.Del(ctx, key).Err(); err != nil {
+		return cache.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete item in redis with key [%s]", key))
+	}
+	return nil
+}
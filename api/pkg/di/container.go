@@ -17,6 +17,10 @@ import (
 	"github.com/pusher/pusher-http-go/v5"
 
 	otelMetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelPrometheus "go.opentelemetry.io/otel/exporters/prometheus"
 
 	"github.com/dgraph-io/ristretto/v2"
 
@@ -96,6 +100,10 @@ type Container struct {
 	userRistrettoCache   *ristretto.Cache[string, entities.AuthContext]
 	phoneRistrettoCache  *ristretto.Cache[string, *entities.Phone]
 	inMemoryCache        cache.Cache
+	contentCipher        repositories.ContentCipher
+	envelopeCipher       *repositories.EnvelopeContentCipher
+	metricsService       *services.MetricsService
+	prometheusExporter   *otelPrometheus.Exporter
 }
 
 // NewLiteContainer creates a Container without any routes or listeners
@@ -145,6 +153,36 @@ func NewContainer(projectID string, version string) (container *Container) {
 	container.RegisterWebhookRoutes()
 	container.RegisterWebhookListeners()
 
+	container.RegisterSuppressionRoutes()
+
+	container.RegisterMessageTemplateRoutes()
+
+	container.RegisterPhonePoolRoutes()
+	container.RegisterPhonePoolListeners()
+
+	container.RegisterOrganisationRoutes()
+
+	container.RegisterUserAPIKeyRoutes()
+
+	container.RegisterScheduledMessageListeners()
+
+	container.RegisterMetricsListeners()
+
+	container.RegisterJobListeners()
+	container.StartJobs()
+
+	container.RegisterAutoReplyRuleRoutes()
+	container.RegisterAutoReplyListeners()
+
+	container.RegisterContactRoutes()
+	container.RegisterContactGroupRoutes()
+	container.RegisterCampaignRoutes()
+	container.RegisterCampaignListeners()
+	container.RegisterBulkJobRoutes()
+	container.RegisterBulkJobListeners()
+	container.RegisterMessageExportRoutes()
+	container.RegisterMessageExportListeners()
+
 	container.RegisterLemonsqueezyRoutes()
 
 	container.RegisterIntegration3CXRoutes()
@@ -173,13 +211,19 @@ func (container *Container) App() (app *fiber.App) {
 
 	container.logger.Debug(fmt.Sprintf("creating %T", app))
 
-	app = fiber.New()
+	// the body limit allows large bulk SMS files to be uploaded to the /v1/bulk-jobs endpoint
+	app = fiber.New(fiber.Config{BodyLimit: 55 * 1024 * 1024})
 
 	// Health check endpoint registered before middleware for reliable Docker health checks
 	app.Get("/health", func(c fiber.Ctx) error {
 		return c.SendStatus(fiber.StatusOK)
 	})
 
+	// Prometheus scrapes are registered before middleware so that they are not logged, traced or authenticated as users
+	if container.PrometheusMetricsEnabled() {
+		container.MetricsHandler().RegisterRoutes(app)
+	}
+
 	app.Use(compress.New(compress.Config{
 		Level: compress.LevelBestCompression,
 	}))
@@ -202,7 +246,7 @@ func (container *Container) App() (app *fiber.App) {
 	)
 	app.Use(middlewares.HTTPRequestLogger(container.Tracer(), container.Logger()))
 	app.Use(middlewares.BearerAuth(container.Logger(), container.Tracer(), container.FirebaseAuthClient()))
-	app.Use(middlewares.APIKeyAuth(container.Logger(), container.Tracer(), container.UserRepository()))
+	app.Use(middlewares.APIKeyAuth(container.Logger(), container.Tracer(), container.UserRepository(), container.UserAPIKeyRepository()))
 
 	container.app = app
 	return app
@@ -223,7 +267,43 @@ func (container *Container) PhoneAPIKeyMiddleware() fiber.Handler {
 // AuthenticatedMiddleware creates a new instance of middlewares.Authenticated
 func (container *Container) AuthenticatedMiddleware() fiber.Handler {
 	container.logger.Debug("creating middlewares.Authenticated")
-	return middlewares.Authenticated(container.Tracer())
+	return middlewares.Authenticated(container.Logger(), container.Tracer(), container.OrganisationRepository())
+}
+
+// OrganisationAdminMiddleware creates a new instance of middlewares.OrganisationWriteRoles which only allows the owner and admins to make changes in an organisation
+func (container *Container) OrganisationAdminMiddleware() fiber.Handler {
+	container.logger.Debug("creating middlewares.OrganisationWriteRoles")
+	return middlewares.OrganisationWriteRoles(container.Tracer(), entities.OrganisationRoleOwner, entities.OrganisationRoleAdmin)
+}
+
+// OrganisationOwnerMiddleware creates a new instance of middlewares.OrganisationRoles which only allows the owner to make requests in an organisation
+func (container *Container) OrganisationOwnerMiddleware() fiber.Handler {
+	container.logger.Debug("creating middlewares.OrganisationRoles")
+	return middlewares.OrganisationRoles(container.Tracer(), entities.OrganisationRoleOwner)
+}
+
+// ScopesMiddleware creates a new instance of middlewares.Scopes which requires the read scope for GET requests and the write scope for other requests made with an entities.UserAPIKey
+func (container *Container) ScopesMiddleware(read entities.UserAPIKeyScope, write entities.UserAPIKeyScope) fiber.Handler {
+	container.logger.Debug("creating middlewares.Scopes")
+	return middlewares.Scopes(container.Tracer(), read, write, nil)
+}
+
+// RouteScopesMiddleware creates a new instance of middlewares.Scopes which checks the routes with their scope instead of the read or write scope
+func (container *Container) RouteScopesMiddleware(read entities.UserAPIKeyScope, write entities.UserAPIKeyScope, routes middlewares.RouteScopes) fiber.Handler {
+	container.logger.Debug("creating middlewares.Scopes")
+	return middlewares.Scopes(container.Tracer(), read, write, routes)
+}
+
+// FullAccessMiddleware creates a new instance of middlewares.FullAccess which rejects requests made with an entities.UserAPIKey
+func (container *Container) FullAccessMiddleware() fiber.Handler {
+	container.logger.Debug("creating middlewares.FullAccess")
+	return middlewares.FullAccess(container.Tracer())
+}
+
+// IdempotencyMiddleware creates a new instance of middlewares.Idempotency
+func (container *Container) IdempotencyMiddleware() fiber.Handler {
+	container.logger.Debug("creating middlewares.Idempotency")
+	return middlewares.Idempotency(container.Logger(), container.Tracer(), container.Cache())
 }
 
 // Logger creates a new instance of telemetry.Logger
@@ -374,6 +454,15 @@ ALTER TABLE discords ADD CONSTRAINT IF NOT EXISTS uni_discords_server_id CHECK (
 		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.Message{}))
 	}
 
+	// search_vector is used for the full text search of messages, it is not a field of entities.Message because GORM cannot migrate generated columns
+	if err = db.Exec(`ALTER TABLE messages ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (to_tsvector('simple', coalesce(content, '') || ' ' || coalesce(contact, '') || ' ' || coalesce(request_id, '') || ' ' || coalesce(failure_reason, ''))) STORED`).Error; err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot add search_vector column to messages"))
+	}
+
+	if err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_messages__search_vector ON messages USING GIN (search_vector)`).Error; err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create index on the search_vector column of messages"))
+	}
+
 	if err = db.AutoMigrate(&entities.MessageThread{}); err != nil {
 		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.MessageThread{}))
 	}
@@ -382,6 +471,10 @@ ALTER TABLE discords ADD CONSTRAINT IF NOT EXISTS uni_discords_server_id CHECK (
 		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.User{}))
 	}
 
+	if err = db.AutoMigrate(&entities.DataKey{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.DataKey{}))
+	}
+
 	if err = db.AutoMigrate(&entities.MessageSendSchedule{}); err != nil {
 		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.MessageSendSchedule{}))
 	}
@@ -414,6 +507,58 @@ ALTER TABLE discords ADD CONSTRAINT IF NOT EXISTS uni_discords_server_id CHECK (
 		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.PhoneAPIKey{}))
 	}
 
+	if err = db.AutoMigrate(&entities.Suppression{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.Suppression{}))
+	}
+
+	if err = db.AutoMigrate(&entities.MessageTemplate{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.MessageTemplate{}))
+	}
+
+	if err = db.AutoMigrate(&entities.PhonePool{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.PhonePool{}))
+	}
+
+	if err = db.AutoMigrate(&entities.Organisation{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.Organisation{}))
+	}
+
+	if err = db.AutoMigrate(&entities.OrganisationMember{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.OrganisationMember{}))
+	}
+
+	if err = db.AutoMigrate(&entities.UserAPIKey{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.UserAPIKey{}))
+	}
+
+	if err = db.AutoMigrate(&entities.ScheduledMessage{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.ScheduledMessage{}))
+	}
+
+	if err = db.AutoMigrate(&entities.AutoReplyRule{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.AutoReplyRule{}))
+	}
+
+	if err = db.AutoMigrate(&entities.Contact{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.Contact{}))
+	}
+
+	if err = db.AutoMigrate(&entities.ContactGroup{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.ContactGroup{}))
+	}
+
+	if err = db.AutoMigrate(&entities.Campaign{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.Campaign{}))
+	}
+
+	if err = db.AutoMigrate(&entities.BulkJob{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.BulkJob{}))
+	}
+
+	if err = db.AutoMigrate(&entities.MessageExport{}); err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot migrate %T", &entities.MessageExport{}))
+	}
+
 	return container.db
 }
 
@@ -520,6 +665,7 @@ func (container *Container) EmulatorEventsQueue() (queue services.PushQueue) {
 		container.Tracer(),
 		container.HTTPClient("emulator_events_queue"),
 		container.EventsQueueConfiguration(),
+		container.MetricsService(),
 	)
 }
 
@@ -531,6 +677,7 @@ func (container *Container) CloudTaskEventsQueue() (queue services.PushQueue) {
 		container.Tracer(),
 		container.CloudTasksClient(),
 		container.EventsQueueConfiguration(),
+		container.MetricsService(),
 	)
 }
 
@@ -578,8 +725,12 @@ func (container *Container) MessageHandlerValidator() (validator *validators.Mes
 		container.Logger(),
 		container.Tracer(),
 		container.PhoneService(),
+		container.SuppressionService(),
+		container.MessageTemplateService(),
+		container.PhonePoolService(),
 		container.TurnstileTokenValidator(),
 		container.Cache(),
+		container.EnvelopeContentCipher() != nil,
 	)
 }
 
@@ -602,6 +753,8 @@ func (container *Container) BulkMessageHandlerValidator() (validator *validators
 		container.Tracer(),
 		container.PhoneService(),
 		container.UserService(),
+		container.SuppressionService(),
+		container.MessageTemplateService(),
 		container.Cache(),
 	)
 }
@@ -677,267 +830,750 @@ func (container *Container) WebhookHandlerValidator() (validator *validators.Web
 	)
 }
 
-// MessageThreadHandler creates a new instance of handlers.MessageThreadHandler
-func (container *Container) MessageThreadHandler() (h *handlers.MessageThreadHandler) {
+// SuppressionHandlerValidator creates a new instance of validators.SuppressionHandlerValidator
+func (container *Container) SuppressionHandlerValidator() (validator *validators.SuppressionHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewSuppressionHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.PhoneService(),
+	)
+}
+
+// SuppressionHandler creates a new instance of handlers.SuppressionHandler
+func (container *Container) SuppressionHandler() (h *handlers.SuppressionHandler) {
 	container.logger.Debug(fmt.Sprintf("creating %T", h))
-	return handlers.NewMessageThreadHandler(
+	return handlers.NewSuppressionHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.MessageThreadHandlerValidator(),
-		container.MessageThreadService(),
+		container.SuppressionService(),
+		container.SuppressionHandlerValidator(),
 	)
 }
 
-// MessageThreadHandlerValidator creates a new instance of validators.MessageThreadHandlerValidator
-func (container *Container) MessageThreadHandlerValidator() (validator *validators.MessageThreadHandlerValidator) {
+// MessageTemplateHandlerValidator creates a new instance of validators.MessageTemplateHandlerValidator
+func (container *Container) MessageTemplateHandlerValidator() (validator *validators.MessageTemplateHandlerValidator) {
 	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewMessageThreadHandlerValidator(
+	return validators.NewMessageTemplateHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
 	)
 }
 
-// PhoneHandlerValidator creates a new instance of validators.PhoneHandlerValidator
-func (container *Container) PhoneHandlerValidator() (validator *validators.PhoneHandlerValidator) {
-	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewPhoneHandlerValidator(
+// MessageTemplateHandler creates a new instance of handlers.MessageTemplateHandler
+func (container *Container) MessageTemplateHandler() (h *handlers.MessageTemplateHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMessageTemplateHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.MessageSendScheduleService(),
+		container.MessageTemplateService(),
+		container.MessageTemplateHandlerValidator(),
 	)
 }
 
-// UserHandlerValidator creates a new instance of validators.UserHandlerValidator
-func (container *Container) UserHandlerValidator() (validator *validators.UserHandlerValidator) {
+// PhonePoolHandlerValidator creates a new instance of validators.PhonePoolHandlerValidator
+func (container *Container) PhonePoolHandlerValidator() (validator *validators.PhonePoolHandlerValidator) {
 	container.logger.Debug(fmt.Sprintf("creating %T", validator))
-	return validators.NewUserHandlerValidator(
+	return validators.NewPhonePoolHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.UserService(),
+		container.PhoneService(),
 	)
 }
 
-// EventDispatcher creates a new instance of services.EventDispatcher
-func (container *Container) EventDispatcher() (dispatcher *services.EventDispatcher) {
-	if container.eventDispatcher != nil {
-		return container.eventDispatcher
-	}
-
-	container.logger.Debug(fmt.Sprintf("creating %T", dispatcher))
-	dispatcher = services.NewEventDispatcher(
+// PhonePoolHandler creates a new instance of handlers.PhonePoolHandler
+func (container *Container) PhonePoolHandler() (h *handlers.PhonePoolHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewPhonePoolHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.Float64Histogram("event.publisher.duration", "ms", "measures the duration of processing CloudEvents"),
-		container.EventsQueue(),
-		container.EventsQueueConfiguration(),
+		container.PhonePoolService(),
+		container.PhonePoolHandlerValidator(),
 	)
-
-	container.eventDispatcher = dispatcher
-	return dispatcher
 }
 
-// Float64Histogram creates a new instance of metric.Float64Histogram
-func (container *Container) Float64Histogram(name, unit, description string) otelMetric.Float64Histogram {
-	container.logger.Debug("creating GORM repositories.MessageRepository")
-	meter := otel.GetMeterProvider().Meter(
-		container.projectID,
-		otelMetric.WithInstrumentationVersion(otel.Version()),
+// OrganisationHandlerValidator creates a new instance of validators.OrganisationHandlerValidator
+func (container *Container) OrganisationHandlerValidator() (validator *validators.OrganisationHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewOrganisationHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
 	)
-	histogram, err := meter.Float64Histogram(name, otelMetric.WithUnit(unit), otelMetric.WithDescription(description))
-	if err != nil {
-		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create float64 histogram"))
-	}
-	return histogram
 }
 
-// MessageRepository creates a new instance of repositories.MessageRepository
-func (container *Container) MessageRepository() (repository repositories.MessageRepository) {
-	container.logger.Debug("creating GORM repositories.MessageRepository")
-	return repositories.NewGormMessageRepository(
+// OrganisationHandler creates a new instance of handlers.OrganisationHandler
+func (container *Container) OrganisationHandler() (h *handlers.OrganisationHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewOrganisationHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.OrganisationService(),
+		container.OrganisationHandlerValidator(),
 	)
 }
 
-// PhoneAPIKeyRepository creates a new instance of repositories.PhoneAPIKeyRepository
-func (container *Container) PhoneAPIKeyRepository() (repository repositories.PhoneAPIKeyRepository) {
-	container.logger.Debug("creating GORM repositories.PhoneAPIKeyRepository")
-	return repositories.NewGormPhoneAPIKeyRepository(
+// UserAPIKeyHandlerValidator creates a new instance of validators.UserAPIKeyHandlerValidator
+func (container *Container) UserAPIKeyHandlerValidator() (validator *validators.UserAPIKeyHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewUserAPIKeyHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
-		container.UserRistrettoCache(),
+		container.PhoneService(),
 	)
 }
 
-// Integration3CXRepository creates a new instance of repositories.Integration3CxRepository
-func (container *Container) Integration3CXRepository() (repository repositories.Integration3CxRepository) {
-	container.logger.Debug("creating GORM repositories.Integration3CxRepository")
-	return repositories.NewGormIntegration3CXRepository(
+// UserAPIKeyHandler creates a new instance of handlers.UserAPIKeyHandler
+func (container *Container) UserAPIKeyHandler() (h *handlers.UserAPIKeyHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewUserAPIKeyHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.UserAPIKeyHandlerValidator(),
+		container.UserAPIKeyService(),
 	)
 }
 
-// PhoneRepository creates a new instance of repositories.PhoneRepository
-func (container *Container) PhoneRepository() (repository repositories.PhoneRepository) {
-	container.logger.Debug("creating GORM repositories.PhoneRepository")
-	return repositories.NewGormPhoneRepository(
+// AutoReplyRuleHandlerValidator creates a new instance of validators.AutoReplyRuleHandlerValidator
+func (container *Container) AutoReplyRuleHandlerValidator() (validator *validators.AutoReplyRuleHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewAutoReplyRuleHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
-		container.PhoneRistrettoCache(),
+		container.PhoneService(),
+		container.MessageSendScheduleHandlerValidator(),
 	)
 }
 
-// MessageSendScheduleRepository creates a new instance of repositories.MessageSendScheduleRepository
-func (container *Container) MessageSendScheduleRepository() repositories.MessageSendScheduleRepository {
-	container.logger.Debug("creating GORM repositories.MessageSendScheduleRepository")
-	return repositories.NewGormMessageSendScheduleRepository(
+// AutoReplyRuleHandler creates a new instance of handlers.AutoReplyRuleHandler
+func (container *Container) AutoReplyRuleHandler() (h *handlers.AutoReplyRuleHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewAutoReplyRuleHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.AutoReplyService(),
+		container.AutoReplyRuleHandlerValidator(),
 	)
 }
 
-// MessageSendScheduleService creates a new instance of services.MessageSendScheduleService
-func (container *Container) MessageSendScheduleService() *services.MessageSendScheduleService {
-	container.logger.Debug("creating services.MessageSendScheduleService")
-	return services.NewMessageSendScheduleService(
+// ContactHandlerValidator creates a new instance of validators.ContactHandlerValidator
+func (container *Container) ContactHandlerValidator() (validator *validators.ContactHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewContactHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.MessageSendScheduleRepository(),
-		container.EventDispatcher(),
+		container.ContactService(),
 	)
 }
 
-// MessageSendScheduleHandlerValidator creates a new instance of validators.MessageSendScheduleHandlerValidator
-func (container *Container) MessageSendScheduleHandlerValidator() *validators.MessageSendScheduleHandlerValidator {
-	container.logger.Debug("creating validators.MessageSendScheduleHandlerValidator")
-	return validators.NewMessageSendScheduleHandlerValidator(
+// ContactHandler creates a new instance of handlers.ContactHandler
+func (container *Container) ContactHandler() (h *handlers.ContactHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewContactHandler(
 		container.Logger(),
 		container.Tracer(),
+		container.ContactService(),
+		container.ContactHandlerValidator(),
 	)
 }
 
-// MessageSendScheduleHandler creates a new instance of handlers.MessageSendScheduleHandler
-func (container *Container) MessageSendScheduleHandler() *handlers.MessageSendScheduleHandler {
-	container.logger.Debug("creating handlers.MessageSendScheduleHandler")
-	return handlers.NewMessageSendScheduleHandler(
+// ContactGroupHandlerValidator creates a new instance of validators.ContactGroupHandlerValidator
+func (container *Container) ContactGroupHandlerValidator() (validator *validators.ContactGroupHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewContactGroupHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.MessageSendScheduleHandlerValidator(),
-		container.MessageSendScheduleService(),
-		container.EntitlementService(),
+		container.PhoneService(),
 	)
 }
 
-// BillingUsageRepository creates a new instance of repositories.BillingUsageRepository
-func (container *Container) BillingUsageRepository() (repository repositories.BillingUsageRepository) {
-	container.logger.Debug("creating GORM repositories.BillingUsageRepository")
-	return repositories.NewGormBillingUsageRepository(
+// BulkJobHandlerValidator creates a new instance of validators.BulkJobHandlerValidator
+func (container *Container) BulkJobHandlerValidator() (validator *validators.BulkJobHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewBulkJobHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
 	)
 }
 
-// EntitlementService creates a new instance of services.EntitlementService
-func (container *Container) EntitlementService() *services.EntitlementService {
-	container.logger.Debug("creating services.EntitlementService")
-	return services.NewEntitlementService(
+// BulkJobHandler creates a new instance of handlers.BulkJobHandler
+func (container *Container) BulkJobHandler() (h *handlers.BulkJobHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewBulkJobHandler(
 		container.Logger(),
 		container.Tracer(),
-		os.Getenv("ENTITLEMENT_ENABLED") == "true",
-		container.UserRepository(),
+		container.BulkJobHandlerValidator(),
+		container.BulkJobService(),
+		container.BillingService(),
 	)
 }
 
-// DiscordRepository creates a new instance of repositories.DiscordRepository
-func (container *Container) DiscordRepository() (repository repositories.DiscordRepository) {
-	container.logger.Debug("creating GORM repositories.DiscordRepository")
-	return repositories.NewGormDiscordRepository(
+// MessageExportHandlerValidator creates a new instance of validators.MessageExportHandlerValidator
+func (container *Container) MessageExportHandlerValidator() (validator *validators.MessageExportHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewMessageExportHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
 	)
 }
 
-// WebhookRepository creates a new instance of repositories.WebhookRepository
-func (container *Container) WebhookRepository() (repository repositories.WebhookRepository) {
-	container.logger.Debug("creating GORM repositories.WebhookRepository")
-	return repositories.NewGormWebhookRepository(
+// MessageExportHandler creates a new instance of handlers.MessageExportHandler
+func (container *Container) MessageExportHandler() (h *handlers.MessageExportHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMessageExportHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.MessageExportHandlerValidator(),
+		container.MessageExportService(),
 	)
 }
 
-// PhoneNotificationRepository creates a new instance of repositories.PhoneNotificationRepository
-func (container *Container) PhoneNotificationRepository() (repository repositories.PhoneNotificationRepository) {
-	container.logger.Debug("creating GORM repositories.PhoneNotificationRepository")
-	return repositories.NewGormPhoneNotificationRepository(
+// CampaignHandlerValidator creates a new instance of validators.CampaignHandlerValidator
+func (container *Container) CampaignHandlerValidator() (validator *validators.CampaignHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewCampaignHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
 	)
 }
 
-// MessageThreadRepository creates a new instance of repositories.MessageThreadRepository
-func (container *Container) MessageThreadRepository() (repository repositories.MessageThreadRepository) {
-	container.logger.Debug("creating GORM repositories.MessageThreadRepository")
-	return repositories.NewGormMessageThreadRepository(
+// CampaignHandler creates a new instance of handlers.CampaignHandler
+func (container *Container) CampaignHandler() (h *handlers.CampaignHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewCampaignHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.DB(),
+		container.CampaignService(),
+		container.CampaignHandlerValidator(),
 	)
 }
 
-// HeartbeatMonitorRepository creates a new instance of repositories.HeartbeatMonitorRepository
-func (container *Container) HeartbeatMonitorRepository() (repository repositories.HeartbeatMonitorRepository) {
-	switch os.Getenv("HEARTBEAT_DB_BACKEND") {
-	case "mongodb":
-		container.logger.Debug("creating MongoDB repositories.HeartbeatMonitorRepository")
-		return repositories.NewMongoHeartbeatMonitorRepository(
-			container.Logger(),
-			container.Tracer(),
-			container.MongoDB(),
-		)
-	default:
-		container.logger.Debug("creating GORM repositories.HeartbeatMonitorRepository")
-		return repositories.NewGormHeartbeatMonitorRepository(
-			container.Logger(),
-			container.Tracer(),
-			container.DedicatedDB(),
-		)
-	}
+// ContactGroupHandler creates a new instance of handlers.ContactGroupHandler
+func (container *Container) ContactGroupHandler() (h *handlers.ContactGroupHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewContactGroupHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactGroupService(),
+		container.ContactGroupHandlerValidator(),
+		container.MessageService(),
+		container.BillingService(),
+		container.CampaignService(),
+	)
 }
 
-// HeartbeatService creates a new instance of services.HeartbeatService
-func (container *Container) HeartbeatService() (service *services.HeartbeatService) {
-	container.logger.Debug(fmt.Sprintf("creating %T", service))
-	return services.NewHeartbeatService(
+// MessageThreadHandler creates a new instance of handlers.MessageThreadHandler
+func (container *Container) MessageThreadHandler() (h *handlers.MessageThreadHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMessageThreadHandler(
 		container.Logger(),
 		container.Tracer(),
-		container.HeartbeatRepository(),
-		container.HeartbeatMonitorRepository(),
-		container.EventDispatcher(),
+		container.MessageThreadHandlerValidator(),
+		container.MessageThreadService(),
 	)
 }
 
-// BillingService creates a new instance of services.BillingService
-func (container *Container) BillingService() (service *services.BillingService) {
-	container.logger.Debug(fmt.Sprintf("creating %T", service))
-	return services.NewBillingService(
+// MessageThreadHandlerValidator creates a new instance of validators.MessageThreadHandlerValidator
+func (container *Container) MessageThreadHandlerValidator() (validator *validators.MessageThreadHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewMessageThreadHandlerValidator(
 		container.Logger(),
 		container.Tracer(),
-		container.InMemoryCache(),
-		container.Mailer(),
-		container.UserEmailFactory(),
-		container.BillingUsageRepository(),
-		container.UserRepository(),
+	)
+}
+
+// PhoneHandlerValidator creates a new instance of validators.PhoneHandlerValidator
+func (container *Container) PhoneHandlerValidator() (validator *validators.PhoneHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewPhoneHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageSendScheduleService(),
+	)
+}
+
+// UserHandlerValidator creates a new instance of validators.UserHandlerValidator
+func (container *Container) UserHandlerValidator() (validator *validators.UserHandlerValidator) {
+	container.logger.Debug(fmt.Sprintf("creating %T", validator))
+	return validators.NewUserHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+		container.UserService(),
+	)
+}
+
+// EventDispatcher creates a new instance of services.EventDispatcher
+func (container *Container) EventDispatcher() (dispatcher *services.EventDispatcher) {
+	if container.eventDispatcher != nil {
+		return container.eventDispatcher
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", dispatcher))
+	dispatcher = services.NewEventDispatcher(
+		container.Logger(),
+		container.Tracer(),
+		container.Float64Histogram("event.publisher.duration", "ms", "measures the duration of processing CloudEvents"),
+		container.EventsQueue(),
+		container.EventsQueueConfiguration(),
+	)
+
+	container.eventDispatcher = dispatcher
+	return dispatcher
+}
+
+// PrometheusMetricsEnabled returns true when the metrics are exported to Prometheus on the /metrics endpoint.
+// The metrics are not exported without PROMETHEUS_METRICS_TOKEN because they contain the phone numbers of the users.
+func (container *Container) PrometheusMetricsEnabled() bool {
+	if os.Getenv("PROMETHEUS_METRICS_ENABLED") != "true" {
+		return false
+	}
+
+	if os.Getenv("PROMETHEUS_METRICS_TOKEN") == "" {
+		container.logger.Error(stacktrace.NewErrorf("the prometheus metrics are disabled because PROMETHEUS_METRICS_TOKEN is not set"))
+		return false
+	}
+	return true
+}
+
+// PrometheusExporter creates a new instance of prometheus.Exporter which is registered with the default Prometheus registry
+func (container *Container) PrometheusExporter() *otelPrometheus.Exporter {
+	if container.prometheusExporter != nil {
+		return container.prometheusExporter
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", container.prometheusExporter))
+	exporter, err := otelPrometheus.New()
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create prometheus metric exporter"))
+	}
+
+	container.prometheusExporter = exporter
+	return exporter
+}
+
+// MetricsHandler creates a new instance of handlers.MetricsHandler
+func (container *Container) MetricsHandler() (h *handlers.MetricsHandler) {
+	container.logger.Debug(fmt.Sprintf("creating %T", h))
+	return handlers.NewMetricsHandler(
+		container.Logger(),
+		container.Tracer(),
+		os.Getenv("PROMETHEUS_METRICS_TOKEN"),
+		promhttp.Handler(),
+	)
+}
+
+// MetricsService creates a new instance of services.MetricsService.
+// The business metrics are only recorded when they are exported to Prometheus because they have a phone number attribute.
+func (container *Container) MetricsService() (service *services.MetricsService) {
+	if container.metricsService != nil {
+		return container.metricsService
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	var meter otelMetric.Meter = noop.NewMeterProvider().Meter(container.projectID)
+	if container.PrometheusMetricsEnabled() {
+		meter = otel.GetMeterProvider().Meter(container.projectID, otelMetric.WithInstrumentationVersion(otel.Version()))
+	}
+
+	service, err := services.NewMetricsService(
+		container.Logger(),
+		container.Tracer(),
+		meter,
+		container.HeartbeatMonitorRepository(),
+	)
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create %T", service))
+	}
+
+	container.metricsService = service
+	return service
+}
+
+// Float64Histogram creates a new instance of metric.Float64Histogram
+func (container *Container) Float64Histogram(name, unit, description string) otelMetric.Float64Histogram {
+	container.logger.Debug("creating GORM repositories.MessageRepository")
+	meter := otel.GetMeterProvider().Meter(
+		container.projectID,
+		otelMetric.WithInstrumentationVersion(otel.Version()),
+	)
+	histogram, err := meter.Float64Histogram(name, otelMetric.WithUnit(unit), otelMetric.WithDescription(description))
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create float64 histogram"))
+	}
+	return histogram
+}
+
+// MessageRepository creates a new instance of repositories.MessageRepository
+func (container *Container) MessageRepository() (repository repositories.MessageRepository) {
+	container.logger.Debug("creating GORM repositories.MessageRepository")
+	return repositories.NewGormMessageRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+		container.ContentCipher(),
+	)
+}
+
+// DataKeyRepository creates a new instance of repositories.DataKeyRepository
+func (container *Container) DataKeyRepository() (repository repositories.DataKeyRepository) {
+	container.logger.Debug("creating GORM repositories.DataKeyRepository")
+	return repositories.NewGormDataKeyRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ContentCipher creates a cached repositories.ContentCipher which encrypts the message content when ENCRYPTION_MASTER_KEY is set
+func (container *Container) ContentCipher() repositories.ContentCipher {
+	if container.contentCipher != nil {
+		return container.contentCipher
+	}
+
+	if envelope := container.EnvelopeContentCipher(); envelope != nil {
+		container.contentCipher = envelope
+	} else {
+		container.logger.Debug("creating plaintext repositories.ContentCipher because ENCRYPTION_MASTER_KEY is not set")
+		container.contentCipher = repositories.NewPlaintextContentCipher()
+	}
+
+	return container.contentCipher
+}
+
+// EnvelopeContentCipher creates a cached *repositories.EnvelopeContentCipher, it returns nil when ENCRYPTION_MASTER_KEY is not set
+func (container *Container) EnvelopeContentCipher() *repositories.EnvelopeContentCipher {
+	if container.envelopeCipher != nil {
+		return container.envelopeCipher
+	}
+
+	masterKey := os.Getenv("ENCRYPTION_MASTER_KEY")
+	if strings.TrimSpace(masterKey) == "" {
+		return nil
+	}
+
+	var previousMasterKeys []string
+	for _, key := range strings.Split(os.Getenv("ENCRYPTION_PREVIOUS_MASTER_KEYS"), ",") {
+		if strings.TrimSpace(key) != "" {
+			previousMasterKeys = append(previousMasterKeys, key)
+		}
+	}
+
+	container.logger.Debug(fmt.Sprintf("creating %T", container.envelopeCipher))
+	envelope, err := repositories.NewEnvelopeContentCipher(
+		container.Logger(),
+		container.Tracer(),
+		container.DataKeyRepository(),
+		masterKey,
+		previousMasterKeys,
+	)
+	if err != nil {
+		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create envelope content cipher"))
+	}
+
+	container.envelopeCipher = envelope
+	return container.envelopeCipher
+}
+
+// PhoneAPIKeyRepository creates a new instance of repositories.PhoneAPIKeyRepository
+func (container *Container) PhoneAPIKeyRepository() (repository repositories.PhoneAPIKeyRepository) {
+	container.logger.Debug("creating GORM repositories.PhoneAPIKeyRepository")
+	return repositories.NewGormPhoneAPIKeyRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+		container.UserRistrettoCache(),
+	)
+}
+
+// Integration3CXRepository creates a new instance of repositories.Integration3CxRepository
+func (container *Container) Integration3CXRepository() (repository repositories.Integration3CxRepository) {
+	container.logger.Debug("creating GORM repositories.Integration3CxRepository")
+	return repositories.NewGormIntegration3CXRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhoneRepository creates a new instance of repositories.PhoneRepository
+func (container *Container) PhoneRepository() (repository repositories.PhoneRepository) {
+	container.logger.Debug("creating GORM repositories.PhoneRepository")
+	return repositories.NewGormPhoneRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+		container.PhoneRistrettoCache(),
+	)
+}
+
+// MessageSendScheduleRepository creates a new instance of repositories.MessageSendScheduleRepository
+func (container *Container) MessageSendScheduleRepository() repositories.MessageSendScheduleRepository {
+	container.logger.Debug("creating GORM repositories.MessageSendScheduleRepository")
+	return repositories.NewGormMessageSendScheduleRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// MessageSendScheduleService creates a new instance of services.MessageSendScheduleService
+func (container *Container) MessageSendScheduleService() *services.MessageSendScheduleService {
+	container.logger.Debug("creating services.MessageSendScheduleService")
+	return services.NewMessageSendScheduleService(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageSendScheduleRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// MessageSendScheduleHandlerValidator creates a new instance of validators.MessageSendScheduleHandlerValidator
+func (container *Container) MessageSendScheduleHandlerValidator() *validators.MessageSendScheduleHandlerValidator {
+	container.logger.Debug("creating validators.MessageSendScheduleHandlerValidator")
+	return validators.NewMessageSendScheduleHandlerValidator(
+		container.Logger(),
+		container.Tracer(),
+	)
+}
+
+// MessageSendScheduleHandler creates a new instance of handlers.MessageSendScheduleHandler
+func (container *Container) MessageSendScheduleHandler() *handlers.MessageSendScheduleHandler {
+	container.logger.Debug("creating handlers.MessageSendScheduleHandler")
+	return handlers.NewMessageSendScheduleHandler(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageSendScheduleHandlerValidator(),
+		container.MessageSendScheduleService(),
+		container.EntitlementService(),
+	)
+}
+
+// BillingUsageRepository creates a new instance of repositories.BillingUsageRepository
+func (container *Container) BillingUsageRepository() (repository repositories.BillingUsageRepository) {
+	container.logger.Debug("creating GORM repositories.BillingUsageRepository")
+	return repositories.NewGormBillingUsageRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// EntitlementService creates a new instance of services.EntitlementService
+func (container *Container) EntitlementService() *services.EntitlementService {
+	container.logger.Debug("creating services.EntitlementService")
+	return services.NewEntitlementService(
+		container.Logger(),
+		container.Tracer(),
+		os.Getenv("ENTITLEMENT_ENABLED") == "true",
+		container.UserRepository(),
+	)
+}
+
+// DiscordRepository creates a new instance of repositories.DiscordRepository
+func (container *Container) DiscordRepository() (repository repositories.DiscordRepository) {
+	container.logger.Debug("creating GORM repositories.DiscordRepository")
+	return repositories.NewGormDiscordRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// WebhookRepository creates a new instance of repositories.WebhookRepository
+func (container *Container) WebhookRepository() (repository repositories.WebhookRepository) {
+	container.logger.Debug("creating GORM repositories.WebhookRepository")
+	return repositories.NewGormWebhookRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// SuppressionRepository creates a new instance of repositories.SuppressionRepository
+func (container *Container) SuppressionRepository() (repository repositories.SuppressionRepository) {
+	container.logger.Debug("creating GORM repositories.SuppressionRepository")
+	return repositories.NewGormSuppressionRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// MessageTemplateRepository creates a new instance of repositories.MessageTemplateRepository
+func (container *Container) MessageTemplateRepository() (repository repositories.MessageTemplateRepository) {
+	container.logger.Debug("creating GORM repositories.MessageTemplateRepository")
+	return repositories.NewGormMessageTemplateRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhonePoolRepository creates a new instance of repositories.PhonePoolRepository
+func (container *Container) PhonePoolRepository() (repository repositories.PhonePoolRepository) {
+	container.logger.Debug("creating GORM repositories.PhonePoolRepository")
+	return repositories.NewGormPhonePoolRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// OrganisationRepository creates a new instance of repositories.OrganisationRepository
+func (container *Container) OrganisationRepository() (repository repositories.OrganisationRepository) {
+	container.logger.Debug("creating GORM repositories.OrganisationRepository")
+	return repositories.NewGormOrganisationRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// UserAPIKeyRepository creates a new instance of repositories.UserAPIKeyRepository
+func (container *Container) UserAPIKeyRepository() (repository repositories.UserAPIKeyRepository) {
+	container.logger.Debug("creating GORM repositories.UserAPIKeyRepository")
+	return repositories.NewGormUserAPIKeyRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+		container.UserRistrettoCache(),
+	)
+}
+
+// AutoReplyRuleRepository creates a new instance of repositories.AutoReplyRuleRepository
+func (container *Container) AutoReplyRuleRepository() (repository repositories.AutoReplyRuleRepository) {
+	container.logger.Debug("creating GORM repositories.AutoReplyRuleRepository")
+	return repositories.NewGormAutoReplyRuleRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ContactRepository creates a new instance of repositories.ContactRepository
+func (container *Container) ContactRepository() (repository repositories.ContactRepository) {
+	container.logger.Debug("creating GORM repositories.ContactRepository")
+	return repositories.NewGormContactRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// BulkJobRepository creates a new instance of repositories.BulkJobRepository
+func (container *Container) BulkJobRepository() (repository repositories.BulkJobRepository) {
+	container.logger.Debug("creating GORM repositories.BulkJobRepository")
+	return repositories.NewGormBulkJobRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// MessageExportRepository creates a new instance of repositories.MessageExportRepository
+func (container *Container) MessageExportRepository() (repository repositories.MessageExportRepository) {
+	container.logger.Debug("creating GORM repositories.MessageExportRepository")
+	return repositories.NewGormMessageExportRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// CampaignRepository creates a new instance of repositories.CampaignRepository
+func (container *Container) CampaignRepository() (repository repositories.CampaignRepository) {
+	container.logger.Debug("creating GORM repositories.CampaignRepository")
+	return repositories.NewGormCampaignRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ContactGroupRepository creates a new instance of repositories.ContactGroupRepository
+func (container *Container) ContactGroupRepository() (repository repositories.ContactGroupRepository) {
+	container.logger.Debug("creating GORM repositories.ContactGroupRepository")
+	return repositories.NewGormContactGroupRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// ScheduledMessageRepository creates a new instance of repositories.ScheduledMessageRepository
+func (container *Container) ScheduledMessageRepository() (repository repositories.ScheduledMessageRepository) {
+	container.logger.Debug("creating GORM repositories.ScheduledMessageRepository")
+	return repositories.NewGormScheduledMessageRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// PhoneNotificationRepository creates a new instance of repositories.PhoneNotificationRepository
+func (container *Container) PhoneNotificationRepository() (repository repositories.PhoneNotificationRepository) {
+	container.logger.Debug("creating GORM repositories.PhoneNotificationRepository")
+	return repositories.NewGormPhoneNotificationRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+	)
+}
+
+// MessageThreadRepository creates a new instance of repositories.MessageThreadRepository
+func (container *Container) MessageThreadRepository() (repository repositories.MessageThreadRepository) {
+	container.logger.Debug("creating GORM repositories.MessageThreadRepository")
+	return repositories.NewGormMessageThreadRepository(
+		container.Logger(),
+		container.Tracer(),
+		container.DB(),
+		container.ContentCipher(),
+	)
+}
+
+// HeartbeatMonitorRepository creates a new instance of repositories.HeartbeatMonitorRepository
+func (container *Container) HeartbeatMonitorRepository() (repository repositories.HeartbeatMonitorRepository) {
+	switch os.Getenv("HEARTBEAT_DB_BACKEND") {
+	case "mongodb":
+		container.logger.Debug("creating MongoDB repositories.HeartbeatMonitorRepository")
+		return repositories.NewMongoHeartbeatMonitorRepository(
+			container.Logger(),
+			container.Tracer(),
+			container.MongoDB(),
+		)
+	default:
+		container.logger.Debug("creating GORM repositories.HeartbeatMonitorRepository")
+		return repositories.NewGormHeartbeatMonitorRepository(
+			container.Logger(),
+			container.Tracer(),
+			container.DedicatedDB(),
+		)
+	}
+}
+
+// HeartbeatService creates a new instance of services.HeartbeatService
+func (container *Container) HeartbeatService() (service *services.HeartbeatService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewHeartbeatService(
+		container.Logger(),
+		container.Tracer(),
+		container.HeartbeatRepository(),
+		container.HeartbeatMonitorRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// BillingService creates a new instance of services.BillingService
+func (container *Container) BillingService() (service *services.BillingService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewBillingService(
+		container.Logger(),
+		container.Tracer(),
+		container.InMemoryCache(),
+		container.Mailer(),
+		container.UserEmailFactory(),
+		container.BillingUsageRepository(),
+		container.UserRepository(),
+		container.MessageRepository(),
 	)
 }
 
@@ -965,6 +1601,7 @@ func (container *Container) WebhookService() (service *services.WebhookService)
 		},
 		container.WebhookRepository(),
 		container.EventDispatcher(),
+		container.MetricsService(),
 	)
 }
 
@@ -1109,6 +1746,7 @@ func (container *Container) MessageThreadService() (service *services.MessageThr
 		container.Tracer(),
 		container.MessageThreadRepository(),
 		container.PhoneRepository(),
+		container.ContactService(),
 		container.EventDispatcher(),
 	)
 }
@@ -1120,6 +1758,7 @@ func (container *Container) EmailNotificationService() (service *services.EmailN
 		container.Logger(),
 		container.Tracer(),
 		container.UserRepository(),
+		container.ContactService(),
 		container.NotificationEmailFactory(),
 		container.Mailer(),
 		container.Cache(),
@@ -1135,6 +1774,8 @@ func (container *Container) MessageHandler() (handler *handlers.MessageHandler)
 		container.MessageHandlerValidator(),
 		container.BillingService(),
 		container.MessageService(),
+		container.PhonePoolService(),
+		container.MessageTemplateService(),
 	)
 }
 
@@ -1147,6 +1788,7 @@ func (container *Container) BulkMessageHandler() (handler *handlers.BulkMessageH
 		container.BulkMessageHandlerValidator(),
 		container.BillingService(),
 		container.MessageService(),
+		container.CampaignService(),
 	)
 }
 
@@ -1345,19 +1987,19 @@ func (container *Container) RegisterLemonsqueezyRoutes() {
 // RegisterIntegration3CXRoutes registers routes for the /integration/3cx prefix
 func (container *Container) RegisterIntegration3CXRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering [%T] routes", &handlers.Integration3CXHandler{}))
-	container.Integration3CXHandler().RegisterRoutes(container.App(), container.BearerAPIKeyMiddleware(), container.AuthenticatedMiddleware())
+	container.Integration3CXHandler().RegisterRoutes(container.App(), container.BearerAPIKeyMiddleware(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend))
 }
 
 // RegisterPhoneAPIKeyRoutes registers routes for the /phone-api-key prefix
 func (container *Container) RegisterPhoneAPIKeyRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering [%T] routes", &handlers.Integration3CXHandler{}))
-	container.PhoneAPIKeyHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.PhoneAPIKeyHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.FullAccessMiddleware())
 }
 
 // RegisterDiscordRoutes registers routes for the /discord prefix
 func (container *Container) RegisterDiscordRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.DiscordHandler{}))
-	container.DiscordHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.DiscordHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeWebhooksRead, entities.UserAPIKeyScopeWebhooksWrite))
 }
 
 // RegisterMessageThreadListeners registers event listeners for listeners.MessageThreadListener
@@ -1367,6 +2009,7 @@ func (container *Container) RegisterMessageThreadListeners() {
 		container.Logger(),
 		container.Tracer(),
 		container.MessageThreadService(),
+		container.MessageService(),
 	)
 
 	for event, handler := range routes {
@@ -1423,6 +2066,13 @@ func (container *Container) RegisterUserListeners() {
 		container.Logger(),
 		container.Tracer(),
 		container.UserService(),
+		container.SuppressionService(),
+		container.MessageTemplateService(),
+		container.ContactService(),
+		container.ContactGroupService(),
+		container.EncryptionService(),
+		container.OrganisationService(),
+		container.UserAPIKeyService(),
 	)
 
 	for event, handler := range routes {
@@ -1540,6 +2190,353 @@ func (container *Container) RegisterWebsocketListeners() {
 	}
 }
 
+// SuppressionService creates a new instance of services.SuppressionService
+func (container *Container) SuppressionService() (service *services.SuppressionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewSuppressionService(
+		container.Logger(),
+		container.Tracer(),
+		container.SuppressionRepository(),
+	)
+}
+
+// MessageTemplateService creates a new instance of services.MessageTemplateService
+func (container *Container) MessageTemplateService() (service *services.MessageTemplateService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageTemplateService(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageTemplateRepository(),
+	)
+}
+
+// PhonePoolService creates a new instance of services.PhonePoolService
+func (container *Container) PhonePoolService() (service *services.PhonePoolService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewPhonePoolService(
+		container.Logger(),
+		container.Tracer(),
+		container.PhonePoolRepository(),
+		container.PhoneService(),
+		container.HeartbeatMonitorRepository(),
+		container.MessageRepository(),
+		container.SuppressionService(),
+	)
+}
+
+// RegisterPhonePoolListeners registers event listeners for listeners.PhonePoolListener
+func (container *Container) RegisterPhonePoolListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.PhonePoolListener{}))
+	_, routes := listeners.NewPhonePoolListener(
+		container.Logger(),
+		container.Tracer(),
+		container.PhonePoolService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// OrganisationService creates a new instance of services.OrganisationService
+func (container *Container) OrganisationService() (service *services.OrganisationService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewOrganisationService(
+		container.Logger(),
+		container.Tracer(),
+		container.OrganisationRepository(),
+		container.Mailer(),
+		container.UserEmailFactory(),
+	)
+}
+
+// UserAPIKeyService creates a new instance of services.UserAPIKeyService
+func (container *Container) UserAPIKeyService() (service *services.UserAPIKeyService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewUserAPIKeyService(
+		container.Logger(),
+		container.Tracer(),
+		container.UserAPIKeyRepository(),
+	)
+}
+
+// ScheduledMessageService creates a new instance of services.ScheduledMessageService
+func (container *Container) ScheduledMessageService() (service *services.ScheduledMessageService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewScheduledMessageService(
+		container.Logger(),
+		container.Tracer(),
+		container.ScheduledMessageRepository(),
+		container.MessageRepository(),
+		container.EventDispatcher(),
+	)
+}
+
+// RegisterScheduledMessageListeners registers event listeners for listeners.ScheduledMessageListener
+func (container *Container) RegisterScheduledMessageListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.ScheduledMessageListener{}))
+	_, routes := listeners.NewScheduledMessageListener(
+		container.Logger(),
+		container.Tracer(),
+		container.ScheduledMessageService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// AutoReplyService creates a new instance of services.AutoReplyService
+func (container *Container) AutoReplyService() (service *services.AutoReplyService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewAutoReplyService(
+		container.Logger(),
+		container.Tracer(),
+		container.AutoReplyRuleRepository(),
+		container.MessageService(),
+		container.PhoneService(),
+		container.SuppressionService(),
+		container.Cache(),
+	)
+}
+
+// RegisterAutoReplyListeners registers event listeners for listeners.AutoReplyListener
+func (container *Container) RegisterAutoReplyListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.AutoReplyListener{}))
+	_, routes := listeners.NewAutoReplyListener(
+		container.Logger(),
+		container.Tracer(),
+		container.AutoReplyService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// ContactService creates a new instance of services.ContactService
+func (container *Container) ContactService() (service *services.ContactService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewContactService(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactRepository(),
+	)
+}
+
+// BulkJobService creates a new instance of services.BulkJobService
+func (container *Container) BulkJobService() (service *services.BulkJobService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewBulkJobService(
+		container.Logger(),
+		container.Tracer(),
+		container.BulkJobRepository(),
+		container.AttachmentRepository(),
+		container.EventDispatcher(),
+		container.UserService(),
+		container.PhoneService(),
+		container.SuppressionService(),
+		container.BulkMessageHandlerValidator(),
+		container.BillingService(),
+		container.MessageService(),
+		container.CampaignService(),
+	)
+}
+
+// MessageExportService creates a new instance of services.MessageExportService
+func (container *Container) MessageExportService() (service *services.MessageExportService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewMessageExportService(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageExportRepository(),
+		container.MessageRepository(),
+		container.AttachmentRepository(),
+		container.EventDispatcher(),
+		container.UserService(),
+		container.Mailer(),
+		container.UserEmailFactory(),
+		container.APIBaseURL(),
+	)
+}
+
+// RegisterMessageExportListeners registers event listeners for listeners.MessageExportListener
+func (container *Container) RegisterMessageExportListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.MessageExportListener{}))
+	_, routes := listeners.NewMessageExportListener(
+		container.Logger(),
+		container.Tracer(),
+		container.MessageExportService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// RegisterBulkJobListeners registers event listeners for listeners.BulkJobListener
+func (container *Container) RegisterBulkJobListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.BulkJobListener{}))
+	_, routes := listeners.NewBulkJobListener(
+		container.Logger(),
+		container.Tracer(),
+		container.BulkJobService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// CampaignService creates a new instance of services.CampaignService
+func (container *Container) CampaignService() (service *services.CampaignService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewCampaignService(
+		container.Logger(),
+		container.Tracer(),
+		container.CampaignRepository(),
+		container.MessageRepository(),
+		container.MessageService(),
+		container.PhoneService(),
+		container.EventDispatcher(),
+	)
+}
+
+// RegisterCampaignListeners registers event listeners for listeners.CampaignListener
+func (container *Container) RegisterCampaignListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.CampaignListener{}))
+	_, routes := listeners.NewCampaignListener(
+		container.Logger(),
+		container.Tracer(),
+		container.CampaignService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// ContactGroupService creates a new instance of services.ContactGroupService
+func (container *Container) ContactGroupService() (service *services.ContactGroupService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewContactGroupService(
+		container.Logger(),
+		container.Tracer(),
+		container.ContactGroupRepository(),
+		container.MessageRepository(),
+		container.SuppressionService(),
+	)
+}
+
+// JobService creates a new instance of services.JobService with the periodic jobs
+func (container *Container) JobService() (service *services.JobService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+
+	sweepInterval := 15 * time.Minute
+	if value, err := time.ParseDuration(os.Getenv("SCHEDULED_MESSAGE_SWEEP_INTERVAL")); err == nil && value > 0 {
+		sweepInterval = value
+	}
+
+	retentionInterval := time.Hour
+	if value, err := time.ParseDuration(os.Getenv("RETENTION_PURGE_INTERVAL")); err == nil && value > 0 {
+		retentionInterval = value
+	}
+
+	jobs := []*services.Job{
+		{Name: "scheduled-message.sweep", Interval: sweepInterval, Run: container.ScheduledMessageService().Sweep},
+		{Name: "retention.purge", Interval: retentionInterval, Run: container.RetentionService().Purge},
+	}
+
+	// the data keys are only rotated when the message content is encrypted at rest with ENCRYPTION_MASTER_KEY
+	if container.EnvelopeContentCipher() != nil {
+		rotationInterval := time.Hour
+		if value, err := time.ParseDuration(os.Getenv("ENCRYPTION_KEY_ROTATION_INTERVAL")); err == nil && value > 0 {
+			rotationInterval = value
+		}
+		jobs = append(jobs, &services.Job{Name: "encryption.rotate", Interval: rotationInterval, Run: container.EncryptionService().Rotate})
+	}
+
+	return services.NewJobService(
+		container.Logger(),
+		container.Tracer(),
+		container.Cache(),
+		container.EventDispatcher(),
+		jobs...,
+	)
+}
+
+// RegisterJobListeners registers event listeners for listeners.JobListener
+func (container *Container) RegisterJobListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.JobListener{}))
+	_, routes := listeners.NewJobListener(
+		container.Logger(),
+		container.Tracer(),
+		container.JobService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
+// StartJobs schedules the next run of the periodic jobs e.g. the sweep of the entities.ScheduledMessage which are inside the push queue horizon
+func (container *Container) StartJobs() {
+	container.logger.Debug("starting periodic jobs")
+	if err := container.JobService().Start(context.Background(), "/v1/jobs"); err != nil {
+		container.logger.Error(stacktrace.Propagatef(err, "cannot start periodic jobs"))
+	}
+}
+
+// RetentionService creates a new instance of services.RetentionService
+func (container *Container) RetentionService() (service *services.RetentionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+	return services.NewRetentionService(
+		container.Logger(),
+		container.Tracer(),
+		container.UserRepository(),
+		container.MessageRepository(),
+		container.HeartbeatRepository(),
+		container.PhoneNotificationRepository(),
+		container.AttachmentRepository(),
+		container.MessageThreadService(),
+		container.APIBaseURL(),
+	)
+}
+
+// EncryptionService creates a new instance of services.EncryptionService
+func (container *Container) EncryptionService() (service *services.EncryptionService) {
+	container.logger.Debug(fmt.Sprintf("creating %T", service))
+
+	rotationPeriod := 90 * 24 * time.Hour
+	if value, err := time.ParseDuration(os.Getenv("ENCRYPTION_DATA_KEY_ROTATION_PERIOD")); err == nil && value > 0 {
+		rotationPeriod = value
+	}
+
+	return services.NewEncryptionService(
+		container.Logger(),
+		container.Tracer(),
+		container.EnvelopeContentCipher(),
+		container.DataKeyRepository(),
+		container.MessageRepository(),
+		container.MessageThreadRepository(),
+		rotationPeriod,
+	)
+}
+
+// RegisterMetricsListeners registers event listeners for listeners.MetricsListener
+func (container *Container) RegisterMetricsListeners() {
+	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.MetricsListener{}))
+	_, routes := listeners.NewMetricsListener(
+		container.Logger(),
+		container.Tracer(),
+		container.MetricsService(),
+	)
+
+	for event, handler := range routes {
+		container.EventDispatcher().Subscribe(event, handler)
+	}
+}
+
 // RegisterWebhookListeners registers event listeners for listeners.WebhookListener
 func (container *Container) RegisterWebhookListeners() {
 	container.logger.Debug(fmt.Sprintf("registering listeners for %T", listeners.WebhookListener{}))
@@ -1563,6 +2560,11 @@ func (container *Container) MessageService() (service *services.MessageService)
 		container.MessageRepository(),
 		container.EventDispatcher(),
 		container.PhoneService(),
+		container.SuppressionService(),
+		container.HeartbeatMonitorRepository(),
+		container.ScheduledMessageService(),
+		container.ContactService(),
+		container.MessageThreadService(),
 		container.AttachmentRepository(),
 		container.APIBaseURL(),
 	)
@@ -1641,71 +2643,156 @@ func (container *Container) NotificationService() (service *services.PhoneNotifi
 		container.PhoneRepository(),
 		container.PhoneNotificationRepository(),
 		container.MessageSendScheduleRepository(),
+		container.MessageRepository(),
 		container.EventDispatcher(),
+		container.MetricsService(),
 	)
 }
 
 // RegisterMessageRoutes registers routes for the /messages prefix
 func (container *Container) RegisterMessageRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageHandler{}))
-	container.MessageHandler().RegisterPhoneAPIKeyRoutes(container.App(), container.PhoneAPIKeyMiddleware(), container.AuthenticatedMiddleware())
-	container.MessageHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.MessageHandler().RegisterPhoneAPIKeyRoutes(container.App(), container.PhoneAPIKeyMiddleware(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopePhonesRead, entities.UserAPIKeyScopePhonesWrite))
+	container.MessageHandler().RegisterRoutes(
+		container.App(),
+		container.IdempotencyMiddleware(),
+		container.AuthenticatedMiddleware(),
+		container.RouteScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, container.MessageHandler().RouteScopes()),
+	)
 }
 
 // RegisterBulkMessageRoutes registers routes for the /bulk-messages prefix
 func (container *Container) RegisterBulkMessageRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.BulkMessageHandler{}))
-	container.BulkMessageHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.BulkMessageHandler().RegisterRoutes(
+		container.App(),
+		container.AuthenticatedMiddleware(),
+		container.RouteScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, container.BulkMessageHandler().RouteScopes()),
+	)
 }
 
 // RegisterMessageThreadRoutes registers routes for the /message-threads prefix
 func (container *Container) RegisterMessageThreadRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageThreadHandler{}))
-	container.MessageThreadHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.MessageThreadHandler().RegisterRoutes(
+		container.App(),
+		container.AuthenticatedMiddleware(),
+		container.RouteScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, container.MessageThreadHandler().RouteScopes()),
+	)
 }
 
 // RegisterHeartbeatRoutes registers routes for the /heartbeats prefix
 func (container *Container) RegisterHeartbeatRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.HeartbeatHandler{}))
-	container.HeartbeatHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
-	container.HeartbeatHandler().RegisterPhoneAPIKeyRoutes(container.App(), container.PhoneAPIKeyMiddleware(), container.AuthenticatedMiddleware())
+	container.HeartbeatHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopePhonesRead, entities.UserAPIKeyScopePhonesWrite))
+	container.HeartbeatHandler().RegisterPhoneAPIKeyRoutes(container.App(), container.PhoneAPIKeyMiddleware(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopePhonesRead, entities.UserAPIKeyScopePhonesWrite))
 }
 
 // RegisterBillingRoutes registers routes for the /billing prefix
 func (container *Container) RegisterBillingRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.BillingHandler{}))
-	container.BillingHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.BillingHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationOwnerMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeBillingRead, entities.UserAPIKeyScopeBillingRead))
 }
 
 // RegisterWebhookRoutes registers routes for the /webhooks prefix
 func (container *Container) RegisterWebhookRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.WebhookHandler{}))
-	container.WebhookHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.WebhookHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeWebhooksRead, entities.UserAPIKeyScopeWebhooksWrite))
+}
+
+// RegisterSuppressionRoutes registers routes for the /suppressions prefix
+func (container *Container) RegisterSuppressionRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.SuppressionHandler{}))
+	container.SuppressionHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeContactsRead, entities.UserAPIKeyScopeContactsWrite))
+}
+
+// RegisterMessageTemplateRoutes registers routes for the /message-templates prefix
+func (container *Container) RegisterMessageTemplateRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageTemplateHandler{}))
+	container.MessageTemplateHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend))
+}
+
+// RegisterPhonePoolRoutes registers routes for the /phone-pools prefix
+func (container *Container) RegisterPhonePoolRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhonePoolHandler{}))
+	container.PhonePoolHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopePhonesRead, entities.UserAPIKeyScopePhonesWrite))
+}
+
+// RegisterOrganisationRoutes registers routes for the /organisations prefix
+func (container *Container) RegisterOrganisationRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.OrganisationHandler{}))
+	container.OrganisationHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.FullAccessMiddleware())
+}
+
+// RegisterUserAPIKeyRoutes registers routes for the /user-api-keys prefix
+func (container *Container) RegisterUserAPIKeyRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.UserAPIKeyHandler{}))
+	container.UserAPIKeyHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationOwnerMiddleware(), container.FullAccessMiddleware())
+}
+
+// RegisterAutoReplyRuleRoutes registers routes for the /auto-reply-rules prefix
+func (container *Container) RegisterAutoReplyRuleRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.AutoReplyRuleHandler{}))
+	container.AutoReplyRuleHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend))
+}
+
+// RegisterContactRoutes registers routes for the /contacts prefix
+func (container *Container) RegisterContactRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ContactHandler{}))
+	container.ContactHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeContactsRead, entities.UserAPIKeyScopeContactsWrite))
+}
+
+// RegisterBulkJobRoutes registers routes for the /bulk-jobs prefix
+func (container *Container) RegisterBulkJobRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.BulkJobHandler{}))
+	container.BulkJobHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend))
+}
+
+// RegisterMessageExportRoutes registers routes for the /message-exports prefix
+func (container *Container) RegisterMessageExportRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageExportHandler{}))
+	container.MessageExportHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesRead))
+}
+
+// RegisterCampaignRoutes registers routes for the /campaigns prefix
+func (container *Container) RegisterCampaignRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.CampaignHandler{}))
+	container.CampaignHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend))
+}
+
+// RegisterContactGroupRoutes registers routes for the /groups prefix
+func (container *Container) RegisterContactGroupRoutes() {
+	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.ContactGroupHandler{}))
+	container.ContactGroupHandler().RegisterRoutes(
+		container.App(),
+		container.AuthenticatedMiddleware(),
+		container.RouteScopesMiddleware(entities.UserAPIKeyScopeContactsRead, entities.UserAPIKeyScopeContactsWrite, container.ContactGroupHandler().RouteScopes()),
+	)
 }
 
 // RegisterPhoneRoutes registers routes for the /phone prefix
 func (container *Container) RegisterPhoneRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.PhoneHandler{}))
-	container.PhoneHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
-	container.PhoneHandler().RegisterPhoneAPIKeyRoutes(container.App(), container.PhoneAPIKeyMiddleware(), container.AuthenticatedMiddleware())
+	container.PhoneHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopePhonesRead, entities.UserAPIKeyScopePhonesWrite))
+	container.PhoneHandler().RegisterPhoneAPIKeyRoutes(container.App(), container.PhoneAPIKeyMiddleware(), container.AuthenticatedMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopePhonesRead, entities.UserAPIKeyScopePhonesWrite))
 }
 
 // RegisterUserRoutes registers routes for the /users prefix
 func (container *Container) RegisterUserRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.UserHandler{}))
-	container.UserHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.UserHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationOwnerMiddleware(), container.FullAccessMiddleware())
 }
 
 // RegisterMessageSendScheduleRoutes registers routes for the /send-schedules prefix
 func (container *Container) RegisterMessageSendScheduleRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.MessageSendScheduleHandler{}))
-	container.MessageSendScheduleHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.MessageSendScheduleHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationAdminMiddleware(), container.ScopesMiddleware(entities.UserAPIKeyScopePhonesRead, entities.UserAPIKeyScopePhonesWrite))
 }
 
 // RegisterEventRoutes registers routes for the /events prefix
 func (container *Container) RegisterEventRoutes() {
 	container.logger.Debug(fmt.Sprintf("registering %T routes", &handlers.EventsHandler{}))
-	container.EventsHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware())
+	container.EventsHandler().RegisterRoutes(container.App(), container.AuthenticatedMiddleware(), container.OrganisationOwnerMiddleware(), container.FullAccessMiddleware())
 }
 
 // RegisterSwaggerRoutes registers routes for swagger
@@ -1791,6 +2878,18 @@ func (container *Container) UserRistrettoCache() *ristretto.Cache[string, entiti
 	return ristrettoCache
 }
 
+// meterProviderOptions adds the Prometheus exporter to the metric readers when the Prometheus metrics are enabled
+func (container *Container) meterProviderOptions(reader metric.Reader, version string, namespace string) []metric.Option {
+	options := []metric.Option{
+		metric.WithReader(reader),
+		metric.WithResource(container.OtelResources(version, namespace)),
+	}
+	if container.PrometheusMetricsEnabled() {
+		options = append(options, metric.WithReader(container.PrometheusExporter()))
+	}
+	return options
+}
+
 // InitializeTraceProvider initializes the open telemetry trace provider
 func (container *Container) InitializeTraceProvider() func() {
 	return container.initializeAxiomTraceProvider(container.version, container.projectID)
@@ -1816,10 +2915,7 @@ func (container *Container) initializeGoogleTraceProvider(version string, namesp
 		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create cloud metric traceExporter"))
 	}
 
-	meterProvider := metric.NewMeterProvider(
-		metric.WithReader(metric.NewPeriodicReader(metricExporter)),
-		metric.WithResource(container.OtelResources(version, namespace)),
-	)
+	meterProvider := metric.NewMeterProvider(container.meterProviderOptions(metric.NewPeriodicReader(metricExporter), version, namespace)...)
 	otel.SetMeterProvider(meterProvider)
 
 	return func() {
@@ -1875,10 +2971,7 @@ func (container *Container) initializeAxiomTraceProvider(version string, namespa
 		container.logger.Fatal(stacktrace.Propagatef(err, "cannot create axiom OTLP metric exporter"))
 	}
 
-	meterProvider := metric.NewMeterProvider(
-		metric.WithReader(metric.NewPeriodicReader(metricExporter)),
-		metric.WithResource(container.OtelResources(version, namespace)),
-	)
+	meterProvider := metric.NewMeterProvider(container.meterProviderOptions(metric.NewPeriodicReader(metricExporter), version, namespace)...)
 	otel.SetMeterProvider(meterProvider)
 
 	return func() {
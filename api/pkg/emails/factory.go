@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/nyaruka/phonenumbers"
 )
@@ -15,6 +16,14 @@ func (factory *factory) formatPhoneNumber(number string) string {
 	return phonenumbers.Format(value, phonenumbers.INTERNATIONAL)
 }
 
+// formatContact formats a phone number and prefixes it with the name of the contact when the name is known
+func (factory *factory) formatContact(number string, name *string) string {
+	if name == nil || strings.TrimSpace(*name) == "" {
+		return factory.formatPhoneNumber(number)
+	}
+	return fmt.Sprintf("%s (%s)", strings.TrimSpace(*name), factory.formatPhoneNumber(number))
+}
+
 func (factory *factory) formatBool(value bool) string {
 	if value == true {
 		return "Yes"
@@ -164,17 +164,17 @@ func replaceWebhookSendFailedEventPayloadPlaceholder(text string, formattedPaylo
 	return before + formattedPayload + after
 }
 
-func (factory *hermesNotificationEmailFactory) MessageExpired(user *entities.User, payload *events.MessageSendExpiredPayload) (*Email, error) {
+func (factory *hermesNotificationEmailFactory) MessageExpired(user *entities.User, payload *events.MessageSendExpiredPayload, contactName *string) (*Email, error) {
 	email := hermes.Email{
 		Body: hermes.Body{
 			Title: "Hello",
 			Intros: []string{
-				fmt.Sprintf("The SMS message which you sent to %s has expired at %s and you will need to resend this message.", factory.formatPhoneNumber(payload.Contact), user.UserTimeString(time.Now())),
+				fmt.Sprintf("The SMS message which you sent to %s has expired at %s and you will need to resend this message.", factory.formatContact(payload.Contact, contactName), user.UserTimeString(time.Now())),
 			},
 			Dictionary: []hermes.Entry{
 				{Key: "ID", Value: payload.MessageID.String()},
 				{Key: "From", Value: factory.formatPhoneNumber(payload.Owner)},
-				{Key: "To", Value: factory.formatPhoneNumber(payload.Contact)},
+				{Key: "To", Value: factory.formatContact(payload.Contact, contactName)},
 				{Key: "Message", Value: payload.Content},
 				{Key: "Encrypted", Value: factory.formatBool(payload.Encrypted)},
 			},
@@ -214,17 +214,17 @@ func (factory *hermesNotificationEmailFactory) MessageExpired(user *entities.Use
 	}, nil
 }
 
-func (factory *hermesNotificationEmailFactory) MessageFailed(user *entities.User, payload *events.MessageSendFailedPayload) (*Email, error) {
+func (factory *hermesNotificationEmailFactory) MessageFailed(user *entities.User, payload *events.MessageSendFailedPayload, contactName *string) (*Email, error) {
 	email := hermes.Email{
 		Body: hermes.Body{
 			Title: "Hello",
 			Intros: []string{
-				fmt.Sprintf("The SMS message which you sent to %s has failed at %s and you will need to resend this message.", factory.formatPhoneNumber(payload.Contact), user.UserTimeString(time.Now())),
+				fmt.Sprintf("The SMS message which you sent to %s has failed at %s and you will need to resend this message.", factory.formatContact(payload.Contact, contactName), user.UserTimeString(time.Now())),
 			},
 			Dictionary: []hermes.Entry{
 				{Key: "ID", Value: payload.ID.String()},
 				{Key: "From", Value: factory.formatPhoneNumber(payload.Owner)},
-				{Key: "To", Value: factory.formatPhoneNumber(payload.Contact)},
+				{Key: "To", Value: factory.formatContact(payload.Contact, contactName)},
 				{Key: "Message", Value: payload.Content},
 				{Key: "Encrypted", Value: factory.formatBool(payload.Encrypted)},
 				{Key: "Failure Reason", Value: payload.ErrorMessage},
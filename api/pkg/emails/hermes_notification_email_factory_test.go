@@ -122,3 +122,25 @@ func TestReplaceWebhookSendFailedEventPayloadPlaceholder(t *testing.T) {
 		})
 	}
 }
+
+func TestMessageFailedIncludesContactName(t *testing.T) {
+	factory := testNotificationEmailFactory()
+	user := &entities.User{Email: "name@email.com", Timezone: "UTC"}
+	payload := &events.MessageSendFailedPayload{
+		ID:           uuid.New(),
+		Owner:        "+18005550199",
+		Contact:      "+18005550100",
+		Content:      "hello",
+		ErrorMessage: "RESULT_ERROR_GENERIC_FAILURE",
+	}
+	name := "Jane Doe"
+
+	email, err := factory.MessageFailed(user, payload, &name)
+	require.NoError(t, err)
+	assert.Contains(t, email.Text, "Jane Doe (+1 800-555-0100)")
+
+	email, err = factory.MessageFailed(user, payload, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, email.Text, "Jane Doe")
+	assert.Contains(t, email.Text, "+1 800-555-0100")
+}
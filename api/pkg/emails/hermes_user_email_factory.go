@@ -2,6 +2,7 @@ package emails
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
@@ -216,3 +217,92 @@ func (factory *hermesUserEmailFactory) PhoneDead(user *entities.User, lastHeartb
 		Text:    text,
 	}, nil
 }
+
+// MessageExportCompleted is the email sent to a user when the export of their messages can be downloaded
+func (factory *hermesUserEmailFactory) MessageExportCompleted(user *entities.User, export *entities.MessageExport) (*Email, error) {
+	email := hermes.Email{
+		Body: hermes.Body{
+			Intros: []string{
+				fmt.Sprintf("The export of your messages is ready. It contains %s messages in the %s format.", factory.formatQuantity(uint(export.MessageCount)), strings.ToUpper(string(export.Format))),
+			},
+			Actions: []hermes.Action{
+				{
+					Instructions: fmt.Sprintf("Click the button below to download the file. The link expires on %s.", export.ExpiresAt.In(user.Location()).Format(time.RFC1123)),
+					Button: hermes.Button{
+						Color:     "#329ef4",
+						TextColor: "#FFFFFF",
+						Text:      "DOWNLOAD",
+						Link:      *export.DownloadURL,
+					},
+				},
+			},
+			Title:     "Hey,",
+			Signature: "Cheers",
+			Outros: []string{
+				fmt.Sprintf("Don't hesitate to contact us by replying to this email."),
+			},
+		},
+	}
+
+	html, err := factory.generator.GenerateHTML(email)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot generate html email")
+	}
+
+	text, err := factory.generator.GeneratePlainText(email)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot generate text email")
+	}
+
+	return &Email{
+		ToEmail: user.Email,
+		Subject: "Your httpSMS message export is ready",
+		HTML:    html,
+		Text:    text,
+	}, nil
+}
+
+// OrganisationInvitation is the email sent to a user who is invited to an organisation
+func (factory *hermesUserEmailFactory) OrganisationInvitation(member *entities.OrganisationMember, organisation *entities.Organisation, inviterEmail string) (*Email, error) {
+	email := hermes.Email{
+		Body: hermes.Body{
+			Intros: []string{
+				fmt.Sprintf("%s invited you to join the %s organisation on httpSMS with the %s role.", inviterEmail, organisation.Name, member.Role),
+				"The members of an organisation share the phones, message threads and webhooks of the organisation.",
+			},
+			Actions: []hermes.Action{
+				{
+					Instructions: fmt.Sprintf("Log in to httpSMS with %s to accept the invitation.", member.Email),
+					Button: hermes.Button{
+						Color:     "#329ef4",
+						TextColor: "#FFFFFF",
+						Text:      "ACCEPT INVITATION",
+						Link:      factory.config.AppURL + "/settings/",
+					},
+				},
+			},
+			Title:     "Hey,",
+			Signature: "Cheers",
+			Outros: []string{
+				fmt.Sprintf("If you were not expecting this invitation, you can ignore this email."),
+			},
+		},
+	}
+
+	html, err := factory.generator.GenerateHTML(email)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot generate html email")
+	}
+
+	text, err := factory.generator.GeneratePlainText(email)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot generate text email")
+	}
+
+	return &Email{
+		ToEmail: member.Email,
+		Subject: fmt.Sprintf("You have been invited to join %s on httpSMS", organisation.Name),
+		HTML:    html,
+		Text:    text,
+	}, nil
+}
@@ -83,3 +83,41 @@ func TestUsageLimitAlert_IncludesPercentBreakdownAndLimit(t *testing.T) {
 	assert.Contains(t, email.Text, "for a total of 18,000 out of your 20,000 message limit")
 	assert.Contains(t, email.HTML, "for a total of 18,000 out of your 20,000 message limit")
 }
+
+func TestMessageExportCompleted_IncludesDownloadLinkAndCount(t *testing.T) {
+	factory := testUserEmailFactory()
+	user := &entities.User{
+		Email:    "name@email.com",
+		Timezone: "UTC",
+	}
+	export := &entities.MessageExport{
+		Format:       entities.MessageExportFormatNDJSON,
+		MessageCount: 15_000,
+	}
+	export.Completed(time.Date(2026, 6, 19, 0, 0, 0, 0, time.UTC), "https://api.httpsms.com/v1/message-exports/32343a19-da5e-4b1b-a767-3298a73703cb/download?token=secret")
+
+	email, err := factory.MessageExportCompleted(user, export)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "name@email.com", email.ToEmail)
+	assert.Equal(t, "Your httpSMS message export is ready", email.Subject)
+	assert.Contains(t, email.Text, "It contains 15,000 messages in the NDJSON format")
+	assert.Contains(t, email.Text, "The link expires on Fri, 26 Jun 2026 00:00:00 UTC")
+	assert.Contains(t, email.HTML, "download?token=secret")
+}
+
+func TestOrganisationInvitation_IncludesInviterAndRole(t *testing.T) {
+	factory := testUserEmailFactory()
+	member := &entities.OrganisationMember{
+		Email: "agent@example.com",
+		Role:  entities.OrganisationRoleAgent,
+	}
+
+	email, err := factory.OrganisationInvitation(member, &entities.Organisation{Name: "Acme Support"}, "owner@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "agent@example.com", email.ToEmail)
+	assert.Equal(t, "You have been invited to join Acme Support on httpSMS", email.Subject)
+	assert.Contains(t, email.Text, "owner@example.com invited you to join the Acme Support organisation on httpSMS with the agent role")
+	assert.Contains(t, email.HTML, "https://httpsms.com/settings/")
+}
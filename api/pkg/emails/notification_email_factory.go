@@ -8,10 +8,10 @@ import (
 // NotificationEmailFactory generates emails to users about a message
 type NotificationEmailFactory interface {
 	// MessageExpired sends an email when the user's message is expired
-	MessageExpired(user *entities.User, payload *events.MessageSendExpiredPayload) (*Email, error)
+	MessageExpired(user *entities.User, payload *events.MessageSendExpiredPayload, contactName *string) (*Email, error)
 
 	// MessageFailed sends an email when the user's message is failed
-	MessageFailed(user *entities.User, payload *events.MessageSendFailedPayload) (*Email, error)
+	MessageFailed(user *entities.User, payload *events.MessageSendFailedPayload, contactName *string) (*Email, error)
 
 	// DiscordSendFailed sends an email when the user's discord message is failed
 	DiscordSendFailed(user *entities.User, payload *events.DiscordSendFailedPayload) (*Email, error)
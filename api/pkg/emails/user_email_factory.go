@@ -19,4 +19,10 @@ type UserEmailFactory interface {
 
 	// APIKeyRotated sends an email when the API key is rotated
 	APIKeyRotated(email string, timestamp time.Time, timezone string) (*Email, error)
+
+	// MessageExportCompleted sends an email when the export of the messages can be downloaded
+	MessageExportCompleted(user *entities.User, export *entities.MessageExport) (*Email, error)
+
+	// OrganisationInvitation sends an email when a user is invited to an organisation
+	OrganisationInvitation(member *entities.OrganisationMember, organisation *entities.Organisation, inviterEmail string) (*Email, error)
 }
@@ -1,6 +1,10 @@
 package entities
 
-import "github.com/google/uuid"
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
 
 // AuthContext is the user gotten from an auth request
 type AuthContext struct {
@@ -8,9 +12,76 @@ type AuthContext struct {
 	PhoneAPIKeyID *uuid.UUID `json:"phone_api_key_id"`
 	PhoneNumbers  []string   `json:"phone_numbers"`
 	Email         string     `json:"email"`
+
+	// EmailVerified is true when the email address was verified by the identity provider of a bearer token
+	EmailVerified bool `json:"email_verified"`
+
+	// OrganisationID is the active organisation of the request, ID is the owner of the organisation when it is set
+	OrganisationID   *uuid.UUID       `json:"organisation_id"`
+	OrganisationRole OrganisationRole `json:"organisation_role"`
+	MemberID         UserID           `json:"member_id"`
+
+	// UserAPIKeyID is set when the request is authenticated with an entities.UserAPIKey which is limited to the Scopes
+	UserAPIKeyID *uuid.UUID        `json:"user_api_key_id"`
+	Scopes       []UserAPIKeyScope `json:"scopes"`
 }
 
 // IsNoop checks if a user is empty
 func (user AuthContext) IsNoop() bool {
 	return user.ID == "" || user.Email == ""
 }
+
+// HasScope checks if the request is allowed to use a scope, only requests which are authenticated with a UserAPIKey are limited
+func (user AuthContext) HasScope(scope UserAPIKeyScope) bool {
+	return user.UserAPIKeyID == nil || slices.Contains(user.Scopes, scope)
+}
+
+// CanUsePhoneNumber checks if the request is allowed to use the phone number of an owner.
+// A UserAPIKey without phone numbers can use all the phones of the user.
+func (user AuthContext) CanUsePhoneNumber(owner string) bool {
+	if user.UserAPIKeyID == nil || len(user.PhoneNumbers) == 0 {
+		return true
+	}
+	return slices.Contains(user.PhoneNumbers, owner)
+}
+
+// CanUsePhoneNumbers checks if the request is allowed to use all the owners, no owners means all the phones of the user.
+func (user AuthContext) CanUsePhoneNumbers(owners []string) bool {
+	if len(owners) == 0 {
+		return user.UserAPIKeyID == nil || len(user.PhoneNumbers) == 0
+	}
+	for _, owner := range owners {
+		if !user.CanUsePhoneNumber(owner) {
+			return false
+		}
+	}
+	return true
+}
+
+// ActorID is the ID of the user who made the request, it is different from the ID when the user acts in an organisation
+func (user AuthContext) ActorID() UserID {
+	if user.OrganisationID != nil {
+		return user.MemberID
+	}
+	return user.ID
+}
+
+// OrganisationActorID is the ActorID of a request which is made in an organisation, it is nil outside an organisation
+func (user AuthContext) OrganisationActorID() *UserID {
+	if user.OrganisationID == nil {
+		return nil
+	}
+
+	actorID := user.ActorID()
+	return &actorID
+}
+
+// InOrganisation returns an AuthContext which uses the resources of the owner of the organisation
+func (user AuthContext) InOrganisation(membership *OrganisationMembership) AuthContext {
+	organisationID := membership.ID
+	user.MemberID = user.ID
+	user.ID = membership.OwnerID
+	user.OrganisationID = &organisationID
+	user.OrganisationRole = membership.Role
+	return user
+}
@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AutoReplyMatchType determines how the content of an inbound message is compared with the pattern of an AutoReplyRule
+type AutoReplyMatchType string
+
+const (
+	// AutoReplyMatchTypeExact matches when the message is the pattern ignoring case and surrounding whitespace e.g "HOURS"
+	AutoReplyMatchTypeExact = AutoReplyMatchType("exact")
+
+	// AutoReplyMatchTypePrefix matches when the message starts with the pattern ignoring case e.g "PRICE"
+	AutoReplyMatchTypePrefix = AutoReplyMatchType("prefix")
+
+	// AutoReplyMatchTypeRegex matches when the message matches the pattern as a regular expression
+	AutoReplyMatchTypeRegex = AutoReplyMatchType("regex")
+
+	// AutoReplyMatchTypeAny matches every inbound message
+	AutoReplyMatchTypeAny = AutoReplyMatchType("any")
+)
+
+// AutoReplyTrigger is the phone activity which causes an AutoReplyRule to reply
+type AutoReplyTrigger string
+
+const (
+	// AutoReplyTriggerMessage replies to inbound messages which match the rule
+	AutoReplyTriggerMessage = AutoReplyTrigger("message")
+
+	// AutoReplyTriggerMissedCall replies to missed phone calls
+	AutoReplyTriggerMissedCall = AutoReplyTrigger("missed_call")
+)
+
+// AutoReplyMinCooldown is the shortest time between 2 replies of an AutoReplyRule to the same contact.
+// It stops 2 phones with auto reply rules from replying to each other in a loop.
+const AutoReplyMinCooldown = time.Minute
+
+// AutoReplyRule is an automatic SMS response which is sent when a phone receives a matching message
+type AutoReplyRule struct {
+	ID        uuid.UUID          `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID             `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner     string             `json:"owner" gorm:"index" example:"+18005550199"`
+	Name      string             `json:"name" example:"Opening Hours"`
+	Priority  int                `json:"priority" example:"1"`
+	Trigger   AutoReplyTrigger   `json:"trigger" gorm:"default:message" example:"message"`
+	MatchType AutoReplyMatchType `json:"match_type" example:"exact"`
+	Pattern   string             `json:"pattern" example:"HOURS"`
+	Content   string             `json:"content" example:"We are open Monday to Friday from 9am to 5pm."`
+	// Timezone of the active windows of the rule e.g Europe/London
+	Timezone string `json:"timezone" example:"Europe/London"`
+	// Windows are the times when the rule is active, the rule is always active when there are no windows
+	Windows []MessageSendScheduleWindow `json:"windows" gorm:"type:jsonb;serializer:json"`
+	// OutsideWindows makes the rule active only outside the windows e.g an out-of-office reply
+	OutsideWindows bool `json:"outside_windows" example:"false"`
+	// CooldownSeconds is the minimum time between 2 replies of the rule to the same contact, it is never shorter than AutoReplyMinCooldown
+	CooldownSeconds uint      `json:"cooldown_seconds" example:"3600"`
+	CreatedAt       time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt       time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Cooldown returns the minimum duration between 2 replies to the same contact
+func (rule *AutoReplyRule) Cooldown() time.Duration {
+	cooldown := time.Duration(rule.CooldownSeconds) * time.Second
+	if cooldown < AutoReplyMinCooldown {
+		return AutoReplyMinCooldown
+	}
+	return cooldown
+}
+
+// IsTriggeredBy checks if the rule replies to a phone activity, rules without a trigger reply to messages
+func (rule *AutoReplyRule) IsTriggeredBy(trigger AutoReplyTrigger) bool {
+	if rule.Trigger == "" {
+		return trigger == AutoReplyTriggerMessage
+	}
+	return rule.Trigger == trigger
+}
+
+// Matches checks if the content of an inbound message matches the rule
+func (rule *AutoReplyRule) Matches(content string) bool {
+	switch rule.MatchType {
+	case AutoReplyMatchTypeAny:
+		return true
+	case AutoReplyMatchTypeExact:
+		return strings.EqualFold(strings.TrimSpace(content), strings.TrimSpace(rule.Pattern))
+	case AutoReplyMatchTypePrefix:
+		return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(content)), strings.ToUpper(strings.TrimSpace(rule.Pattern)))
+	case AutoReplyMatchTypeRegex:
+		expression, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return false
+		}
+		return expression.MatchString(content)
+	default:
+		return false
+	}
+}
+
+// IsActive checks if the rule is active at a point in time.
+// A rule without windows or with an invalid timezone is always active.
+// A window which ends before it starts crosses midnight e.g 22:00 to 06:00 on Friday is active until 06:00 on Saturday.
+func (rule *AutoReplyRule) IsActive(current time.Time) bool {
+	if len(rule.Windows) == 0 {
+		return true
+	}
+
+	location, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		return true
+	}
+
+	local := current.In(location)
+	minute := local.Hour()*60 + local.Minute()
+
+	day := int(local.Weekday())
+	previousDay := (day + 6) % 7
+
+	inside := false
+	for _, window := range rule.Windows {
+		if window.EndMinute > window.StartMinute {
+			inside = window.DayOfWeek == day && minute >= window.StartMinute && minute < window.EndMinute
+		} else {
+			inside = (window.DayOfWeek == day && minute >= window.StartMinute) || (window.DayOfWeek == previousDay && minute < window.EndMinute)
+		}
+
+		if inside {
+			break
+		}
+	}
+
+	return inside != rule.OutsideWindows
+}
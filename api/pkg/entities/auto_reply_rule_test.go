@@ -0,0 +1,90 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoReplyRule_Matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    AutoReplyRule
+		content string
+		want    bool
+	}{
+		{"exact ignores case and whitespace", AutoReplyRule{MatchType: AutoReplyMatchTypeExact, Pattern: "HOURS"}, "  hours ", true},
+		{"exact does not match longer message", AutoReplyRule{MatchType: AutoReplyMatchTypeExact, Pattern: "HOURS"}, "hours please", false},
+		{"prefix ignores case", AutoReplyRule{MatchType: AutoReplyMatchTypePrefix, Pattern: "PRICE"}, "price of bread", true},
+		{"prefix does not match middle", AutoReplyRule{MatchType: AutoReplyMatchTypePrefix, Pattern: "PRICE"}, "the price", false},
+		{"regex", AutoReplyRule{MatchType: AutoReplyMatchTypeRegex, Pattern: `(?i)order\s+#?\d+`}, "Where is ORDER #123?", true},
+		{"invalid regex", AutoReplyRule{MatchType: AutoReplyMatchTypeRegex, Pattern: `(`}, "(", false},
+		{"any", AutoReplyRule{MatchType: AutoReplyMatchTypeAny}, "hello", true},
+		{"unknown match type", AutoReplyRule{MatchType: "unknown", Pattern: "hello"}, "hello", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Matches(tt.content))
+		})
+	}
+}
+
+func TestAutoReplyRule_IsActive(t *testing.T) {
+	// 2024-01-01 is a Monday
+	windows := []MessageSendScheduleWindow{{DayOfWeek: 1, StartMinute: 9 * 60, EndMinute: 17 * 60}}
+	insideHours := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	outsideHours := time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)
+	sunday := time.Date(2023, 12, 31, 10, 0, 0, 0, time.UTC)
+
+	businessHours := &AutoReplyRule{Timezone: "UTC", Windows: windows}
+	assert.True(t, businessHours.IsActive(insideHours))
+	assert.False(t, businessHours.IsActive(outsideHours))
+	assert.False(t, businessHours.IsActive(sunday))
+
+	outOfOffice := &AutoReplyRule{Timezone: "UTC", Windows: windows, OutsideWindows: true}
+	assert.False(t, outOfOffice.IsActive(insideHours))
+	assert.True(t, outOfOffice.IsActive(outsideHours))
+	assert.True(t, outOfOffice.IsActive(sunday))
+
+	always := &AutoReplyRule{}
+	assert.True(t, always.IsActive(outsideHours))
+}
+
+func TestAutoReplyRule_IsActive_Timezone(t *testing.T) {
+	rule := &AutoReplyRule{
+		Timezone: "Africa/Douala",
+		Windows:  []MessageSendScheduleWindow{{DayOfWeek: 1, StartMinute: 9 * 60, EndMinute: 17 * 60}},
+	}
+
+	// 08:30 UTC is 09:30 in Douala
+	assert.True(t, rule.IsActive(time.Date(2024, 1, 1, 8, 30, 0, 0, time.UTC)))
+	// 16:30 UTC is 17:30 in Douala
+	assert.False(t, rule.IsActive(time.Date(2024, 1, 1, 16, 30, 0, 0, time.UTC)))
+}
+
+func TestAutoReplyRule_IsActive_OvernightWindow(t *testing.T) {
+	// 2024-01-05 is a Friday
+	rule := &AutoReplyRule{
+		Timezone: "UTC",
+		Windows:  []MessageSendScheduleWindow{{DayOfWeek: 5, StartMinute: 22 * 60, EndMinute: 6 * 60}},
+	}
+
+	assert.False(t, rule.IsActive(time.Date(2024, 1, 5, 21, 59, 0, 0, time.UTC)))
+	assert.True(t, rule.IsActive(time.Date(2024, 1, 5, 23, 30, 0, 0, time.UTC)))
+	assert.True(t, rule.IsActive(time.Date(2024, 1, 6, 5, 59, 0, 0, time.UTC)))
+	assert.False(t, rule.IsActive(time.Date(2024, 1, 6, 6, 0, 0, 0, time.UTC)))
+	assert.False(t, rule.IsActive(time.Date(2024, 1, 5, 5, 0, 0, 0, time.UTC)))
+}
+
+func TestAutoReplyRule_Cooldown(t *testing.T) {
+	assert.Equal(t, AutoReplyMinCooldown, (&AutoReplyRule{}).Cooldown())
+	assert.Equal(t, time.Hour, (&AutoReplyRule{CooldownSeconds: 3600}).Cooldown())
+}
+
+func TestAutoReplyRule_IsTriggeredBy(t *testing.T) {
+	assert.True(t, (&AutoReplyRule{}).IsTriggeredBy(AutoReplyTriggerMessage))
+	assert.False(t, (&AutoReplyRule{}).IsTriggeredBy(AutoReplyTriggerMissedCall))
+	assert.True(t, (&AutoReplyRule{Trigger: AutoReplyTriggerMissedCall}).IsTriggeredBy(AutoReplyTriggerMissedCall))
+}
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BulkJobStatus is the status of an entities.BulkJob
+type BulkJobStatus string
+
+const (
+	// BulkJobStatusPending means the file of the job has been uploaded and is waiting to be processed
+	BulkJobStatusPending = BulkJobStatus("pending")
+
+	// BulkJobStatusProcessing means the rows of the file are being validated and queued
+	BulkJobStatusProcessing = BulkJobStatus("processing")
+
+	// BulkJobStatusCompleted means all the rows of the file have been processed
+	BulkJobStatusCompleted = BulkJobStatus("completed")
+
+	// BulkJobStatusFailed means the file could not be processed
+	BulkJobStatusFailed = BulkJobStatus("failed")
+)
+
+// MaxBulkJobErrors is the maximum number of row errors which are stored on an entities.BulkJob
+const MaxBulkJobErrors = 1000
+
+// BulkJobError is a validation error for a row in the file of an entities.BulkJob
+type BulkJobError struct {
+	Row     int    `json:"row" example:"2"`
+	Message string `json:"message" example:"The ToPhoneNumber [+1800] is not a valid E.164 phone number"`
+}
+
+// BulkJob is a large bulk SMS file which is processed in the background
+type BulkJob struct {
+	ID     uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID    `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	// ActorID is the member of an organisation who uploaded the file on behalf of the owner of the organisation
+	ActorID *UserID `json:"actor_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC" validate:"optional"`
+	// PhoneNumbers are the owners which the API key that uploaded the file can use, all the phones of the user can be used when it is empty
+	PhoneNumbers pq.StringArray `json:"-" gorm:"type:text[]"`
+	Filename     string         `json:"filename" example:"httpsms-bulk.csv"`
+	Status       BulkJobStatus  `json:"status" example:"processing"`
+	// Path is the location where the uploaded file is stored
+	Path       string     `json:"-"`
+	RequestID  string     `json:"request_id" example:"bulk-1ZkSs9M-httpsms-bulk.csv"`
+	CampaignID *uuid.UUID `json:"campaign_id" example:"8f9c71b8-b84e-4417-8408-a62274f65a08" validate:"optional"`
+	// RowOffset is the number of rows after the header which have been processed, the next batch starts after this row
+	RowOffset int `json:"-"`
+	// SendIndexes is the number of queued messages without a SendTime for each FromPhoneNumber, the send delays of the next batch continue from it
+	SendIndexes map[string]int `json:"-" gorm:"type:jsonb;serializer:json"`
+	// TotalRows is the number of rows which have been read from the file so far
+	TotalRows  int `json:"total_rows" example:"100000"`
+	QueuedRows int `json:"queued_rows" example:"99998"`
+	FailedRows int `json:"failed_rows" example:"2"`
+	// Errors contains the first MaxBulkJobErrors row errors
+	Errors      []BulkJobError `json:"errors" gorm:"type:jsonb;serializer:json"`
+	Failure     *string        `json:"failure" example:"The uploaded file is not a valid CSV or Excel file." validate:"optional"`
+	StartedAt   *time.Time     `json:"started_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	CompletedAt *time.Time     `json:"completed_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	CreatedAt   time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt   time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsPending checks if the job is waiting to be processed
+func (job *BulkJob) IsPending() bool {
+	return job.Status == BulkJobStatusPending
+}
+
+// IsFinished checks if the job has been completed or has failed
+func (job *BulkJob) IsFinished() bool {
+	return job.Status == BulkJobStatusCompleted || job.Status == BulkJobStatusFailed
+}
+
+// AddError records a row which cannot be sent
+func (job *BulkJob) AddError(row int, message string) {
+	job.FailedRows++
+	if len(job.Errors) < MaxBulkJobErrors {
+		job.Errors = append(job.Errors, BulkJobError{Row: row, Message: message})
+	}
+}
+
+// Processing marks the job as processing
+func (job *BulkJob) Processing(timestamp time.Time) *BulkJob {
+	job.Status = BulkJobStatusProcessing
+	job.StartedAt = &timestamp
+	job.UpdatedAt = timestamp
+	return job
+}
+
+// Completed marks the job as completed
+func (job *BulkJob) Completed(timestamp time.Time) *BulkJob {
+	job.Status = BulkJobStatusCompleted
+	job.CompletedAt = &timestamp
+	job.UpdatedAt = timestamp
+	return job
+}
+
+// Failed marks the job as failed with the reason shown to the user
+func (job *BulkJob) Failed(timestamp time.Time, failure string) *BulkJob {
+	job.Status = BulkJobStatusFailed
+	job.Failure = &failure
+	job.CompletedAt = &timestamp
+	job.UpdatedAt = timestamp
+	return job
+}
+
+// CanUsePhoneNumber checks if a row of the job can be sent from the phone number of an owner
+func (job *BulkJob) CanUsePhoneNumber(owner string) bool {
+	return len(job.PhoneNumbers) == 0 || slices.Contains(job.PhoneNumbers, owner)
+}
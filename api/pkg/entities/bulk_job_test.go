@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkJob_AddError(t *testing.T) {
+	job := &BulkJob{}
+	for row := 2; row < MaxBulkJobErrors+12; row++ {
+		job.AddError(row, "invalid row")
+	}
+
+	assert.Equal(t, MaxBulkJobErrors+10, job.FailedRows)
+	assert.Len(t, job.Errors, MaxBulkJobErrors)
+	assert.Equal(t, BulkJobError{Row: 2, Message: "invalid row"}, job.Errors[0])
+}
+
+func TestBulkJob_Transitions(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	job := &BulkJob{Status: BulkJobStatusPending}
+	assert.True(t, job.IsPending())
+
+	job.Processing(timestamp)
+	assert.False(t, job.IsPending())
+	assert.Equal(t, &timestamp, job.StartedAt)
+
+	job.Failed(timestamp, "The uploaded file is empty.")
+	assert.Equal(t, BulkJobStatusFailed, job.Status)
+	assert.Equal(t, "The uploaded file is empty.", *job.Failure)
+	assert.Equal(t, &timestamp, job.CompletedAt)
+}
+
+func TestBulkJob_CanUsePhoneNumber(t *testing.T) {
+	assert.True(t, (&BulkJob{}).CanUsePhoneNumber("+18005550100"))
+	assert.True(t, (&BulkJob{PhoneNumbers: []string{"+18005550199"}}).CanUsePhoneNumber("+18005550199"))
+	assert.False(t, (&BulkJob{PhoneNumbers: []string{"+18005550199"}}).CanUsePhoneNumber("+18005550100"))
+}
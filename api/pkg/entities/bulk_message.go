@@ -12,5 +12,7 @@ type BulkMessage struct {
 	ExpiredCount   int64     `json:"expired_count" example:"3"`
 	SentCount      int64     `json:"sent_count" example:"40"`
 	DeliveredCount int64     `json:"delivered_count" example:"25"`
+	PausedCount    int64     `json:"paused_count" example:"0"`
+	CanceledCount  int64     `json:"canceled_count" example:"0"`
 	CreatedAt      time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
 }
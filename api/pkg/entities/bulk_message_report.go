@@ -0,0 +1,38 @@
+package entities
+
+import "time"
+
+// BulkMessageReport is the result of validating a bulk SMS file without sending the messages
+type BulkMessageReport struct {
+	TotalRows   int                     `json:"total_rows" example:"900"`
+	ValidRows   int                     `json:"valid_rows" example:"898"`
+	InvalidRows int                     `json:"invalid_rows" example:"2"`
+	Segments    uint                    `json:"segments" example:"1250"`
+	Rows        []*BulkMessageReportRow `json:"rows"`
+}
+
+// BulkMessageReportRow is the validation result of a single row in a bulk SMS file
+type BulkMessageReportRow struct {
+	Row             int    `json:"row" example:"2"`
+	FromPhoneNumber string `json:"from_phone_number" example:"+18005550199"`
+	ToPhoneNumber   string `json:"to_phone_number" example:"+18005550100"`
+	// SendAt is the time in UTC when the message will be sent, it is null when the message is sent immediately
+	SendAt   *time.Time      `json:"send_at" example:"2022-06-05T14:26:09Z" validate:"optional"`
+	Encoding MessageEncoding `json:"encoding" example:"GSM-7"`
+	Segments uint            `json:"segments" example:"1"`
+	Errors   []string        `json:"errors" example:"The ToPhoneNumber [+1800] is not a valid E.164 phone number"`
+}
+
+// NewBulkMessageReport creates a BulkMessageReport from the rows of a bulk SMS file
+func NewBulkMessageReport(rows []*BulkMessageReportRow) *BulkMessageReport {
+	report := &BulkMessageReport{TotalRows: len(rows), Rows: rows}
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			report.InvalidRows++
+			continue
+		}
+		report.ValidRows++
+		report.Segments += row.Segments
+	}
+	return report
+}
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBulkMessageReport(t *testing.T) {
+	report := NewBulkMessageReport([]*BulkMessageReportRow{
+		{Row: 2, Segments: 1, Errors: []string{}},
+		{Row: 3, Segments: 2, Errors: []string{}},
+		{Row: 4, Segments: 3, Errors: []string{"The ToPhoneNumber [invalid] is not a valid E.164 phone number"}},
+	})
+
+	assert.Equal(t, 3, report.TotalRows)
+	assert.Equal(t, 2, report.ValidRows)
+	assert.Equal(t, 1, report.InvalidRows)
+	assert.Equal(t, uint(3), report.Segments)
+}
@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CampaignStatus is the status of an entities.Campaign
+type CampaignStatus string
+
+const (
+	// CampaignStatusDraft means the messages of the campaign are still being queued
+	CampaignStatusDraft = CampaignStatus("draft")
+
+	// CampaignStatusRunning means the messages of the campaign are being sent
+	CampaignStatusRunning = CampaignStatus("running")
+
+	// CampaignStatusPaused means the messages of the campaign which have not been sent are on hold
+	CampaignStatusPaused = CampaignStatus("paused")
+
+	// CampaignStatusCompleted means all the messages of the campaign have been processed by the phones
+	CampaignStatusCompleted = CampaignStatus("completed")
+
+	// CampaignStatusCanceled means the messages of the campaign which had not been sent were canceled
+	CampaignStatusCanceled = CampaignStatus("canceled")
+)
+
+// Campaign is a named batch of messages which share the same request ID
+type Campaign struct {
+	ID     uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID         `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name   string         `json:"name" example:"httpsms-bulk.csv"`
+	Status CampaignStatus `json:"status" example:"running"`
+	// RequestID is the request ID of all the messages in the campaign
+	RequestID string         `json:"request_id" gorm:"index" example:"bulk-1ZkSs9M-httpsms-bulk.csv"`
+	Owners    pq.StringArray `json:"owners" example:"+18005550199,+18005550100" gorm:"type:text[]" swaggertype:"array,string"`
+	// ScheduledAt is the earliest time when the messages of the campaign are sent, it is null when the messages are sent immediately
+	ScheduledAt *time.Time `json:"scheduled_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	// Counters are the number of messages of the campaign in each status
+	Counters    *BulkMessage `json:"counters" gorm:"-"`
+	PausedAt    *time.Time   `json:"paused_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	CanceledAt  *time.Time   `json:"canceled_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	CompletedAt *time.Time   `json:"completed_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	CreatedAt   time.Time    `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt   time.Time    `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// CanBePaused checks if the campaign is sending messages
+func (campaign *Campaign) CanBePaused() bool {
+	return campaign.Status == CampaignStatusRunning
+}
+
+// CanBeResumed checks if the campaign is paused
+func (campaign *Campaign) CanBeResumed() bool {
+	return campaign.Status == CampaignStatusPaused
+}
+
+// CanBeCanceled checks if the campaign still has messages which can be stopped
+func (campaign *Campaign) CanBeCanceled() bool {
+	return campaign.Status == CampaignStatusDraft || campaign.Status == CampaignStatusRunning || campaign.Status == CampaignStatusPaused
+}
+
+// IsFinished checks if the phones have processed all the messages of a running campaign
+func (campaign *Campaign) IsFinished() bool {
+	if campaign.Status != CampaignStatusRunning || campaign.Counters == nil {
+		return false
+	}
+
+	counters := campaign.Counters
+	return counters.SentCount+counters.DeliveredCount+counters.FailedCount+counters.ExpiredCount+counters.CanceledCount >= counters.Total
+}
+
+// Running marks the campaign as running
+func (campaign *Campaign) Running(timestamp time.Time) *Campaign {
+	campaign.Status = CampaignStatusRunning
+	campaign.PausedAt = nil
+	campaign.UpdatedAt = timestamp
+	return campaign
+}
+
+// Paused marks the campaign as paused
+func (campaign *Campaign) Paused(timestamp time.Time) *Campaign {
+	campaign.Status = CampaignStatusPaused
+	campaign.PausedAt = &timestamp
+	campaign.UpdatedAt = timestamp
+	return campaign
+}
+
+// Canceled marks the campaign as canceled
+func (campaign *Campaign) Canceled(timestamp time.Time) *Campaign {
+	campaign.Status = CampaignStatusCanceled
+	campaign.CanceledAt = &timestamp
+	campaign.UpdatedAt = timestamp
+	return campaign
+}
+
+// Completed marks the campaign as completed
+func (campaign *Campaign) Completed(timestamp time.Time) *Campaign {
+	campaign.Status = CampaignStatusCompleted
+	campaign.CompletedAt = &timestamp
+	campaign.UpdatedAt = timestamp
+	return campaign
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCampaign_IsFinished(t *testing.T) {
+	campaign := &Campaign{Status: CampaignStatusRunning}
+	assert.False(t, campaign.IsFinished())
+
+	campaign.Counters = &BulkMessage{Total: 5, SentCount: 1, DeliveredCount: 1, FailedCount: 1, ExpiredCount: 1, PendingCount: 1}
+	assert.False(t, campaign.IsFinished())
+
+	campaign.Counters.PendingCount, campaign.Counters.CanceledCount = 0, 1
+	assert.True(t, campaign.IsFinished())
+
+	campaign.Status = CampaignStatusPaused
+	assert.False(t, campaign.IsFinished())
+}
+
+func TestCampaign_Transitions(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	campaign := &Campaign{Status: CampaignStatusDraft}
+
+	assert.True(t, campaign.CanBeCanceled())
+	assert.False(t, campaign.CanBePaused())
+
+	campaign.Running(timestamp)
+	assert.True(t, campaign.CanBePaused())
+	assert.False(t, campaign.CanBeResumed())
+
+	campaign.Paused(timestamp)
+	assert.Equal(t, &timestamp, campaign.PausedAt)
+	assert.True(t, campaign.CanBeResumed())
+	assert.True(t, campaign.CanBeCanceled())
+
+	campaign.Running(timestamp)
+	assert.Nil(t, campaign.PausedAt)
+
+	campaign.Canceled(timestamp)
+	assert.False(t, campaign.CanBeCanceled())
+	assert.False(t, campaign.CanBeResumed())
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Contact is an entry in the address book of a user
+type Contact struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID      UserID    `json:"user_id" gorm:"uniqueIndex:idx_contacts__user_id__phone_number" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	PhoneNumber string    `json:"phone_number" gorm:"uniqueIndex:idx_contacts__user_id__phone_number" example:"+18005550100"`
+	Name        string    `json:"name" example:"Jane Doe"`
+	// OrganisationID is the organisation of the owner which shares the contact with its members
+	OrganisationID *uuid.UUID `json:"organisation_id" gorm:"type:uuid;index" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// ActorID is the member of an organisation who last changed the contact on behalf of the owner of the organisation
+	ActorID *UserID        `json:"actor_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC" validate:"optional"`
+	Tags    pq.StringArray `json:"tags" example:"customer,vip" gorm:"type:text[]" swaggertype:"array,string"`
+	// Attributes are arbitrary custom fields of the contact e.g {"company": "Acme"}
+	Attributes map[string]any `json:"attributes" gorm:"type:jsonb;serializer:json" swaggertype:"object"`
+	CreatedAt  time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt  time.Time      `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
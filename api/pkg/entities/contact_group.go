@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ContactGroupType is the type of an entities.ContactGroup
+type ContactGroupType string
+
+const (
+	// ContactGroupTypeStatic is a group with a fixed list of phone numbers
+	ContactGroupTypeStatic = ContactGroupType("static")
+
+	// ContactGroupTypeDynamic is a group whose members are computed from the message history of an owner phone
+	ContactGroupTypeDynamic = ContactGroupType("dynamic")
+)
+
+// ContactGroupDirection filters the messages used to compute the members of a dynamic entities.ContactGroup
+type ContactGroupDirection string
+
+const (
+	// ContactGroupDirectionReceived selects contacts who sent a message to the owner
+	ContactGroupDirectionReceived = ContactGroupDirection("received")
+
+	// ContactGroupDirectionSent selects contacts who received a message from the owner
+	ContactGroupDirectionSent = ContactGroupDirection("sent")
+
+	// ContactGroupDirectionAny selects contacts who exchanged any message with the owner
+	ContactGroupDirectionAny = ContactGroupDirection("any")
+)
+
+// ContactGroup is a named list of recipients which can be messaged at once
+type ContactGroup struct {
+	ID     uuid.UUID        `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID           `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name   string           `json:"name" example:"Newsletter"`
+	Type   ContactGroupType `json:"type" example:"static"`
+	// Members are the phone numbers of a static group
+	Members pq.StringArray `json:"members" example:"+18005550199,+18005550100" gorm:"type:text[]" swaggertype:"array,string"`
+	// Owner is the phone number whose message history is used to compute the members of a dynamic group
+	Owner     *string                `json:"owner" example:"+18005550100"`
+	Direction *ContactGroupDirection `json:"direction" example:"received"`
+	// WithinDays is the number of days of message history used to compute the members of a dynamic group
+	WithinDays *uint     `json:"within_days" example:"90"`
+	CreatedAt  time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsDynamic checks if the members of the group are computed from the message history
+func (group *ContactGroup) IsDynamic() bool {
+	return group.Type == ContactGroupTypeDynamic
+}
+
+// MessageTypes returns the MessageType matching the direction of a dynamic group
+func (group *ContactGroup) MessageTypes() []MessageType {
+	if group.Direction == nil {
+		return []MessageType{MessageTypeMobileOriginated, MessageTypeMobileTerminated}
+	}
+
+	switch *group.Direction {
+	case ContactGroupDirectionReceived:
+		return []MessageType{MessageTypeMobileOriginated}
+	case ContactGroupDirectionSent:
+		return []MessageType{MessageTypeMobileTerminated}
+	default:
+		return []MessageType{MessageTypeMobileOriginated, MessageTypeMobileTerminated}
+	}
+}
+
+// Since returns the earliest message timestamp used to compute the members of a dynamic group
+func (group *ContactGroup) Since(now time.Time) time.Time {
+	if group.WithinDays == nil {
+		return time.Time{}
+	}
+	return now.Add(-time.Duration(*group.WithinDays) * 24 * time.Hour)
+}
@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContactGroup_MessageTypes(t *testing.T) {
+	received := ContactGroupDirectionReceived
+	sent := ContactGroupDirectionSent
+	both := ContactGroupDirectionAny
+
+	assert.Equal(t, []MessageType{MessageTypeMobileOriginated}, (&ContactGroup{Direction: &received}).MessageTypes())
+	assert.Equal(t, []MessageType{MessageTypeMobileTerminated}, (&ContactGroup{Direction: &sent}).MessageTypes())
+	assert.Equal(t, []MessageType{MessageTypeMobileOriginated, MessageTypeMobileTerminated}, (&ContactGroup{Direction: &both}).MessageTypes())
+	assert.Equal(t, []MessageType{MessageTypeMobileOriginated, MessageTypeMobileTerminated}, (&ContactGroup{}).MessageTypes())
+}
+
+func TestContactGroup_Since(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	days := uint(90)
+
+	assert.Equal(t, time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC), (&ContactGroup{WithinDays: &days}).Since(now))
+	assert.True(t, (&ContactGroup{}).Since(now).IsZero())
+}
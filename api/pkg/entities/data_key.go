@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataKey is the key which encrypts the message content of a user.
+// It is persisted wrapped by the master key so that the content cannot be decrypted with access to the database only.
+type DataKey struct {
+	ID     uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID    `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	// MasterKeyID identifies the master key which wrapped the key
+	MasterKeyID string `json:"master_key_id" gorm:"index" example:"5d41402abc4b2a76"`
+	WrappedKey  []byte `json:"-" gorm:"NOT NULL"`
+	// RetiredAt is set when a newer key is used to encrypt content, a retired key is only used to decrypt content
+	RetiredAt *time.Time `json:"retired_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	// ReEncryptedAt is set when all the content of the user has been re-encrypted with this key
+	ReEncryptedAt *time.Time `json:"re_encrypted_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	CreatedAt     time.Time  `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt     time.Time  `json:"updated_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// TableName overrides the table name used by DataKey
+func (DataKey) TableName() string {
+	return "data_keys"
+}
@@ -51,8 +51,26 @@ const (
 
 	// MessageStatusDeleted is for deleted messages and threads
 	MessageStatusDeleted = "deleted"
+
+	// MessageStatusCanceled means the message was canceled before it was sent by the mobile phone
+	MessageStatusCanceled = "canceled"
+
+	// MessageStatusPaused means the campaign of the message was paused before the message was sent by the mobile phone
+	MessageStatusPaused = "paused"
 )
 
+// MessageFinalStatuses returns the statuses of an entities.Message which will not be changed by the mobile phone anymore
+func MessageFinalStatuses() []MessageStatus {
+	return []MessageStatus{
+		MessageStatusSent,
+		MessageStatusDelivered,
+		MessageStatusFailed,
+		MessageStatusExpired,
+		MessageStatusReceived,
+		MessageStatusCanceled,
+	}
+}
+
 // MessageEventName is the type of event generated by the mobile phone for a message
 type MessageEventName string
 
@@ -84,10 +102,14 @@ func (s SIM) String() string {
 
 // Message represents a message sent between 2 phone numbers
 type Message struct {
-	ID          uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
-	RequestID   *string        `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
-	Owner       string         `json:"owner" example:"+18005550199"`
-	UserID      UserID         `json:"user_id" gorm:"index:idx_messages__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	RequestID *string   `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
+	Owner     string    `json:"owner" example:"+18005550199"`
+	UserID    UserID    `json:"user_id" gorm:"index:idx_messages__user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	// OrganisationID is the organisation of the owner which shares the message with its members
+	OrganisationID *uuid.UUID `json:"organisation_id" gorm:"type:uuid;index" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// ActorID is the member of an organisation who sent the message on behalf of the owner of the organisation
+	ActorID     *UserID        `json:"actor_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC" validate:"optional"`
 	Contact     string         `json:"contact" example:"+18005550100"`
 	Content     string         `json:"content" example:"This is a sample text message"`
 	Attachments pq.StringArray `json:"attachments" gorm:"type:text[]" swaggertype:"array,string" example:"https://example.com/image.jpg,https://example.com/video.mp4"`
@@ -100,6 +122,11 @@ type Message struct {
 	// * DEFAULT: used the default communication SIM card
 	SIM SIM `json:"sim" example:"DEFAULT"`
 
+	// Encoding is the character set used to send the message, it is empty for encrypted messages
+	Encoding MessageEncoding `json:"encoding" example:"GSM-7"`
+	// Segments is the number of SMS segments the carrier uses to send the message
+	Segments uint `json:"segments" example:"1"`
+
 	// SendDuration is the number of nanoseconds from when the request was received until when the mobile phone send the message
 	SendDuration *int64 `json:"send_time" example:"133414" validate:"optional"`
 
@@ -114,11 +141,20 @@ type Message struct {
 	DeliveredAt             *time.Time `json:"delivered_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
 	ExpiredAt               *time.Time `json:"expired_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
 	FailedAt                *time.Time `json:"failed_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	CanceledAt              *time.Time `json:"canceled_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
 	CanBePolled             bool       `json:"-" example:"false" swaggerignore:"true"`
 	SendAttemptCount        uint       `json:"send_attempt_count" example:"0"`
 	MaxSendAttempts         uint       `json:"max_send_attempts" example:"1"`
 	ReceivedAt              *time.Time `json:"received_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
 	FailureReason           *string    `json:"failure_reason" example:"UNKNOWN" validate:"optional"`
+
+	// UsageRegisteredAt is when the message was added to the billing usage of the user, it is cleared when the message is canceled.
+	// It is read-only so that saving a message which was loaded before the usage was registered does not overwrite it.
+	UsageRegisteredAt *time.Time `json:"-" gorm:"->" swaggerignore:"true"`
+
+	// ReroutedFrom is the phone number which was offline when the message expired and the message was moved to a fallback phone
+	ReroutedFrom *string    `json:"rerouted_from" example:"+18005550199" validate:"optional"`
+	ReroutedAt   *time.Time `json:"rerouted_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
 }
 
 // IsSending determines if a message is being sent
@@ -146,8 +182,28 @@ func (message *Message) IsExpired() bool {
 	return message.Status == MessageStatusExpired
 }
 
-// CanBeRescheduled checks if a message can be rescheduled
+// IsCanceled checks if a message is canceled
+func (message *Message) IsCanceled() bool {
+	return message.Status == MessageStatusCanceled
+}
+
+// IsPaused checks if a message is paused
+func (message *Message) IsPaused() bool {
+	return message.Status == MessageStatusPaused
+}
+
+// CanBeCanceled checks if a message is still waiting to be sent by the mobile phone
+func (message *Message) CanBeCanceled() bool {
+	return message.IsPending() || message.IsScheduled() || message.IsPaused()
+}
+
+// CanBeRescheduled checks if the send time of a message can be changed because it is still waiting to be sent by the mobile phone
 func (message *Message) CanBeRescheduled() bool {
+	return message.IsPending() || message.IsScheduled() || message.IsPaused()
+}
+
+// CanBeRetried checks if a message which was not sent has send attempts left
+func (message *Message) CanBeRetried() bool {
 	return message.SendAttemptCount < message.MaxSendAttempts
 }
 
@@ -204,6 +260,36 @@ func (message *Message) Expired(timestamp time.Time) *Message {
 	return message
 }
 
+// Canceled registers a message as canceled
+func (message *Message) Canceled(timestamp time.Time) *Message {
+	message.CanceledAt = &timestamp
+	message.Status = MessageStatusCanceled
+	message.updateOrderTimestamp(timestamp)
+	return message
+}
+
+// SendAtChanged changes the time when the message will be sent by the mobile phone
+func (message *Message) SendAtChanged(sendAt time.Time) *Message {
+	message.ScheduledSendTime = &sendAt
+	message.Status = MessageStatusPending
+	message.NotificationScheduledAt = nil
+	return message
+}
+
+// Rerouted moves an expired message to a fallback phone which will send it.
+// The send attempts of the previous phone are kept and the fallback phone gets its own send attempts on top of them.
+func (message *Message) Rerouted(timestamp time.Time, phone *Phone) *Message {
+	previousOwner := message.Owner
+	message.ReroutedFrom = &previousOwner
+	message.ReroutedAt = &timestamp
+	message.Owner = phone.PhoneNumber
+	message.SIM = phone.SIM
+	message.MaxSendAttempts = message.SendAttemptCount + phone.MaxSendAttemptsSanitized()
+	message.Status = MessageStatusPending
+	message.updateOrderTimestamp(timestamp)
+	return message
+}
+
 // NotificationScheduled registers a message as scheduled
 func (message *Message) NotificationScheduled(timestamp time.Time) *Message {
 	message.NotificationScheduledAt = &timestamp
@@ -0,0 +1,77 @@
+package entities
+
+import "time"
+
+// MessageAnalyticsGranularity is the length of the periods of the MessageAnalytics
+type MessageAnalyticsGranularity string
+
+const (
+	// MessageAnalyticsGranularityHour groups the messages by hour
+	MessageAnalyticsGranularityHour = MessageAnalyticsGranularity("hour")
+
+	// MessageAnalyticsGranularityDay groups the messages by day
+	MessageAnalyticsGranularityDay = MessageAnalyticsGranularity("day")
+
+	// MessageAnalyticsGranularityWeek groups the messages by week starting on Monday
+	MessageAnalyticsGranularityWeek = MessageAnalyticsGranularity("week")
+
+	// MessageAnalyticsGranularityMonth groups the messages by month
+	MessageAnalyticsGranularityMonth = MessageAnalyticsGranularity("month")
+)
+
+// MessageAnalyticsStats are the aggregated statistics of a group of messages
+type MessageAnalyticsStats struct {
+	Total          int64 `json:"total" example:"150"`
+	PendingCount   int64 `json:"pending_count" example:"3"`
+	ScheduledCount int64 `json:"scheduled_count" example:"0"`
+	SendingCount   int64 `json:"sending_count" example:"1"`
+	SentCount      int64 `json:"sent_count" example:"20"`
+	DeliveredCount int64 `json:"delivered_count" example:"80"`
+	FailedCount    int64 `json:"failed_count" example:"4"`
+	ExpiredCount   int64 `json:"expired_count" example:"2"`
+	ReceivedCount  int64 `json:"received_count" example:"40"`
+	CanceledCount  int64 `json:"canceled_count" example:"0"`
+	PausedCount    int64 `json:"paused_count" example:"0"`
+
+	// DeliveryRate is the fraction of the sent, delivered, failed and expired messages which were delivered, it is null when there are no such messages
+	DeliveryRate *float64 `json:"delivery_rate" example:"0.7843"`
+
+	// SendDurationP50 and SendDurationP95 are percentiles in milliseconds of the time from when the request was received until when the mobile phone sent the message
+	SendDurationP50 *float64 `json:"send_duration_p50_ms" gorm:"column:send_duration_p50" example:"1250.5"`
+	SendDurationP95 *float64 `json:"send_duration_p95_ms" gorm:"column:send_duration_p95" example:"8400"`
+
+	// DeliveryLatencyP50 and DeliveryLatencyP95 are percentiles in milliseconds of the time from when the message was sent until when it was delivered
+	DeliveryLatencyP50 *float64 `json:"delivery_latency_p50_ms" gorm:"column:delivery_latency_p50" example:"3200"`
+	DeliveryLatencyP95 *float64 `json:"delivery_latency_p95_ms" gorm:"column:delivery_latency_p95" example:"15000"`
+}
+
+// MessageAnalyticsPeriod are the statistics of the messages with an order timestamp in a period
+type MessageAnalyticsPeriod struct {
+	Timestamp time.Time `json:"timestamp" example:"2022-06-05T00:00:00Z"`
+	MessageAnalyticsStats
+}
+
+// MessageAnalyticsPhone are the statistics of the messages of a phone number
+type MessageAnalyticsPhone struct {
+	Owner string `json:"owner" example:"+18005550199"`
+	MessageAnalyticsStats
+}
+
+// MessageAnalyticsFailureReason is the number of messages which failed with a reason
+type MessageAnalyticsFailureReason struct {
+	FailureReason string `json:"failure_reason" example:"RESULT_ERROR_GENERIC_FAILURE"`
+	Count         int64  `json:"count" example:"4"`
+}
+
+// MessageAnalytics are the statistics of the messages of a user with an order timestamp in a time range
+type MessageAnalytics struct {
+	From        time.Time                   `json:"from" example:"2022-06-01T00:00:00Z"`
+	To          time.Time                   `json:"to" example:"2022-07-01T00:00:00Z"`
+	Owner       *string                     `json:"owner" example:"+18005550199"`
+	Granularity MessageAnalyticsGranularity `json:"granularity" example:"day"`
+	Totals      MessageAnalyticsStats       `json:"totals"`
+	// Periods are sorted by timestamp, the periods without messages are omitted
+	Periods        []*MessageAnalyticsPeriod        `json:"periods"`
+	Phones         []*MessageAnalyticsPhone         `json:"phones"`
+	FailureReasons []*MessageAnalyticsFailureReason `json:"failure_reasons"`
+}
@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MessageExportFormat is the file format of exported messages
+type MessageExportFormat string
+
+const (
+	// MessageExportFormatCSV exports messages as a CSV file
+	MessageExportFormatCSV = MessageExportFormat("csv")
+
+	// MessageExportFormatNDJSON exports messages as newline delimited JSON
+	MessageExportFormatNDJSON = MessageExportFormat("ndjson")
+
+	// MessageExportFormatXLSX exports messages as an Excel file
+	MessageExportFormatXLSX = MessageExportFormat("xlsx")
+)
+
+// ContentType is the MIME type of the exported file
+func (format MessageExportFormat) ContentType() string {
+	switch format {
+	case MessageExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case MessageExportFormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "text/csv"
+	}
+}
+
+// MessageExportStatus is the status of an entities.MessageExport
+type MessageExportStatus string
+
+const (
+	// MessageExportStatusPending means the export is waiting to be processed
+	MessageExportStatusPending = MessageExportStatus("pending")
+
+	// MessageExportStatusProcessing means the messages are being written to the file
+	MessageExportStatusProcessing = MessageExportStatus("processing")
+
+	// MessageExportStatusCompleted means the file can be downloaded
+	MessageExportStatusCompleted = MessageExportStatus("completed")
+
+	// MessageExportStatusFailed means the file could not be created
+	MessageExportStatusFailed = MessageExportStatus("failed")
+)
+
+// MessageExportLinkDuration is how long the download link of an entities.MessageExport is valid
+const MessageExportLinkDuration = 7 * 24 * time.Hour
+
+// MessageExport is a file with the message history of a user which is created in the background
+type MessageExport struct {
+	ID       uuid.UUID           `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID   UserID              `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Format   MessageExportFormat `json:"format" example:"csv"`
+	Status   MessageExportStatus `json:"status" example:"completed"`
+	Owners   pq.StringArray      `json:"owners" example:"+18005550199" gorm:"type:text[]" swaggertype:"array,string"`
+	Contacts pq.StringArray      `json:"contacts" example:"+18005550100" gorm:"type:text[]" swaggertype:"array,string"`
+	Types    pq.StringArray      `json:"types" example:"mobile-terminated" gorm:"type:text[]" swaggertype:"array,string"`
+	Statuses pq.StringArray      `json:"statuses" example:"delivered" gorm:"type:text[]" swaggertype:"array,string"`
+	Since    *time.Time          `json:"since" example:"2024-01-01T00:00:00Z" validate:"optional"`
+	Until    *time.Time          `json:"until" example:"2024-04-01T00:00:00Z" validate:"optional"`
+	// Path is the location where the exported file is stored
+	Path string `json:"-"`
+	// Token is the secret in the download link of the file
+	Token        string     `json:"-"`
+	MessageCount int        `json:"message_count" example:"15000"`
+	DownloadURL  *string    `json:"download_url" example:"https://api.httpsms.com/v1/message-exports/32343a19-da5e-4b1b-a767-3298a73703cb/download?token=9f3a" validate:"optional"`
+	ExpiresAt    *time.Time `json:"expires_at" example:"2022-06-12T14:26:09.527976+03:00" validate:"optional"`
+	Failure      *string    `json:"failure" example:"Cannot fetch the messages. Please create the export again." validate:"optional"`
+	CompletedAt  *time.Time `json:"completed_at" example:"2022-06-05T14:26:09.527976+03:00" validate:"optional"`
+	CreatedAt    time.Time  `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt    time.Time  `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// IsPending checks if the export is waiting to be processed
+func (export *MessageExport) IsPending() bool {
+	return export.Status == MessageExportStatusPending
+}
+
+// IsProcessing checks if the file of the export is being created
+func (export *MessageExport) IsProcessing() bool {
+	return export.Status == MessageExportStatusProcessing
+}
+
+// IsDownloadable checks if the file of the export can be downloaded at a timestamp
+func (export *MessageExport) IsDownloadable(timestamp time.Time) bool {
+	return export.Status == MessageExportStatusCompleted && export.ExpiresAt != nil && timestamp.Before(*export.ExpiresAt)
+}
+
+// Filename is the name of the exported file
+func (export *MessageExport) Filename() string {
+	return "httpsms-messages-" + export.ID.String() + "." + string(export.Format)
+}
+
+// Processing marks the export as processing
+func (export *MessageExport) Processing(timestamp time.Time) *MessageExport {
+	export.Status = MessageExportStatusProcessing
+	export.UpdatedAt = timestamp
+	return export
+}
+
+// Completed marks the export as completed with a download link which expires after MessageExportLinkDuration
+func (export *MessageExport) Completed(timestamp time.Time, downloadURL string) *MessageExport {
+	expiresAt := timestamp.Add(MessageExportLinkDuration)
+	export.Status = MessageExportStatusCompleted
+	export.DownloadURL = &downloadURL
+	export.ExpiresAt = &expiresAt
+	export.CompletedAt = &timestamp
+	export.UpdatedAt = timestamp
+	return export
+}
+
+// Failed marks the export as failed with the reason shown to the user
+func (export *MessageExport) Failed(timestamp time.Time, failure string) *MessageExport {
+	export.Status = MessageExportStatusFailed
+	export.Failure = &failure
+	export.CompletedAt = &timestamp
+	export.UpdatedAt = timestamp
+	return export
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageExport_IsDownloadable(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	export := &MessageExport{Status: MessageExportStatusPending}
+	assert.True(t, export.IsPending())
+	assert.False(t, export.IsDownloadable(timestamp))
+
+	export.Processing(timestamp).Completed(timestamp, "https://api.httpsms.com/v1/message-exports/1/download?token=secret")
+	assert.False(t, export.IsPending())
+	assert.Equal(t, "https://api.httpsms.com/v1/message-exports/1/download?token=secret", *export.DownloadURL)
+	assert.True(t, export.IsDownloadable(timestamp.Add(MessageExportLinkDuration-time.Second)))
+	assert.False(t, export.IsDownloadable(timestamp.Add(MessageExportLinkDuration)))
+}
+
+func TestMessageExport_Failed(t *testing.T) {
+	timestamp := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	export := (&MessageExport{Status: MessageExportStatusProcessing}).Failed(timestamp, "Cannot save the file. Please create the export again.")
+
+	assert.Equal(t, MessageExportStatusFailed, export.Status)
+	assert.Equal(t, "Cannot save the file. Please create the export again.", *export.Failure)
+	assert.False(t, export.IsDownloadable(timestamp))
+}
+
+func TestMessageExport_Filename(t *testing.T) {
+	export := &MessageExport{ID: uuid.MustParse("32343a19-da5e-4b1b-a767-3298a73703cb"), Format: MessageExportFormatNDJSON}
+	assert.Equal(t, "httpsms-messages-32343a19-da5e-4b1b-a767-3298a73703cb.ndjson", export.Filename())
+	assert.Equal(t, "application/x-ndjson", export.Format.ContentType())
+}
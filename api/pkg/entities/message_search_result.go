@@ -0,0 +1,8 @@
+package entities
+
+// MessageSearchResult is an entities.Message which matches a search query
+type MessageSearchResult struct {
+	Message
+	// Highlight is an HTML escaped snippet of the content with the matching words wrapped in <mark> tags
+	Highlight *string `json:"highlight" example:"your order <mark>ORD-123456</mark> has been shipped" validate:"optional"`
+}
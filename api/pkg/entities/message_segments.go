@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"strings"
+)
+
+// MessageEncoding is the character set used by the carrier to send an SMS message
+type MessageEncoding string
+
+const (
+	// MessageEncodingGSM7 is used when all the characters are in the GSM 03.38 alphabet
+	MessageEncodingGSM7 = MessageEncoding("GSM-7")
+
+	// MessageEncodingUCS2 is used when the message contains a character which is not in the GSM 03.38 alphabet e.g an emoji
+	MessageEncodingUCS2 = MessageEncoding("UCS-2")
+)
+
+const (
+	gsm7SingleSegmentLength = 160
+	gsm7MultiSegmentLength  = 153
+	ucs2SingleSegmentLength = 70
+	ucs2MultiSegmentLength  = 67
+)
+
+// gsm7BasicCharacters is the GSM 03.38 default alphabet, each character uses 1 septet
+const gsm7BasicCharacters = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7ExtensionCharacters is the GSM 03.38 extension table, each character uses 2 septets because of the escape character
+const gsm7ExtensionCharacters = "\f^{}\\[~]|€"
+
+// MessageSegments is the encoding and number of segments needed to send the content of an SMS message
+type MessageSegments struct {
+	Encoding             MessageEncoding `json:"encoding" example:"GSM-7"`
+	Segments             uint            `json:"segments" example:"1"`
+	Characters           uint            `json:"characters" example:"12"`
+	CharactersPerSegment uint            `json:"characters_per_segment" example:"160"`
+	RemainingCharacters  uint            `json:"remaining_characters" example:"148"`
+}
+
+// CalculateMessageSegments computes the encoding and the number of segments which the carrier uses to send the content.
+// Characters are counted in septets for GSM-7 and in UTF-16 code units for UCS-2.
+func CalculateMessageSegments(content string) MessageSegments {
+	encoding := MessageEncodingGSM7
+	for _, character := range content {
+		if messageCharacterLength(MessageEncodingGSM7, character) == 0 {
+			encoding = MessageEncodingUCS2
+			break
+		}
+	}
+
+	singleLength, multiLength := uint(gsm7SingleSegmentLength), uint(gsm7MultiSegmentLength)
+	if encoding == MessageEncodingUCS2 {
+		singleLength, multiLength = ucs2SingleSegmentLength, ucs2MultiSegmentLength
+	}
+
+	characters := uint(0)
+	for _, character := range content {
+		characters += messageCharacterLength(encoding, character)
+	}
+
+	if characters == 0 {
+		return MessageSegments{Encoding: encoding, CharactersPerSegment: singleLength, RemainingCharacters: singleLength}
+	}
+
+	if characters <= singleLength {
+		return MessageSegments{
+			Encoding:             encoding,
+			Segments:             1,
+			Characters:           characters,
+			CharactersPerSegment: singleLength,
+			RemainingCharacters:  singleLength - characters,
+		}
+	}
+
+	// characters which need 2 units are never split across 2 segments
+	segments, current := uint(1), uint(0)
+	for _, character := range content {
+		length := messageCharacterLength(encoding, character)
+		if current+length > multiLength {
+			segments++
+			current = 0
+		}
+		current += length
+	}
+
+	return MessageSegments{
+		Encoding:             encoding,
+		Segments:             segments,
+		Characters:           characters,
+		CharactersPerSegment: multiLength,
+		RemainingCharacters:  multiLength - current,
+	}
+}
+
+// messageCharacterLength returns the number of units used by a character, 0 is returned if the character cannot be encoded
+func messageCharacterLength(encoding MessageEncoding, character rune) uint {
+	if encoding == MessageEncodingUCS2 {
+		if character > 0xFFFF {
+			return 2
+		}
+		return 1
+	}
+
+	if strings.ContainsRune(gsm7BasicCharacters, character) {
+		return 1
+	}
+	if strings.ContainsRune(gsm7ExtensionCharacters, character) {
+		return 2
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateMessageSegments_GSM7SingleSegment(t *testing.T) {
+	segments := CalculateMessageSegments("Hello World")
+
+	assert.Equal(t, MessageEncodingGSM7, segments.Encoding)
+	assert.Equal(t, uint(1), segments.Segments)
+	assert.Equal(t, uint(11), segments.Characters)
+	assert.Equal(t, uint(149), segments.RemainingCharacters)
+}
+
+func TestCalculateMessageSegments_GSM7Boundary(t *testing.T) {
+	assert.Equal(t, uint(1), CalculateMessageSegments(strings.Repeat("a", 160)).Segments)
+	assert.Equal(t, uint(2), CalculateMessageSegments(strings.Repeat("a", 161)).Segments)
+	assert.Equal(t, uint(2), CalculateMessageSegments(strings.Repeat("a", 306)).Segments)
+	assert.Equal(t, uint(3), CalculateMessageSegments(strings.Repeat("a", 307)).Segments)
+}
+
+func TestCalculateMessageSegments_GSM7ExtensionCharactersUseTwoSeptets(t *testing.T) {
+	segments := CalculateMessageSegments("€" + strings.Repeat("a", 158))
+
+	assert.Equal(t, MessageEncodingGSM7, segments.Encoding)
+	assert.Equal(t, uint(160), segments.Characters)
+	assert.Equal(t, uint(1), segments.Segments)
+
+	// the escape sequence is not split between segments
+	segments = CalculateMessageSegments(strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10))
+	assert.Equal(t, uint(2), segments.Segments)
+	assert.Equal(t, uint(141), segments.RemainingCharacters)
+}
+
+func TestCalculateMessageSegments_EmojiSwitchesToUCS2(t *testing.T) {
+	segments := CalculateMessageSegments(strings.Repeat("a", 100) + "😀")
+
+	assert.Equal(t, MessageEncodingUCS2, segments.Encoding)
+	assert.Equal(t, uint(102), segments.Characters)
+	assert.Equal(t, uint(2), segments.Segments)
+	assert.Equal(t, uint(67), segments.CharactersPerSegment)
+}
+
+func TestCalculateMessageSegments_UCS2Boundary(t *testing.T) {
+	assert.Equal(t, uint(1), CalculateMessageSegments(strings.Repeat("é", 60)+strings.Repeat("ж", 10)).Segments)
+	assert.Equal(t, uint(2), CalculateMessageSegments(strings.Repeat("ж", 71)).Segments)
+	assert.Equal(t, uint(3), CalculateMessageSegments(strings.Repeat("ж", 135)).Segments)
+}
+
+func TestCalculateMessageSegments_Empty(t *testing.T) {
+	segments := CalculateMessageSegments("")
+
+	assert.Equal(t, MessageEncodingGSM7, segments.Encoding)
+	assert.Equal(t, uint(0), segments.Segments)
+}
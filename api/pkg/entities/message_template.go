@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// messageTemplateVariableRegex matches variables in a template e.g {{ first_name }}
+var messageTemplateVariableRegex = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// MessageTemplate is reusable message content with variables which are substituted when sending a message
+type MessageTemplate struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID    `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Name      string    `json:"name" example:"Order Shipped"`
+	Content   string    `json:"content" example:"Hello {{name}}, your order {{order_id}} has been shipped."`
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// Variables returns the unique variable names used in the template in the order they first appear
+func (template *MessageTemplate) Variables() []string {
+	seen := map[string]struct{}{}
+	result := make([]string, 0)
+	for _, match := range messageTemplateVariableRegex.FindAllStringSubmatch(template.Content, -1) {
+		if _, ok := seen[match[1]]; ok {
+			continue
+		}
+		seen[match[1]] = struct{}{}
+		result = append(result, match[1])
+	}
+	return result
+}
+
+// Render substitutes the variables in the template content.
+// The names of variables which are not present in the map are returned as missing.
+func (template *MessageTemplate) Render(variables map[string]string) (content string, missing []string) {
+	for _, name := range template.Variables() {
+		if _, ok := variables[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	content = messageTemplateVariableRegex.ReplaceAllStringFunc(template.Content, func(match string) string {
+		name := messageTemplateVariableRegex.FindStringSubmatch(match)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return match
+	})
+
+	return content, missing
+}
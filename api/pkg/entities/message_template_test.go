@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageTemplate_Variables(t *testing.T) {
+	template := &MessageTemplate{Content: "Hi {{name}}, {{ name }} your code is {{code}}"}
+
+	assert.Equal(t, []string{"name", "code"}, template.Variables())
+}
+
+func TestMessageTemplate_Render_AllVariables(t *testing.T) {
+	template := &MessageTemplate{Content: "Hi {{name}}, your code is {{ code }}"}
+
+	content, missing := template.Render(map[string]string{"name": "Ada", "code": "1234", "unused": "x"})
+
+	assert.Equal(t, "Hi Ada, your code is 1234", content)
+	assert.Empty(t, missing)
+}
+
+func TestMessageTemplate_Render_MissingVariables(t *testing.T) {
+	template := &MessageTemplate{Content: "Hi {{name}}, your code is {{code}}"}
+
+	content, missing := template.Render(map[string]string{"name": "Ada"})
+
+	assert.Equal(t, "Hi Ada, your code is {{code}}", content)
+	assert.Equal(t, []string{"code"}, missing)
+}
+
+func TestMessageTemplate_Render_EmptyValueIsNotMissing(t *testing.T) {
+	template := &MessageTemplate{Content: "Hi {{name}}!"}
+
+	content, missing := template.Render(map[string]string{"name": ""})
+
+	assert.Equal(t, "Hi !", content)
+	assert.Empty(t, missing)
+}
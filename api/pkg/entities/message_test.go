@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_CanBeRescheduled(t *testing.T) {
+	statuses := map[MessageStatus]bool{
+		MessageStatusPending:   true,
+		MessageStatusScheduled: true,
+		MessageStatusPaused:    true,
+		MessageStatusSending:   false,
+		MessageStatusSent:      false,
+		MessageStatusDelivered: false,
+		MessageStatusFailed:    false,
+		MessageStatusExpired:   false,
+		MessageStatusCanceled:  false,
+	}
+
+	for status, expected := range statuses {
+		message := &Message{Status: status}
+		assert.Equal(t, expected, message.CanBeRescheduled(), status)
+	}
+}
@@ -8,13 +8,19 @@ import (
 
 // MessageThread represents a message thread between 2 phone numbers
 type MessageThread struct {
-	ID                 uuid.UUID     `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
-	Owner              string        `json:"owner" example:"+18005550199"`
-	Contact            string        `json:"contact" example:"+18005550100"`
-	IsArchived         bool          `json:"is_archived" example:"false"`
-	IsRead             bool          `json:"is_read" gorm:"not null;default:true" example:"true"`
-	LastReadAt         time.Time     `json:"-" gorm:"not null;default:CURRENT_TIMESTAMP"`
-	UserID             UserID        `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	ID      uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	Owner   string    `json:"owner" example:"+18005550199"`
+	Contact string    `json:"contact" example:"+18005550100"`
+	// ContactName is the name of the contact in the address book of the user, it is not persisted with the thread
+	ContactName *string   `json:"contact_name" gorm:"-" example:"Jane Doe"`
+	IsArchived  bool      `json:"is_archived" example:"false"`
+	IsRead      bool      `json:"is_read" gorm:"not null;default:true" example:"true"`
+	LastReadAt  time.Time `json:"-" gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UserID      UserID    `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	// OrganisationID is the organisation of the owner which shares the thread with its members
+	OrganisationID *uuid.UUID `json:"organisation_id" gorm:"type:uuid;index" example:"32343a19-da5e-4b1b-a767-3298a73703ca" validate:"optional"`
+	// ActorID is the member of an organisation who last archived or read the thread on behalf of the owner of the organisation
+	ActorID            *UserID       `json:"actor_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC" validate:"optional"`
 	Color              string        `json:"color" example:"indigo"`
 	Status             MessageStatus `json:"status" example:"PENDING"`
 	LastMessageContent *string       `json:"last_message_content" example:"This is a sample message content"`
@@ -0,0 +1,92 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrganisationRole is the role of an OrganisationMember
+type OrganisationRole string
+
+const (
+	// OrganisationRoleOwner is the user who created the organisation, the phones, webhooks, threads, send schedules and billing of the organisation belong to this user
+	OrganisationRoleOwner = OrganisationRole("owner")
+
+	// OrganisationRoleAdmin can manage the members, phones, webhooks and send schedules of the organisation
+	OrganisationRoleAdmin = OrganisationRole("admin")
+
+	// OrganisationRoleAgent can send and read messages but cannot change the settings of the organisation
+	OrganisationRoleAgent = OrganisationRole("agent")
+
+	// OrganisationRoleReadOnly can only read the resources of the organisation
+	OrganisationRoleReadOnly = OrganisationRole("read-only")
+)
+
+// CanWrite returns true when the role can make requests which are not read-only
+func (role OrganisationRole) CanWrite() bool {
+	return role == OrganisationRoleOwner || role == OrganisationRoleAdmin || role == OrganisationRoleAgent
+}
+
+// CanManageMembers returns true when the role can invite, update and remove members
+func (role OrganisationRole) CanManageMembers() bool {
+	return role == OrganisationRoleOwner || role == OrganisationRoleAdmin
+}
+
+// CanAssign returns true when the role can invite a member with the other role or change the role of a member with the other role.
+// There is only one owner per organisation so the owner role cannot be assigned.
+func (role OrganisationRole) CanAssign(other OrganisationRole) bool {
+	switch role {
+	case OrganisationRoleOwner:
+		return other == OrganisationRoleAdmin || other == OrganisationRoleAgent || other == OrganisationRoleReadOnly
+	case OrganisationRoleAdmin:
+		return other == OrganisationRoleAgent || other == OrganisationRoleReadOnly
+	default:
+		return false
+	}
+}
+
+// Organisation is a team of users who share the phones, webhooks, threads, send schedules and billing of the owner
+type Organisation struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Name      string    `json:"name" example:"Acme Support"`
+	OwnerID   UserID    `json:"owner_id" gorm:"uniqueIndex" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// TableName overrides the table name used by Organisation
+func (Organisation) TableName() string {
+	return "organisations"
+}
+
+// OrganisationMember is a user who is invited to an Organisation by email
+type OrganisationMember struct {
+	ID             uuid.UUID        `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	OrganisationID uuid.UUID        `json:"organisation_id" gorm:"type:uuid;uniqueIndex:idx_organisation_members_organisation_id_email" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID         *UserID          `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Email          string           `json:"email" gorm:"uniqueIndex:idx_organisation_members_organisation_id_email" example:"agent@example.com"`
+	Role           OrganisationRole `json:"role" example:"agent"`
+	InvitedBy      UserID           `json:"invited_by" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	AcceptedAt     *time.Time       `json:"accepted_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	CreatedAt      time.Time        `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt      time.Time        `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
+
+// TableName overrides the table name used by OrganisationMember
+func (OrganisationMember) TableName() string {
+	return "organisation_members"
+}
+
+// IsPending returns true when the member has not accepted the invitation
+func (member *OrganisationMember) IsPending() bool {
+	return member.AcceptedAt == nil
+}
+
+// OrganisationMembership is an Organisation with the membership of a user
+type OrganisationMembership struct {
+	Organisation
+	MemberID   uuid.UUID        `json:"member_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Role       OrganisationRole `json:"role" example:"agent"`
+	AcceptedAt *time.Time       `json:"accepted_at" example:"2022-06-05T14:26:09.527976+03:00"`
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganisationRole_CanAssign(t *testing.T) {
+	assert.True(t, OrganisationRoleOwner.CanAssign(OrganisationRoleAdmin))
+	assert.True(t, OrganisationRoleOwner.CanAssign(OrganisationRoleReadOnly))
+	assert.False(t, OrganisationRoleOwner.CanAssign(OrganisationRoleOwner))
+
+	assert.True(t, OrganisationRoleAdmin.CanAssign(OrganisationRoleAgent))
+	assert.False(t, OrganisationRoleAdmin.CanAssign(OrganisationRoleAdmin))
+
+	assert.False(t, OrganisationRoleAgent.CanAssign(OrganisationRoleReadOnly))
+	assert.False(t, OrganisationRoleReadOnly.CanAssign(OrganisationRoleReadOnly))
+}
+
+func TestOrganisationRole_CanWrite(t *testing.T) {
+	assert.True(t, OrganisationRoleAgent.CanWrite())
+	assert.False(t, OrganisationRoleReadOnly.CanWrite())
+	assert.False(t, OrganisationRole("unknown").CanWrite())
+}
+
+func TestAuthContext_InOrganisation(t *testing.T) {
+	membership := &OrganisationMembership{
+		Organisation: Organisation{ID: uuid.New(), OwnerID: "owner-id"},
+		Role:         OrganisationRoleAgent,
+	}
+	user := AuthContext{ID: "agent-id", Email: "agent@example.com"}
+	assert.Equal(t, UserID("agent-id"), user.ActorID())
+
+	member := user.InOrganisation(membership)
+
+	assert.Equal(t, UserID("owner-id"), member.ID)
+	assert.Equal(t, UserID("agent-id"), member.ActorID())
+	assert.Equal(t, membership.ID, *member.OrganisationID)
+	assert.Equal(t, OrganisationRoleAgent, member.OrganisationRole)
+	assert.Equal(t, "agent@example.com", member.Email)
+}
+
+func TestAuthContext_OrganisationActorID(t *testing.T) {
+	user := AuthContext{ID: "agent-id"}
+	assert.Nil(t, user.OrganisationActorID())
+
+	member := user.InOrganisation(&OrganisationMembership{
+		Organisation: Organisation{ID: uuid.New(), OwnerID: "owner-id"},
+		Role:         OrganisationRoleAgent,
+	})
+
+	assert.Equal(t, UserID("agent-id"), *member.OrganisationActorID())
+}
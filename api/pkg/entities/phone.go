@@ -4,12 +4,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Phone represents an android phone which has installed the http sms app
 type Phone struct {
-	ID                    uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
-	UserID                UserID     `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	ID     uuid.UUID `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID UserID    `json:"user_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	// OrganisationID is the organisation of the owner which shares the phone with its members
+	OrganisationID *uuid.UUID `json:"organisation_id" gorm:"type:uuid;index" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// ActorID is the member of an organisation who last changed the phone on behalf of the owner of the organisation
+	ActorID               *UserID    `json:"actor_id" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC" validate:"optional"`
 	FcmToken              *string    `json:"fcm_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJzd....." validate:"optional"`
 	PhoneNumber           string     `json:"phone_number" example:"+18005550199"`
 	MessagesPerMinute     uint       `json:"messages_per_minute" example:"1"`
@@ -22,11 +27,15 @@ type Phone struct {
 	// MessageExpirationSeconds is the duration in seconds after sending a message when it is considered to be expired.
 	MessageExpirationSeconds uint `json:"message_expiration_seconds"`
 
+	// MissedCallAutoReply is the reply to missed calls when the phone has no AutoReplyRule with the missed_call trigger
 	MissedCallAutoReply *string `json:"missed_call_auto_reply" example:"This phone cannot receive calls. Please send an SMS instead." validate:"optional"`
 
 	// UnarchiveThread moves an archived message thread back to the inbox when a new message is received on this phone.
 	UnarchiveThread bool `json:"unarchive_thread" gorm:"default:false" example:"false"`
 
+	// FallbackPhoneNumbers are phones which send the expired messages of this phone when it is offline, in order of preference.
+	FallbackPhoneNumbers pq.StringArray `json:"fallback_phone_numbers" gorm:"type:text[]" swaggertype:"array,string" example:"+18005550100"`
+
 	CreatedAt time.Time `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
 	UpdatedAt time.Time `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
 }
@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduledMessage is an event for a message which is sent further in the future than the push queue can hold.
+// The event is enqueued by a sweeper once the SendAt time falls inside the queue horizon.
+// The sweeper sets ClaimedAt before it enqueues the event and deletes the row after the event is enqueued.
+type ScheduledMessage struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID     `json:"user_id" gorm:"index;NOT NULL" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	MessageID uuid.UUID  `json:"message_id" gorm:"uniqueIndex:idx_scheduled_messages__message_id__event_type;type:uuid;NOT NULL" example:"32343a19-da5e-4b1b-a767-3298a73703ca"`
+	EventType string     `json:"event_type" gorm:"uniqueIndex:idx_scheduled_messages__message_id__event_type;NOT NULL" example:"message.api.sent"`
+	Event     string     `json:"event" gorm:"type:text"`
+	SendAt    time.Time  `json:"send_at" gorm:"index" example:"2022-06-05T14:26:09.527976+03:00"`
+	ClaimedAt *time.Time `json:"claimed_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	CreatedAt time.Time  `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time  `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuppressionSource is the origin of an entities.Suppression
+type SuppressionSource string
+
+const (
+	// SuppressionSourceKeyword is used when the contact opted out by replying with a keyword e.g STOP
+	SuppressionSourceKeyword = SuppressionSource("keyword")
+
+	// SuppressionSourceAPI is used when the suppression was added through the API
+	SuppressionSourceAPI = SuppressionSource("api")
+)
+
+// Suppression is a contact which must not receive messages from an owner phone number
+type Suppression struct {
+	ID        uuid.UUID         `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	UserID    UserID            `json:"user_id" gorm:"uniqueIndex:idx_suppressions__user_id__owner__contact;NOT NULL" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	Owner     string            `json:"owner" gorm:"uniqueIndex:idx_suppressions__user_id__owner__contact;NOT NULL" example:"+18005550199"`
+	Contact   string            `json:"contact" gorm:"uniqueIndex:idx_suppressions__user_id__owner__contact;NOT NULL" example:"+18005550100"`
+	Source    SuppressionSource `json:"source" example:"keyword"`
+	Keyword   *string           `json:"keyword" example:"STOP"`
+	CreatedAt time.Time         `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt time.Time         `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
+}
@@ -86,6 +86,8 @@ type User struct {
 	NotificationWebhookEnabled       bool             `json:"notification_webhook_enabled" gorm:"default:true" example:"true"`
 	NotificationHeartbeatEnabled     bool             `json:"notification_heartbeat_enabled" gorm:"default:true" example:"true"`
 	NotificationNewsletterEnabled    bool             `json:"notification_newsletter_enabled" gorm:"default:true" example:"true"`
+	MessageContentRetentionDays      *uint            `json:"message_content_retention_days" example:"30" validate:"optional"`
+	DataRetentionDays                *uint            `json:"data_retention_days" example:"365" validate:"optional"`
 	CreatedAt                        time.Time        `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
 	UpdatedAt                        time.Time        `json:"updated_at" example:"2022-06-05T14:26:10.303278+03:00"`
 }
@@ -137,3 +139,23 @@ func (user User) GetBillingAnchorDay() int {
 	}
 	return user.CreatedAt.Day()
 }
+
+// MessageContentRetentionCutoff returns the time before which the content and attachments of messages are deleted.
+// It is nil when the content of messages is kept forever.
+func (user User) MessageContentRetentionCutoff(now time.Time) *time.Time {
+	return retentionCutoff(now, user.MessageContentRetentionDays)
+}
+
+// DataRetentionCutoff returns the time before which messages, heartbeats and phone notifications are deleted.
+// It is nil when the data is kept forever.
+func (user User) DataRetentionCutoff(now time.Time) *time.Time {
+	return retentionCutoff(now, user.DataRetentionDays)
+}
+
+func retentionCutoff(now time.Time, days *uint) *time.Time {
+	if days == nil || *days == 0 {
+		return nil
+	}
+	cutoff := now.AddDate(0, 0, -int(*days))
+	return &cutoff
+}
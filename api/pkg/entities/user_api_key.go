@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UserAPIKeyScope is a permission which is granted to a UserAPIKey
+type UserAPIKeyScope string
+
+const (
+	// UserAPIKeyScopeMessagesRead allows reading messages, threads, templates, campaigns and analytics
+	UserAPIKeyScopeMessagesRead = UserAPIKeyScope("messages:read")
+
+	// UserAPIKeyScopeMessagesSend allows sending, scheduling and cancelling messages
+	UserAPIKeyScopeMessagesSend = UserAPIKeyScope("messages:send")
+
+	// UserAPIKeyScopeMessagesDelete allows deleting messages and message threads
+	UserAPIKeyScopeMessagesDelete = UserAPIKeyScope("messages:delete")
+
+	// UserAPIKeyScopePhonesRead allows reading phones, phone pools, heartbeats and send schedules
+	UserAPIKeyScopePhonesRead = UserAPIKeyScope("phones:read")
+
+	// UserAPIKeyScopePhonesWrite allows changing phones, phone pools, heartbeats and send schedules
+	UserAPIKeyScopePhonesWrite = UserAPIKeyScope("phones:write")
+
+	// UserAPIKeyScopeWebhooksRead allows reading webhooks and discord integrations
+	UserAPIKeyScopeWebhooksRead = UserAPIKeyScope("webhooks:read")
+
+	// UserAPIKeyScopeWebhooksWrite allows changing webhooks and discord integrations
+	UserAPIKeyScopeWebhooksWrite = UserAPIKeyScope("webhooks:write")
+
+	// UserAPIKeyScopeContactsRead allows reading contacts, contact groups and suppressions
+	UserAPIKeyScopeContactsRead = UserAPIKeyScope("contacts:read")
+
+	// UserAPIKeyScopeContactsWrite allows changing contacts, contact groups and suppressions
+	UserAPIKeyScopeContactsWrite = UserAPIKeyScope("contacts:write")
+
+	// UserAPIKeyScopeBillingRead allows reading the billing usage
+	UserAPIKeyScopeBillingRead = UserAPIKeyScope("billing:read")
+)
+
+// UserAPIKeyScopes returns all the scopes which can be granted to a UserAPIKey
+func UserAPIKeyScopes() []UserAPIKeyScope {
+	return []UserAPIKeyScope{
+		UserAPIKeyScopeMessagesRead,
+		UserAPIKeyScopeMessagesSend,
+		UserAPIKeyScopeMessagesDelete,
+		UserAPIKeyScopePhonesRead,
+		UserAPIKeyScopePhonesWrite,
+		UserAPIKeyScopeWebhooksRead,
+		UserAPIKeyScopeWebhooksWrite,
+		UserAPIKeyScopeContactsRead,
+		UserAPIKeyScopeContactsWrite,
+		UserAPIKeyScopeBillingRead,
+	}
+}
+
+// UserAPIKey is an API key of a user which is limited to a set of scopes and optionally to some phone numbers
+type UserAPIKey struct {
+	ID        uuid.UUID      `json:"id" gorm:"primaryKey;type:uuid;" example:"32343a19-da5e-4b1b-a767-3298a73703cb"`
+	Name      string         `json:"name" example:"Analytics Job"`
+	UserID    UserID         `json:"user_id" gorm:"index" example:"WB7DRDWrJZRGbYrv2CKGkqbzvqdC"`
+	UserEmail string         `json:"user_email" example:"user@gmail.com"`
+	Scopes    pq.StringArray `json:"scopes" example:"messages:read,phones:read" gorm:"type:text[]" swaggertype:"array,string"`
+	// PhoneNumbers are the owner phone numbers which the key can use, the key can use all the phones of the user when it is empty
+	PhoneNumbers pq.StringArray `json:"phone_numbers" example:"+18005550199" gorm:"type:text[]" swaggertype:"array,string"`
+	APIKey       string         `json:"api_key" gorm:"uniqueIndex:idx_user_api_keys__api_key;NOT NULL" example:"sk_DGW8NwQp7mxKaSZ72Xq9v6xxxxx"`
+	ExpiresAt    *time.Time     `json:"expires_at" example:"2023-06-05T14:26:02.302718+03:00"`
+	LastUsedAt   *time.Time     `json:"last_used_at" example:"2022-06-05T14:26:09.527976+03:00"`
+	CreatedAt    time.Time      `json:"created_at" example:"2022-06-05T14:26:02.302718+03:00"`
+	UpdatedAt    time.Time      `json:"updated_at" example:"2022-06-05T14:26:02.302718+03:00"`
+}
+
+// TableName overrides the table name used by UserAPIKey
+func (UserAPIKey) TableName() string {
+	return "user_api_keys"
+}
+
+// IsExpired checks if the UserAPIKey can no longer be used at a point in time
+func (key *UserAPIKey) IsExpired(now time.Time) bool {
+	return key.ExpiresAt != nil && !now.Before(*key.ExpiresAt)
+}
+
+// ScopeList returns the scopes of the UserAPIKey
+func (key *UserAPIKey) ScopeList() []UserAPIKeyScope {
+	scopes := make([]UserAPIKeyScope, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		scopes = append(scopes, UserAPIKeyScope(scope))
+	}
+	return scopes
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAPIKey_IsExpired(t *testing.T) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(time.Hour)
+
+	assert.False(t, (&UserAPIKey{}).IsExpired(now))
+	assert.False(t, (&UserAPIKey{ExpiresAt: &expiresAt}).IsExpired(now))
+	assert.True(t, (&UserAPIKey{ExpiresAt: &expiresAt}).IsExpired(expiresAt))
+}
+
+func TestAuthContext_HasScope(t *testing.T) {
+	keyID := uuid.New()
+	scoped := AuthContext{ID: "user-id", UserAPIKeyID: &keyID, Scopes: []UserAPIKeyScope{UserAPIKeyScopeMessagesRead}}
+
+	assert.True(t, scoped.HasScope(UserAPIKeyScopeMessagesRead))
+	assert.False(t, scoped.HasScope(UserAPIKeyScopeMessagesSend))
+	assert.True(t, AuthContext{ID: "user-id"}.HasScope(UserAPIKeyScopeMessagesSend))
+}
+
+func TestAuthContext_CanUsePhoneNumber(t *testing.T) {
+	keyID := uuid.New()
+	restricted := AuthContext{ID: "user-id", UserAPIKeyID: &keyID, PhoneNumbers: []string{"+18005550199"}}
+
+	assert.True(t, restricted.CanUsePhoneNumber("+18005550199"))
+	assert.False(t, restricted.CanUsePhoneNumber("+18005550100"))
+	assert.True(t, AuthContext{ID: "user-id", UserAPIKeyID: &keyID}.CanUsePhoneNumber("+18005550100"))
+	assert.True(t, AuthContext{ID: "user-id"}.CanUsePhoneNumber("+18005550100"))
+}
+
+func TestAuthContext_CanUsePhoneNumbers(t *testing.T) {
+	keyID := uuid.New()
+	restricted := AuthContext{ID: "user-id", UserAPIKeyID: &keyID, PhoneNumbers: []string{"+18005550199"}}
+
+	assert.True(t, restricted.CanUsePhoneNumbers([]string{"+18005550199"}))
+	assert.False(t, restricted.CanUsePhoneNumbers([]string{"+18005550199", "+18005550100"}))
+	assert.False(t, restricted.CanUsePhoneNumbers(nil))
+	assert.True(t, AuthContext{ID: "user-id", UserAPIKeyID: &keyID}.CanUsePhoneNumbers(nil))
+	assert.True(t, AuthContext{ID: "user-id"}.CanUsePhoneNumbers(nil))
+}
@@ -51,3 +51,12 @@ func TestUser_GetBillingAnchorDay_PaidUserDay31(t *testing.T) {
 	}
 	assert.Equal(t, 31, user.GetBillingAnchorDay())
 }
+
+func TestUser_RetentionCutoff(t *testing.T) {
+	now := time.Date(2026, 7, 18, 7, 0, 0, 0, time.UTC)
+	days := uint(30)
+	user := User{DataRetentionDays: &days}
+
+	assert.Equal(t, time.Date(2026, 6, 18, 7, 0, 0, 0, time.UTC), *user.DataRetentionCutoff(now))
+	assert.Nil(t, user.MessageContentRetentionCutoff(now))
+}
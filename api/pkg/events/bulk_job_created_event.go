@@ -0,0 +1,19 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// BulkJobCreated is emitted when a new bulk SMS file is uploaded to be processed in the background
+const BulkJobCreated = "bulk-job.created"
+
+// BulkJobCreatedPayload is the payload of the BulkJobCreated event
+type BulkJobCreatedPayload struct {
+	BulkJobID uuid.UUID       `json:"bulk_job_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Source    string          `json:"source"`
+	Timestamp time.Time       `json:"timestamp"`
+}
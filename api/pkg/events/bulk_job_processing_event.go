@@ -0,0 +1,20 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// BulkJobProcessing is emitted after a batch of rows of a bulk SMS file is processed to process the next batch
+const BulkJobProcessing = "bulk-job.processing"
+
+// BulkJobProcessingPayload is the payload of the BulkJobProcessing event
+type BulkJobProcessingPayload struct {
+	BulkJobID uuid.UUID       `json:"bulk_job_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Source    string          `json:"source"`
+	RowOffset int             `json:"row_offset"`
+	Timestamp time.Time       `json:"timestamp"`
+}
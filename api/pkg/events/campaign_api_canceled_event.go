@@ -0,0 +1,19 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// CampaignAPICanceled is emitted when a campaign is canceled, the paused messages of the campaign are canceled in the background
+const CampaignAPICanceled = "campaign.api.canceled"
+
+// CampaignAPICanceledPayload is the payload of the CampaignAPICanceled event
+type CampaignAPICanceledPayload struct {
+	CampaignID uuid.UUID       `json:"campaign_id"`
+	UserID     entities.UserID `json:"user_id"`
+	RequestID  string          `json:"request_id"`
+	Timestamp  time.Time       `json:"timestamp"`
+}
@@ -0,0 +1,19 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// CampaignAPIResumed is emitted when a paused campaign is resumed, the paused messages of the campaign are rescheduled in the background
+const CampaignAPIResumed = "campaign.api.resumed"
+
+// CampaignAPIResumedPayload is the payload of the CampaignAPIResumed event
+type CampaignAPIResumedPayload struct {
+	CampaignID uuid.UUID       `json:"campaign_id"`
+	UserID     entities.UserID `json:"user_id"`
+	RequestID  string          `json:"request_id"`
+	Timestamp  time.Time       `json:"timestamp"`
+}
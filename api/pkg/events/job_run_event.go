@@ -0,0 +1,14 @@
+package events
+
+import (
+	"time"
+)
+
+// JobRun is emitted when a periodic job must run, the job schedules its next run by emitting this event again
+const JobRun = "job.run"
+
+// JobRunPayload is the payload of the JobRun event
+type JobRunPayload struct {
+	Name        string    `json:"name"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
@@ -0,0 +1,28 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// MessageAPICanceled is emitted when a message is canceled before it is sent by the mobile phone
+const MessageAPICanceled = "message.api.canceled"
+
+// MessageAPICanceledPayload is the payload of the MessageAPICanceled event
+type MessageAPICanceledPayload struct {
+	MessageID uuid.UUID       `json:"message_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Owner     string          `json:"owner"`
+	RequestID *string         `json:"request_id"`
+	Contact   string          `json:"contact"`
+	Timestamp time.Time       `json:"timestamp"`
+	Content   string          `json:"content"`
+	Encrypted bool            `json:"encrypted"`
+	SIM       entities.SIM    `json:"sim"`
+	// Segments and RequestReceivedAt are used to remove the canceled message from the billing usage
+	Segments          uint      `json:"segments"`
+	RequestReceivedAt time.Time `json:"request_received_at"`
+}
@@ -0,0 +1,25 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// MessageAPIPaused is emitted when the campaign of a message is paused before the message is sent by the mobile phone
+const MessageAPIPaused = "message.api.paused"
+
+// MessageAPIPausedPayload is the payload of the MessageAPIPaused event
+type MessageAPIPausedPayload struct {
+	MessageID uuid.UUID       `json:"message_id"`
+	UserID    entities.UserID `json:"user_id"`
+	Owner     string          `json:"owner"`
+	RequestID *string         `json:"request_id"`
+	Contact   string          `json:"contact"`
+	Timestamp time.Time       `json:"timestamp"`
+	Content   string          `json:"content"`
+	Encrypted bool            `json:"encrypted"`
+	SIM       entities.SIM    `json:"sim"`
+}
@@ -0,0 +1,26 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// MessageAPIRescheduled is emitted when the send time of a queued message is changed
+const MessageAPIRescheduled = "message.api.rescheduled"
+
+// MessageAPIRescheduledPayload is the payload of the MessageAPIRescheduled event
+type MessageAPIRescheduledPayload struct {
+	MessageID         uuid.UUID       `json:"message_id"`
+	UserID            entities.UserID `json:"user_id"`
+	Owner             string          `json:"owner"`
+	RequestID         *string         `json:"request_id"`
+	Contact           string          `json:"contact"`
+	Timestamp         time.Time       `json:"timestamp"`
+	ScheduledSendTime time.Time       `json:"scheduled_send_time"`
+	Content           string          `json:"content"`
+	Encrypted         bool            `json:"encrypted"`
+	SIM               entities.SIM    `json:"sim"`
+}
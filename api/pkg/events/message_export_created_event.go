@@ -0,0 +1,19 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// MessageExportCreated is emitted when a user requests an export of their message history
+const MessageExportCreated = "message-export.created"
+
+// MessageExportCreatedPayload is the payload of the MessageExportCreated event
+type MessageExportCreatedPayload struct {
+	MessageExportID uuid.UUID       `json:"message_export_id"`
+	UserID          entities.UserID `json:"user_id"`
+	Source          string          `json:"source"`
+	Timestamp       time.Time       `json:"timestamp"`
+}
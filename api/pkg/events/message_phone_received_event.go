@@ -18,6 +18,7 @@ type MessagePhoneReceivedPayload struct {
 	Owner       string          `json:"owner"`
 	Encrypted   bool            `json:"encrypted"`
 	Contact     string          `json:"contact"`
+	ContactName *string         `json:"contact_name"`
 	Timestamp   time.Time       `json:"timestamp"`
 	Content     string          `json:"content"`
 	SIM         entities.SIM    `json:"sim"`
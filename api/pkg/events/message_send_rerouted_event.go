@@ -0,0 +1,28 @@
+package events
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+
+	"github.com/google/uuid"
+)
+
+// EventTypeMessageSendRerouted is emitted when an expired message is moved from an offline phone to a fallback phone
+const EventTypeMessageSendRerouted = "message.send.rerouted"
+
+// MessageSendReroutedPayload is the payload of the EventTypeMessageSendRerouted event
+type MessageSendReroutedPayload struct {
+	MessageID     uuid.UUID                `json:"message_id"`
+	PreviousOwner string                   `json:"previous_owner"`
+	Owner         string                   `json:"owner"`
+	Contact       string                   `json:"contact"`
+	RequestID     *string                  `json:"request_id"`
+	Encrypted     bool                     `json:"encrypted"`
+	UserID        entities.UserID          `json:"user_id"`
+	Timestamp     time.Time                `json:"timestamp"`
+	Content       string                   `json:"content"`
+	SIM           entities.SIM             `json:"sim"`
+	Encoding      entities.MessageEncoding `json:"encoding"`
+	Segments      uint                     `json:"segments"`
+}
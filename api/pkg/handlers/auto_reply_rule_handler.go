@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// AutoReplyRuleHandler handles auto reply rule requests
+type AutoReplyRuleHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.AutoReplyService
+	validator *validators.AutoReplyRuleHandlerValidator
+}
+
+// NewAutoReplyRuleHandler creates a new AutoReplyRuleHandler
+func NewAutoReplyRuleHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.AutoReplyService,
+	validator *validators.AutoReplyRuleHandlerValidator,
+) (h *AutoReplyRuleHandler) {
+	return &AutoReplyRuleHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the AutoReplyRuleHandler
+func (h *AutoReplyRuleHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/auto-reply-rules", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/auto-reply-rules", middlewares, h.Store)
+	h.register(router, fiber.MethodGet, "/v1/auto-reply-rules/:ruleID", middlewares, h.Show)
+	h.register(router, fiber.MethodPut, "/v1/auto-reply-rules/:ruleID", middlewares, h.Update)
+	h.register(router, fiber.MethodDelete, "/v1/auto-reply-rules/:ruleID", middlewares, h.Delete)
+}
+
+// Index returns the auto reply rules of a user
+// @Summary      Get auto reply rules of a user
+// @Description  Get the auto reply rules of a user
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplyRules
+// @Accept       json
+// @Produce      json
+// @Param        owner		query  string  	false	"filter rules of a phone number"	default(+18005550199)
+// @Param        skip		query  int  	false	"number of rules to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter rules containing query"
+// @Param        limit		query  int  	false	"number of rules to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.AutoReplyRulesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-reply-rules 	[get]
+func (h *AutoReplyRuleHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.AutoReplyRuleIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching auto reply rules [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching auto reply rules")
+	}
+
+	if request.Owner != "" && !h.userFromContext(c).CanUsePhoneNumber(request.Owner) {
+		return h.responsePhoneNumberForbidden(c, request.Owner, h.userFromContext(c))
+	}
+
+	rules, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get auto reply rules with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	rules = slices.DeleteFunc(rules, func(rule *entities.AutoReplyRule) bool {
+		return !h.userFromContext(c).CanUsePhoneNumber(rule.Owner)
+	})
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d auto reply %s", len(rules), h.pluralize("rule", len(rules))), rules)
+}
+
+// Show returns a single auto reply rule
+// @Summary      Get an auto reply rule
+// @Description  Get an auto reply rule of a user by ID
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplyRules
+// @Accept       json
+// @Produce      json
+// @Param 		 ruleID 	path		string 							true 	"ID of the auto reply rule"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.AutoReplyRuleResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-reply-rules/{ruleID} [get]
+func (h *AutoReplyRuleHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	ruleID := c.Params("ruleID")
+	if errors := h.validator.ValidateUUID(ruleID, "ruleID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching auto reply rule with ID [%s]", spew.Sdump(errors), ruleID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching auto reply rule")
+	}
+
+	rule, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(ruleID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply rule with ID [%s]", ruleID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load auto reply rule with ID [%s]", ruleID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumber(rule.Owner) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply rule with ID [%s]", ruleID))
+	}
+
+	return h.responseOK(c, "auto reply rule fetched successfully", rule)
+}
+
+// Store an auto reply rule
+// @Summary      Store an auto reply rule
+// @Description  Create an auto reply rule which responds to inbound messages received by a phone of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplyRules
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.AutoReplyRuleStore  		true "Payload of the auto reply rule"
+// @Success      201 		{object}	responses.AutoReplyRuleResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-reply-rules [post]
+func (h *AutoReplyRuleHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.AutoReplyRuleStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing auto reply rule [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing auto reply rule")
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumber(request.Owner) {
+		return h.responsePhoneNumberForbidden(c, request.Owner, h.userFromContext(c))
+	}
+
+	rule, err := h.service.Store(ctx, request.ToUpsertParams(h.userIDFomContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store auto reply rule with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "auto reply rule created successfully", rule)
+}
+
+// Update an auto reply rule
+// @Summary      Update an auto reply rule
+// @Description  Update an auto reply rule of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplyRules
+// @Accept       json
+// @Produce      json
+// @Param 		 ruleID 	path		string 							true 	"ID of the auto reply rule"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.AutoReplyRuleStore  		true "Payload of the auto reply rule"
+// @Success      200 		{object}	responses.AutoReplyRuleResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-reply-rules/{ruleID} [put]
+func (h *AutoReplyRuleHandler) Update(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	ruleID := c.Params("ruleID")
+	if errors := h.validator.ValidateUUID(ruleID, "ruleID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating auto reply rule with ID [%s]", spew.Sdump(errors), ruleID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating auto reply rule")
+	}
+
+	var request requests.AutoReplyRuleStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating auto reply rule [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating auto reply rule")
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumber(request.Owner) {
+		return h.responsePhoneNumberForbidden(c, request.Owner, h.userFromContext(c))
+	}
+
+	rule, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(ruleID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound || (err == nil && !h.userFromContext(c).CanUsePhoneNumber(rule.Owner)) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply rule with ID [%s]", ruleID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load auto reply rule with ID [%s]", ruleID))
+		return h.responseInternalServerError(c)
+	}
+
+	rule, err = h.service.Update(ctx, uuid.MustParse(ruleID), request.ToUpsertParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply rule with ID [%s]", ruleID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update auto reply rule with ID [%s]", ruleID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "auto reply rule updated successfully", rule)
+}
+
+// Delete an auto reply rule
+// @Summary      Delete an auto reply rule
+// @Description  Delete an auto reply rule of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         AutoReplyRules
+// @Accept       json
+// @Produce      json
+// @Param 		 ruleID 	path		string 							true 	"ID of the auto reply rule"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /auto-reply-rules/{ruleID} [delete]
+func (h *AutoReplyRuleHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	ruleID := c.Params("ruleID")
+	if errors := h.validator.ValidateUUID(ruleID, "ruleID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting auto reply rule with ID [%s]", spew.Sdump(errors), ruleID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting auto reply rule")
+	}
+
+	rule, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(ruleID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound || (err == nil && !h.userFromContext(c).CanUsePhoneNumber(rule.Owner)) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply rule with ID [%s]", ruleID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load auto reply rule with ID [%s]", ruleID))
+		return h.responseInternalServerError(c)
+	}
+
+	err = h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(ruleID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find auto reply rule with ID [%s]", ruleID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete auto reply rule with ID [%s]", ruleID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "auto reply rule deleted successfully")
+}
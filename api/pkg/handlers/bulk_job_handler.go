@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// BulkJobHandler handles requests for large bulk SMS files which are processed in the background
+type BulkJobHandler struct {
+	handler
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	validator      *validators.BulkJobHandlerValidator
+	service        *services.BulkJobService
+	billingService *services.BillingService
+}
+
+// NewBulkJobHandler creates a new BulkJobHandler
+func NewBulkJobHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.BulkJobHandlerValidator,
+	service *services.BulkJobService,
+	billingService *services.BillingService,
+) (h *BulkJobHandler) {
+	return &BulkJobHandler{
+		logger:         logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:         tracer,
+		validator:      validator,
+		service:        service,
+		billingService: billingService,
+	}
+}
+
+// RegisterRoutes registers the routes for the BulkJobHandler
+func (h *BulkJobHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodPost, "/v1/bulk-jobs", middlewares, h.Store)
+	h.register(router, fiber.MethodGet, "/v1/bulk-jobs/:bulkJobID", middlewares, h.Show)
+}
+
+// Store uploads a large bulk SMS file to be processed in the background
+// @Summary      Upload a large bulk SMS file
+// @Description  Upload a CSV or Excel file in the same format as our [CSV template](https://httpsms.com/templates/httpsms-bulk.csv) with more than 1000 rows. The rows are validated and added to the queue in batches in the background. Use the ID of the job to check the progress and the errors of each row.
+// @Security	 ApiKeyAuth
+// @Tags         BulkSMS
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        document	formData  	file   							true	"The Excel or CSV file containing the messages to be sent."
+// @Success      201 		{object}	responses.BulkJobResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      402		{object}	responses.PaymentRequired
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /bulk-jobs [post]
+func (h *BulkJobHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	file, err := c.FormFile("document")
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot fetch file with name [%s] from request", "document"))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), file); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while uploading bulk job file [%s] for [%s]", spew.Sdump(errors), file.Filename, h.userIDFomContext(c)))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while uploading bulk SMS file")
+	}
+
+	if msg := h.billingService.IsEntitled(ctx, h.userIDFomContext(c)); msg != nil {
+		ctxLogger.Warn(stacktrace.NewErrorf("user with ID [%s] is not entitled to send messages", h.userIDFomContext(c)))
+		return h.responsePaymentRequired(c, *msg)
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot open bulk job file [%s] for user [%s]", file.Filename, h.userIDFomContext(c)))
+		return h.responseInternalServerError(c)
+	}
+	defer func() {
+		if err = content.Close(); err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot close bulk job file [%s] for user [%s]", file.Filename, h.userIDFomContext(c)))
+		}
+	}()
+
+	job, err := h.service.Store(ctx, &services.BulkJobStoreParams{
+		UserID:       h.userIDFomContext(c),
+		ActorID:      h.actorIDFromContext(c),
+		PhoneNumbers: h.userFromContext(c).PhoneNumbers,
+		Filename:     file.Filename,
+		RequestID:    fmt.Sprintf("bulk-%s-%s", encodeBase62(time.Now().UnixMilli()), truncateFilename(sanitizeFilename(file.Filename), 32)),
+		Content:      content,
+		Source:       c.OriginalURL(),
+	})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store bulk job for file [%s]", file.Filename))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, fmt.Sprintf("bulk job [%s] created successfully, the rows will be processed in the background", job.ID), job)
+}
+
+// Show returns the progress of a bulk job
+// @Summary      Get a bulk job
+// @Description  Get the status of a large bulk SMS file with the number of rows which have been queued and the errors of the rows which cannot be sent. Only the first 1000 row errors are returned.
+// @Security	 ApiKeyAuth
+// @Tags         BulkSMS
+// @Accept       json
+// @Produce      json
+// @Param 		 bulkJobID 	path		string 							true 	"ID of the bulk job"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.BulkJobResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /bulk-jobs/{bulkJobID} [get]
+func (h *BulkJobHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	bulkJobID := c.Params("bulkJobID")
+	if errors := h.validator.ValidateUUID(bulkJobID, "bulkJobID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching bulk job with ID [%s]", spew.Sdump(errors), bulkJobID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching bulk job")
+	}
+
+	job, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(bulkJobID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find bulk job with ID [%s]", bulkJobID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load bulk job with ID [%s]", bulkJobID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumbers(job.PhoneNumbers) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find bulk job with ID [%s]", bulkJobID))
+	}
+
+	return h.responseOK(c, "bulk job fetched successfully", job)
+}
@@ -1,13 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/middlewares"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -20,11 +28,12 @@ import (
 // BulkMessageHandler handles bulk SMS http requests
 type BulkMessageHandler struct {
 	handler
-	logger         telemetry.Logger
-	tracer         telemetry.Tracer
-	validator      *validators.BulkMessageHandlerValidator
-	messageService *services.MessageService
-	billingService *services.BillingService
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	validator       *validators.BulkMessageHandlerValidator
+	messageService  *services.MessageService
+	billingService  *services.BillingService
+	campaignService *services.CampaignService
 }
 
 // NewBulkMessageHandler creates a new BulkMessageHandler
@@ -34,13 +43,22 @@ func NewBulkMessageHandler(
 	validator *validators.BulkMessageHandlerValidator,
 	billingService *services.BillingService,
 	messageService *services.MessageService,
+	campaignService *services.CampaignService,
 ) (h *BulkMessageHandler) {
 	return &BulkMessageHandler{
-		logger:         logger.WithService(fmt.Sprintf("%T", h)),
-		tracer:         tracer,
-		validator:      validator,
-		messageService: messageService,
-		billingService: billingService,
+		logger:          logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:          tracer,
+		validator:       validator,
+		messageService:  messageService,
+		billingService:  billingService,
+		campaignService: campaignService,
+	}
+}
+
+// RouteScopes returns the scopes of the routes of the BulkMessageHandler which do not follow the read and write scopes, validating a bulk SMS file does not send messages so it only needs the read scope
+func (h *BulkMessageHandler) RouteScopes() middlewares.RouteScopes {
+	return middlewares.RouteScopes{
+		"POST /v1/bulk-messages/validate": entities.UserAPIKeyScopeMessagesRead,
 	}
 }
 
@@ -48,6 +66,8 @@ func NewBulkMessageHandler(
 func (h *BulkMessageHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
 	h.register(router, fiber.MethodGet, "/v1/bulk-messages", middlewares, h.Index)
 	h.register(router, fiber.MethodPost, "/v1/bulk-messages", middlewares, h.Store)
+	h.register(router, fiber.MethodPost, "/v1/bulk-messages/validate", middlewares, h.Validate)
+	h.register(router, fiber.MethodGet, "/v1/bulk-messages/:requestID/export", middlewares, h.Export)
 }
 
 // Index fetches the bulk message order history.
@@ -104,17 +124,31 @@ func (h *BulkMessageHandler) Store(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, validationErrors, "validation errors while sending bulk SMS")
 	}
 
+	for _, message := range messages {
+		if !h.userFromContext(c).CanUsePhoneNumber(message.FromPhoneNumber) {
+			ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to send messages from [%s] in CSV file [%s]", h.userFromContext(c).UserAPIKeyID, message.FromPhoneNumber, file.Filename))
+			return h.responsePhoneNumberForbidden(c, message.FromPhoneNumber, h.userFromContext(c))
+		}
+	}
+
 	if msg := h.billingService.IsEntitledWithCount(ctx, h.userIDFomContext(c), uint(len(messages))); msg != nil {
 		ctxLogger.Warn(stacktrace.NewErrorf("user with ID [%s] is not entitled to send [%d] messages", h.userIDFomContext(c), len(messages)))
 		return h.responsePaymentRequired(c, *msg)
 	}
 
 	requestID := h.generateRequestID(file.Filename)
+	campaign, err := h.campaignService.Store(ctx, h.campaignParams(h.userIDFomContext(c), file.Filename, requestID, messages, userLocation))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store campaign for bulk messages in file [%s]", file.Filename))
+		return h.responseInternalServerError(c)
+	}
+
 	wg := sync.WaitGroup{}
 	count := atomic.Int64{}
 
 	// Compute per-phone index for rate-based dispatch delay
 	phoneIndexCounter := make(map[string]int)
+	actorID := h.actorIDFromContext(c)
 
 	for _, message := range messages {
 		wg.Add(1)
@@ -126,10 +160,9 @@ func (h *BulkMessageHandler) Store(c fiber.Ctx) error {
 
 		go func(message *requests.BulkMessage, index int) {
 			count.Add(1)
-			_, err = h.messageService.SendMessage(
-				ctx,
-				message.ToMessageSendParams(h.userIDFomContext(c), requestID, c.OriginalURL(), index, userLocation),
-			)
+			params := message.ToMessageSendParams(h.userIDFomContext(c), requestID, c.OriginalURL(), index, userLocation)
+			params.ActorID = actorID
+			_, err = h.messageService.SendMessage(ctx, params)
 			if err != nil {
 				count.Add(-1)
 
@@ -140,7 +173,168 @@ func (h *BulkMessageHandler) Store(c fiber.Ctx) error {
 	}
 
 	wg.Wait()
-	return h.responseAccepted(c, fmt.Sprintf("Added %d out of %d messages to the queue", count.Load(), len(messages)))
+	if _, err = h.campaignService.Start(ctx, campaign.UserID, campaign.ID); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot start campaign [%s] for bulk messages in file [%s]", campaign.ID, file.Filename))
+	}
+
+	return h.responseAccepted(c, fmt.Sprintf("Added %d out of %d messages to the queue for campaign [%s]", count.Load(), len(messages), campaign.ID))
+}
+
+// Validate checks a bulk SMS file without sending the messages.
+// @Summary      Validate bulk SMS file
+// @Description  Runs the same validation as the store endpoint on a bulk SMS file without sending any message. The report contains the normalized recipient, the send time in UTC, the number of segments and the errors of every row.
+// @Security	 ApiKeyAuth
+// @Tags         BulkSMS
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        document	formData  	file   							true	"The Excel or CSV file containing the messages to be validated."
+// @Success      200 		{object}	responses.BulkMessageReportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /bulk-messages/validate [post]
+func (h *BulkMessageHandler) Validate(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	file, err := c.FormFile("document")
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot fetch file with name [%s] from request", "document"))
+		return h.responseBadRequest(c, err)
+	}
+
+	report, validationErrors := h.validator.ValidateDryRun(ctx, h.userIDFomContext(c), file)
+	if len(validationErrors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while validating bulk sms file [%s] for [%s]", spew.Sdump(validationErrors), file.Filename, h.userIDFomContext(c)))
+		return h.responseUnprocessableEntity(c, validationErrors, "validation errors while validating bulk SMS file")
+	}
+
+	return h.responseOK(c, fmt.Sprintf("validated %d %s, %d valid and %d invalid", report.TotalRows, h.pluralize("row", report.TotalRows), report.ValidRows, report.InvalidRows), report)
+}
+
+// Export downloads the messages of a bulk SMS request.
+// @Summary      Export bulk messages
+// @Description  Download every message of a bulk SMS request as a CSV or Excel file with the recipient, status, sent, delivered and failed timestamps and the failure reason.
+// @Security	 ApiKeyAuth
+// @Tags         BulkSMS
+// @Accept       json
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param 		 requestID 	path		string 	true 	"The request ID of the bulk messages"	default(bulk-1ZkSs9M-httpsms-bulk.csv)
+// @Param        format		query  		string	false	"The format of the exported file"	Enums(csv, xlsx)	default(csv)
+// @Success      200 		{file}		file
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /bulk-messages/{requestID}/export [get]
+func (h *BulkMessageHandler) Export(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.BulkMessageExport
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	// request IDs contain the name of the uploaded file which can have spaces
+	request.RequestID = c.Params("requestID")
+	if requestID, err := url.PathUnescape(request.RequestID); err == nil {
+		request.RequestID = requestID
+	}
+	request = request.Sanitize()
+	if errors := h.validator.ValidateExport(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while exporting bulk messages [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while exporting bulk messages")
+	}
+
+	userID := h.userIDFomContext(c)
+	_, err := h.messageService.GetBulkMessage(ctx, userID, request.RequestID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find bulk messages with request ID [%s]", request.RequestID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load bulk messages with request ID [%s]", request.RequestID))
+		return h.responseInternalServerError(c)
+	}
+
+	// the export is written to a temporary file so that an error while loading the messages is returned as an error instead of a truncated file
+	file, err := os.CreateTemp("", "httpsms-bulk-export-*")
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot create temporary file to export bulk messages with request ID [%s]", request.RequestID))
+		return h.responseInternalServerError(c)
+	}
+
+	// the file is unlinked straight away and its contents are released when it is closed after the response is sent
+	if err = os.Remove(file.Name()); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot remove temporary file [%s] of bulk messages export with request ID [%s]", file.Name(), request.RequestID))
+	}
+
+	size, err := h.exportBulkMessages(ctx, file, userID, request)
+	if err != nil {
+		if e := file.Close(); e != nil {
+			ctxLogger.Warn(stacktrace.Propagatef(e, "cannot close temporary file [%s] of bulk messages export with request ID [%s]", file.Name(), request.RequestID))
+		}
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot export bulk messages with request ID [%s]", request.RequestID))
+		return h.responseInternalServerError(c)
+	}
+
+	c.Attachment(fmt.Sprintf("%s.%s", request.RequestID, request.Format))
+	c.Set(fiber.HeaderContentType, request.ExportFormat().ContentType())
+	return c.SendStream(file, int(size))
+}
+
+// exportBulkMessages writes the export to the file and rewinds it so that it can be sent, it returns the size of the export
+func (h *BulkMessageHandler) exportBulkMessages(ctx context.Context, file *os.File, userID entities.UserID, request requests.BulkMessageExport) (int64, error) {
+	if err := h.messageService.ExportBulkMessages(ctx, file, userID, request.RequestID, request.ExportFormat()); err != nil {
+		return 0, stacktrace.Propagatef(err, "cannot write bulk messages with request ID [%s] to file [%s]", request.RequestID, file.Name())
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, stacktrace.Propagatef(err, "cannot get the size of file [%s]", file.Name())
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return 0, stacktrace.Propagatef(err, "cannot rewind file [%s]", file.Name())
+	}
+
+	return size, nil
+}
+
+// campaignParams creates the parameters of the campaign which tracks the messages in a bulk file
+func (h *BulkMessageHandler) campaignParams(userID entities.UserID, filename string, requestID string, messages []*requests.BulkMessage, location *time.Location) *services.CampaignStoreParams {
+	var owners []string
+	var scheduledAt *time.Time
+	immediate := false
+	for _, message := range messages {
+		if !slices.Contains(owners, message.FromPhoneNumber) {
+			owners = append(owners, message.FromPhoneNumber)
+		}
+
+		sendAt := message.GetSendTime(location)
+		if sendAt == nil {
+			immediate = true
+		} else if scheduledAt == nil || sendAt.Before(*scheduledAt) {
+			scheduledAt = sendAt
+		}
+	}
+
+	if immediate {
+		scheduledAt = nil
+	}
+
+	return &services.CampaignStoreParams{
+		UserID:      userID,
+		Name:        filename,
+		RequestID:   requestID,
+		Owners:      owners,
+		ScheduledAt: scheduledAt,
+	}
 }
 
 func (h *BulkMessageHandler) generateRequestID(filename string) string {
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// CampaignHandler handles campaign requests
+type CampaignHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.CampaignService
+	validator *validators.CampaignHandlerValidator
+}
+
+// NewCampaignHandler creates a new CampaignHandler
+func NewCampaignHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.CampaignService,
+	validator *validators.CampaignHandlerValidator,
+) (h *CampaignHandler) {
+	return &CampaignHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the CampaignHandler
+func (h *CampaignHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/campaigns", middlewares, h.Index)
+	h.register(router, fiber.MethodGet, "/v1/campaigns/:campaignID", middlewares, h.Show)
+	h.register(router, fiber.MethodPost, "/v1/campaigns/:campaignID/pause", middlewares, h.Pause)
+	h.register(router, fiber.MethodPost, "/v1/campaigns/:campaignID/resume", middlewares, h.Resume)
+	h.register(router, fiber.MethodPost, "/v1/campaigns/:campaignID/cancel", middlewares, h.Cancel)
+}
+
+// Index returns the campaigns of a user
+// @Summary      Get campaigns of a user
+// @Description  Get the bulk message campaigns of a user with the number of messages in each status
+// @Security	 ApiKeyAuth
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of campaigns to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter campaigns containing query"
+// @Param        status		query  string  	false 	"filter campaigns by status"	Enums(draft, running, paused, completed, canceled)
+// @Param        limit		query  int  	false	"number of campaigns to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.CampaignsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /campaigns 	[get]
+func (h *CampaignHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.CampaignIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching campaigns [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching campaigns")
+	}
+
+	campaigns, err := h.service.Index(ctx, h.userIDFomContext(c), request.Status, request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get campaigns with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(campaigns), h.pluralize("campaign", len(campaigns))), campaigns)
+}
+
+// Show returns a single campaign
+// @Summary      Get a campaign
+// @Description  Get a bulk message campaign of a user by ID with the number of messages in each status
+// @Security	 ApiKeyAuth
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Param 		 campaignID 	path		string 							true 	"ID of the campaign"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.CampaignResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /campaigns/{campaignID} [get]
+func (h *CampaignHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	campaignID := c.Params("campaignID")
+	if errors := h.validator.ValidateUUID(campaignID, "campaignID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching campaign with ID [%s]", spew.Sdump(errors), campaignID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching campaign")
+	}
+
+	campaign, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(campaignID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load campaign with ID [%s]", campaignID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumbers(campaign.Owners) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	return h.responseOK(c, "campaign fetched successfully", campaign)
+}
+
+// Pause a campaign
+// @Summary      Pause a campaign
+// @Description  Stop the phones from sending the messages of a running campaign which have not yet been sent. The messages are kept with the paused status until the campaign is resumed or canceled.
+// @Security	 ApiKeyAuth
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Param 		 campaignID 	path		string 							true 	"ID of the campaign"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.CampaignResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /campaigns/{campaignID}/pause [post]
+func (h *CampaignHandler) Pause(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	campaignID := c.Params("campaignID")
+	if errors := h.validator.ValidateUUID(campaignID, "campaignID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while pausing campaign with ID [%s]", spew.Sdump(errors), campaignID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while pausing campaign")
+	}
+
+	campaign, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(campaignID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load campaign with ID [%s]", campaignID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumbers(campaign.Owners) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	if !campaign.CanBePaused() {
+		ctxLogger.Warn(stacktrace.NewErrorf("campaign with ID [%s] has status [%s] and cannot be paused", campaignID, campaign.Status))
+		return h.responseUnprocessableEntity(c, url.Values{"status": []string{fmt.Sprintf("the campaign has status [%s] and can only be paused when it is [%s]", campaign.Status, entities.CampaignStatusRunning)}}, "validation errors while pausing campaign")
+	}
+
+	campaign, err = h.service.Pause(ctx, c.OriginalURL(), campaign)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot pause campaign with ID [%s] for user with ID [%s]", campaignID, h.userIDFomContext(c)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "campaign paused successfully", campaign)
+}
+
+// Resume a campaign
+// @Summary      Resume a campaign
+// @Description  Resume a paused campaign. The paused messages are spread out again in the background according to the messages per minute setting of each phone.
+// @Security	 ApiKeyAuth
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Param 		 campaignID 	path		string 							true 	"ID of the campaign"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.CampaignResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /campaigns/{campaignID}/resume [post]
+func (h *CampaignHandler) Resume(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	campaignID := c.Params("campaignID")
+	if errors := h.validator.ValidateUUID(campaignID, "campaignID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while resuming campaign with ID [%s]", spew.Sdump(errors), campaignID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while resuming campaign")
+	}
+
+	campaign, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(campaignID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load campaign with ID [%s]", campaignID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumbers(campaign.Owners) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	if !campaign.CanBeResumed() {
+		ctxLogger.Warn(stacktrace.NewErrorf("campaign with ID [%s] has status [%s] and cannot be resumed", campaignID, campaign.Status))
+		return h.responseUnprocessableEntity(c, url.Values{"status": []string{fmt.Sprintf("the campaign has status [%s] and can only be resumed when it is [%s]", campaign.Status, entities.CampaignStatusPaused)}}, "validation errors while resuming campaign")
+	}
+
+	campaign, err = h.service.Resume(ctx, c.OriginalURL(), campaign)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot resume campaign with ID [%s] for user with ID [%s]", campaignID, h.userIDFomContext(c)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "campaign resumed successfully", campaign)
+}
+
+// Cancel a campaign
+// @Summary      Cancel a campaign
+// @Description  Cancel the messages of a campaign which have not yet been sent by the phones. The messages are put on hold immediately and canceled in the background.
+// @Security	 ApiKeyAuth
+// @Tags         Campaigns
+// @Accept       json
+// @Produce      json
+// @Param 		 campaignID 	path		string 							true 	"ID of the campaign"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.CampaignResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /campaigns/{campaignID}/cancel [post]
+func (h *CampaignHandler) Cancel(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	campaignID := c.Params("campaignID")
+	if errors := h.validator.ValidateUUID(campaignID, "campaignID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while canceling campaign with ID [%s]", spew.Sdump(errors), campaignID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while canceling campaign")
+	}
+
+	campaign, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(campaignID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load campaign with ID [%s]", campaignID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumbers(campaign.Owners) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find campaign with ID [%s]", campaignID))
+	}
+
+	if !campaign.CanBeCanceled() {
+		ctxLogger.Warn(stacktrace.NewErrorf("campaign with ID [%s] has status [%s] and cannot be canceled", campaignID, campaign.Status))
+		return h.responseUnprocessableEntity(c, url.Values{"status": []string{fmt.Sprintf("the campaign has status [%s] and can only be canceled when it is [%s], [%s] or [%s]", campaign.Status, entities.CampaignStatusDraft, entities.CampaignStatusRunning, entities.CampaignStatusPaused)}}, "validation errors while canceling campaign")
+	}
+
+	campaign, err = h.service.Cancel(ctx, c.OriginalURL(), campaign)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot cancel campaign with ID [%s] for user with ID [%s]", campaignID, h.userIDFomContext(c)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "campaign canceled successfully", campaign)
+}
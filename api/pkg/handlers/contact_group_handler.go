@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/middlewares"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// ContactGroupHandler handles contact group requests
+type ContactGroupHandler struct {
+	handler
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	service         *services.ContactGroupService
+	validator       *validators.ContactGroupHandlerValidator
+	messageService  *services.MessageService
+	billingService  *services.BillingService
+	campaignService *services.CampaignService
+}
+
+// NewContactGroupHandler creates a new ContactGroupHandler
+func NewContactGroupHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ContactGroupService,
+	validator *validators.ContactGroupHandlerValidator,
+	messageService *services.MessageService,
+	billingService *services.BillingService,
+	campaignService *services.CampaignService,
+) (h *ContactGroupHandler) {
+	return &ContactGroupHandler{
+		logger:          logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:          tracer,
+		service:         service,
+		validator:       validator,
+		messageService:  messageService,
+		billingService:  billingService,
+		campaignService: campaignService,
+	}
+}
+
+// RouteScopes returns the scopes of the routes of the ContactGroupHandler which do not follow the read and write scopes, sending a message to a group needs the send scope
+func (h *ContactGroupHandler) RouteScopes() middlewares.RouteScopes {
+	return middlewares.RouteScopes{
+		"POST /v1/groups/:groupID/send": entities.UserAPIKeyScopeMessagesSend,
+	}
+}
+
+// RegisterRoutes registers the routes for the ContactGroupHandler
+func (h *ContactGroupHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/groups", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/groups", middlewares, h.Store)
+	h.register(router, fiber.MethodGet, "/v1/groups/:groupID", middlewares, h.Show)
+	h.register(router, fiber.MethodPut, "/v1/groups/:groupID", middlewares, h.Update)
+	h.register(router, fiber.MethodDelete, "/v1/groups/:groupID", middlewares, h.Delete)
+	h.register(router, fiber.MethodPost, "/v1/groups/:groupID/send", middlewares, h.Send)
+}
+
+// Index returns the contact groups of a user
+// @Summary      Get contact groups of a user
+// @Description  Get the contact groups of a user
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of groups to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter groups containing query"
+// @Param        limit		query  int  	false	"number of groups to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.ContactGroupsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /groups 	[get]
+func (h *ContactGroupHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactGroupIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching contact groups [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact groups")
+	}
+
+	groups, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get contact groups with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d contact %s", len(groups), h.pluralize("group", len(groups))), groups)
+}
+
+// Show returns a single contact group
+// @Summary      Get a contact group
+// @Description  Get a contact group of a user by ID
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 groupID 	path		string 							true 	"ID of the contact group"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /groups/{groupID} [get]
+func (h *ContactGroupHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	groupID := c.Params("groupID")
+	if errors := h.validator.ValidateUUID(groupID, "groupID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching contact group with ID [%s]", spew.Sdump(errors), groupID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact group")
+	}
+
+	group, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(groupID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", groupID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load contact group with ID [%s]", groupID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact group fetched successfully", group)
+}
+
+// Store a contact group
+// @Summary      Store a contact group
+// @Description  Save a named group of recipients with either a static list of phone numbers or a dynamic filter over the message history of a phone
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.ContactGroupStore  		true "Payload of the contact group"
+// @Success      201 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /groups [post]
+func (h *ContactGroupHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactGroupStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing contact group [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing contact group")
+	}
+
+	group, err := h.service.Store(ctx, request.ToUpsertParams(h.userIDFomContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store contact group with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "contact group created successfully", group)
+}
+
+// Update a contact group
+// @Summary      Update a contact group
+// @Description  Update a contact group of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 groupID 	path		string 							true 	"ID of the contact group"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.ContactGroupStore  		true "Payload of the contact group"
+// @Success      200 		{object}	responses.ContactGroupResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /groups/{groupID} [put]
+func (h *ContactGroupHandler) Update(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	groupID := c.Params("groupID")
+	if errors := h.validator.ValidateUUID(groupID, "groupID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating contact group with ID [%s]", spew.Sdump(errors), groupID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating contact group")
+	}
+
+	var request requests.ContactGroupStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating contact group [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating contact group")
+	}
+
+	group, err := h.service.Update(ctx, uuid.MustParse(groupID), request.ToUpsertParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", groupID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update contact group with ID [%s]", groupID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact group updated successfully", group)
+}
+
+// Delete a contact group
+// @Summary      Delete a contact group
+// @Description  Delete a contact group of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 groupID 	path		string 							true 	"ID of the contact group"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /groups/{groupID} [delete]
+func (h *ContactGroupHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	groupID := c.Params("groupID")
+	if errors := h.validator.ValidateUUID(groupID, "groupID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting contact group with ID [%s]", spew.Sdump(errors), groupID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting contact group")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(groupID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", groupID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete contact group with ID [%s]", groupID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "contact group deleted successfully")
+}
+
+// Send a message to a contact group
+// @Summary      Send a message to a contact group
+// @Description  Send a message to every member of a contact group. Contacts who opted out are skipped, the messages are dispatched at the rate of the sending phone and the order is listed as a single bulk message.
+// @Security	 ApiKeyAuth
+// @Tags         ContactGroups
+// @Accept       json
+// @Produce      json
+// @Param 		 groupID 	path		string 							true 	"ID of the contact group"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.ContactGroupSend  		true "Payload of the message"
+// @Success      202 		{object}	responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      402		{object}	responses.PaymentRequired
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /groups/{groupID}/send [post]
+func (h *ContactGroupHandler) Send(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	groupID := c.Params("groupID")
+	if errors := h.validator.ValidateUUID(groupID, "groupID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while sending message to contact group with ID [%s]", spew.Sdump(errors), groupID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while sending message to contact group")
+	}
+
+	var request requests.ContactGroupSend
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateSend(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while sending message to contact group [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while sending message to contact group")
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumber(request.From) {
+		ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to send messages from [%s]", h.userFromContext(c).UserAPIKeyID, request.From))
+		return h.responsePhoneNumberForbidden(c, request.From, h.userFromContext(c))
+	}
+
+	group, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(groupID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact group with ID [%s]", groupID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load contact group with ID [%s]", groupID))
+		return h.responseInternalServerError(c)
+	}
+
+	recipients, err := h.service.Recipients(ctx, group, request.From)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot fetch recipients of contact group with ID [%s]", groupID))
+		return h.responseInternalServerError(c)
+	}
+
+	if len(recipients) == 0 || len(recipients) > services.MaxContactGroupRecipients {
+		errors := url.Values{}
+		errors.Add("groupID", fmt.Sprintf("the contact group [%s] must have between 1 and %d recipients but it has [%d]", group.Name, services.MaxContactGroupRecipients, len(recipients)))
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while sending message to contact group [%s]", spew.Sdump(errors), groupID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while sending message to contact group")
+	}
+
+	if msg := h.billingService.IsEntitledWithCount(ctx, h.userIDFomContext(c), uint(len(recipients))); msg != nil {
+		ctxLogger.Warn(stacktrace.NewErrorf("user with ID [%s] is not entitled to send [%d] messages", h.userIDFomContext(c), len(recipients)))
+		return h.responsePaymentRequired(c, *msg)
+	}
+
+	requestID := fmt.Sprintf("bulk-%s-group-%s", encodeBase62(time.Now().UnixMilli()), truncateFilename(sanitizeFilename(group.Name), 32))
+	campaign, err := h.campaignService.Store(ctx, &services.CampaignStoreParams{
+		UserID:      h.userIDFomContext(c),
+		Name:        group.Name,
+		RequestID:   requestID,
+		Owners:      []string{request.From},
+		ScheduledAt: request.SendAt,
+	})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store campaign for contact group [%s]", groupID))
+		return h.responseInternalServerError(c)
+	}
+
+	wg := sync.WaitGroup{}
+	count := atomic.Int64{}
+
+	// the index spreads out the messages at the sending rate of the phone in the same way as a bulk upload
+	actorID := h.actorIDFromContext(c)
+	for index, recipient := range recipients {
+		wg.Add(1)
+		go func(recipient string, index int) {
+			defer wg.Done()
+			params := request.ToMessageSendParams(h.userIDFomContext(c), requestID, c.OriginalURL(), recipient, index)
+			params.ActorID = actorID
+			if _, err := h.messageService.SendMessage(ctx, params); err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot send message to [%s] of contact group [%s] at index [%d]", recipient, groupID, index))
+				return
+			}
+			count.Add(1)
+		}(recipient, index)
+	}
+
+	wg.Wait()
+	if _, err = h.campaignService.Start(ctx, campaign.UserID, campaign.ID); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot start campaign [%s] for contact group [%s]", campaign.ID, groupID))
+	}
+
+	return h.responseAccepted(c, fmt.Sprintf("Added %d out of %d messages to the queue for campaign [%s]", count.Load(), len(recipients), campaign.ID))
+}
@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// ContactHandler handles contact requests
+type ContactHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.ContactService
+	validator *validators.ContactHandlerValidator
+}
+
+// NewContactHandler creates a new ContactHandler
+func NewContactHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ContactService,
+	validator *validators.ContactHandlerValidator,
+) (h *ContactHandler) {
+	return &ContactHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the ContactHandler
+func (h *ContactHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/contacts", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/contacts", middlewares, h.Store)
+	h.register(router, fiber.MethodPost, "/v1/contacts/import", middlewares, h.Import)
+	h.register(router, fiber.MethodGet, "/v1/contacts/:contactID", middlewares, h.Show)
+	h.register(router, fiber.MethodPut, "/v1/contacts/:contactID", middlewares, h.Update)
+	h.register(router, fiber.MethodDelete, "/v1/contacts/:contactID", middlewares, h.Delete)
+}
+
+// Index returns the contacts of a user
+// @Summary      Get contacts of a user
+// @Description  Get the contacts in the address book of a user. The query searches the name, phone number, tags and attributes of the contacts.
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param        tag		query  string  	false	"filter contacts with a tag"
+// @Param        skip		query  int  	false	"number of contacts to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter contacts containing query"
+// @Param        limit		query  int  	false	"number of contacts to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.ContactsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts 	[get]
+func (h *ContactHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching contacts [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contacts")
+	}
+
+	params := request.ToIndexParams()
+	params.OrganisationID = h.userFromContext(c).OrganisationID
+	contacts, err := h.service.Index(ctx, h.userIDFomContext(c), request.Tag, params)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get contacts with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(contacts), h.pluralize("contact", len(contacts))), contacts)
+}
+
+// Show returns a single contact
+// @Summary      Get a contact
+// @Description  Get a contact of a user by ID
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param 		 contactID 	path		string 							true 	"ID of the contact"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.ContactResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contactID} [get]
+func (h *ContactHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contactID := c.Params("contactID")
+	if errors := h.validator.ValidateUUID(contactID, "contactID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching contact with ID [%s]", spew.Sdump(errors), contactID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching contact")
+	}
+
+	contact, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(contactID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact with ID [%s]", contactID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load contact with ID [%s]", contactID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact fetched successfully", contact)
+}
+
+// Store a contact
+// @Summary      Store a contact
+// @Description  Add a contact with a name, tags and custom attributes to the address book of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.ContactStore  		true "Payload of the contact"
+// @Success      201 		{object}	responses.ContactResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts [post]
+func (h *ContactHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.ContactStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), nil, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing contact [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing contact")
+	}
+
+	params := request.ToUpsertParams(h.userIDFomContext(c))
+	params.ActorID = h.actorIDFromContext(c)
+
+	contact, err := h.service.Store(ctx, params)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store contact with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "contact created successfully", contact)
+}
+
+// Update a contact
+// @Summary      Update a contact
+// @Description  Update a contact of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param 		 contactID 	path		string 							true 	"ID of the contact"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.ContactStore  		true "Payload of the contact"
+// @Success      200 		{object}	responses.ContactResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contactID} [put]
+func (h *ContactHandler) Update(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contactID := c.Params("contactID")
+	if errors := h.validator.ValidateUUID(contactID, "contactID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating contact with ID [%s]", spew.Sdump(errors), contactID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating contact")
+	}
+
+	var request requests.ContactStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	id := uuid.MustParse(contactID)
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), &id, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating contact [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating contact")
+	}
+
+	params := request.ToUpsertParams(h.userIDFomContext(c))
+	params.ActorID = h.actorIDFromContext(c)
+
+	contact, err := h.service.Update(ctx, id, params)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact with ID [%s]", contactID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update contact with ID [%s]", contactID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "contact updated successfully", contact)
+}
+
+// Delete a contact
+// @Summary      Delete a contact
+// @Description  Delete a contact of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       json
+// @Produce      json
+// @Param 		 contactID 	path		string 							true 	"ID of the contact"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/{contactID} [delete]
+func (h *ContactHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	contactID := c.Params("contactID")
+	if errors := h.validator.ValidateUUID(contactID, "contactID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting contact with ID [%s]", spew.Sdump(errors), contactID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting contact")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(contactID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find contact with ID [%s]", contactID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete contact with ID [%s]", contactID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "contact deleted successfully")
+}
+
+// Import contacts from a CSV file
+// @Summary      Import contacts from a CSV file
+// @Description  Create or update contacts from a CSV file with the PhoneNumber, Name and Tags(optional) columns. Tags are comma separated and any other column is saved as an attribute of the contact. An existing contact with the same phone number is overwritten.
+// @Security	 ApiKeyAuth
+// @Tags         Contacts
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        document	formData  	file   							true	"The CSV file containing the contacts"
+// @Success      200 		{object}	responses.ContactImportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /contacts/import [post]
+func (h *ContactHandler) Import(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	file, err := c.FormFile("document")
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot fetch file with name [%s] from request", "document"))
+		return h.responseBadRequest(c, err)
+	}
+
+	contacts, errors := h.validator.ValidateImport(ctx, h.userIDFomContext(c), file)
+	if len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while importing contacts from file [%s] for [%s]", spew.Sdump(errors), file.Filename, h.userIDFomContext(c)))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while importing contacts")
+	}
+
+	params := make([]*services.ContactUpsertParams, 0, len(contacts))
+	for _, contact := range contacts {
+		item := contact.ToUpsertParams(h.userIDFomContext(c))
+		item.ActorID = h.actorIDFromContext(c)
+		params = append(params, item)
+	}
+
+	count, err := h.service.Import(ctx, h.userIDFomContext(c), params)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot import [%d] contacts from file [%s]", len(params), file.Filename))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("imported %d %s successfully", count, h.pluralize("contact", count)), fiber.Map{"count": count})
+}
@@ -59,11 +59,12 @@ func (h *DiscordHandler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Han
 	router := app.Group("discord")
 	h.register(router, fiber.MethodPost, "/event", middlewares, h.Event)
 
+	authMiddlewares := append([]fiber.Handler{authMiddleware}, middlewares...)
 	authRouter := app.Group("v1/discord-integrations")
-	h.register(authRouter, fiber.MethodPost, "/", append(middlewares, authMiddleware), h.Store)
-	h.register(authRouter, fiber.MethodGet, "/", append(middlewares, authMiddleware), h.Index)
-	h.register(authRouter, fiber.MethodDelete, "/:discordID", append(middlewares, authMiddleware), h.Delete)
-	h.register(authRouter, fiber.MethodPut, "/:discordID", append(middlewares, authMiddleware), h.Update)
+	h.register(authRouter, fiber.MethodPost, "/", authMiddlewares, h.Store)
+	h.register(authRouter, fiber.MethodGet, "/", authMiddlewares, h.Index)
+	h.register(authRouter, fiber.MethodDelete, "/:discordID", authMiddlewares, h.Delete)
+	h.register(authRouter, fiber.MethodPut, "/:discordID", authMiddlewares, h.Update)
 }
 
 // Index returns the discord integrations of a user
@@ -328,7 +329,8 @@ func (h *DiscordHandler) sendSMS(ctx context.Context, c fiber.Ctx, payload map[s
 		},
 	}
 
-	if errors := h.messageValidator.ValidateMessageSend(ctx, discord.UserID, request.Sanitize()); len(errors) != 0 {
+	request.Sanitize()
+	if errors := h.messageValidator.ValidateMessageSend(ctx, discord.UserID, request); len(errors) != 0 {
 		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while sending payload [%s]", spew.Sdump(errors), c.Body()))
 
 		var embeds []fiber.Map
@@ -46,6 +46,22 @@ func (h *handler) responsePhoneAPIKeyUnauthorized(c fiber.Ctx, owner string, aut
 	})
 }
 
+func (h *handler) responsePhoneNumberForbidden(c fiber.Ctx, owner string, authCtx entities.AuthContext) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  "error",
+		"message": "You are not authorized to carry out the request for this phone number",
+		"data":    fmt.Sprintf("The API key does not have permission to use the phone number [%s]. The API key is only configured for these phone numbers [%s]", owner, strings.Join(authCtx.PhoneNumbers, ",")),
+	})
+}
+
+func (h *handler) responseScopeForbidden(c fiber.Ctx, scope entities.UserAPIKeyScope) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  "error",
+		"message": fiber.ErrForbidden.Message,
+		"data":    fmt.Sprintf("The API key does not have the [%s] scope which is required for this request", scope),
+	})
+}
+
 func (h *handler) responseForbidden(c fiber.Ctx) error {
 	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 		"status":  "error",
@@ -97,6 +113,16 @@ func (h *handler) responseOK(c fiber.Ctx, message string, data interface{}) erro
 	})
 }
 
+func (h *handler) responseOKWithCursors(c fiber.Ctx, message string, data interface{}, next *string, prev *string) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"status":      "success",
+		"message":     message,
+		"data":        data,
+		"next_cursor": next,
+		"prev_cursor": prev,
+	})
+}
+
 func (h *handler) responseCreated(c fiber.Ctx, message string, data interface{}) error {
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"status":  "success",
@@ -123,6 +149,11 @@ func (h *handler) userIDFomContext(c fiber.Ctx) entities.UserID {
 	return h.userFromContext(c).ID
 }
 
+// actorIDFromContext returns the ID of the member who made the request in an organisation, it is nil outside an organisation
+func (h *handler) actorIDFromContext(c fiber.Ctx) *entities.UserID {
+	return h.userFromContext(c).OrganisationActorID()
+}
+
 func (h *handler) register(router fiber.Router, method, path string, middlewares []fiber.Handler, route fiber.Handler) {
 	handlers := make([]any, 0, len(middlewares)+1)
 	for _, middleware := range middlewares {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -60,6 +61,7 @@ func (h *HeartbeatHandler) RegisterPhoneAPIKeyRoutes(router fiber.Router, middle
 // @Param        skip		query  int  	false	"number of heartbeats to skip"		minimum(0)
 // @Param        query		query  string  	false 	"filter containing query"
 // @Param        limit		query  int  	false	"number of heartbeats to return"	minimum(1)	maximum(20)
+// @Param        cursor		query  string  	false	"next_cursor or prev_cursor of a previous response. It is faster than skip for deep pages"
 // @Success      200 		{object}	responses.HeartbeatsResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401	    {object}	responses.Unauthorized
@@ -83,13 +85,25 @@ func (h *HeartbeatHandler) Index(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching heartbeats")
 	}
 
-	heartbeats, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, request.ToIndexParams())
+	if !h.userFromContext(c).CanUsePhoneNumber(request.Owner) {
+		ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to fetch heartbeats of [%s]", h.userFromContext(c).UserAPIKeyID, request.Owner))
+		return h.responsePhoneNumberForbidden(c, request.Owner, h.userFromContext(c))
+	}
+
+	params := request.ToIndexParams()
+	heartbeats, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, params)
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get messgaes with params [%+#v]", request))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(*heartbeats), h.pluralize("heartbeat", len(*heartbeats))), heartbeats)
+	positions := make([]repositories.Cursor, 0, len(*heartbeats))
+	for _, heartbeat := range *heartbeats {
+		positions = append(positions, repositories.Cursor{Timestamp: heartbeat.Timestamp, ID: heartbeat.ID})
+	}
+
+	next, prev := repositories.PageCursors(params, positions)
+	return h.responseOKWithCursors(c, fmt.Sprintf("fetched %d %s", len(*heartbeats), h.pluralize("heartbeat", len(*heartbeats))), heartbeats, next, prev)
 }
 
 // Store the heartbeat of a phone number
@@ -128,6 +142,10 @@ func (h *HeartbeatHandler) Store(c fiber.Ctx) error {
 			ctxLogger.Warn(stacktrace.NewErrorf("phone API Key ID [%s] is not authorized to store heartbeat for phone number [%s]", h.userFromContext(c).PhoneAPIKeyID, phoneNumber))
 			return h.responsePhoneAPIKeyUnauthorized(c, phoneNumber, h.userFromContext(c))
 		}
+		if !h.userFromContext(c).CanUsePhoneNumber(phoneNumber) {
+			ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to store heartbeat for phone number [%s]", h.userFromContext(c).UserAPIKeyID, phoneNumber))
+			return h.responsePhoneNumberForbidden(c, phoneNumber, h.userFromContext(c))
+		}
 	}
 
 	params := request.ToStoreParams(h.userFromContext(c), c.OriginalURL(), c.Get("X-Client-Version"))
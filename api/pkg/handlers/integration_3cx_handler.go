@@ -72,7 +72,10 @@ func (h *Integration3CXHandler) Messages(c fiber.Ctx) error {
 	}
 
 	request.Sanitize()
-	message, err := h.messageService.SendMessage(ctx, request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL()))
+	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
+	params.ActorID = h.actorIDFromContext(c)
+
+	message, err := h.messageService.SendMessage(ctx, params)
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot send [3cx] message with payload [%s]", c.Body()))
 		return h.responseInternalServerError(c)
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// MessageExportHandler handles requests for exporting the message history of a user
+type MessageExportHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.MessageExportHandlerValidator
+	service   *services.MessageExportService
+}
+
+// NewMessageExportHandler creates a new MessageExportHandler
+func NewMessageExportHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.MessageExportHandlerValidator,
+	service *services.MessageExportService,
+) (h *MessageExportHandler) {
+	return &MessageExportHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the MessageExportHandler.
+// The download route is not authenticated because the link is sent by email and contains a secret token.
+func (h *MessageExportHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodPost, "/v1/message-exports", middlewares, h.Store)
+	h.register(router, fiber.MethodGet, "/v1/message-exports/:exportID", middlewares, h.Show)
+	router.Get("/v1/message-exports/:exportID/download", h.Download)
+}
+
+// Store creates an export of the message history
+// @Summary      Export messages
+// @Description  Export the messages of the authenticated user as a CSV, newline delimited JSON or Excel file. The messages can be filtered by phone numbers, contacts, types, statuses and a time range. Small exports are completed immediately, large exports are processed in the background and the download link is sent by email. The download link expires after 7 days.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.MessageExportStore  		true "Filters and format of the export"
+// @Success      201 		{object}	responses.MessageExportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-exports [post]
+func (h *MessageExportHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageExportStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing message export [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while exporting messages")
+	}
+
+	if authUser := h.userFromContext(c); authUser.UserAPIKeyID != nil && len(request.Owners) == 0 {
+		request.Owners = authUser.PhoneNumbers
+	}
+	for _, owner := range request.Owners {
+		if !h.userFromContext(c).CanUsePhoneNumber(owner) {
+			return h.responsePhoneNumberForbidden(c, owner, h.userFromContext(c))
+		}
+	}
+
+	export, err := h.service.Store(ctx, request.ToStoreParams(h.userIDFomContext(c), c.OriginalURL()))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store message export with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	if export.IsPending() {
+		return h.responseCreated(c, fmt.Sprintf("message export [%s] created successfully, the download link will be sent by email when the file is ready", export.ID), export)
+	}
+
+	return h.responseCreated(c, "message export created successfully", export)
+}
+
+// Show returns the status of a message export
+// @Summary      Get a message export
+// @Description  Get the status of a message export with the download link when the file is ready
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 exportID 	path		string 							true 	"ID of the message export"	default(32343a19-da5e-4b1b-a767-3298a73703cb)
+// @Success      200 		{object}	responses.MessageExportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-exports/{exportID} [get]
+func (h *MessageExportHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	exportID := c.Params("exportID")
+	if errors := h.validator.ValidateUUID(exportID, "exportID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching message export with ID [%s]", spew.Sdump(errors), exportID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message export")
+	}
+
+	export, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(exportID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message export with ID [%s]", exportID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load message export with ID [%s]", exportID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumbers(export.Owners) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message export with ID [%s]", exportID))
+	}
+
+	return h.responseOK(c, "message export fetched successfully", export)
+}
+
+// Download returns the file of a message export
+// @Summary      Download a message export
+// @Description  Download the file of a message export using the link which expires after 7 days
+// @Tags         Messages
+// @Produce      application/octet-stream
+// @Param 		 exportID 	path		string 		true 	"ID of the message export"	default(32343a19-da5e-4b1b-a767-3298a73703cb)
+// @Param 		 token 		query		string 		true 	"Token of the download link"
+// @Success      200  		{file}  	binary
+// @Failure      404		{object}	responses.NotFound
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-exports/{exportID}/download [get]
+func (h *MessageExportHandler) Download(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	exportID, err := uuid.Parse(c.Params("exportID"))
+	if err != nil || c.Query("token") == "" {
+		return h.responseNotFound(c, "the download link is invalid or has expired")
+	}
+
+	export, content, err := h.service.Download(ctx, exportID, c.Query("token"))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot download message export with ID [%s]", exportID))
+		return h.responseNotFound(c, "the download link is invalid or has expired")
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot download message export with ID [%s]", exportID))
+		return h.responseInternalServerError(c)
+	}
+
+	c.Attachment(export.Filename())
+	c.Set(fiber.HeaderContentType, export.Format.ContentType())
+	c.Set("X-Content-Type-Options", "nosniff")
+
+	// the file is streamed from the storage and the reader is closed after the response is sent
+	return c.SendStream(content)
+}
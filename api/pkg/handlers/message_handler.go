@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
+	"github.com/NdoleStudio/httpsms/pkg/middlewares"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/google/uuid"
 
@@ -24,11 +27,13 @@ import (
 // MessageHandler handles message http requests.
 type MessageHandler struct {
 	handler
-	logger         telemetry.Logger
-	tracer         telemetry.Tracer
-	billingService *services.BillingService
-	validator      *validators.MessageHandlerValidator
-	service        *services.MessageService
+	logger          telemetry.Logger
+	tracer          telemetry.Tracer
+	billingService  *services.BillingService
+	validator       *validators.MessageHandlerValidator
+	service         *services.MessageService
+	poolService     *services.PhonePoolService
+	templateService *services.MessageTemplateService
 }
 
 // NewMessageHandler creates a new MessageHandler
@@ -38,24 +43,42 @@ func NewMessageHandler(
 	validator *validators.MessageHandlerValidator,
 	billingService *services.BillingService,
 	service *services.MessageService,
+	poolService *services.PhonePoolService,
+	templateService *services.MessageTemplateService,
 ) (h *MessageHandler) {
 	return &MessageHandler{
-		logger:         logger.WithService(fmt.Sprintf("%T", h)),
-		tracer:         tracer,
-		validator:      validator,
-		billingService: billingService,
-		service:        service,
+		logger:          logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:          tracer,
+		validator:       validator,
+		billingService:  billingService,
+		service:         service,
+		poolService:     poolService,
+		templateService: templateService,
+	}
+}
+
+// RouteScopes returns the scopes of the routes of the MessageHandler which do not follow the read and write scopes, calculating the segments of a message only needs the read scope and deleting messages needs the delete scope
+func (h *MessageHandler) RouteScopes() middlewares.RouteScopes {
+	return middlewares.RouteScopes{
+		"POST /v1/messages/segments":     entities.UserAPIKeyScopeMessagesRead,
+		"DELETE /v1/messages/:messageID": entities.UserAPIKeyScopeMessagesDelete,
 	}
 }
 
 // RegisterRoutes registers the routes for the MessageHandler
-func (h *MessageHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
-	h.register(router, fiber.MethodPost, "/v1/messages/send", middlewares, h.PostSend)
-	h.register(router, fiber.MethodPost, "/v1/messages/bulk-send", middlewares, h.BulkSend)
+func (h *MessageHandler) RegisterRoutes(router fiber.Router, idempotency fiber.Handler, middlewares ...fiber.Handler) {
+	sendMiddlewares := append(slices.Clone(middlewares), idempotency)
+	h.register(router, fiber.MethodPost, "/v1/messages/send", sendMiddlewares, h.PostSend)
+	h.register(router, fiber.MethodPost, "/v1/messages/bulk-send", sendMiddlewares, h.BulkSend)
+	h.register(router, fiber.MethodPost, "/v1/messages/segments", middlewares, h.PostSegments)
+	h.register(router, fiber.MethodPost, "/v1/messages/import", middlewares, h.Import)
 	h.register(router, fiber.MethodGet, "/v1/messages", middlewares, h.Index)
 	h.register(router, fiber.MethodGet, "/v1/messages/search", middlewares, h.Search)
+	h.register(router, fiber.MethodGet, "/v1/analytics/messages", middlewares, h.Analytics)
 	h.register(router, fiber.MethodGet, "/v1/messages/:messageID", middlewares, h.Get)
 	h.register(router, fiber.MethodDelete, "/v1/messages/:messageID", middlewares, h.Delete)
+	h.register(router, fiber.MethodPost, "/v1/messages/:messageID/cancel", middlewares, h.Cancel)
+	h.register(router, fiber.MethodPut, "/v1/messages/:messageID/schedule", middlewares, h.Reschedule)
 }
 
 // RegisterPhoneAPIKeyRoutes registers the routes for the MessageHandler
@@ -68,15 +91,17 @@ func (h *MessageHandler) RegisterPhoneAPIKeyRoutes(router fiber.Router, middlewa
 
 // PostSend a new entities.Message
 // @Summary      Send an SMS message
-// @Description  Add a new SMS message to be sent by your Android phone
+// @Description  Add a new SMS message to be sent by your Android phone. Set the pool_id instead of the from number to let httpSMS pick the sending phone from a phone pool
 // @Security	 ApiKeyAuth
 // @Tags         Messages
 // @Accept       json
 // @Produce      json
+// @Param        Idempotency-Key	header	string	false	"Unique key used to safely retry the request within 24 hours"
 // @Param        payload   body requests.MessageSend  true  "Send message request payload"
 // @Success      200  {object}  responses.MessageResponse
 // @Failure      400  {object}  responses.BadRequest
 // @Failure 	 401  {object}	responses.Unauthorized
+// @Failure      409  {object}  responses.Conflict
 // @Failure      422  {object}  responses.UnprocessableEntity
 // @Failure      500  {object}  responses.InternalServerError
 // @Router       /messages/send [post]
@@ -92,17 +117,49 @@ func (h *MessageHandler) PostSend(c fiber.Ctx) error {
 		return h.responseBadRequest(c, err)
 	}
 
-	if errors := h.validator.ValidateMessageSend(ctx, h.userIDFomContext(c), request.Sanitize()); len(errors) != 0 {
+	request.Sanitize()
+	if errors := h.validator.ValidateMessageSend(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
 		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while sending payload [%s]", spew.Sdump(errors), c.Body()))
 		return h.responseUnprocessableEntity(c, errors, "validation errors while sending message")
 	}
 
+	if request.TemplateID != "" {
+		content, err := h.templateService.Render(ctx, h.userIDFomContext(c), uuid.MustParse(request.TemplateID), request.Variables)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot render message template [%s] with payload [%s]", request.TemplateID, c.Body()))
+			return h.responseInternalServerError(c)
+		}
+		request.Content = content
+	}
+
 	if msg := h.billingService.IsEntitled(ctx, h.userIDFomContext(c)); msg != nil {
 		ctxLogger.Warn(stacktrace.NewErrorf("user with ID [%s] can't send a message", h.userIDFomContext(c)))
 		return h.responsePaymentRequired(c, *msg)
 	}
 
-	message, err := h.service.SendMessage(ctx, request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL()))
+	if request.PoolID != "" {
+		phone, err := h.poolService.SelectPhone(ctx, h.userFromContext(c), uuid.MustParse(request.PoolID), request.To)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			ctxLogger.Warn(stacktrace.Propagatef(err, "no phone in pool [%s] can send the message with payload [%s]", request.PoolID, c.Body()))
+			return h.responseUnprocessableEntity(c, url.Values{"pool_id": []string{fmt.Sprintf("the phone pool [%s] has no phones which can send messages to [%s] with your API key", request.PoolID, request.To)}}, "validation errors while sending message")
+		}
+
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot select phone from pool [%s] with payload [%s]", request.PoolID, c.Body()))
+			return h.responseInternalServerError(c)
+		}
+		request.From = phone.PhoneNumber
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumber(request.From) {
+		ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to send messages from [%s]", h.userFromContext(c).UserAPIKeyID, request.From))
+		return h.responsePhoneNumberForbidden(c, request.From, h.userFromContext(c))
+	}
+
+	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
+	params.ActorID = h.actorIDFromContext(c)
+
+	message, err := h.service.SendMessage(ctx, params)
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot send message with paylod [%s]", c.Body()))
 		return h.responseInternalServerError(c)
@@ -118,10 +175,12 @@ func (h *MessageHandler) PostSend(c fiber.Ctx) error {
 // @Tags         Messages
 // @Accept       json
 // @Produce      json
+// @Param        Idempotency-Key	header	string	false	"Unique key used to safely retry the request within 24 hours"
 // @Param        payload   body requests.MessageBulkSend  true  "Bulk send message request payload"
 // @Success      200  {object}  []responses.MessagesResponse
 // @Failure      400  {object}  responses.BadRequest
 // @Failure 	 401  {object}	responses.Unauthorized
+// @Failure      409  {object}  responses.Conflict
 // @Failure      422  {object}  responses.UnprocessableEntity
 // @Failure      500  {object}  responses.InternalServerError
 // @Router       /messages/bulk-send [post]
@@ -142,6 +201,11 @@ func (h *MessageHandler) BulkSend(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while sending messages")
 	}
 
+	if !h.userFromContext(c).CanUsePhoneNumber(request.From) {
+		ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to send messages from [%s]", h.userFromContext(c).UserAPIKeyID, request.From))
+		return h.responsePhoneNumberForbidden(c, request.From, h.userFromContext(c))
+	}
+
 	if msg := h.billingService.IsEntitledWithCount(ctx, h.userIDFomContext(c), uint(len(request.To))); msg != nil {
 		ctxLogger.Warn(stacktrace.NewErrorf("user with ID [%s] is not entitled to send [%d] messages", h.userIDFomContext(c), len(request.To)))
 		return h.responsePaymentRequired(c, *msg)
@@ -149,6 +213,9 @@ func (h *MessageHandler) BulkSend(c fiber.Ctx) error {
 
 	wg := sync.WaitGroup{}
 	params := request.ToMessageSendParams(h.userIDFomContext(c), c.OriginalURL())
+	for index := range params {
+		params[index].ActorID = h.actorIDFromContext(c)
+	}
 	responses := make([]*entities.Message, len(params))
 	count := atomic.Int64{}
 
@@ -230,6 +297,7 @@ func (h *MessageHandler) GetOutstanding(c fiber.Ctx) error {
 // @Param        skip		query  int  	false	"number of messages to skip"		minimum(0)
 // @Param        query		query  string  	false 	"filter messages containing query"
 // @Param        limit		query  int  	false	"number of messages to return"		minimum(1)	maximum(20)
+// @Param        cursor		query  string  	false	"next_cursor or prev_cursor of a previous response. It is faster than skip for deep pages"
 // @Success      200 		{object}	responses.MessagesResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
@@ -253,13 +321,26 @@ func (h *MessageHandler) Index(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching messages")
 	}
 
-	messages, err := h.service.GetMessages(ctx, request.ToGetParams(h.userIDFomContext(c)))
+	if !h.userFromContext(c).CanUsePhoneNumber(request.Owner) {
+		ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to fetch messages of [%s]", h.userFromContext(c).UserAPIKeyID, request.Owner))
+		return h.responsePhoneNumberForbidden(c, request.Owner, h.userFromContext(c))
+	}
+
+	params := request.ToGetParams(h.userIDFomContext(c))
+	params.OrganisationID = h.userFromContext(c).OrganisationID
+	messages, err := h.service.GetMessages(ctx, params)
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get messages with params [%+#v]", request))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(*messages), h.pluralize("message", len(*messages))), messages)
+	positions := make([]repositories.Cursor, 0, len(*messages))
+	for _, message := range *messages {
+		positions = append(positions, repositories.Cursor{Timestamp: message.OrderTimestamp, ID: message.ID})
+	}
+
+	next, prev := repositories.PageCursors(params.IndexParams, positions)
+	return h.responseOKWithCursors(c, fmt.Sprintf("fetched %d %s", len(*messages), h.pluralize("message", len(*messages))), messages, next, prev)
 }
 
 // PostEvent registers an event on a message
@@ -410,6 +491,10 @@ func (h *MessageHandler) Delete(c fiber.Ctx) error {
 		return h.responseInternalServerError(c)
 	}
 
+	if !h.userFromContext(c).CanUsePhoneNumber(message.Owner) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", messageID))
+	}
+
 	if err = h.service.DeleteMessage(ctx, c.OriginalURL(), message); err != nil {
 		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete message with ID [%s] for user with ID [%s]", messageID, message.UserID)))
 		return h.responseInternalServerError(c)
@@ -418,6 +503,157 @@ func (h *MessageHandler) Delete(c fiber.Ctx) error {
 	return h.responseNoContent(c, "message deleted successfully")
 }
 
+// PostSegments previews the SMS segments of a message
+// @Summary      Preview the SMS segments of a message
+// @Description  Calculate the encoding (GSM-7 or UCS-2) and the number of SMS segments which the carrier uses to send the content of a message.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param        payload   body 		requests.MessageSegments  	true 	"Content of the draft message"
+// @Success      200  {object}  responses.MessageSegmentsResponse
+// @Failure      400  {object}  responses.BadRequest
+// @Failure      401  {object}  responses.Unauthorized
+// @Failure      422  {object}  responses.UnprocessableEntity
+// @Failure      500  {object}  responses.InternalServerError
+// @Router       /messages/segments [post]
+func (h *MessageHandler) PostSegments(c fiber.Ctx) error {
+	ctx, span := h.tracer.StartFromFiberCtx(c)
+	defer span.End()
+
+	ctxLogger := h.tracer.CtxLogger(h.logger, span)
+
+	var request requests.MessageSegments
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateMessageSegments(ctx, request.Sanitize()); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while calculating segments for payload [%s]", spew.Sdump(errors), c.Body()))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while calculating message segments")
+	}
+
+	return h.responseOK(c, "message segments calculated successfully", entities.CalculateMessageSegments(request.Content))
+}
+
+// Cancel a message
+// @Summary      Cancel a message which has not been sent
+// @Description  Cancel a message in the pending or scheduled status so that it is not sent by your Android phone.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 messageID 	path		string 							true 	"ID of the message" 			default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200  		{object} 	responses.MessageResponse
+// @Failure      400  		{object}  	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /messages/{messageID}/cancel [post]
+func (h *MessageHandler) Cancel(c fiber.Ctx) error {
+	ctx, span := h.tracer.StartFromFiberCtx(c)
+	defer span.End()
+
+	ctxLogger := h.tracer.CtxLogger(h.logger, span)
+
+	messageID := c.Params("messageID")
+	if errors := h.validator.ValidateUUID(messageID, "messageID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while canceling a message with ID [%s]", spew.Sdump(errors), messageID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while canceling message")
+	}
+
+	message, err := h.service.GetMessage(ctx, h.userIDFomContext(c), uuid.MustParse(messageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", messageID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot find message with id [%s]", messageID)))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumber(message.Owner) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", messageID))
+	}
+
+	if !message.CanBeCanceled() {
+		ctxLogger.Warn(stacktrace.NewErrorf("message with ID [%s] has status [%s] and cannot be canceled", messageID, message.Status))
+		return h.responseUnprocessableEntity(c, url.Values{"status": []string{fmt.Sprintf("the message has status [%s] and can only be canceled when it is [%s], [%s] or [%s]", message.Status, entities.MessageStatusPending, entities.MessageStatusScheduled, entities.MessageStatusPaused)}}, "validation errors while canceling message")
+	}
+
+	message, err = h.service.CancelMessage(ctx, c.OriginalURL(), message)
+	if err != nil {
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot cancel message with ID [%s] for user with ID [%s]", messageID, h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message canceled successfully", message)
+}
+
+// Reschedule a message
+// @Summary      Change the send time of a message
+// @Description  Change the time when a message in the pending or scheduled status will be sent by your Android phone.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       json
+// @Produce      json
+// @Param 		 messageID 	path		string 							true 	"ID of the message" 			default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.MessageReschedule  	true 	"New send time of the message"
+// @Success      200  		{object} 	responses.MessageResponse
+// @Failure      400  		{object}  	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /messages/{messageID}/schedule [put]
+func (h *MessageHandler) Reschedule(c fiber.Ctx) error {
+	ctx, span := h.tracer.StartFromFiberCtx(c)
+	defer span.End()
+
+	ctxLogger := h.tracer.CtxLogger(h.logger, span)
+
+	var request requests.MessageReschedule
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request.MessageID = c.Params("messageID")
+	if errors := h.validator.ValidateMessageReschedule(ctx, request.Sanitize()); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while rescheduling message [%s] with payload [%s]", spew.Sdump(errors), request.MessageID, c.Body()))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while rescheduling message")
+	}
+
+	message, err := h.service.GetMessage(ctx, h.userIDFomContext(c), uuid.MustParse(request.MessageID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", request.MessageID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot find message with id [%s]", request.MessageID)))
+		return h.responseInternalServerError(c)
+	}
+
+	if !h.userFromContext(c).CanUsePhoneNumber(message.Owner) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", request.MessageID))
+	}
+
+	if !message.IsPending() && !message.IsScheduled() {
+		ctxLogger.Warn(stacktrace.NewErrorf("message with ID [%s] has status [%s] and cannot be rescheduled", request.MessageID, message.Status))
+		return h.responseUnprocessableEntity(c, url.Values{"status": []string{fmt.Sprintf("the message has status [%s] and can only be rescheduled when it is [%s] or [%s], paused messages are sent when their campaign is resumed", message.Status, entities.MessageStatusPending, entities.MessageStatusScheduled)}}, "validation errors while rescheduling message")
+	}
+
+	message, err = h.service.RescheduleMessage(ctx, c.OriginalURL(), message, *request.SendAt)
+	if err != nil {
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot reschedule message with ID [%s] for user with ID [%s]", request.MessageID, h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message rescheduled successfully", message)
+}
+
 // Get a message
 // @Summary      Get a message from the database.
 // @Description  Get a message from the database by the message ID.
@@ -455,6 +691,10 @@ func (h *MessageHandler) Get(c fiber.Ctx) error {
 		return h.responseInternalServerError(c)
 	}
 
+	if !h.userFromContext(c).CanUsePhoneNumber(message.Owner) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message with ID [%s]", messageID))
+	}
+
 	return h.responseOK(c, "message fetched successfully", message)
 }
 
@@ -504,19 +744,74 @@ func (h *MessageHandler) PostCallMissed(c fiber.Ctx) error {
 	return h.responseOK(c, "missed call event stored successfully", message)
 }
 
+// Import messages from an "SMS Backup & Restore" XML file
+// @Summary      Import messages from an SMS Backup & Restore file
+// @Description  Import the SMS history of an android phone from an XML file created by the SMS Backup & Restore app. Received, sent and failed messages are imported and the message threads are updated. Messages which have already been imported are skipped and imported messages are not counted in your usage.
+// @Security	 ApiKeyAuth
+// @Tags         Messages
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        owner		formData  	string   						true	"The phone number of the android phone which created the backup"	default(+18005550199)
+// @Param        document	formData  	file   							true	"The XML file created by SMS Backup & Restore"
+// @Success      200 		{object}	responses.MessageImportResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /messages/import [post]
+func (h *MessageHandler) Import(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	file, err := c.FormFile("document")
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot fetch file with name [%s] from request", "document"))
+		return h.responseBadRequest(c, err)
+	}
+
+	request := requests.MessageImportStore{Owner: c.FormValue("owner")}
+	request = request.Sanitize()
+
+	if !h.userFromContext(c).CanUsePhoneNumber(request.Owner) {
+		return h.responsePhoneNumberForbidden(c, request.Owner, h.userFromContext(c))
+	}
+
+	messages, errors := h.validator.ValidateImport(ctx, h.userIDFomContext(c), request, file)
+	if len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while importing messages from file [%s] for [%s]", spew.Sdump(errors), file.Filename, h.userIDFomContext(c)))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while importing messages")
+	}
+
+	params := make([]*services.MessageImportParams, 0, len(messages))
+	for _, message := range messages {
+		params = append(params, message.ToImportParams(request.Owner))
+	}
+
+	count, err := h.service.Import(ctx, h.userIDFomContext(c), params)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot import [%d] messages from file [%s]", len(params), file.Filename))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("imported %d %s successfully", count, h.pluralize("message", count)), fiber.Map{"count": count, "duplicates": len(params) - count})
+}
+
 // Search returns a filtered list of messages of a user
 // @Summary      Search all messages of a user
-// @Description  This returns the list of all messages based on the filter criteria including missed calls
+// @Description  This returns the list of all messages based on the filter criteria including missed calls. The query uses full text search, words match as a prefix and words in double quotes match as a phrase. When the query is set and no sort order is given, the most relevant messages are returned first with a highlighted snippet of the content. The query cannot be used when the message content is encrypted at rest.
 // @Security	 ApiKeyAuth
 // @Tags         Messages
 // @Accept       json
 // @Produce      json
 // @Param        token    	header string   true   	"Cloudflare turnstile token https://www.cloudflare.com/en-gb/application-services/products/turnstile/"
 // @Param        owners		query  string  	true 	"the owner's phone numbers" 		default(+18005550199,+18005550100)
+// @Param        contacts	query  string  	false 	"the contact phone numbers" 		default(+18005550100)
 // @Param        skip		query  int  	false	"number of messages to skip"		minimum(0)
-// @Param        query		query  string  	false 	"filter messages containing query"
+// @Param        query		query  string  	false 	"full text search query e.g. ord-1234 or \"out for delivery\""
+// @Param        from		query  string  	false 	"only messages on or after this date or RFC3339 timestamp"	default(2024-01-01)
+// @Param        to			query  string  	false 	"only messages on or before this date or before this RFC3339 timestamp"	default(2024-01-31)
 // @Param        limit		query  int  	false	"number of messages to return"		minimum(1)	maximum(200)
-// @Success      200 		{object}	responses.MessagesResponse
+// @Success      200 		{object}	responses.MessageSearchResponse
 // @Failure      400		{object}	responses.BadRequest
 // @Failure 	 401    	{object}	responses.Unauthorized
 // @Failure      422		{object}	responses.UnprocessableEntity
@@ -540,6 +835,16 @@ func (h *MessageHandler) Search(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while searching messages")
 	}
 
+	if authUser := h.userFromContext(c); authUser.UserAPIKeyID != nil && len(request.Owners) == 0 {
+		request.Owners = authUser.PhoneNumbers
+	}
+
+	for _, owner := range request.Owners {
+		if !h.userFromContext(c).CanUsePhoneNumber(owner) {
+			return h.responsePhoneNumberForbidden(c, owner, h.userFromContext(c))
+		}
+	}
+
 	messages, err := h.service.SearchMessages(ctx, request.ToSearchParams(h.userIDFomContext(c)))
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot search messages with params [%+#v]", request))
@@ -548,3 +853,55 @@ func (h *MessageHandler) Search(c fiber.Ctx) error {
 
 	return h.responseOK(c, fmt.Sprintf("found %d %s", len(messages), h.pluralize("message", len(messages))), messages)
 }
+
+// Analytics returns the delivery rates, latency and failures of the messages of a user
+// @Summary      Get message analytics
+// @Description  Aggregates the messages with an order timestamp in a time range by period and by phone with the counts of each status, the delivery rate, the p50 and p95 of the send duration and of the time from sent to delivered, and the most frequent failure reasons. Periods without messages are omitted.
+// @Security	 ApiKeyAuth
+// @Tags         Analytics
+// @Accept       json
+// @Produce      json
+// @Param        from			query  string  	false 	"only messages created on or after this date or RFC3339 timestamp, defaults to 30 days before the to field"	default(2024-01-01)
+// @Param        to				query  string  	false 	"only messages created on or before this date or before this RFC3339 timestamp, defaults to now"	default(2024-01-31)
+// @Param        owner			query  string  	false 	"only messages of this phone number"	default(+18005550199)
+// @Param        granularity	query  string  	false 	"length of the periods"	Enums(hour, day, week, month)	default(day)
+// @Success      200 		{object}	responses.MessageAnalyticsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /analytics/messages [get]
+func (h *MessageHandler) Analytics(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageAnalytics
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params in [%s] into [%T]", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	now := time.Now().UTC()
+	if errors := h.validator.ValidateMessageAnalytics(ctx, request.Sanitize(), now); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching message analytics [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message analytics")
+	}
+
+	authUser := h.userFromContext(c)
+	if request.Owner != "" && !authUser.CanUsePhoneNumber(request.Owner) {
+		return h.responsePhoneNumberForbidden(c, request.Owner, authUser)
+	}
+
+	params := request.ToAnalyticsParams(h.userIDFomContext(c), now)
+	if authUser.UserAPIKeyID != nil && request.Owner == "" {
+		params.Owners = authUser.PhoneNumbers
+	}
+
+	analytics, err := h.service.Analytics(ctx, params)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot fetch message analytics with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("aggregated %d %s", analytics.Totals.Total, h.pluralize("message", int(analytics.Totals.Total))), analytics)
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// MessageTemplateHandler handles message template requests
+type MessageTemplateHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.MessageTemplateService
+	validator *validators.MessageTemplateHandlerValidator
+}
+
+// NewMessageTemplateHandler creates a new MessageTemplateHandler
+func NewMessageTemplateHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.MessageTemplateService,
+	validator *validators.MessageTemplateHandlerValidator,
+) (h *MessageTemplateHandler) {
+	return &MessageTemplateHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the MessageTemplateHandler
+func (h *MessageTemplateHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/message-templates", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/message-templates", middlewares, h.Store)
+	h.register(router, fiber.MethodGet, "/v1/message-templates/:templateID", middlewares, h.Show)
+	h.register(router, fiber.MethodPut, "/v1/message-templates/:templateID", middlewares, h.Update)
+	h.register(router, fiber.MethodDelete, "/v1/message-templates/:templateID", middlewares, h.Delete)
+}
+
+// Index returns the message templates of a user
+// @Summary      Get message templates of a user
+// @Description  Get the message templates of a user
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of templates to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter templates containing query"
+// @Param        limit		query  int  	false	"number of templates to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.MessageTemplatesResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates 	[get]
+func (h *MessageTemplateHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageTemplateIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching message templates [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message templates")
+	}
+
+	templates, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get message templates with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d message %s", len(templates), h.pluralize("template", len(templates))), templates)
+}
+
+// Show returns a single message template
+// @Summary      Get a message template
+// @Description  Get a message template of a user by ID
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param 		 templateID 	path		string 							true 	"ID of the message template"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.MessageTemplateResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates/{templateID} [get]
+func (h *MessageTemplateHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	templateID := c.Params("templateID")
+	if errors := h.validator.ValidateUUID(templateID, "templateID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching message template with ID [%s]", spew.Sdump(errors), templateID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message template")
+	}
+
+	template, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(templateID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message template with ID [%s]", templateID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load message template with ID [%s]", templateID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message template fetched successfully", template)
+}
+
+// Store a message template
+// @Summary      Store a message template
+// @Description  Store a reusable message template for the authenticated user. Variables are written in double curly braces e.g {{name}}
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.MessageTemplateStore  		true "Payload of the message template"
+// @Success      201 		{object}	responses.MessageTemplateResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates [post]
+func (h *MessageTemplateHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.MessageTemplateStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing message template [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing message template")
+	}
+
+	template, err := h.service.Store(ctx, request.ToUpsertParams(h.userIDFomContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store message template with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "message template created successfully", template)
+}
+
+// Update a message template
+// @Summary      Update a message template
+// @Description  Update a message template of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param 		 templateID 	path		string 							true 	"ID of the message template"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.MessageTemplateStore  		true "Payload of the message template"
+// @Success      200 		{object}	responses.MessageTemplateResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates/{templateID} [put]
+func (h *MessageTemplateHandler) Update(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	templateID := c.Params("templateID")
+	if errors := h.validator.ValidateUUID(templateID, "templateID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating message template with ID [%s]", spew.Sdump(errors), templateID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message template")
+	}
+
+	var request requests.MessageTemplateStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating message template [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message template")
+	}
+
+	template, err := h.service.Update(ctx, uuid.MustParse(templateID), request.ToUpsertParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message template with ID [%s]", templateID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update message template with ID [%s]", templateID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "message template updated successfully", template)
+}
+
+// Delete a message template
+// @Summary      Delete a message template
+// @Description  Delete a message template of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         MessageTemplates
+// @Accept       json
+// @Produce      json
+// @Param 		 templateID 	path		string 							true 	"ID of the message template"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /message-templates/{templateID} [delete]
+func (h *MessageTemplateHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	templateID := c.Params("templateID")
+	if errors := h.validator.ValidateUUID(templateID, "templateID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting message template with ID [%s]", spew.Sdump(errors), templateID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting message template")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(templateID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message template with ID [%s]", templateID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete message template with ID [%s]", templateID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "message template deleted successfully")
+}
@@ -3,6 +3,8 @@ package handlers
 import (
 	"fmt"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/middlewares"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/google/uuid"
 
@@ -39,6 +41,13 @@ func NewMessageThreadHandler(
 	}
 }
 
+// RouteScopes returns the scopes of the routes of the MessageThreadHandler which do not follow the read and write scopes, deleting a message thread needs the delete scope
+func (h *MessageThreadHandler) RouteScopes() middlewares.RouteScopes {
+	return middlewares.RouteScopes{
+		"DELETE /v1/message-threads/:messageThreadID": entities.UserAPIKeyScopeMessagesDelete,
+	}
+}
+
 // RegisterRoutes registers the routes for the MessageHandler
 func (h *MessageThreadHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
 	h.register(router, fiber.MethodGet, "/v1/message-threads", middlewares, h.Index)
@@ -57,6 +66,7 @@ func (h *MessageThreadHandler) RegisterRoutes(router fiber.Router, middlewares .
 // @Param        skip	query  int  	false	"number of messages to skip"				minimum(0)
 // @Param        query	query  string  	false 	"filter message threads containing query"
 // @Param        limit	query  int  	false	"number of messages to return"				minimum(1)	maximum(20)
+// @Param        cursor	query  string  	false	"next_cursor or prev_cursor of a previous response. It is faster than skip for deep pages"
 // @Success      200 	{object}	responses.MessageThreadsResponse
 // @Failure      400	{object}	responses.BadRequest
 // @Failure 	 401    {object}	responses.Unauthorized
@@ -82,13 +92,26 @@ func (h *MessageThreadHandler) Index(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching message threads")
 	}
 
-	threads, err := h.service.GetThreads(ctx, request.ToGetParams(h.userIDFomContext(c)))
+	if !h.userFromContext(c).CanUsePhoneNumber(request.Owner) {
+		ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to fetch message threads of [%s]", h.userFromContext(c).UserAPIKeyID, request.Owner))
+		return h.responsePhoneNumberForbidden(c, request.Owner, h.userFromContext(c))
+	}
+
+	params := request.ToGetParams(h.userIDFomContext(c))
+	params.OrganisationID = h.userFromContext(c).OrganisationID
+	threads, err := h.service.GetThreads(ctx, params)
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get message threads with params [%+#v]", request))
 		return h.responseInternalServerError(c)
 	}
 
-	return h.responseOK(c, fmt.Sprintf("fetched %d message %s", len(*threads), h.pluralize("thread", len(*threads))), threads)
+	positions := make([]repositories.Cursor, 0, len(*threads))
+	for _, thread := range *threads {
+		positions = append(positions, repositories.Cursor{Timestamp: thread.OrderTimestamp, ID: thread.ID})
+	}
+
+	next, prev := repositories.PageCursors(params.IndexParams, positions)
+	return h.responseOKWithCursors(c, fmt.Sprintf("fetched %d message %s", len(*threads), h.pluralize("thread", len(*threads))), threads, next, prev)
 }
 
 // Update an entities.MessageThread
@@ -123,7 +146,19 @@ func (h *MessageThreadHandler) Update(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while updating message thread")
 	}
 
-	thread, err := h.service.UpdateStatus(ctx, request.ToUpdateParams(h.userIDFomContext(c)))
+	thread, err := h.service.GetThread(ctx, h.userIDFomContext(c), uuid.MustParse(request.MessageThreadID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound || (err == nil && !h.userFromContext(c).CanUsePhoneNumber(thread.Owner)) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", request.MessageThreadID))
+	}
+	if err != nil {
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot find message thread with id [%s]", request.MessageThreadID)))
+		return h.responseInternalServerError(c)
+	}
+
+	params := request.ToUpdateParams(h.userIDFomContext(c))
+	params.ActorID = h.actorIDFromContext(c)
+
+	thread, err = h.service.UpdateStatus(ctx, params)
 	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
 		return h.responseNotFound(c, fmt.Sprintf("cannot find message thread with ID [%s]", request.MessageThreadID))
 	}
@@ -170,6 +205,10 @@ func (h *MessageThreadHandler) Delete(c fiber.Ctx) error {
 		return h.responseInternalServerError(c)
 	}
 
+	if !h.userFromContext(c).CanUsePhoneNumber(thread.Owner) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find thread thread with ID [%s]", messageThreadID))
+	}
+
 	if err = h.service.DeleteThread(ctx, c.OriginalURL(), thread); err != nil {
 		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete thread thread with ID [%s] for user with ID [%s]", messageThreadID, thread.UserID)))
 		return h.responseInternalServerError(c)
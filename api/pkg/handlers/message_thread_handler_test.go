@@ -23,7 +23,9 @@ import (
 	"gorm.io/gorm"
 )
 
-type messageThreadHandlerRepositoryStub struct{}
+type messageThreadHandlerRepositoryStub struct {
+	thread *entities.MessageThread
+}
 
 func (stub *messageThreadHandlerRepositoryStub) Store(context.Context, *entities.MessageThread) error {
 	return nil
@@ -42,7 +44,10 @@ func (stub *messageThreadHandlerRepositoryStub) LoadByOwnerContact(context.Conte
 }
 
 func (stub *messageThreadHandlerRepositoryStub) Load(context.Context, entities.UserID, uuid.UUID) (*entities.MessageThread, error) {
-	return nil, nil
+	if stub.thread == nil {
+		return nil, stacktrace.PropagateWithCodef(gorm.ErrRecordNotFound, repositories.ErrCodeNotFound, "not found")
+	}
+	return stub.thread, nil
 }
 
 func (stub *messageThreadHandlerRepositoryStub) Index(context.Context, entities.UserID, string, bool, repositories.IndexParams) (*[]entities.MessageThread, error) {
@@ -61,10 +66,14 @@ func (stub *messageThreadHandlerRepositoryStub) DeleteAllForUser(context.Context
 	return nil
 }
 
+func (stub *messageThreadHandlerRepositoryStub) ReEncrypt(context.Context, entities.UserID, int) (int, error) {
+	return 0, nil
+}
+
 func TestMessageThreadHandlerUpdate_ReturnsNotFoundWhenThreadIsMissing(t *testing.T) {
 	logger := &messageThreadHandlerNoopLogger{}
 	tracer := telemetry.NewOtelLogger("test", logger)
-	service := services.NewMessageThreadService(logger, tracer, &messageThreadHandlerRepositoryStub{}, nil, nil)
+	service := services.NewMessageThreadService(logger, tracer, &messageThreadHandlerRepositoryStub{}, nil, nil, nil)
 	handler := NewMessageThreadHandler(logger, tracer, validators.NewMessageThreadHandlerValidator(logger, tracer), service)
 
 	app := fiber.New()
@@ -90,6 +99,31 @@ func TestMessageThreadHandlerUpdate_ReturnsNotFoundWhenThreadIsMissing(t *testin
 	require.Equal(t, "cannot find message thread with ID ["+messageThreadID.String()+"]", payload.Message)
 }
 
+func TestMessageThreadHandlerUpdate_ReturnsNotFoundWhenTheAPIKeyCannotUseTheOwner(t *testing.T) {
+	logger := &messageThreadHandlerNoopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	messageThreadID := uuid.New()
+	repository := &messageThreadHandlerRepositoryStub{thread: &entities.MessageThread{ID: messageThreadID, Owner: "+18005550100"}}
+	service := services.NewMessageThreadService(logger, tracer, repository, nil, nil, nil)
+	handler := NewMessageThreadHandler(logger, tracer, validators.NewMessageThreadHandlerValidator(logger, tracer), service)
+
+	keyID := uuid.New()
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals(middlewares.ContextKeyAuthUserID, entities.AuthContext{ID: entities.UserID("user-id"), Email: "user@example.com", UserAPIKeyID: &keyID, PhoneNumbers: []string{"+18005550199"}})
+		return c.Next()
+	})
+	handler.RegisterRoutes(app)
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/message-threads/"+messageThreadID.String(), bytes.NewBufferString(`{"is_read":true}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, fiber.TestConfig{Timeout: time.Second})
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 type messageThreadHandlerNoopLogger struct{}
 
 var _ telemetry.Logger = (*messageThreadHandlerNoopLogger)(nil)
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/adaptor"
+)
+
+// MetricsHandler serves the metrics which are scraped by Prometheus
+type MetricsHandler struct {
+	handler
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	token   string
+	metrics fiber.Handler
+}
+
+// NewMetricsHandler creates a new MetricsHandler, the metrics are not served when the token is empty
+func NewMetricsHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	token string,
+	metrics http.Handler,
+) (h *MetricsHandler) {
+	return &MetricsHandler{
+		logger:  logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:  tracer,
+		token:   token,
+		metrics: adaptor.HTTPHandler(metrics),
+	}
+}
+
+// RegisterRoutes registers the routes for the MetricsHandler
+func (h *MetricsHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/metrics", middlewares, h.Metrics)
+}
+
+// Metrics serves the metrics in the Prometheus exposition format
+// This is an internal API so no documentation provided
+func (h *MetricsHandler) Metrics(c fiber.Ctx) error {
+	if h.token == "" || subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), []byte("Bearer "+h.token)) != 1 {
+		h.logger.Warn(stacktrace.NewErrorf("cannot scrape metrics from IP [%s] without a valid bearer token", c.IP()))
+		return h.responseUnauthorized(c)
+	}
+	return h.metrics(c)
+}
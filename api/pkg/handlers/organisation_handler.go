@@ -0,0 +1,581 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// OrganisationHandler handles organisation requests
+type OrganisationHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.OrganisationService
+	validator *validators.OrganisationHandlerValidator
+}
+
+// NewOrganisationHandler creates a new OrganisationHandler
+func NewOrganisationHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.OrganisationService,
+	validator *validators.OrganisationHandlerValidator,
+) (h *OrganisationHandler) {
+	return &OrganisationHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the OrganisationHandler
+func (h *OrganisationHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/organisations", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/organisations", middlewares, h.Store)
+	h.register(router, fiber.MethodGet, "/v1/organisations/:organisationID", middlewares, h.Show)
+	h.register(router, fiber.MethodPut, "/v1/organisations/:organisationID", middlewares, h.Update)
+	h.register(router, fiber.MethodDelete, "/v1/organisations/:organisationID", middlewares, h.Delete)
+	h.register(router, fiber.MethodPost, "/v1/organisations/:organisationID/accept", middlewares, h.Accept)
+	h.register(router, fiber.MethodGet, "/v1/organisations/:organisationID/members", middlewares, h.IndexMembers)
+	h.register(router, fiber.MethodPost, "/v1/organisations/:organisationID/members", middlewares, h.StoreMember)
+	h.register(router, fiber.MethodPut, "/v1/organisations/:organisationID/members/:memberID", middlewares, h.UpdateMember)
+	h.register(router, fiber.MethodDelete, "/v1/organisations/:organisationID/members/:memberID", middlewares, h.DeleteMember)
+}
+
+// Index returns the organisations of a user
+// @Summary      Get organisations of a user
+// @Description  Get the organisations of the authenticated user including the pending invitations to the email address of the user
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Success      200 		{object}	responses.OrganisationMembershipsResponse
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations 	[get]
+func (h *OrganisationHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	authUser := h.userFromContext(c)
+	memberships, err := h.service.FetchMemberships(ctx, authUser.ActorID(), strings.ToLower(authUser.Email))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot fetch organisations of user [%s]", authUser.ActorID()))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(memberships), h.pluralize("organisation", len(memberships))), memberships)
+}
+
+// Store an organisation
+// @Summary      Store an organisation
+// @Description  Create an organisation which shares the phones, webhooks, threads, send schedules and billing of the authenticated user with invited members
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.OrganisationStore  	true "Payload of the organisation"
+// @Success      201 		{object}	responses.OrganisationResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations [post]
+func (h *OrganisationHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.OrganisationStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing organisation [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing organisation")
+	}
+
+	authUser := h.userFromContext(c)
+	_, err := h.service.LoadByOwner(ctx, authUser.ActorID())
+	if err == nil {
+		return h.responseUnprocessableEntity(c, url.Values{"name": []string{"you already own an organisation"}}, "validation errors while storing organisation")
+	}
+
+	if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation owned by user [%s]", authUser.ActorID()))
+		return h.responseInternalServerError(c)
+	}
+
+	organisation, err := h.service.Store(ctx, request.ToStoreParams(authUser.ActorID(), authUser.Email))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store organisation with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "organisation created successfully", organisation)
+}
+
+// Show returns a single organisation
+// @Summary      Get an organisation
+// @Description  Get an organisation which the authenticated user is a member of
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.OrganisationResponse
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID} [get]
+func (h *OrganisationHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	organisationID := c.Params("organisationID")
+	if errors := h.validator.ValidateUUID(organisationID, "organisationID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching organisation with ID [%s]", spew.Sdump(errors), organisationID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching organisation")
+	}
+
+	membership, err := h.service.LoadMembership(ctx, uuid.MustParse(organisationID), h.userFromContext(c).ActorID())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find organisation with ID [%s]", organisationID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "organisation fetched successfully", membership.Organisation)
+}
+
+// Update an organisation
+// @Summary      Update an organisation
+// @Description  Change the name of an organisation, only the owner and admins can update the organisation
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.OrganisationStore  	true "Payload of the organisation"
+// @Success      200 		{object}	responses.OrganisationResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID} [put]
+func (h *OrganisationHandler) Update(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	organisationID := c.Params("organisationID")
+	if errors := h.validator.ValidateUUID(organisationID, "organisationID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating organisation with ID [%s]", spew.Sdump(errors), organisationID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating organisation")
+	}
+
+	var request requests.OrganisationStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating organisation [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating organisation")
+	}
+
+	membership, err := h.service.LoadMembership(ctx, uuid.MustParse(organisationID), h.userFromContext(c).ActorID())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find organisation with ID [%s]", organisationID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !membership.Role.CanManageMembers() {
+		ctxLogger.Warn(stacktrace.NewErrorf("member [%s] with role [%s] cannot update organisation [%s]", membership.MemberID, membership.Role, organisationID))
+		return h.responseForbidden(c)
+	}
+
+	organisation, err := h.service.Update(ctx, &membership.Organisation, request.Name)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "organisation updated successfully", organisation)
+}
+
+// Delete an organisation
+// @Summary      Delete an organisation
+// @Description  Delete an organisation and remove all its members, only the owner can delete the organisation. The phones, webhooks and messages of the owner are not deleted.
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID} [delete]
+func (h *OrganisationHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	organisationID := c.Params("organisationID")
+	if errors := h.validator.ValidateUUID(organisationID, "organisationID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting organisation with ID [%s]", spew.Sdump(errors), organisationID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting organisation")
+	}
+
+	membership, err := h.service.LoadMembership(ctx, uuid.MustParse(organisationID), h.userFromContext(c).ActorID())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find organisation with ID [%s]", organisationID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	if membership.Role != entities.OrganisationRoleOwner {
+		ctxLogger.Warn(stacktrace.NewErrorf("member [%s] with role [%s] cannot delete organisation [%s]", membership.MemberID, membership.Role, organisationID))
+		return h.responseForbidden(c)
+	}
+
+	if err = h.service.Delete(ctx, membership.ID); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "organisation deleted successfully")
+}
+
+// Accept the invitation to an organisation
+// @Summary      Accept an invitation
+// @Description  Accept the invitation which was sent to the email address of the authenticated user to join an organisation. The email address must be verified so the invitation can only be accepted from the web app.
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.OrganisationMemberResponse
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID}/accept [post]
+func (h *OrganisationHandler) Accept(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	organisationID := c.Params("organisationID")
+	if errors := h.validator.ValidateUUID(organisationID, "organisationID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while accepting invitation to organisation with ID [%s]", spew.Sdump(errors), organisationID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while accepting invitation")
+	}
+
+	authUser := h.userFromContext(c)
+	if !authUser.EmailVerified {
+		ctxLogger.Warn(stacktrace.NewErrorf("user [%s] cannot accept invitation to organisation with ID [%s] because the email [%s] is not verified", authUser.ActorID(), organisationID, authUser.Email))
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "error",
+			"message": fiber.ErrForbidden.Message,
+			"data":    fmt.Sprintf("You must verify your email address [%s] before accepting the invitation", authUser.Email),
+		})
+	}
+
+	member, err := h.service.Accept(ctx, uuid.MustParse(organisationID), authUser.ActorID(), strings.ToLower(authUser.Email))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find invitation to organisation with ID [%s] for [%s]", organisationID, authUser.Email))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot accept invitation to organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "invitation accepted successfully", member)
+}
+
+// IndexMembers returns the members of an organisation
+// @Summary      Get members of an organisation
+// @Description  Get the members of an organisation which the authenticated user is a member of
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        skip		query  int  	false	"number of members to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter members with email containing query"
+// @Param        limit		query  int  	false	"number of members to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.OrganisationMembersResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID}/members [get]
+func (h *OrganisationHandler) IndexMembers(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.OrganisationMemberIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	organisationID := c.Params("organisationID")
+	request = request.Sanitize()
+	if errors := h.mergeErrors(h.validator.ValidateMemberIndex(ctx, request), h.validator.ValidateUUID(organisationID, "organisationID")); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching members of organisation [%s]", spew.Sdump(errors), organisationID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching organisation members")
+	}
+
+	membership, err := h.service.LoadMembership(ctx, uuid.MustParse(organisationID), h.userFromContext(c).ActorID())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find organisation with ID [%s]", organisationID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	members, err := h.service.IndexMembers(ctx, membership.ID, request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get members of organisation [%s] with params [%+#v]", organisationID, request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(members), h.pluralize("member", len(members))), members)
+}
+
+// StoreMember invites a member to an organisation
+// @Summary      Invite a member
+// @Description  Invite a user by email to an organisation. The owner can invite admins, agents and read-only members while admins can only invite agents and read-only members.
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.OrganisationMemberStore  	true "Payload of the invitation"
+// @Success      201 		{object}	responses.OrganisationMemberResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID}/members [post]
+func (h *OrganisationHandler) StoreMember(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.OrganisationMemberStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	organisationID := c.Params("organisationID")
+	request = request.Sanitize()
+	if errors := h.mergeErrors(h.validator.ValidateMemberStore(ctx, request), h.validator.ValidateUUID(organisationID, "organisationID")); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while inviting member [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while inviting member")
+	}
+
+	authUser := h.userFromContext(c)
+	membership, err := h.service.LoadMembership(ctx, uuid.MustParse(organisationID), authUser.ActorID())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find organisation with ID [%s]", organisationID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !membership.Role.CanManageMembers() || !membership.Role.CanAssign(entities.OrganisationRole(request.Role)) {
+		ctxLogger.Warn(stacktrace.NewErrorf("member [%s] with role [%s] cannot invite a member with role [%s] to organisation [%s]", membership.MemberID, membership.Role, request.Role, organisationID))
+		return h.responseForbidden(c)
+	}
+
+	_, err = h.service.LoadMemberByEmail(ctx, membership.ID, request.Email)
+	if err == nil {
+		return h.responseUnprocessableEntity(c, url.Values{"email": []string{fmt.Sprintf("[%s] has already been invited to this organisation", request.Email)}}, "validation errors while inviting member")
+	}
+
+	if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load member with email [%s] in organisation [%s]", request.Email, organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	member, err := h.service.Invite(ctx, request.ToInviteParams(&membership.Organisation, authUser))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot invite member with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "member invited successfully", member)
+}
+
+// UpdateMember changes the role of a member
+// @Summary      Update a member
+// @Description  Change the role of a member of an organisation. The owner can change the role of admins, agents and read-only members while admins can only change agents and read-only members.
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param 		 memberID 	path		string 		true 	"ID of the member"	default(32343a19-da5e-4b1b-a767-3298a73703cb)
+// @Param        payload   	body 		requests.OrganisationMemberUpdate  	true "Payload of the member"
+// @Success      200 		{object}	responses.OrganisationMemberResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID}/members/{memberID} [put]
+func (h *OrganisationHandler) UpdateMember(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.OrganisationMemberUpdate
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	organisationID := c.Params("organisationID")
+	memberID := c.Params("memberID")
+	request = request.Sanitize()
+	if errors := h.mergeErrors(h.validator.ValidateMemberUpdate(ctx, request), h.validator.ValidateUUID(organisationID, "organisationID"), h.validator.ValidateUUID(memberID, "memberID")); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating member [%s] with [%+#v]", spew.Sdump(errors), memberID, request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating member")
+	}
+
+	membership, err := h.service.LoadMembership(ctx, uuid.MustParse(organisationID), h.userFromContext(c).ActorID())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find organisation with ID [%s]", organisationID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	member, err := h.service.LoadMember(ctx, membership.ID, uuid.MustParse(memberID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find member with ID [%s]", memberID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load member with ID [%s] in organisation [%s]", memberID, organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	if !membership.Role.CanManageMembers() || !membership.Role.CanAssign(member.Role) || !membership.Role.CanAssign(request.OrganisationRole()) {
+		ctxLogger.Warn(stacktrace.NewErrorf("member [%s] with role [%s] cannot change the role of member [%s] from [%s] to [%s]", membership.MemberID, membership.Role, member.ID, member.Role, request.Role))
+		return h.responseForbidden(c)
+	}
+
+	member, err = h.service.UpdateMemberRole(ctx, member, request.OrganisationRole())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update member with ID [%s] in organisation [%s]", memberID, organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "member updated successfully", member)
+}
+
+// DeleteMember removes a member from an organisation
+// @Summary      Remove a member
+// @Description  Remove a member from an organisation or cancel a pending invitation. Members can also remove themselves except the owner.
+// @Security	 ApiKeyAuth
+// @Tags         Organisations
+// @Accept       json
+// @Produce      json
+// @Param 		 organisationID 	path		string 		true 	"ID of the organisation"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param 		 memberID 	path		string 		true 	"ID of the member"	default(32343a19-da5e-4b1b-a767-3298a73703cb)
+// @Success      204		{object}    responses.NoContent
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 403	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /organisations/{organisationID}/members/{memberID} [delete]
+func (h *OrganisationHandler) DeleteMember(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	organisationID := c.Params("organisationID")
+	memberID := c.Params("memberID")
+	if errors := h.mergeErrors(h.validator.ValidateUUID(organisationID, "organisationID"), h.validator.ValidateUUID(memberID, "memberID")); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting member [%s] of organisation [%s]", spew.Sdump(errors), memberID, organisationID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting member")
+	}
+
+	membership, err := h.service.LoadMembership(ctx, uuid.MustParse(organisationID), h.userFromContext(c).ActorID())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find organisation with ID [%s]", organisationID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	member, err := h.service.LoadMember(ctx, membership.ID, uuid.MustParse(memberID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find member with ID [%s]", memberID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load member with ID [%s] in organisation [%s]", memberID, organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	isLeaving := member.ID == membership.MemberID && member.Role != entities.OrganisationRoleOwner
+	if !isLeaving && (!membership.Role.CanManageMembers() || !membership.Role.CanAssign(member.Role)) {
+		ctxLogger.Warn(stacktrace.NewErrorf("member [%s] with role [%s] cannot remove member [%s] with role [%s]", membership.MemberID, membership.Role, member.ID, member.Role))
+		return h.responseForbidden(c)
+	}
+
+	if err = h.service.DeleteMember(ctx, member); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete member with ID [%s] from organisation [%s]", memberID, organisationID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "member removed successfully")
+}
@@ -123,6 +123,13 @@ func (h *PhoneHandler) Upsert(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while updating phones")
 	}
 
+	for _, phoneNumber := range append([]string{request.PhoneNumber}, request.FallbackPhoneNumbers...) {
+		if !h.userFromContext(c).CanUsePhoneNumber(phoneNumber) {
+			ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to update phone [%s]", h.userFromContext(c).UserAPIKeyID, phoneNumber))
+			return h.responsePhoneNumberForbidden(c, phoneNumber, h.userFromContext(c))
+		}
+	}
+
 	phone, err := h.service.Upsert(ctx, request.ToUpsertParams(h.userFromContext(c), c.OriginalURL(), c.Body()))
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update phones with params [%+#v]", request))
@@ -158,7 +165,16 @@ func (h *PhoneHandler) Delete(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting phone")
 	}
 
-	err := h.service.Delete(ctx, c.OriginalURL(), h.userIDFomContext(c), request.PhoneIDUuid())
+	phone, err := h.service.LoadByID(ctx, h.userIDFomContext(c), request.PhoneIDUuid())
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound || (err == nil && !h.userFromContext(c).CanUsePhoneNumber(phone.PhoneNumber)) {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load phone with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	err = h.service.Delete(ctx, c.OriginalURL(), h.userIDFomContext(c), request.PhoneIDUuid())
 	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
 		return h.responseNotFound(c, fmt.Sprintf("cannot find phone with ID [%s]", request.PhoneID))
 	}
@@ -201,6 +217,11 @@ func (h *PhoneHandler) UpsertFCMToken(c fiber.Ctx) error {
 		return h.responseUnprocessableEntity(c, errors, "validation errors while updating phones")
 	}
 
+	if !h.userFromContext(c).CanUsePhoneNumber(request.PhoneNumber) {
+		ctxLogger.Warn(stacktrace.NewErrorf("user API key [%s] is not allowed to update phone [%s]", h.userFromContext(c).UserAPIKeyID, request.PhoneNumber))
+		return h.responsePhoneNumberForbidden(c, request.PhoneNumber, h.userFromContext(c))
+	}
+
 	phone, err := h.service.UpsertFCMToken(ctx, request.ToPhoneFCMTokenParams(h.userFromContext(c), c.OriginalURL()))
 	if err != nil {
 		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete phones with params [%+#v]", request))
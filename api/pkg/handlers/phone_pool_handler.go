@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// PhonePoolHandler handles phone pool requests
+type PhonePoolHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.PhonePoolService
+	validator *validators.PhonePoolHandlerValidator
+}
+
+// NewPhonePoolHandler creates a new PhonePoolHandler
+func NewPhonePoolHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhonePoolService,
+	validator *validators.PhonePoolHandlerValidator,
+) (h *PhonePoolHandler) {
+	return &PhonePoolHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the PhonePoolHandler
+func (h *PhonePoolHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/phone-pools", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/phone-pools", middlewares, h.Store)
+	h.register(router, fiber.MethodGet, "/v1/phone-pools/:poolID", middlewares, h.Show)
+	h.register(router, fiber.MethodPut, "/v1/phone-pools/:poolID", middlewares, h.Update)
+	h.register(router, fiber.MethodDelete, "/v1/phone-pools/:poolID", middlewares, h.Delete)
+}
+
+// Index returns the phone pools of a user
+// @Summary      Get phone pools of a user
+// @Description  Get the phone pools of a user
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of pools to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter pools containing query"
+// @Param        limit		query  int  	false	"number of pools to return"	minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.PhonePoolsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools 	[get]
+func (h *PhonePoolHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhonePoolIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching phone pools [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone pools")
+	}
+
+	pools, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get phone pools with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d phone %s", len(pools), h.pluralize("pool", len(pools))), pools)
+}
+
+// Show returns a single phone pool
+// @Summary      Get a phone pool
+// @Description  Get a phone pool of a user by ID
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param 		 poolID 	path		string 							true 	"ID of the phone pool"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      200 		{object}	responses.PhonePoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools/{poolID} [get]
+func (h *PhonePoolHandler) Show(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	poolID := c.Params("poolID")
+	if errors := h.validator.ValidateUUID(poolID, "poolID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching phone pool with ID [%s]", spew.Sdump(errors), poolID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching phone pool")
+	}
+
+	pool, err := h.service.Load(ctx, h.userIDFomContext(c), uuid.MustParse(poolID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone pool with ID [%s]", poolID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load phone pool with ID [%s]", poolID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "phone pool fetched successfully", pool)
+}
+
+// Store a phone pool
+// @Summary      Store a phone pool
+// @Description  Group phones of the authenticated user into a pool which shares the load of sending messages
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.PhonePoolStore  		true "Payload of the phone pool"
+// @Success      201 		{object}	responses.PhonePoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools [post]
+func (h *PhonePoolHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.PhonePoolStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing phone pool [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing phone pool")
+	}
+
+	pool, err := h.service.Store(ctx, request.ToUpsertParams(h.userIDFomContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store phone pool with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "phone pool created successfully", pool)
+}
+
+// Update a phone pool
+// @Summary      Update a phone pool
+// @Description  Update a phone pool of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param 		 poolID 	path		string 							true 	"ID of the phone pool"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.PhonePoolStore  		true "Payload of the phone pool"
+// @Success      200 		{object}	responses.PhonePoolResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools/{poolID} [put]
+func (h *PhonePoolHandler) Update(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	poolID := c.Params("poolID")
+	if errors := h.validator.ValidateUUID(poolID, "poolID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating phone pool with ID [%s]", spew.Sdump(errors), poolID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating phone pool")
+	}
+
+	var request requests.PhonePoolStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating phone pool [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating phone pool")
+	}
+
+	pool, err := h.service.Update(ctx, uuid.MustParse(poolID), request.ToUpsertParams(h.userIDFomContext(c)))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone pool with ID [%s]", poolID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update phone pool with ID [%s]", poolID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "phone pool updated successfully", pool)
+}
+
+// Delete a phone pool
+// @Summary      Delete a phone pool
+// @Description  Delete a phone pool of the authenticated user
+// @Security	 ApiKeyAuth
+// @Tags         PhonePools
+// @Accept       json
+// @Produce      json
+// @Param 		 poolID 	path		string 							true 	"ID of the phone pool"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /phone-pools/{poolID} [delete]
+func (h *PhonePoolHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	poolID := c.Params("poolID")
+	if errors := h.validator.ValidateUUID(poolID, "poolID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting phone pool with ID [%s]", spew.Sdump(errors), poolID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting phone pool")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(poolID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find phone pool with ID [%s]", poolID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete phone pool with ID [%s]", poolID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "phone pool deleted successfully")
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// SuppressionHandler handles suppression list requests
+type SuppressionHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	service   *services.SuppressionService
+	validator *validators.SuppressionHandlerValidator
+}
+
+// NewSuppressionHandler creates a new SuppressionHandler
+func NewSuppressionHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.SuppressionService,
+	validator *validators.SuppressionHandlerValidator,
+) (h *SuppressionHandler) {
+	return &SuppressionHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		service:   service,
+		validator: validator,
+	}
+}
+
+// RegisterRoutes registers the routes for the SuppressionHandler
+func (h *SuppressionHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/suppressions", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/suppressions", middlewares, h.Store)
+	h.register(router, fiber.MethodDelete, "/v1/suppressions/:suppressionID", middlewares, h.Delete)
+}
+
+// Index returns the suppressed contacts of a user
+// @Summary      Get suppressed contacts
+// @Description  Get the contacts which opted out of receiving messages from the phones of a user
+// @Security	 ApiKeyAuth
+// @Tags         Suppressions
+// @Accept       json
+// @Produce      json
+// @Param        owner		query  string  	false 	"filter suppressions by the owner phone number"	default(+18005550199)
+// @Param        skip		query  int  	false	"number of suppressions to skip"		minimum(0)
+// @Param        query		query  string  	false 	"filter suppressions containing query"
+// @Param        limit		query  int  	false	"number of suppressions to return"	minimum(1)	maximum(1000)
+// @Success      200 		{object}	responses.SuppressionsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /suppressions 	[get]
+func (h *SuppressionHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.SuppressionIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching suppressions [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching suppressions")
+	}
+
+	suppressions, err := h.service.Index(ctx, h.userIDFomContext(c), request.Owner, request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get suppressions with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d %s", len(suppressions), h.pluralize("suppression", len(suppressions))), suppressions)
+}
+
+// Store adds contacts to the suppression list
+// @Summary      Add suppressed contacts
+// @Description  Add contacts to the suppression list of a phone. Messages will not be sent to these contacts from the phone.
+// @Security	 ApiKeyAuth
+// @Tags         Suppressions
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.SuppressionStore  		true "Payload of the suppression request"
+// @Success      200 		{object}	responses.SuppressionsResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401	    {object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /suppressions [post]
+func (h *SuppressionHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.SuppressionStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing suppressions [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing suppressions")
+	}
+
+	suppressions, err := h.service.Store(ctx, request.ToStoreParams(h.userIDFomContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store suppressions with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("added %d %s to the suppression list", len(suppressions), h.pluralize("contact", len(suppressions))), suppressions)
+}
+
+// Delete a suppression
+// @Summary      Delete suppression
+// @Description  Remove a contact from the suppression list so that messages can be sent to it again
+// @Security	 ApiKeyAuth
+// @Tags         Suppressions
+// @Accept       json
+// @Produce      json
+// @Param 		 suppressionID 	path		string 							true 	"ID of the suppression"	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204		{object}    responses.NoContent
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      404		{object}	responses.NotFound
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /suppressions/{suppressionID} [delete]
+func (h *SuppressionHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	suppressionID := c.Params("suppressionID")
+	if errors := h.validator.ValidateUUID(suppressionID, "suppressionID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting suppression with ID [%s]", spew.Sdump(errors), suppressionID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting suppression")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(suppressionID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find suppression with ID [%s]", suppressionID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete suppression with ID [%s]", suppressionID))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "suppression deleted successfully")
+}
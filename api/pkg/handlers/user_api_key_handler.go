@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// UserAPIKeyHandler handles user API key http requests
+type UserAPIKeyHandler struct {
+	handler
+	logger    telemetry.Logger
+	tracer    telemetry.Tracer
+	validator *validators.UserAPIKeyHandlerValidator
+	service   *services.UserAPIKeyService
+}
+
+// NewUserAPIKeyHandler creates a new UserAPIKeyHandler
+func NewUserAPIKeyHandler(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	validator *validators.UserAPIKeyHandlerValidator,
+	service *services.UserAPIKeyService,
+) (h *UserAPIKeyHandler) {
+	return &UserAPIKeyHandler{
+		logger:    logger.WithService(fmt.Sprintf("%T", h)),
+		tracer:    tracer,
+		validator: validator,
+		service:   service,
+	}
+}
+
+// RegisterRoutes registers the routes for the UserAPIKeyHandler
+func (h *UserAPIKeyHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.Handler) {
+	h.register(router, fiber.MethodGet, "/v1/user-api-keys", middlewares, h.Index)
+	h.register(router, fiber.MethodPost, "/v1/user-api-keys", middlewares, h.Store)
+	h.register(router, fiber.MethodDelete, "/v1/user-api-keys/:userAPIKeyID", middlewares, h.Delete)
+}
+
+// Index returns the user API keys of a user
+// @Summary      Get the user API keys of a user
+// @Description  Get the scoped API keys which a user has created for their integrations
+// @Security	 ApiKeyAuth
+// @Tags         UserAPIKeys
+// @Accept       json
+// @Produce      json
+// @Param        skip		query  int  	false	"number of user api keys to skip"					minimum(0)
+// @Param        query		query  string  	false 	"filter user api keys with name containing query"
+// @Param        limit		query  int  	false	"number of user api keys to return"					minimum(1)	maximum(100)
+// @Success      200 		{object}	responses.UserAPIKeysResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /user-api-keys [get]
+func (h *UserAPIKeyHandler) Index(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.UserAPIKeyIndex
+	if err := c.Bind().Query(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall URL [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateIndex(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while fetching user API keys [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while fetching user API keys")
+	}
+
+	apiKeys, err := h.service.Index(ctx, h.userIDFomContext(c), request.ToIndexParams())
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot index user API keys with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, fmt.Sprintf("fetched %d user API %s", len(apiKeys), h.pluralize("key", len(apiKeys))), apiKeys)
+}
+
+// Store a user API key
+// @Summary      Store a user API key
+// @Description  Creates a new API key which is limited to a set of scopes, optionally to some of your phone numbers and optionally expires
+// @Security	 ApiKeyAuth
+// @Tags         UserAPIKeys
+// @Accept       json
+// @Produce      json
+// @Param        payload   	body 		requests.UserAPIKeyStore 	true 	"Payload of new user API key."
+// @Success      201 		{object}	responses.UserAPIKeyResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /user-api-keys [post]
+func (h *UserAPIKeyHandler) Store(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.UserAPIKeyStore
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.Body(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	request = request.Sanitize()
+	if errors := h.validator.ValidateStore(ctx, h.userIDFomContext(c), request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while storing user API key [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while storing user API key")
+	}
+
+	userAPIKey, err := h.service.Create(ctx, request.ToCreateParams(h.userFromContext(c)))
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store user API key with params [%+#v]", request))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseCreated(c, "user API key created successfully", userAPIKey)
+}
+
+// Delete a user API key
+// @Summary      Delete a user API key
+// @Description  Delete a user API key so that it cannot be used for authentication anymore.
+// @Security	 ApiKeyAuth
+// @Tags         UserAPIKeys
+// @Accept       json
+// @Produce      json
+// @Param 		 userAPIKeyID 	path	string 		true 	"ID of the user API key" 	default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Success      204  		{object} 	responses.NoContent
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure 	 404		{object}	responses.NotFound
+// @Failure      422  		{object} 	responses.UnprocessableEntity
+// @Failure      500  		{object}  	responses.InternalServerError
+// @Router       /user-api-keys/{userAPIKeyID} [delete]
+func (h *UserAPIKeyHandler) Delete(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	userAPIKeyID := c.Params("userAPIKeyID")
+	if errors := h.validator.ValidateUUID(userAPIKeyID, "userAPIKeyID"); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while deleting a user API key with ID [%s]", spew.Sdump(errors), userAPIKeyID))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while deleting user API key")
+	}
+
+	err := h.service.Delete(ctx, h.userIDFomContext(c), uuid.MustParse(userAPIKeyID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return h.responseNotFound(c, fmt.Sprintf("cannot find user API key with ID [%s]", userAPIKeyID))
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete user API key with ID [%s] for user with ID [%s]", userAPIKeyID, h.userIDFomContext(c)))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseNoContent(c, "user API key deleted successfully")
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/middlewares"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/httpsms/pkg/validators"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAPIKeyScopes_ReadOnlyKeyCannotSendMessages(t *testing.T) {
+	logger := &messageThreadHandlerNoopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	scopes := middlewares.Scopes(tracer, entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, nil)
+	messageHandler := NewMessageHandler(logger, tracer, nil, nil, nil, nil, nil)
+	contactGroupHandler := NewContactGroupHandler(logger, tracer, nil, nil, nil, nil, nil)
+
+	// the key can change contacts but sending to a contact group needs the send scope of the route
+	app := userAPIKeyScopesTestApp(entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeContactsWrite)
+	messageHandler.RegisterRoutes(app, func(c fiber.Ctx) error { return c.Next() }, middlewares.Scopes(tracer, entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, messageHandler.RouteScopes()))
+	NewCampaignHandler(logger, tracer, nil, nil).RegisterRoutes(app, scopes)
+	NewBulkJobHandler(logger, tracer, nil, nil, nil).RegisterRoutes(app, scopes)
+	NewAutoReplyRuleHandler(logger, tracer, nil, nil).RegisterRoutes(app, scopes)
+	contactGroupHandler.RegisterRoutes(app, middlewares.Scopes(tracer, entities.UserAPIKeyScopeContactsRead, entities.UserAPIKeyScopeContactsWrite, contactGroupHandler.RouteScopes()))
+
+	paths := []string{
+		"/v1/messages/send",
+		"/v1/messages/bulk-send",
+		"/v1/campaigns/" + uuid.NewString() + "/resume",
+		"/v1/bulk-jobs",
+		"/v1/auto-reply-rules",
+		"/v1/groups/" + uuid.NewString() + "/send",
+	}
+	for _, path := range paths {
+		response := userAPIKeyScopesTestRequest(t, app, http.MethodPost, path)
+		require.Equal(t, http.StatusForbidden, response.StatusCode, path)
+	}
+}
+
+func TestUserAPIKeyScopes_ReadOnlyKeyCanCalculateMessageSegments(t *testing.T) {
+	logger := &messageThreadHandlerNoopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	validator := validators.NewMessageHandlerValidator(logger, tracer, nil, nil, nil, nil, nil, nil, false)
+	messageHandler := NewMessageHandler(logger, tracer, validator, nil, nil, nil, nil)
+
+	app := userAPIKeyScopesTestApp(entities.UserAPIKeyScopeMessagesRead)
+	messageHandler.RegisterRoutes(app, func(c fiber.Ctx) error { return c.Next() }, middlewares.Scopes(tracer, entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, messageHandler.RouteScopes()))
+
+	require.Equal(t, http.StatusOK, userAPIKeyScopesTestRequest(t, app, http.MethodPost, "/v1/messages/segments").StatusCode)
+	require.Equal(t, http.StatusForbidden, userAPIKeyScopesTestRequest(t, app, http.MethodDelete, "/v1/messages/"+uuid.NewString()).StatusCode)
+}
+
+func userAPIKeyScopesTestApp(scopes ...entities.UserAPIKeyScope) *fiber.App {
+	userAPIKeyID := uuid.New()
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals(middlewares.ContextKeyAuthUserID, entities.AuthContext{ID: "user-id", Email: "user@example.com", UserAPIKeyID: &userAPIKeyID, Scopes: scopes})
+		return c.Next()
+	})
+	return app
+}
+
+func userAPIKeyScopesTestRequest(t *testing.T, app *fiber.App, method string, path string) *http.Response {
+	request := httptest.NewRequest(method, path, bytes.NewBufferString(`{"content":"Hello, world"}`))
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := app.Test(request, fiber.TestConfig{Timeout: time.Second})
+	require.NoError(t, err)
+	return response
+}
@@ -45,6 +45,7 @@ func (h *UserHandler) RegisterRoutes(router fiber.Router, middlewares ...fiber.H
 	h.register(router, fiber.MethodDelete, "/v1/users/me", middlewares, h.Delete)
 	h.register(router, fiber.MethodDelete, "/v1/users/:userID/api-keys", middlewares, h.DeleteAPIKey)
 	h.register(router, fiber.MethodPut, "/v1/users/:userID/notifications", middlewares, h.UpdateNotifications)
+	h.register(router, fiber.MethodPut, "/v1/users/:userID/retention", middlewares, h.UpdateRetention)
 	h.register(router, fiber.MethodGet, "/v1/users/subscription-update-url", middlewares, h.subscriptionUpdateURL)
 	h.register(router, fiber.MethodDelete, "/v1/users/subscription", middlewares, h.cancelSubscription)
 	h.register(router, fiber.MethodGet, "/v1/users/subscription/payments", middlewares, h.subscriptionPayments)
@@ -175,6 +176,45 @@ func (h *UserHandler) UpdateNotifications(c fiber.Ctx) error {
 	return h.responseOK(c, "user notification settings updated successfully", user)
 }
 
+// UpdateRetention an entities.User
+// @Summary      Update data retention settings
+// @Description  Update how long the messages, heartbeats and phone notifications of a user are kept. Data which is older than the retention period is deleted by a background job.
+// @Security	 ApiKeyAuth
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param 		 userID 	path		string 							true 	"ID of the user to update" 				default(32343a19-da5e-4b1b-a767-3298a73703ca)
+// @Param        payload   	body 		requests.UserRetentionUpdate	true 	"User retention settings to update"
+// @Success      200 		{object}	responses.UserResponse
+// @Failure      400		{object}	responses.BadRequest
+// @Failure 	 401    	{object}	responses.Unauthorized
+// @Failure      422		{object}	responses.UnprocessableEntity
+// @Failure      500		{object}	responses.InternalServerError
+// @Router       /users/{userID}/retention [put]
+func (h *UserHandler) UpdateRetention(c fiber.Ctx) error {
+	ctx, span, ctxLogger := h.tracer.StartFromFiberCtxWithLogger(c, h.logger)
+	defer span.End()
+
+	var request requests.UserRetentionUpdate
+	if err := c.Bind().Body(&request); err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot marshall params [%s] into %T", c.OriginalURL(), request))
+		return h.responseBadRequest(c, err)
+	}
+
+	if errors := h.validator.ValidateRetentionUpdate(ctx, request); len(errors) != 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("validation errors [%s], while updating retention settings [%+#v]", spew.Sdump(errors), request))
+		return h.responseUnprocessableEntity(c, errors, "validation errors while updating retention settings")
+	}
+
+	user, err := h.service.UpdateRetentionSettings(ctx, h.userIDFomContext(c), request.ToUserRetentionUpdateParams())
+	if err != nil {
+		ctxLogger.Error(h.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update retention settings for [%T] with ID [%s]", user, h.userIDFomContext(c))))
+		return h.responseInternalServerError(c)
+	}
+
+	return h.responseOK(c, "user retention settings updated successfully", user)
+}
+
 // subscriptionUpdateURL returns the subscription update URL for the authenticated entities.User
 // @Summary      Currently authenticated user subscription update URL
 // @Description  Fetches the subscription URL of the authenticated user.
@@ -283,7 +323,7 @@ func (h *UserHandler) subscriptionPayments(c fiber.Ctx) error {
 
 	invoices, err := h.service.GetSubscriptionPayments(ctx, h.userIDFomContext(c))
 	if err != nil {
-		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get current subscription invoices for user [%s]", h.userFromContext(c)))
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot get current subscription invoices for user [%s]", h.userIDFomContext(c)))
 		return h.responseInternalServerError(c)
 	}
 
@@ -323,7 +363,7 @@ func (h *UserHandler) subscriptionInvoice(c fiber.Ctx) error {
 
 	reader, err := h.service.GenerateReceipt(ctx, request.UserInvoiceGenerateParams(h.userIDFomContext(c)))
 	if err != nil {
-		ctxLogger.Error(stacktrace.Propagatef(err, "cannot generate receipt for invoice ID [%s] and user [%s]", request.SubscriptionInvoiceID, h.userFromContext(c)))
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot generate receipt for invoice ID [%s] and user [%s]", request.SubscriptionInvoiceID, h.userIDFomContext(c)))
 		return h.responseInternalServerError(c)
 	}
 
@@ -0,0 +1,107 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// AutoReplyListener handles cloud events which need to trigger or update auto reply rules
+type AutoReplyListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.AutoReplyService
+}
+
+// NewAutoReplyListener creates a new instance of AutoReplyListener
+func NewAutoReplyListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.AutoReplyService,
+) (l *AutoReplyListener, routes map[string]events.EventListener) {
+	l = &AutoReplyListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.EventTypeMessagePhoneReceived: l.onMessagePhoneReceived,
+		events.MessageCallMissed:             l.onMessageCallMissed,
+		events.EventTypePhoneDeleted:         l.onPhoneDeleted,
+		events.UserAccountDeleted:            l.onUserAccountDeleted,
+	}
+}
+
+// onMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
+func (listener *AutoReplyListener) onMessagePhoneReceived(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.MessagePhoneReceivedPayload)
+	if err := event.DataAs(payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Respond(ctx, event.Source(), payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot send auto reply for [%s] event with ID [%s] and message [%s]", event.Type(), event.ID(), payload.MessageID))
+	}
+
+	return nil
+}
+
+// onMessageCallMissed handles the events.MessageCallMissed event
+func (listener *AutoReplyListener) onMessageCallMissed(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	payload := new(events.MessageCallMissedPayload)
+	if err := event.DataAs(payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.RespondToMissedCall(ctx, event.Source(), payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot send auto reply for [%s] event with ID [%s] and message [%s]", event.Type(), event.ID(), payload.MessageID))
+	}
+
+	return nil
+}
+
+// onPhoneDeleted handles the events.EventTypePhoneDeleted event
+func (listener *AutoReplyListener) onPhoneDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.PhoneDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteAllForOwner(ctx, payload.UserID, payload.Owner); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete auto reply rules of phone [%s] for [%s] event with ID [%s]", payload.Owner, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onUserAccountDeleted handles the events.UserAccountDeleted event
+func (listener *AutoReplyListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.AutoReplyRule] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
@@ -34,11 +34,30 @@ func NewBillingListener(
 
 	return l, map[string]events.EventListener{
 		events.EventTypeMessageAPISent:       l.OnMessageAPISent,
+		events.MessageAPICanceled:            l.OnMessageAPICanceled,
+		events.MessageAPIRescheduled:         l.OnMessageAPIRescheduled,
 		events.UserAccountDeleted:            l.onUserAccountDeleted,
 		events.EventTypeMessagePhoneReceived: l.OnMessagePhoneReceived,
 	}
 }
 
+// OnMessageAPICanceled handles the events.MessageAPICanceled event
+func (listener *BillingListener) OnMessageAPICanceled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPICanceledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.UnregisterSentMessage(ctx, payload.MessageID, payload.RequestReceivedAt, payload.UserID, payload.Segments); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot unregister canceled message for event [%s] for event with ID [%s]", spew.Sdump(payload), event.ID()))
+	}
+
+	return nil
+}
+
 // OnMessageAPISent handles the events.EventTypeMessageAPISent event
 func (listener *BillingListener) OnMessageAPISent(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -49,13 +68,30 @@ func (listener *BillingListener) OnMessageAPISent(ctx context.Context, event clo
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
 	}
 
-	if err := listener.service.RegisterSentMessage(ctx, payload.MessageID, payload.RequestReceivedAt, payload.UserID); err != nil {
+	if err := listener.service.RegisterSentMessage(ctx, payload.MessageID, payload.RequestReceivedAt, payload.UserID, payload.Segments); err != nil {
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot register sent message for event [%s] for event with ID [%s]", spew.Sdump(payload), event.ID()))
 	}
 
 	return nil
 }
 
+// OnMessageAPIRescheduled handles the events.MessageAPIRescheduled event
+func (listener *BillingListener) OnMessageAPIRescheduled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPIRescheduledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.RegisterRescheduledMessage(ctx, payload.UserID, payload.MessageID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot register rescheduled message for event [%s] for event with ID [%s]", spew.Sdump(payload), event.ID()))
+	}
+
+	return nil
+}
+
 // OnMessagePhoneReceived handles the events.EventTypeMessagePhoneReceived event
 func (listener *BillingListener) OnMessagePhoneReceived(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -0,0 +1,133 @@
+package listeners
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listenerBillingUsageRepository struct {
+	repositories.BillingUsageRepository
+	timestamp    time.Time
+	segments     uint
+	registered   bool
+	unregistered bool
+}
+
+func (repository *listenerBillingUsageRepository) RegisterSentMessage(_ context.Context, timestamp time.Time, _ entities.UserID, segments uint) error {
+	repository.registered = true
+	repository.timestamp = timestamp
+	repository.segments = segments
+	return nil
+}
+
+func (repository *listenerBillingUsageRepository) UnregisterSentMessage(_ context.Context, timestamp time.Time, _ entities.UserID, segments uint) error {
+	repository.unregistered = true
+	repository.timestamp = timestamp
+	repository.segments = segments
+	return nil
+}
+
+type listenerBillingMessageRepository struct {
+	repositories.MessageRepository
+	status     entities.MessageStatus
+	registered bool
+}
+
+func (repository *listenerBillingMessageRepository) RegisterUsage(_ context.Context, _ entities.UserID, _ uuid.UUID, _ time.Time) (bool, error) {
+	if repository.registered || repository.status == entities.MessageStatusCanceled || repository.status == entities.MessageStatusPaused {
+		return false, nil
+	}
+	repository.registered = true
+	return true, nil
+}
+
+func (repository *listenerBillingMessageRepository) UnregisterUsage(_ context.Context, _ entities.UserID, _ uuid.UUID) (bool, error) {
+	if !repository.registered {
+		return false, nil
+	}
+	repository.registered = false
+	return true, nil
+}
+
+func newBillingListenerForTest(usageRepository *listenerBillingUsageRepository, messageRepository *listenerBillingMessageRepository) map[string]events.EventListener {
+	logger := &noopListenerLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	_, routes := NewBillingListener(logger, tracer, services.NewBillingService(logger, tracer, nil, nil, nil, usageRepository, nil, messageRepository))
+	return routes
+}
+
+func newBillingListenerCanceledEventForTest(t *testing.T, requestReceivedAt time.Time) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetSource("/v1/messages/:messageID/cancel")
+	event.SetType(events.MessageAPICanceled)
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, events.MessageAPICanceledPayload{
+		MessageID:         uuid.New(),
+		UserID:            entities.UserID("user-id"),
+		Owner:             "+18005550199",
+		Contact:           "+18005550100",
+		Timestamp:         requestReceivedAt.Add(time.Hour),
+		Segments:          2,
+		RequestReceivedAt: requestReceivedAt,
+	}))
+	return event
+}
+
+func TestBillingListenerUnregistersCanceledMessage(t *testing.T) {
+	repository := &listenerBillingUsageRepository{}
+	routes := newBillingListenerForTest(repository, &listenerBillingMessageRepository{registered: true})
+
+	requestReceivedAt := time.Date(2026, 7, 18, 6, 59, 0, 0, time.UTC)
+	event := newBillingListenerCanceledEventForTest(t, requestReceivedAt)
+	require.Contains(t, routes, events.MessageAPICanceled)
+
+	err := routes[events.MessageAPICanceled](context.Background(), event)
+
+	require.NoError(t, err)
+	assert.Equal(t, requestReceivedAt, repository.timestamp)
+	assert.Equal(t, uint(2), repository.segments)
+}
+
+func TestBillingListenerDoesNotUnregisterMessageWhichWasNotRegistered(t *testing.T) {
+	repository := &listenerBillingUsageRepository{}
+	routes := newBillingListenerForTest(repository, &listenerBillingMessageRepository{})
+	event := newBillingListenerCanceledEventForTest(t, time.Date(2026, 7, 18, 6, 59, 0, 0, time.UTC))
+
+	err := routes[events.MessageAPICanceled](context.Background(), event)
+
+	require.NoError(t, err)
+	assert.False(t, repository.unregistered)
+}
+
+func TestBillingListenerDoesNotRegisterMessageWhichWasCanceledBeforeTheEvent(t *testing.T) {
+	repository := &listenerBillingUsageRepository{}
+	routes := newBillingListenerForTest(repository, &listenerBillingMessageRepository{status: entities.MessageStatusCanceled})
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetSource("/v1/messages/send")
+	event.SetType(events.EventTypeMessageAPISent)
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, events.MessageAPISentPayload{
+		MessageID:         uuid.New(),
+		UserID:            entities.UserID("user-id"),
+		Owner:             "+18005550199",
+		Contact:           "+18005550100",
+		Segments:          1,
+		RequestReceivedAt: time.Date(2026, 7, 18, 6, 59, 0, 0, time.UTC),
+	}))
+
+	err := routes[events.EventTypeMessageAPISent](context.Background(), event)
+
+	require.NoError(t, err)
+	assert.False(t, repository.registered)
+}
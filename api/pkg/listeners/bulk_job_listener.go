@@ -0,0 +1,102 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// BulkJobListener handles cloud events related to large bulk SMS files
+type BulkJobListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.BulkJobService
+}
+
+// NewBulkJobListener creates a new instance of BulkJobListener
+func NewBulkJobListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.BulkJobService,
+) (l *BulkJobListener, routes map[string]events.EventListener) {
+	l = &BulkJobListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.BulkJobCreated:     l.onBulkJobCreated,
+		events.BulkJobProcessing:  l.onBulkJobProcessing,
+		events.UserAccountDeleted: l.onUserAccountDeleted,
+	}
+}
+
+// onBulkJobCreated handles the events.BulkJobCreated event
+func (listener *BulkJobListener) onBulkJobCreated(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.BulkJobCreatedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	params := &services.BulkJobProcessParams{
+		UserID:    payload.UserID,
+		BulkJobID: payload.BulkJobID,
+		Source:    payload.Source,
+	}
+
+	if err := listener.service.Process(ctx, params); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot process bulk job [%s] for user [%s] on [%s] event with ID [%s]", payload.BulkJobID, payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onBulkJobProcessing handles the events.BulkJobProcessing event
+func (listener *BulkJobListener) onBulkJobProcessing(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.BulkJobProcessingPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	params := &services.BulkJobProcessParams{
+		UserID:    payload.UserID,
+		BulkJobID: payload.BulkJobID,
+		Source:    payload.Source,
+		RowOffset: payload.RowOffset,
+	}
+
+	if err := listener.service.Process(ctx, params); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot process bulk job [%s] at row offset [%d] for user [%s] on [%s] event with ID [%s]", payload.BulkJobID, payload.RowOffset, payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onUserAccountDeleted handles the events.UserAccountDeleted event
+func (listener *BulkJobListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.BulkJob] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
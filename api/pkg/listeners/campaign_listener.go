@@ -0,0 +1,179 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// CampaignListener handles cloud events related to the campaigns of a user
+type CampaignListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.CampaignService
+}
+
+// NewCampaignListener creates a new instance of CampaignListener
+func NewCampaignListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.CampaignService,
+) (l *CampaignListener, routes map[string]events.EventListener) {
+	l = &CampaignListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.CampaignAPIResumed:             l.onCampaignAPIResumed,
+		events.CampaignAPICanceled:            l.onCampaignAPICanceled,
+		events.EventTypeMessagePhoneSent:      l.onMessagePhoneSent,
+		events.EventTypeMessagePhoneDelivered: l.onMessagePhoneDelivered,
+		events.EventTypeMessageSendFailed:     l.onMessageSendFailed,
+		events.EventTypeMessageSendExpired:    l.onMessageSendExpired,
+		events.MessageAPICanceled:             l.onMessageAPICanceled,
+		events.UserAccountDeleted:             l.onUserAccountDeleted,
+	}
+}
+
+// onCampaignAPIResumed handles the events.CampaignAPIResumed event
+func (listener *CampaignListener) onCampaignAPIResumed(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.CampaignAPIResumedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.ResumeMessages(ctx, event.Source(), &payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot resume messages of campaign [%s] on [%s] event with ID [%s]", payload.CampaignID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onCampaignAPICanceled handles the events.CampaignAPICanceled event
+func (listener *CampaignListener) onCampaignAPICanceled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.CampaignAPICanceledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.CancelMessages(ctx, event.Source(), &payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot cancel messages of campaign [%s] on [%s] event with ID [%s]", payload.CampaignID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessagePhoneSent handles the events.EventTypeMessagePhoneSent event
+func (listener *CampaignListener) onMessagePhoneSent(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessagePhoneSentPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Complete(ctx, payload.UserID, payload.RequestID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot complete campaign for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessagePhoneDelivered handles the events.EventTypeMessagePhoneDelivered event
+func (listener *CampaignListener) onMessagePhoneDelivered(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessagePhoneDeliveredPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Complete(ctx, payload.UserID, payload.RequestID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot complete campaign for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessageSendFailed handles the events.EventTypeMessageSendFailed event
+func (listener *CampaignListener) onMessageSendFailed(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendFailedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Complete(ctx, payload.UserID, payload.RequestID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot complete campaign for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessageSendExpired handles the events.EventTypeMessageSendExpired event
+func (listener *CampaignListener) onMessageSendExpired(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendExpiredPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Complete(ctx, payload.UserID, payload.RequestID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot complete campaign for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessageAPICanceled handles the events.MessageAPICanceled event
+func (listener *CampaignListener) onMessageAPICanceled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPICanceledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Complete(ctx, payload.UserID, payload.RequestID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot complete campaign for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onUserAccountDeleted handles the events.UserAccountDeleted event
+func (listener *CampaignListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.Campaign] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// JobListener runs the periodic jobs
+type JobListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.JobService
+}
+
+// NewJobListener creates a new instance of JobListener
+func NewJobListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.JobService,
+) (l *JobListener, routes map[string]events.EventListener) {
+	l = &JobListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.JobRun: l.onJobRun,
+	}
+}
+
+// onJobRun handles the events.JobRun event
+func (listener *JobListener) onJobRun(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.JobRunPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Run(ctx, event.Source(), &payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot run job [%s] for event with ID [%s]", payload.Name, event.ID()))
+	}
+
+	return nil
+}
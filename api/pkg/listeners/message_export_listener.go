@@ -0,0 +1,71 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// MessageExportListener handles cloud events related to exports of the message history
+type MessageExportListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.MessageExportService
+}
+
+// NewMessageExportListener creates a new instance of MessageExportListener
+func NewMessageExportListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.MessageExportService,
+) (l *MessageExportListener, routes map[string]events.EventListener) {
+	l = &MessageExportListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.MessageExportCreated: l.onMessageExportCreated,
+		events.UserAccountDeleted:   l.onUserAccountDeleted,
+	}
+}
+
+// onMessageExportCreated handles the events.MessageExportCreated event
+func (listener *MessageExportListener) onMessageExportCreated(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageExportCreatedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Process(ctx, &payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot process message export [%s] for user [%s] on [%s] event with ID [%s]", payload.MessageExportID, payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onUserAccountDeleted handles the events.UserAccountDeleted event
+func (listener *MessageExportListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.MessageExport] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
@@ -42,7 +42,6 @@ func NewMessageListener(
 		events.EventTypeMessageSendExpired:           l.onMessageSendExpired,
 		events.EventTypeMessageNotificationScheduled: l.onMessageNotificationScheduled,
 		events.MessageThreadAPIDeleted:               l.onMessageThreadAPIDeleted,
-		events.MessageCallMissed:                     l.onMessageCallMissed,
 		events.UserAccountDeleted:                    l.onUserAccountDeleted,
 	}
 }
@@ -290,23 +289,6 @@ func (listener *MessageListener) onMessageThreadAPIDeleted(ctx context.Context,
 	return nil
 }
 
-// onMessageThreadAPIDeleted handles the events.MessageThreadAPIDeleted event
-func (listener *MessageListener) onMessageCallMissed(ctx context.Context, event cloudevents.Event) error {
-	ctx, span := listener.tracer.Start(ctx)
-	defer span.End()
-
-	payload := new(events.MessageCallMissedPayload)
-	if err := event.DataAs(payload); err != nil {
-		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
-	}
-
-	if err := listener.service.RespondToMissedCall(ctx, event.Source(), payload); err != nil {
-		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot handle [%s] event with ID [%s] and userID [%s]", event.Type(), event.ID(), payload.UserID))
-	}
-
-	return nil
-}
-
 func (listener *MessageListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
 	defer span.End()
@@ -7,6 +7,7 @@ import (
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
 	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/NdoleStudio/stacktrace"
@@ -15,9 +16,10 @@ import (
 
 // MessageThreadListener handles cloud events which need to update entities.MessageThread
 type MessageThreadListener struct {
-	logger  telemetry.Logger
-	tracer  telemetry.Tracer
-	service *services.MessageThreadService
+	logger         telemetry.Logger
+	tracer         telemetry.Tracer
+	service        *services.MessageThreadService
+	messageService *services.MessageService
 }
 
 // NewMessageThreadListener creates a new instance of MessageThreadListener
@@ -25,11 +27,13 @@ func NewMessageThreadListener(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	service *services.MessageThreadService,
+	messageService *services.MessageService,
 ) (l *MessageThreadListener, routes map[string]events.EventListener) {
 	l = &MessageThreadListener{
-		logger:  logger.WithService(fmt.Sprintf("%T", l)),
-		tracer:  tracer,
-		service: service,
+		logger:         logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:         tracer,
+		service:        service,
+		messageService: messageService,
 	}
 
 	return l, map[string]events.EventListener{
@@ -43,13 +47,15 @@ func NewMessageThreadListener(
 		events.MessageCallMissed:                     l.OnMessageCallMissed,
 		events.EventTypeMessageNotificationScheduled: l.onMessageNotificationScheduled,
 		events.EventTypeMessageSendExpired:           l.onMessageExpired,
+		events.EventTypeMessageSendRerouted:          l.onMessageSendRerouted,
+		events.MessageAPICanceled:                    l.onMessageAPICanceled,
 		events.UserAccountDeleted:                    l.onUserAccountDeleted,
 	}
 }
 
 // OnMessageAPISent handles the events.EventTypeMessageAPISent event
 func (listener *MessageThreadListener) OnMessageAPISent(ctx context.Context, event cloudevents.Event) error {
-	ctx, span := listener.tracer.Start(ctx)
+	ctx, span, ctxLogger := listener.tracer.StartWithLogger(ctx, listener.logger)
 	defer span.End()
 
 	var payload events.MessageAPISentPayload
@@ -57,6 +63,21 @@ func (listener *MessageThreadListener) OnMessageAPISent(ctx context.Context, eve
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
 	}
 
+	message, err := listener.messageService.GetMessage(ctx, payload.UserID, payload.MessageID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] for event with ID [%s] was deleted, not updating the thread", payload.MessageID, event.ID()))
+		return nil
+	}
+
+	if err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load message with ID [%s] for event with ID [%s]", payload.MessageID, event.ID()))
+	}
+
+	if message.IsCanceled() || message.IsPaused() {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] has status [%s], not updating the thread for event with ID [%s]", message.ID, message.Status, event.ID()))
+		return nil
+	}
+
 	updateParams := services.MessageThreadUpdateParams{
 		Owner:     payload.Owner,
 		Contact:   payload.Contact,
@@ -74,6 +95,33 @@ func (listener *MessageThreadListener) OnMessageAPISent(ctx context.Context, eve
 	return nil
 }
 
+// onMessageSendRerouted handles the events.EventTypeMessageSendRerouted event
+func (listener *MessageThreadListener) onMessageSendRerouted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendReroutedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	updateParams := services.MessageThreadUpdateParams{
+		Owner:     payload.Owner,
+		Contact:   payload.Contact,
+		UserID:    payload.UserID,
+		Status:    entities.MessageStatusPending,
+		Timestamp: payload.Timestamp,
+		Content:   payload.Content,
+		MessageID: payload.MessageID,
+	}
+
+	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update thread for message with ID [%s] for event with ID [%s]", updateParams.MessageID, event.ID()))
+	}
+
+	return nil
+}
+
 // onMessageDeleted handles the events.MessageAPIDeleted event
 func (listener *MessageThreadListener) onMessageDeleted(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -328,3 +376,30 @@ func (listener *MessageThreadListener) onUserAccountDeleted(ctx context.Context,
 func (listener *MessageThreadListener) updateThread(ctx context.Context, params services.MessageThreadUpdateParams) error {
 	return listener.service.UpdateThread(ctx, params)
 }
+
+// onMessageAPICanceled handles the events.MessageAPICanceled event
+func (listener *MessageThreadListener) onMessageAPICanceled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPICanceledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	updateParams := services.MessageThreadUpdateParams{
+		Owner:     payload.Owner,
+		Contact:   payload.Contact,
+		UserID:    payload.UserID,
+		Status:    entities.MessageStatusCanceled,
+		Timestamp: payload.Timestamp,
+		Content:   payload.Content,
+		MessageID: payload.MessageID,
+	}
+
+	if err := listener.service.UpdateThread(ctx, updateParams); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update thread for message with ID [%s] for event with ID [%s]", updateParams.MessageID, event.ID()))
+	}
+
+	return nil
+}
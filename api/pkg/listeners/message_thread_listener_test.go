@@ -7,8 +7,10 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -62,11 +64,63 @@ func TestMessageThreadListenerMarksMissedCallUnread(t *testing.T) {
 	assert.Equal(t, event.Time(), repository.activity.EventTimestamp)
 }
 
+func TestMessageThreadListenerIgnoresAPISentEventOfCanceledOrPausedMessage(t *testing.T) {
+	for _, status := range []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusCanceled, entities.MessageStatusPaused} {
+		t.Run(string(status), func(t *testing.T) {
+			message := &entities.Message{ID: uuid.New(), UserID: "user-id", Owner: "+18005550199", Contact: "+18005550100", Status: status}
+			repository, routes := newMessageThreadListenerWithMessagesForTest(message)
+			event := cloudevents.NewEvent()
+			event.SetID(uuid.NewString())
+			event.SetSource("/v1/messages/send")
+			event.SetType(events.EventTypeMessageAPISent)
+			require.NoError(t, event.SetData(cloudevents.ApplicationJSON, events.MessageAPISentPayload{
+				MessageID:         message.ID,
+				UserID:            message.UserID,
+				Owner:             message.Owner,
+				Contact:           message.Contact,
+				Content:           "hello",
+				RequestReceivedAt: time.Date(2026, 7, 18, 6, 59, 0, 0, time.UTC),
+			}))
+
+			err := routes[events.EventTypeMessageAPISent](context.Background(), event)
+
+			require.NoError(t, err)
+			if status == entities.MessageStatusPending {
+				assert.Equal(t, message.ID, repository.activity.MessageID)
+			} else {
+				assert.Equal(t, uuid.Nil, repository.activity.MessageID)
+			}
+		})
+	}
+}
+
+type listenerMessageRepository struct {
+	repositories.MessageRepository
+	messages map[uuid.UUID]*entities.Message
+}
+
+func (repository *listenerMessageRepository) Load(_ context.Context, _ entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
+	if message, ok := repository.messages[messageID]; ok {
+		return message, nil
+	}
+	return nil, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "message not found")
+}
+
 func newMessageThreadListenerForTest() (*listenerMessageThreadRepository, map[string]events.EventListener) {
+	return newMessageThreadListenerWithMessagesForTest()
+}
+
+func newMessageThreadListenerWithMessagesForTest(messages ...*entities.Message) (*listenerMessageThreadRepository, map[string]events.EventListener) {
 	repository := &listenerMessageThreadRepository{}
+	messageRepository := &listenerMessageRepository{messages: map[uuid.UUID]*entities.Message{}}
+	for _, message := range messages {
+		messageRepository.messages[message.ID] = message
+	}
+
 	logger := &noopListenerLogger{}
 	tracer := telemetry.NewOtelLogger("test", logger)
-	service := services.NewMessageThreadService(logger, tracer, repository, nil, nil)
-	_, routes := NewMessageThreadListener(logger, tracer, service)
+	service := services.NewMessageThreadService(logger, tracer, repository, nil, nil, nil)
+	messageService := services.NewMessageService(logger, tracer, messageRepository, nil, nil, nil, nil, nil, nil, service, nil, "")
+	_, routes := NewMessageThreadListener(logger, tracer, service, messageService)
 	return repository, routes
 }
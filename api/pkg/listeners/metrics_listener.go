@@ -0,0 +1,64 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// MetricsListener records the business metrics of cloud events
+type MetricsListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.MetricsService
+}
+
+// NewMetricsListener creates a new instance of MetricsListener
+func NewMetricsListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.MetricsService,
+) (l *MetricsListener, routes map[string]events.EventListener) {
+	l = &MetricsListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.EventTypeMessageAPISent:        l.onMessageStatus(entities.MessageStatusPending),
+		events.EventTypeMessagePhoneSending:   l.onMessageStatus(entities.MessageStatusSending),
+		events.EventTypeMessagePhoneSent:      l.onMessageStatus(entities.MessageStatusSent),
+		events.EventTypeMessagePhoneDelivered: l.onMessageStatus(entities.MessageStatusDelivered),
+		events.EventTypeMessageSendFailed:     l.onMessageStatus(entities.MessageStatusFailed),
+		events.EventTypeMessageSendExpired:    l.onMessageStatus(entities.MessageStatusExpired),
+		events.EventTypeMessagePhoneReceived:  l.onMessageStatus(entities.MessageStatusReceived),
+		events.MessageAPICanceled:             l.onMessageStatus(entities.MessageStatusCanceled),
+		events.MessageAPIPaused:               l.onMessageStatus(entities.MessageStatusPaused),
+	}
+}
+
+// onMessageStatus counts the message in an event which changes the status of a message
+func (listener *MetricsListener) onMessageStatus(status entities.MessageStatus) events.EventListener {
+	return func(ctx context.Context, event cloudevents.Event) error {
+		ctx, span := listener.tracer.Start(ctx)
+		defer span.End()
+
+		// all the message events have the owner phone number in the payload
+		var payload struct {
+			Owner string `json:"owner"`
+		}
+		if err := event.DataAs(&payload); err != nil {
+			return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+		}
+
+		listener.service.RecordMessageStatus(ctx, payload.Owner, status)
+		return nil
+	}
+}
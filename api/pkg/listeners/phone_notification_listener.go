@@ -35,10 +35,14 @@ func NewNotificationListener(
 	return l, map[string]events.EventListener{
 		events.EventTypeMessageAPISent:          l.onMessageAPISent,
 		events.EventTypeMessageSendRetry:        l.onMessageSendRetry,
+		events.EventTypeMessageSendRerouted:     l.onMessageSendRerouted,
 		events.EventTypeMessageNotificationSend: l.onMessageNotificationSend,
 		events.PhoneHeartbeatMissed:             l.onPhoneHeartbeatMissed,
 		events.UserAccountDeleted:               l.onUserAccountDeleted,
 		events.MessageAPIDeleted:                l.onMessageAPIDeleted,
+		events.MessageAPICanceled:               l.onMessageAPICanceled,
+		events.MessageAPIPaused:                 l.onMessageAPIPaused,
+		events.MessageAPIRescheduled:            l.onMessageAPIRescheduled,
 	}
 }
 
@@ -100,6 +104,34 @@ func (listener *PhoneNotificationListener) onMessageSendRetry(ctx context.Contex
 	return nil
 }
 
+// onMessageSendRerouted handles the events.EventTypeMessageSendRerouted event
+func (listener *PhoneNotificationListener) onMessageSendRerouted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendReroutedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	sendParams := &services.PhoneNotificationScheduleParams{
+		UserID:    payload.UserID,
+		Owner:     payload.Owner,
+		Contact:   payload.Contact,
+		Content:   payload.Content,
+		SIM:       payload.SIM,
+		Encrypted: payload.Encrypted,
+		Source:    event.Source(),
+		MessageID: payload.MessageID,
+	}
+
+	if err := listener.service.Schedule(ctx, sendParams); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot send notification with params [%s] for event with ID [%s]", spew.Sdump(sendParams), event.ID()))
+	}
+
+	return nil
+}
+
 // onPhoneHeartbeatMissed handles the events.PhoneHeartbeatMissed event
 func (listener *PhoneNotificationListener) onPhoneHeartbeatMissed(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -175,3 +207,71 @@ func (listener *PhoneNotificationListener) onMessageAPIDeleted(ctx context.Conte
 
 	return nil
 }
+
+// onMessageAPICanceled handles the events.MessageAPICanceled event
+func (listener *PhoneNotificationListener) onMessageAPICanceled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPICanceledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteByMessageID(ctx, payload.UserID, payload.MessageID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.PhoneNotification] for user [%s] and message [%s] on [%s] event with ID [%s]", payload.UserID, payload.MessageID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessageAPIPaused handles the events.MessageAPIPaused event
+func (listener *PhoneNotificationListener) onMessageAPIPaused(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPIPausedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteByMessageID(ctx, payload.UserID, payload.MessageID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.PhoneNotification] for user [%s] and message [%s] on [%s] event with ID [%s]", payload.UserID, payload.MessageID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessageAPIRescheduled handles the events.MessageAPIRescheduled event
+func (listener *PhoneNotificationListener) onMessageAPIRescheduled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPIRescheduledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteByMessageID(ctx, payload.UserID, payload.MessageID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.PhoneNotification] for user [%s] and message [%s] on [%s] event with ID [%s]", payload.UserID, payload.MessageID, event.Type(), event.ID()))
+	}
+
+	sendParams := &services.PhoneNotificationScheduleParams{
+		UserID:            payload.UserID,
+		Owner:             payload.Owner,
+		Contact:           payload.Contact,
+		Content:           payload.Content,
+		SIM:               payload.SIM,
+		Encrypted:         payload.Encrypted,
+		Source:            event.Source(),
+		MessageID:         payload.MessageID,
+		ExactSendTime:     true,
+		ScheduledSendTime: &payload.ScheduledSendTime,
+	}
+
+	if err := listener.service.Schedule(ctx, sendParams); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot send notification with params [%s] for event with ID [%s]", spew.Sdump(sendParams), event.ID()))
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// PhonePoolListener handles cloud events related to phone pools
+type PhonePoolListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.PhonePoolService
+}
+
+// NewPhonePoolListener creates a new instance of PhonePoolListener
+func NewPhonePoolListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.PhonePoolService,
+) (l *PhonePoolListener, routes map[string]events.EventListener) {
+	l = &PhonePoolListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.EventTypePhoneDeleted: l.onPhoneDeleted,
+		events.UserAccountDeleted:    l.onUserAccountDeleted,
+	}
+}
+
+// onPhoneDeleted handles the events.EventTypePhoneDeleted event
+func (listener *PhonePoolListener) onPhoneDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.PhoneDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.RemovePhone(ctx, payload.UserID, payload.Owner); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot remove phone [%s] from phone pools for [%s] event with ID [%s]", payload.Owner, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onUserAccountDeleted handles the events.UserAccountDeleted event
+func (listener *PhonePoolListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.PhonePool] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
@@ -64,3 +64,7 @@ func (repository *listenerMessageThreadRepository) Delete(context.Context, entit
 func (repository *listenerMessageThreadRepository) DeleteAllForUser(context.Context, entities.UserID) error {
 	return nil
 }
+
+func (repository *listenerMessageThreadRepository) ReEncrypt(context.Context, entities.UserID, int) (int, error) {
+	return 0, nil
+}
@@ -0,0 +1,107 @@
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// ScheduledMessageListener handles cloud events which need to update entities.ScheduledMessage
+type ScheduledMessageListener struct {
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.ScheduledMessageService
+}
+
+// NewScheduledMessageListener creates a new instance of ScheduledMessageListener
+func NewScheduledMessageListener(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ScheduledMessageService,
+) (l *ScheduledMessageListener, routes map[string]events.EventListener) {
+	l = &ScheduledMessageListener{
+		logger:  logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:  tracer,
+		service: service,
+	}
+
+	return l, map[string]events.EventListener{
+		events.MessageAPICanceled: l.onMessageAPICanceled,
+		events.MessageAPIPaused:   l.onMessageAPIPaused,
+		events.MessageAPIDeleted:  l.onMessageAPIDeleted,
+		events.UserAccountDeleted: l.onUserAccountDeleted,
+	}
+}
+
+// onMessageAPICanceled handles the events.MessageAPICanceled event
+func (listener *ScheduledMessageListener) onMessageAPICanceled(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPICanceledPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteByMessageID(ctx, payload.UserID, payload.MessageID, ""); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.ScheduledMessage] for message [%s] on [%s] event with ID [%s]", payload.MessageID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessageAPIPaused handles the events.MessageAPIPaused event
+func (listener *ScheduledMessageListener) onMessageAPIPaused(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPIPausedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteByMessageID(ctx, payload.UserID, payload.MessageID, ""); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.ScheduledMessage] for message [%s] on [%s] event with ID [%s]", payload.MessageID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onMessageAPIDeleted handles the events.MessageAPIDeleted event
+func (listener *ScheduledMessageListener) onMessageAPIDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageAPIDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteByMessageID(ctx, payload.UserID, payload.MessageID, ""); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.ScheduledMessage] for message [%s] on [%s] event with ID [%s]", payload.MessageID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
+// onUserAccountDeleted handles the events.UserAccountDeleted event
+func (listener *ScheduledMessageListener) onUserAccountDeleted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.UserAccountDeletedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.DeleteAllForUser(ctx, payload.UserID); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.ScheduledMessage] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
+	}
+
+	return nil
+}
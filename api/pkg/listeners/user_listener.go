@@ -6,6 +6,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/events"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -13,23 +14,31 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 )
 
+// UserDataDeleter deletes the entities of a user when the account of the user is deleted
+type UserDataDeleter interface {
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
+
 // UserListener handles cloud events which sends notifications
 type UserListener struct {
-	logger  telemetry.Logger
-	tracer  telemetry.Tracer
-	service *services.UserService
+	logger   telemetry.Logger
+	tracer   telemetry.Tracer
+	service  *services.UserService
+	deleters []UserDataDeleter
 }
 
-// NewUserListener creates a new instance of UserListener
+// NewUserListener creates a new instance of UserListener, the deleters remove the data of a user whose account is deleted
 func NewUserListener(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	service *services.UserService,
+	deleters ...UserDataDeleter,
 ) (l *UserListener, routes map[string]events.EventListener) {
 	l = &UserListener{
-		logger:  logger.WithService(fmt.Sprintf("%T", l)),
-		tracer:  tracer,
-		service: service,
+		logger:   logger.WithService(fmt.Sprintf("%T", l)),
+		tracer:   tracer,
+		service:  service,
+		deleters: deleters,
 	}
 
 	return l, map[string]events.EventListener{
@@ -161,6 +170,13 @@ func (listener *UserListener) onUserAccountDeleted(ctx context.Context, event cl
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
 	}
 
+	// the data is deleted before the auth user so that the event can be retried when a deleter fails
+	for _, deleter := range listener.deleters {
+		if err := deleter.DeleteAllForUser(ctx, payload.UserID); err != nil {
+			return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete the data of user [%s] with [%T] on [%s] event with ID [%s]", payload.UserID, deleter, event.Type(), event.ID()))
+		}
+	}
+
 	if err := listener.service.DeleteAuthUser(ctx, payload.UserID); err != nil {
 		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.AuthUser] for user [%s] on [%s] event with ID [%s]", payload.UserID, event.Type(), event.ID()))
 	}
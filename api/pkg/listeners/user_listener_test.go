@@ -0,0 +1,44 @@
+package listeners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type listenerUserDataDeleter struct {
+	err     error
+	deleted []entities.UserID
+}
+
+func (deleter *listenerUserDataDeleter) DeleteAllForUser(_ context.Context, userID entities.UserID) error {
+	deleter.deleted = append(deleter.deleted, userID)
+	return deleter.err
+}
+
+func TestUserListenerStopsDeletingTheAccountWhenADeleterFails(t *testing.T) {
+	failing := &listenerUserDataDeleter{err: stacktrace.NewErrorf("cannot delete")}
+	next := &listenerUserDataDeleter{}
+	logger := &noopListenerLogger{}
+	_, routes := NewUserListener(logger, telemetry.NewOtelLogger("test", logger), nil, failing, next)
+
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetSource("/v1/users/me")
+	event.SetType(events.UserAccountDeleted)
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, events.UserAccountDeletedPayload{UserID: "user-id"}))
+
+	err := routes[events.UserAccountDeleted](context.Background(), event)
+
+	require.Error(t, err)
+	assert.Equal(t, []entities.UserID{"user-id"}, failing.deleted)
+	assert.Empty(t, next.deleted)
+}
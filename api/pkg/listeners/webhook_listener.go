@@ -33,6 +33,7 @@ func NewWebhookListener(
 	return l, map[string]events.EventListener{
 		events.EventTypeMessagePhoneReceived:  l.OnMessagePhoneReceived,
 		events.EventTypeMessageSendExpired:    l.OnMessageSendExpired,
+		events.EventTypeMessageSendRerouted:   l.onMessageSendRerouted,
 		events.EventTypeMessagePhoneDelivered: l.OnMessagePhoneDelivered,
 		events.EventTypeMessageSendFailed:     l.OnMessageSendFailed,
 		events.EventTypeMessagePhoneSent:      l.OnMessagePhoneSent,
@@ -77,6 +78,23 @@ func (listener *WebhookListener) OnMessageSendExpired(ctx context.Context, event
 	return nil
 }
 
+// onMessageSendRerouted handles the events.EventTypeMessageSendRerouted event
+func (listener *WebhookListener) onMessageSendRerouted(ctx context.Context, event cloudevents.Event) error {
+	ctx, span := listener.tracer.Start(ctx)
+	defer span.End()
+
+	var payload events.MessageSendReroutedPayload
+	if err := event.DataAs(&payload); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%s] into [%T]", event.Data(), payload))
+	}
+
+	if err := listener.service.Send(ctx, payload.UserID, event, payload.PreviousOwner); err != nil {
+		return listener.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot process [%s] event with ID [%s]", event.Type(), event.ID()))
+	}
+
+	return nil
+}
+
 // OnMessageSendFailed handles the events.EventTypeMessageSendFailed event
 func (listener *WebhookListener) OnMessageSendFailed(ctx context.Context, event cloudevents.Event) error {
 	ctx, span := listener.tracer.Start(ctx)
@@ -10,8 +10,8 @@ import (
 	"github.com/gofiber/fiber/v3"
 )
 
-// APIKeyAuth authenticates a user from the X-API-Key header
-func APIKeyAuth(logger telemetry.Logger, tracer telemetry.Tracer, userRepository repositories.UserRepository) fiber.Handler {
+// APIKeyAuth authenticates a user from the X-API-Key header, keys with the sk_ prefix are scoped entities.UserAPIKey
+func APIKeyAuth(logger telemetry.Logger, tracer telemetry.Tracer, userRepository repositories.UserRepository, userAPIKeyRepository repositories.UserAPIKeyRepository) fiber.Handler {
 	logger = logger.WithService("middlewares.APIKeyAuth")
 
 	return func(c fiber.Ctx) error {
@@ -26,6 +26,17 @@ func APIKeyAuth(logger telemetry.Logger, tracer telemetry.Tracer, userRepository
 			return c.Next()
 		}
 
+		if strings.HasPrefix(apiKey, userAPIKeyPrefix) {
+			authUser, err := userAPIKeyRepository.LoadAuthContext(ctx, apiKey)
+			if err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot load user with user api key [%s]", apiKey))
+				return c.Next()
+			}
+
+			c.Locals(ContextKeyAuthUserID, authUser)
+			return c.Next()
+		}
+
 		authUser, err := userRepository.LoadAuthContext(ctx, apiKey)
 		if err != nil {
 			ctxLogger.Error(stacktrace.Propagatef(err, "cannot load user with api key [%s]", apiKey))
@@ -37,6 +48,8 @@ func APIKeyAuth(logger telemetry.Logger, tracer telemetry.Tracer, userRepository
 	}
 }
 
+const userAPIKeyPrefix = "sk_"
+
 func getAPIKeyFromRequest(c fiber.Ctx) string {
 	apiKey := c.Get(authHeaderAPIKey)
 	if len(apiKey) != 0 {
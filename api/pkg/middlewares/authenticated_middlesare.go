@@ -1,9 +1,14 @@
 package middlewares
 
 import (
+	"fmt"
+
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
 	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
 )
 
 const (
@@ -12,18 +17,26 @@ const (
 	bearerScheme     = "Bearer"
 )
 
+const (
+	// HeaderOrganisationID is the header used to select the active entities.Organisation of a request
+	HeaderOrganisationID = "X-Organisation-ID"
+)
+
 const (
 	// ContextKeyAuthUserID is the context key used to store the ID of an authenticated user
 	ContextKeyAuthUserID = "auth.user.id"
 )
 
-// Authenticated checks if the request is authenticated
-func Authenticated(tracer telemetry.Tracer) fiber.Handler {
+// Authenticated checks if the request is authenticated and resolves the active organisation of the request
+func Authenticated(logger telemetry.Logger, tracer telemetry.Tracer, organisationRepository repositories.OrganisationRepository) fiber.Handler {
+	logger = logger.WithService("middlewares.Authenticated")
+
 	return func(c fiber.Ctx) error {
-		_, span := tracer.StartFromFiberCtx(c, "middlewares.Authenticated")
+		ctx, span, ctxLogger := tracer.StartFromFiberCtxWithLogger(c, logger, "middlewares.Authenticated")
 		defer span.End()
 
-		if tokenUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthContext); !ok || tokenUser.IsNoop() {
+		tokenUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthContext)
+		if !ok || tokenUser.IsNoop() {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"status":  "error",
 				"message": "You are not authorized to carry out this request.",
@@ -31,6 +44,50 @@ func Authenticated(tracer telemetry.Tracer) fiber.Handler {
 			})
 		}
 
+		organisationID := c.Get(HeaderOrganisationID)
+		if organisationID == "" || tokenUser.PhoneAPIKeyID != nil || tokenUser.OrganisationID != nil {
+			return c.Next()
+		}
+
+		id, err := uuid.Parse(organisationID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": fmt.Sprintf("The [%s] header must be a valid UUID", HeaderOrganisationID),
+			})
+		}
+
+		membership, err := organisationRepository.LoadMembership(ctx, id, tokenUser.ID)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			return responseForbidden(c, fmt.Sprintf("You are not a member of the organisation [%s]", id))
+		}
+
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot load membership of user [%s] in organisation [%s]", tokenUser.ID, id))
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "We ran into an internal error while handling the request.",
+			})
+		}
+
+		if !membership.Role.CanWrite() && c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return responseForbidden(c, fmt.Sprintf("The [%s] role can only read the resources of the organisation", membership.Role))
+		}
+
+		// the resources of the owner are changed by the member so the member is recorded for each change
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			ctxLogger.Info(fmt.Sprintf("user [%s] with role [%s] in organisation [%s] is making a [%s] request to [%s] on behalf of user [%s]", tokenUser.ID, membership.Role, id, c.Method(), c.Path(), membership.OwnerID))
+		}
+
+		c.Locals(ContextKeyAuthUserID, tokenUser.InOrganisation(membership))
 		return c.Next()
 	}
 }
+
+func responseForbidden(c fiber.Ctx, message string) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  "error",
+		"message": fiber.ErrForbidden.Message,
+		"data":    message,
+	})
+}
@@ -0,0 +1,120 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type authenticatedOrganisationRepositoryStub struct {
+	repositories.OrganisationRepository
+	memberships map[entities.UserID]*entities.OrganisationMembership
+}
+
+func (stub *authenticatedOrganisationRepositoryStub) LoadMembership(_ context.Context, organisationID uuid.UUID, userID entities.UserID) (*entities.OrganisationMembership, error) {
+	if membership, ok := stub.memberships[userID]; ok && membership.ID == organisationID {
+		return membership, nil
+	}
+	return nil, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "membership not found")
+}
+
+func TestAuthenticated_ScopesTheRequestToTheOwnerOfTheOrganisation(t *testing.T) {
+	organisationID := uuid.New()
+	app, authUser := authenticatedTestApp(entities.OrganisationRoleAgent, organisationID)
+
+	response := authenticatedTestRequest(t, app, http.MethodPost, organisationID.String())
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, entities.UserID("owner-id"), authUser.ID)
+	require.Equal(t, entities.UserID("member-id"), authUser.ActorID())
+	require.Equal(t, &organisationID, authUser.OrganisationID)
+}
+
+func TestAuthenticated_WithoutOrganisationHeader(t *testing.T) {
+	app, authUser := authenticatedTestApp(entities.OrganisationRoleAgent, uuid.New())
+
+	response := authenticatedTestRequest(t, app, http.MethodPost, "")
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.Equal(t, entities.UserID("member-id"), authUser.ID)
+	require.Nil(t, authUser.OrganisationID)
+}
+
+func TestAuthenticated_RejectsUsersWhoAreNotMembers(t *testing.T) {
+	app, _ := authenticatedTestApp(entities.OrganisationRoleAgent, uuid.New())
+
+	response := authenticatedTestRequest(t, app, http.MethodGet, uuid.New().String())
+
+	require.Equal(t, http.StatusForbidden, response.StatusCode)
+}
+
+func TestAuthenticated_ReadOnlyMembersCannotWrite(t *testing.T) {
+	organisationID := uuid.New()
+	app, _ := authenticatedTestApp(entities.OrganisationRoleReadOnly, organisationID)
+
+	require.Equal(t, http.StatusOK, authenticatedTestRequest(t, app, http.MethodGet, organisationID.String()).StatusCode)
+	require.Equal(t, http.StatusForbidden, authenticatedTestRequest(t, app, http.MethodPost, organisationID.String()).StatusCode)
+}
+
+func TestOrganisationWriteRoles_OnlyAllowsTheRolesToWrite(t *testing.T) {
+	organisationID := uuid.New()
+	app, _ := authenticatedTestApp(entities.OrganisationRoleAgent, organisationID, OrganisationWriteRoles(telemetry.NewOtelLogger("test", &idempotencyNoopLogger{}), entities.OrganisationRoleOwner, entities.OrganisationRoleAdmin))
+
+	require.Equal(t, http.StatusOK, authenticatedTestRequest(t, app, http.MethodGet, organisationID.String()).StatusCode)
+	require.Equal(t, http.StatusForbidden, authenticatedTestRequest(t, app, http.MethodPost, organisationID.String()).StatusCode)
+	require.Equal(t, http.StatusOK, authenticatedTestRequest(t, app, http.MethodPost, "").StatusCode)
+}
+
+func authenticatedTestApp(role entities.OrganisationRole, organisationID uuid.UUID, handlers ...fiber.Handler) (*fiber.App, *entities.AuthContext) {
+	logger := &idempotencyNoopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	repository := &authenticatedOrganisationRepositoryStub{
+		memberships: map[entities.UserID]*entities.OrganisationMembership{
+			"member-id": {
+				Organisation: entities.Organisation{ID: organisationID, OwnerID: "owner-id"},
+				MemberID:     uuid.New(),
+				Role:         role,
+			},
+		},
+	}
+
+	authUser := &entities.AuthContext{}
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals(ContextKeyAuthUserID, entities.AuthContext{ID: entities.UserID("member-id"), Email: "member@example.com"})
+		return c.Next()
+	})
+
+	route := []any{Authenticated(logger, tracer, repository)}
+	for _, handler := range handlers {
+		route = append(route, handler)
+	}
+	route = append(route, func(c fiber.Ctx) error {
+		*authUser = c.Locals(ContextKeyAuthUserID).(entities.AuthContext)
+		return c.JSON(fiber.Map{"status": "success"})
+	})
+	app.Add([]string{fiber.MethodGet, fiber.MethodPost}, "/v1/phones", route[0], route[1:]...)
+
+	return app, authUser
+}
+
+func authenticatedTestRequest(t *testing.T, app *fiber.App, method string, organisationID string) *http.Response {
+	req := httptest.NewRequest(method, "/v1/phones", nil)
+	if organisationID != "" {
+		req.Header.Set(HeaderOrganisationID, organisationID)
+	}
+
+	response, err := app.Test(req, fiber.TestConfig{Timeout: time.Second})
+	require.NoError(t, err)
+	return response
+}
@@ -39,9 +39,11 @@ func BearerAuth(logger telemetry.Logger, tracer telemetry.Tracer, authClient *au
 
 		span.AddEvent(fmt.Sprintf("[%s] token is valid", bearerScheme))
 
+		emailVerified, _ := token.Claims["email_verified"].(bool)
 		authUser := entities.AuthContext{
-			Email: token.Claims["email"].(string),
-			ID:    entities.UserID(token.Claims["user_id"].(string)),
+			Email:         token.Claims["email"].(string),
+			EmailVerified: emailVerified,
+			ID:            entities.UserID(token.Claims["user_id"].(string)),
 		}
 
 		c.Locals(ContextKeyAuthUserID, authUser)
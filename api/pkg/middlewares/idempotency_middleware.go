@@ -0,0 +1,155 @@
+package middlewares
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	// HeaderIdempotencyKey is the request header containing the idempotency key
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// HeaderIdempotentReplayed is set on responses which are replayed from the cache
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	idempotencyKeyMaxLength = 255
+	idempotencyTTL          = 24 * time.Hour
+	idempotencyLockTTL      = 2 * time.Minute
+)
+
+// idempotencyRecord is the cached result of a request made with an idempotency key
+type idempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	InProgress  bool   `json:"in_progress"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Idempotency replays the original response when a request is retried with the same Idempotency-Key header
+func Idempotency(logger telemetry.Logger, tracer telemetry.Tracer, appCache cache.Cache) fiber.Handler {
+	logger = logger.WithService("middlewares.Idempotency")
+
+	return func(c fiber.Ctx) error {
+		ctx, span, ctxLogger := tracer.StartFromFiberCtxWithLogger(c, logger, "middlewares.Idempotency")
+		defer span.End()
+
+		idempotencyKey := c.Get(HeaderIdempotencyKey)
+		if len(idempotencyKey) == 0 {
+			return c.Next()
+		}
+
+		if len(idempotencyKey) > idempotencyKeyMaxLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"status":  "error",
+				"message": "The request isn't properly formed",
+				"data":    fmt.Sprintf("The [%s] header must not be longer than %d characters", HeaderIdempotencyKey, idempotencyKeyMaxLength),
+			})
+		}
+
+		authUser, _ := c.Locals(ContextKeyAuthUserID).(entities.AuthContext)
+		key := fmt.Sprintf("idempotency.%s.%s.%s", authUser.ID, c.Path(), idempotencyKey)
+		hash := sha256.Sum256(c.Body())
+		requestHash := hex.EncodeToString(hash[:])
+
+		lock, err := json.Marshal(&idempotencyRecord{RequestHash: requestHash, InProgress: true})
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot marshal idempotency lock with key [%s]", key))
+			return c.Next()
+		}
+
+		locked, err := appCache.SetNX(ctx, key, string(lock), idempotencyLockTTL)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot lock idempotency key [%s]", key))
+			return c.Next()
+		}
+
+		if !locked {
+			return replayIdempotencyRecord(ctx, c, ctxLogger, appCache, key, idempotencyKey, requestHash, authUser.ID)
+		}
+
+		if err = c.Next(); err != nil {
+			releaseIdempotencyKey(ctx, ctxLogger, appCache, key)
+			return err
+		}
+
+		if c.Response().StatusCode() >= fiber.StatusInternalServerError {
+			releaseIdempotencyKey(ctx, ctxLogger, appCache, key)
+			return nil
+		}
+
+		record := &idempotencyRecord{
+			RequestHash: requestHash,
+			StatusCode:  c.Response().StatusCode(),
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err = setIdempotencyRecord(ctx, appCache, key, record, idempotencyTTL); err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot store response for idempotency key [%s]", key))
+		}
+
+		return nil
+	}
+}
+
+// replayIdempotencyRecord responds with the cached result of the request which already holds the idempotency key
+func replayIdempotencyRecord(ctx context.Context, c fiber.Ctx, ctxLogger telemetry.Logger, appCache cache.Cache, key string, idempotencyKey string, requestHash string, userID entities.UserID) error {
+	value, err := appCache.Get(ctx, key)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "idempotency key [%s] was released before it could be replayed", key))
+		return idempotencyConflict(c, fmt.Sprintf("A request with the [%s] header [%s] is still being processed", HeaderIdempotencyKey, idempotencyKey))
+	}
+
+	record := new(idempotencyRecord)
+	if err = json.Unmarshal([]byte(value), record); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot unmarshal idempotency record with key [%s]", key))
+		return idempotencyConflict(c, fmt.Sprintf("A request with the [%s] header [%s] is still being processed", HeaderIdempotencyKey, idempotencyKey))
+	}
+
+	if record.RequestHash != requestHash {
+		ctxLogger.Warn(stacktrace.NewErrorf("idempotency key [%s] was reused by user [%s] with a different request body", idempotencyKey, userID))
+		return idempotencyConflict(c, fmt.Sprintf("The [%s] header [%s] has already been used with a different request body", HeaderIdempotencyKey, idempotencyKey))
+	}
+
+	if record.InProgress {
+		return idempotencyConflict(c, fmt.Sprintf("A request with the [%s] header [%s] is still being processed", HeaderIdempotencyKey, idempotencyKey))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("replaying response for idempotency key [%s] of user [%s]", idempotencyKey, userID))
+	c.Set(HeaderIdempotentReplayed, "true")
+	c.Set(fiber.HeaderContentType, record.ContentType)
+	return c.Status(record.StatusCode).Send(record.Body)
+}
+
+// releaseIdempotencyKey deletes the lock of a request which failed so that it can be retried with the same key
+func releaseIdempotencyKey(ctx context.Context, ctxLogger telemetry.Logger, appCache cache.Cache, key string) {
+	if err := appCache.Delete(ctx, key); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot release idempotency key [%s]", key))
+	}
+}
+
+func setIdempotencyRecord(ctx context.Context, appCache cache.Cache, key string, record *idempotencyRecord, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return stacktrace.Propagatef(err, "cannot marshal idempotency record with key [%s]", key)
+	}
+	return appCache.Set(ctx, key, string(value), ttl)
+}
+
+func idempotencyConflict(c fiber.Ctx, data string) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"status":  "error",
+		"message": "The idempotency key has already been used",
+		"data":    data,
+	})
+}
@@ -0,0 +1,154 @@
+package middlewares
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v3"
+	ttlCache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestIdempotency_ReplaysTheOriginalResponse(t *testing.T) {
+	app, calls := idempotencyTestApp()
+
+	first := idempotencyTestRequest(t, app, "key-1", `{"content":"hello"}`)
+	second := idempotencyTestRequest(t, app, "key-1", `{"content":"hello"}`)
+
+	require.Equal(t, 1, *calls)
+	require.Equal(t, http.StatusOK, first.StatusCode)
+	require.Equal(t, http.StatusOK, second.StatusCode)
+	require.Equal(t, "true", second.Header.Get(HeaderIdempotentReplayed))
+	require.Equal(t, idempotencyTestBody(t, first), idempotencyTestBody(t, second))
+}
+
+func TestIdempotency_RejectsADifferentBodyWithTheSameKey(t *testing.T) {
+	app, calls := idempotencyTestApp()
+
+	_ = idempotencyTestRequest(t, app, "key-1", `{"content":"hello"}`)
+	response := idempotencyTestRequest(t, app, "key-1", `{"content":"world"}`)
+
+	require.Equal(t, 1, *calls)
+	require.Equal(t, http.StatusConflict, response.StatusCode)
+}
+
+func TestIdempotency_IgnoresRequestsWithoutAKey(t *testing.T) {
+	app, calls := idempotencyTestApp()
+
+	_ = idempotencyTestRequest(t, app, "", `{"content":"hello"}`)
+	_ = idempotencyTestRequest(t, app, "", `{"content":"hello"}`)
+
+	require.Equal(t, 2, *calls)
+}
+
+func TestIdempotency_RejectsConcurrentRequestsWithTheSameKey(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := atomic.Int32{}
+	app := idempotencyTestAppWithHandler(func(c fiber.Ctx) error {
+		calls.Add(1)
+		close(started)
+		<-release
+		return c.JSON(fiber.Map{"status": "success"})
+	})
+
+	done := make(chan *http.Response)
+	go func() {
+		done <- idempotencyTestRequest(t, app, "key-1", `{"content":"hello"}`)
+	}()
+
+	<-started
+	response := idempotencyTestRequest(t, app, "key-1", `{"content":"hello"}`)
+	close(release)
+
+	require.Equal(t, http.StatusConflict, response.StatusCode)
+	require.Equal(t, http.StatusOK, (<-done).StatusCode)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestIdempotency_ReleasesTheKeyWhenTheRequestFails(t *testing.T) {
+	calls := 0
+	app := idempotencyTestAppWithHandler(func(c fiber.Ctx) error {
+		calls++
+		if calls == 1 {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error"})
+		}
+		return c.JSON(fiber.Map{"status": "success"})
+	})
+
+	first := idempotencyTestRequest(t, app, "key-1", `{"content":"hello"}`)
+	second := idempotencyTestRequest(t, app, "key-1", `{"content":"hello"}`)
+
+	require.Equal(t, http.StatusInternalServerError, first.StatusCode)
+	require.Equal(t, http.StatusOK, second.StatusCode)
+	require.Equal(t, 2, calls)
+}
+
+func idempotencyTestApp() (*fiber.App, *int) {
+	calls := 0
+	app := idempotencyTestAppWithHandler(func(c fiber.Ctx) error {
+		calls++
+		return c.JSON(fiber.Map{"status": "success", "call": calls})
+	})
+
+	return app, &calls
+}
+
+func idempotencyTestAppWithHandler(handler fiber.Handler) *fiber.App {
+	logger := &idempotencyNoopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	appCache := cache.NewMemoryCache(tracer, ttlCache.New(time.Hour, time.Hour))
+
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals(ContextKeyAuthUserID, entities.AuthContext{ID: entities.UserID("user-id"), Email: "user@example.com"})
+		return c.Next()
+	})
+	app.Post("/v1/messages/send", Idempotency(logger, tracer, appCache), handler)
+
+	return app
+}
+
+func idempotencyTestRequest(t *testing.T, app *fiber.App, key string, body string) *http.Response {
+	req := httptest.NewRequest(http.MethodPost, "/v1/messages/send", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+
+	response, err := app.Test(req, fiber.TestConfig{Timeout: time.Second})
+	require.NoError(t, err)
+	return response
+}
+
+func idempotencyTestBody(t *testing.T, response *http.Response) string {
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+type idempotencyNoopLogger struct{}
+
+var _ telemetry.Logger = (*idempotencyNoopLogger)(nil)
+
+func (logger *idempotencyNoopLogger) Error(_ error)                           {}
+func (logger *idempotencyNoopLogger) WithService(_ string) telemetry.Logger   { return logger }
+func (logger *idempotencyNoopLogger) WithString(_, _ string) telemetry.Logger { return logger }
+func (logger *idempotencyNoopLogger) WithSpan(_ trace.SpanContext) telemetry.Logger {
+	return logger
+}
+func (logger *idempotencyNoopLogger) Trace(_ string)                    {}
+func (logger *idempotencyNoopLogger) Info(_ string)                     {}
+func (logger *idempotencyNoopLogger) Warn(_ error)                      {}
+func (logger *idempotencyNoopLogger) Debug(_ string)                    {}
+func (logger *idempotencyNoopLogger) Fatal(_ error)                     {}
+func (logger *idempotencyNoopLogger) Printf(_ string, _ ...interface{}) {}
@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v3"
+)
+
+// OrganisationRoles only allows members with one of the roles to make requests in an organisation.
+// Requests which are not made in an organisation are not affected.
+// It must be registered after the Authenticated middleware.
+func OrganisationRoles(tracer telemetry.Tracer, roles ...entities.OrganisationRole) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		_, span := tracer.StartFromFiberCtx(c, "middlewares.OrganisationRoles")
+		defer span.End()
+
+		if authUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthContext); ok && authUser.OrganisationID != nil && !slices.Contains(roles, authUser.OrganisationRole) {
+			return responseForbidden(c, fmt.Sprintf("The [%s] role cannot carry out this request in the organisation", authUser.OrganisationRole))
+		}
+
+		return c.Next()
+	}
+}
+
+// OrganisationWriteRoles only allows members with one of the roles to make requests which are not read-only in an organisation.
+// Requests which are not made in an organisation are not affected.
+// It must be registered after the Authenticated middleware.
+func OrganisationWriteRoles(tracer telemetry.Tracer, roles ...entities.OrganisationRole) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		_, span := tracer.StartFromFiberCtx(c, "middlewares.OrganisationWriteRoles")
+		defer span.End()
+
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			return c.Next()
+		}
+
+		if authUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthContext); ok && authUser.OrganisationID != nil && !slices.Contains(roles, authUser.OrganisationRole) {
+			return responseForbidden(c, fmt.Sprintf("The [%s] role can only read these resources of the organisation", authUser.OrganisationRole))
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v3"
+)
+
+// RouteScopes are the scopes of the routes which do not follow the read and write scopes of their method.
+// The key is the method and the path of the route e.g. "POST /v1/messages/segments".
+type RouteScopes map[string]entities.UserAPIKeyScope
+
+// Scopes checks that a request which is authenticated with an entities.UserAPIKey has the read scope for GET requests and the write scope for other requests.
+// The routes are checked with their scope instead. Requests which are not authenticated with an entities.UserAPIKey are not affected.
+// It must be registered after the Authenticated middleware.
+func Scopes(tracer telemetry.Tracer, read entities.UserAPIKeyScope, write entities.UserAPIKeyScope, routes RouteScopes) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		_, span := tracer.StartFromFiberCtx(c, "middlewares.Scopes")
+		defer span.End()
+
+		scope := write
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			scope = read
+		}
+
+		if routeScope, ok := routes[c.Method()+" "+c.Route().Path]; ok {
+			scope = routeScope
+		}
+
+		if authUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthContext); ok && !authUser.HasScope(scope) {
+			return responseForbidden(c, fmt.Sprintf("The API key does not have the [%s] scope which is required for this request", scope))
+		}
+
+		return c.Next()
+	}
+}
+
+// FullAccess rejects requests which are authenticated with an entities.UserAPIKey e.g to manage the account or the API keys of a user.
+// It must be registered after the Authenticated middleware.
+func FullAccess(tracer telemetry.Tracer) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		_, span := tracer.StartFromFiberCtx(c, "middlewares.FullAccess")
+		defer span.End()
+
+		if authUser, ok := c.Locals(ContextKeyAuthUserID).(entities.AuthContext); ok && authUser.UserAPIKeyID != nil {
+			return responseForbidden(c, "This request cannot be made with a scoped API key, use the API key in your account settings instead")
+		}
+
+		return c.Next()
+	}
+}
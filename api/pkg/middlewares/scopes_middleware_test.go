@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopes_ChecksTheReadScopeForGetRequests(t *testing.T) {
+	tracer := telemetry.NewOtelLogger("test", &idempotencyNoopLogger{})
+	app := scopesTestApp(scopesTestAuthContext(entities.UserAPIKeyScopeMessagesRead), Scopes(tracer, entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, nil))
+
+	require.Equal(t, http.StatusOK, scopesTestRequest(t, app, http.MethodGet).StatusCode)
+	require.Equal(t, http.StatusForbidden, scopesTestRequest(t, app, http.MethodPost).StatusCode)
+}
+
+func TestScopes_ChecksTheScopeOfTheRoute(t *testing.T) {
+	tracer := telemetry.NewOtelLogger("test", &idempotencyNoopLogger{})
+	routes := RouteScopes{"POST /v1/messages": entities.UserAPIKeyScopeMessagesRead}
+
+	app := scopesTestApp(scopesTestAuthContext(entities.UserAPIKeyScopeMessagesRead), Scopes(tracer, entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, routes))
+	require.Equal(t, http.StatusOK, scopesTestRequest(t, app, http.MethodPost).StatusCode)
+
+	routes = RouteScopes{"POST /v1/messages": entities.UserAPIKeyScopeMessagesDelete}
+	app = scopesTestApp(scopesTestAuthContext(entities.UserAPIKeyScopeMessagesSend), Scopes(tracer, entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, routes))
+	require.Equal(t, http.StatusForbidden, scopesTestRequest(t, app, http.MethodPost).StatusCode)
+}
+
+func TestScopes_DoesNotAffectRequestsWithoutUserAPIKey(t *testing.T) {
+	tracer := telemetry.NewOtelLogger("test", &idempotencyNoopLogger{})
+	app := scopesTestApp(entities.AuthContext{ID: "user-id"}, Scopes(tracer, entities.UserAPIKeyScopeMessagesRead, entities.UserAPIKeyScopeMessagesSend, nil))
+
+	require.Equal(t, http.StatusOK, scopesTestRequest(t, app, http.MethodGet).StatusCode)
+	require.Equal(t, http.StatusOK, scopesTestRequest(t, app, http.MethodPost).StatusCode)
+}
+
+func TestFullAccess_RejectsUserAPIKeys(t *testing.T) {
+	tracer := telemetry.NewOtelLogger("test", &idempotencyNoopLogger{})
+
+	require.Equal(t, http.StatusForbidden, scopesTestRequest(t, scopesTestApp(scopesTestAuthContext(entities.UserAPIKeyScopes()...), FullAccess(tracer)), http.MethodGet).StatusCode)
+	require.Equal(t, http.StatusOK, scopesTestRequest(t, scopesTestApp(entities.AuthContext{ID: "user-id"}, FullAccess(tracer)), http.MethodGet).StatusCode)
+}
+
+func scopesTestAuthContext(scopes ...entities.UserAPIKeyScope) entities.AuthContext {
+	userAPIKeyID := uuid.New()
+	return entities.AuthContext{ID: "user-id", UserAPIKeyID: &userAPIKeyID, Scopes: scopes}
+}
+
+func scopesTestApp(authUser entities.AuthContext, handler fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals(ContextKeyAuthUserID, authUser)
+		return c.Next()
+	})
+
+	app.Add([]string{fiber.MethodGet, fiber.MethodPost}, "/v1/messages", handler, func(c fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "success"})
+	})
+	return app
+}
+
+func scopesTestRequest(t *testing.T, app *fiber.App, method string) *http.Response {
+	response, err := app.Test(httptest.NewRequest(method, "/v1/messages", nil), fiber.TestConfig{Timeout: time.Second})
+	require.NoError(t, err)
+	return response
+}
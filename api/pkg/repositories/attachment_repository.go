@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 )
@@ -11,8 +12,12 @@ import (
 type AttachmentRepository interface {
 	// Upload stores attachment data at the given path with the specified content type
 	Upload(ctx context.Context, path string, data []byte, contentType string) error
+	// UploadStream stores the contents of a reader at the given path without loading them into memory
+	UploadStream(ctx context.Context, path string, reader io.Reader, contentType string) error
 	// Download retrieves attachment data from the given path
 	Download(ctx context.Context, path string) ([]byte, error)
+	// DownloadStream opens a reader for the attachment at the given path which must be closed by the caller
+	DownloadStream(ctx context.Context, path string) (io.ReadCloser, error)
 	// Delete removes an attachment at the given path
 	Delete(ctx context.Context, path string) error
 }
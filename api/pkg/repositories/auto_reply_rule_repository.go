@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// AutoReplyRuleRepository loads and persists an entities.AutoReplyRule
+type AutoReplyRuleRepository interface {
+	// Store a new entities.AutoReplyRule
+	Store(ctx context.Context, rule *entities.AutoReplyRule) error
+
+	// Update an existing entities.AutoReplyRule
+	Update(ctx context.Context, rule *entities.AutoReplyRule) error
+
+	// Load an entities.AutoReplyRule by ID
+	Load(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) (*entities.AutoReplyRule, error)
+
+	// Index entities.AutoReplyRule of a user
+	Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.AutoReplyRule, error)
+
+	// FetchForOwner returns all the entities.AutoReplyRule of a phone ordered by priority
+	FetchForOwner(ctx context.Context, userID entities.UserID, owner string) ([]*entities.AutoReplyRule, error)
+
+	// Delete an entities.AutoReplyRule by ID
+	Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error
+
+	// DeleteAllForOwner deletes all entities.AutoReplyRule of a phone
+	DeleteAllForOwner(ctx context.Context, userID entities.UserID, owner string) error
+
+	// DeleteAllForUser deletes all entities.AutoReplyRule for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -9,8 +9,11 @@ import (
 
 // BillingUsageRepository loads and persists an entities.BillingUsage
 type BillingUsageRepository interface {
-	// RegisterSentMessage registers a message as sent
-	RegisterSentMessage(ctx context.Context, timestamp time.Time, user entities.UserID) error
+	// RegisterSentMessage registers a message with its number of SMS segments as sent
+	RegisterSentMessage(ctx context.Context, timestamp time.Time, user entities.UserID, segments uint) error
+
+	// UnregisterSentMessage removes a canceled message with its number of SMS segments from the sent messages
+	UnregisterSentMessage(ctx context.Context, timestamp time.Time, user entities.UserID, segments uint) error
 
 	// RegisterReceivedMessage registers a message as received
 	RegisterReceivedMessage(ctx context.Context, timestamp time.Time, user entities.UserID) error
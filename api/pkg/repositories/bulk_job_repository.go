@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// BulkJobRepository loads and persists an entities.BulkJob
+type BulkJobRepository interface {
+	// Store a new entities.BulkJob
+	Store(ctx context.Context, job *entities.BulkJob) error
+
+	// Update an existing entities.BulkJob
+	Update(ctx context.Context, job *entities.BulkJob) error
+
+	// Load an entities.BulkJob by ID
+	Load(ctx context.Context, userID entities.UserID, jobID uuid.UUID) (*entities.BulkJob, error)
+
+	// DeleteAllForUser deletes all entities.BulkJob for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
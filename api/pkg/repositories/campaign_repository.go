@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// CampaignRepository loads and persists an entities.Campaign
+type CampaignRepository interface {
+	// Store a new entities.Campaign
+	Store(ctx context.Context, campaign *entities.Campaign) error
+
+	// Update an existing entities.Campaign
+	Update(ctx context.Context, campaign *entities.Campaign) error
+
+	// Load an entities.Campaign by ID
+	Load(ctx context.Context, userID entities.UserID, campaignID uuid.UUID) (*entities.Campaign, error)
+
+	// LoadByRequestID loads the entities.Campaign of a user with the request ID of its messages
+	LoadByRequestID(ctx context.Context, userID entities.UserID, requestID string) (*entities.Campaign, error)
+
+	// Index entities.Campaign of a user
+	Index(ctx context.Context, userID entities.UserID, status string, params IndexParams) ([]*entities.Campaign, error)
+
+	// DeleteAllForUser deletes all entities.Campaign for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// ContactGroupRepository loads and persists an entities.ContactGroup
+type ContactGroupRepository interface {
+	// Store a new entities.ContactGroup
+	Store(ctx context.Context, group *entities.ContactGroup) error
+
+	// Update an existing entities.ContactGroup
+	Update(ctx context.Context, group *entities.ContactGroup) error
+
+	// Load an entities.ContactGroup by ID
+	Load(ctx context.Context, userID entities.UserID, groupID uuid.UUID) (*entities.ContactGroup, error)
+
+	// Index entities.ContactGroup of a user
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.ContactGroup, error)
+
+	// Delete an entities.ContactGroup by ID
+	Delete(ctx context.Context, userID entities.UserID, groupID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.ContactGroup for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// ContactRepository loads and persists an entities.Contact
+type ContactRepository interface {
+	// Store a new entities.Contact
+	Store(ctx context.Context, contact *entities.Contact) error
+
+	// Upsert creates or updates multiple entities.Contact using the phone number as the key
+	Upsert(ctx context.Context, contacts []*entities.Contact) error
+
+	// Update an existing entities.Contact
+	Update(ctx context.Context, contact *entities.Contact) error
+
+	// Load an entities.Contact by ID
+	Load(ctx context.Context, userID entities.UserID, contactID uuid.UUID) (*entities.Contact, error)
+
+	// Index entities.Contact of a user
+	Index(ctx context.Context, userID entities.UserID, tag string, params IndexParams) ([]*entities.Contact, error)
+
+	// FetchByPhoneNumbers returns the entities.Contact of a user with the given phone numbers
+	FetchByPhoneNumbers(ctx context.Context, userID entities.UserID, phoneNumbers []string) ([]*entities.Contact, error)
+
+	// Delete an entities.Contact by ID
+	Delete(ctx context.Context, userID entities.UserID, contactID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.Contact for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// ContentCipher encrypts the content of messages before it is persisted and decrypts it after it is loaded
+type ContentCipher interface {
+	// Encrypt the content of a user with the active key of the user
+	Encrypt(ctx context.Context, userID entities.UserID, content string) (string, error)
+
+	// Decrypt content of a user which was returned by Encrypt, content which is not encrypted is returned unchanged
+	Decrypt(ctx context.Context, userID entities.UserID, content string) (string, error)
+
+	// ActivePrefix returns the prefix of the content which is encrypted with the active key of a user.
+	// It is empty when the content is not encrypted.
+	ActivePrefix(ctx context.Context, userID entities.UserID) (string, error)
+}
+
+// plaintextContentCipher is the ContentCipher used when encryption at rest is disabled
+type plaintextContentCipher struct{}
+
+// NewPlaintextContentCipher creates a ContentCipher which does not encrypt the content
+func NewPlaintextContentCipher() ContentCipher {
+	return &plaintextContentCipher{}
+}
+
+// Encrypt returns the content unchanged
+func (contentCipher *plaintextContentCipher) Encrypt(_ context.Context, _ entities.UserID, content string) (string, error) {
+	return content, nil
+}
+
+// Decrypt returns the content unchanged
+func (contentCipher *plaintextContentCipher) Decrypt(_ context.Context, _ entities.UserID, content string) (string, error) {
+	return content, nil
+}
+
+// ActivePrefix is always empty because the content is not encrypted
+func (contentCipher *plaintextContentCipher) ActivePrefix(context.Context, entities.UserID) (string, error) {
+	return "", nil
+}
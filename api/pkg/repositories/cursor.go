@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Cursor is the position of an entity in a list which is sorted by (timestamp, id) in descending order.
+// It is sent to clients as an opaque string so that they can fetch the next or the previous page of the list.
+type Cursor struct {
+	Timestamp time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+	// Previous is true when the cursor fetches the entities which come before the position
+	Previous bool `json:"p,omitempty"`
+}
+
+// Encode converts the cursor into an opaque string
+func (cursor Cursor) Encode() string {
+	payload, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// DecodeCursor converts an opaque string created by Cursor.Encode back into a Cursor
+func DecodeCursor(value string) (*Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot decode cursor [%s]", value)
+	}
+
+	cursor := new(Cursor)
+	if err = json.Unmarshal(payload, cursor); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot unmarshal cursor [%s]", value)
+	}
+
+	if cursor.Timestamp.IsZero() || cursor.ID == uuid.Nil {
+		return nil, stacktrace.NewErrorf("cursor [%s] does not have a timestamp and an ID", value)
+	}
+
+	return cursor, nil
+}
+
+// PageCursors returns the encoded cursors of the next and the previous pages after fetching a page with IndexParams.
+// positions are the cursors of the entities on the page in the order they were returned.
+func PageCursors(params IndexParams, positions []Cursor) (next *string, prev *string) {
+	encode := func(cursor Cursor, previous bool) *string {
+		cursor.Previous = previous
+		value := cursor.Encode()
+		return &value
+	}
+
+	if len(positions) == 0 {
+		// an empty page after the last entity can still go back to the entities before it
+		if params.Cursor != nil && !params.Cursor.Previous {
+			prev = encode(*params.Cursor, true)
+		}
+		return next, prev
+	}
+
+	first, last := positions[0], positions[len(positions)-1]
+	if params.Cursor != nil && params.Cursor.Previous {
+		next = encode(last, false)
+		if len(positions) >= params.Limit {
+			prev = encode(first, true)
+		}
+		return next, prev
+	}
+
+	if len(positions) >= params.Limit {
+		next = encode(last, false)
+	}
+	if params.Cursor != nil || params.Skip > 0 {
+		prev = encode(first, true)
+	}
+	return next, prev
+}
+
+// paginate sorts a query by the timestamp column and the id in descending order and applies the cursor or the offset in IndexParams.
+// When the cursor fetches the previous page, the query is sorted in ascending order and the results must be reversed with reversePage.
+func paginate(query *gorm.DB, column string, params IndexParams) *gorm.DB {
+	if params.Cursor == nil {
+		return query.Order(column + " DESC").Limit(params.Limit).Offset(params.Skip)
+	}
+
+	if params.Cursor.Previous {
+		return query.
+			Where(fmt.Sprintf("(%s, id) > (?, ?)", column), params.Cursor.Timestamp, params.Cursor.ID).
+			Order(column + " ASC").
+			Order("id ASC").
+			Limit(params.Limit)
+	}
+
+	return query.
+		Where(fmt.Sprintf("(%s, id) < (?, ?)", column), params.Cursor.Timestamp, params.Cursor.ID).
+		Order(column + " DESC").
+		Order("id DESC").
+		Limit(params.Limit)
+}
+
+// reversePage restores the descending order of a page which was fetched with a cursor for the previous page
+func reversePage[T any](params IndexParams, entities []T) {
+	if params.Cursor != nil && params.Cursor.Previous {
+		slices.Reverse(entities)
+	}
+}
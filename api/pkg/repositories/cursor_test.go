@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestCursorEncodeAndDecode(t *testing.T) {
+	cursor := Cursor{
+		Timestamp: time.Date(2026, 7, 18, 7, 0, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+		Previous:  true,
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+
+	require.NoError(t, err)
+	assert.True(t, cursor.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.True(t, decoded.Previous)
+}
+
+func TestDecodeCursorWithInvalidValue(t *testing.T) {
+	for _, value := range []string{"not a cursor!", "e30", Cursor{ID: uuid.New()}.Encode()} {
+		_, err := DecodeCursor(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestPageCursors(t *testing.T) {
+	first := Cursor{Timestamp: time.Date(2026, 7, 18, 7, 2, 0, 0, time.UTC), ID: uuid.New()}
+	last := Cursor{Timestamp: time.Date(2026, 7, 18, 7, 1, 0, 0, time.UTC), ID: uuid.New()}
+	encode := func(cursor Cursor, previous bool) *string {
+		cursor.Previous = previous
+		value := cursor.Encode()
+		return &value
+	}
+
+	// the first page in offset mode
+	next, prev := PageCursors(IndexParams{Limit: 2}, []Cursor{first, last})
+	assert.Equal(t, encode(last, false), next)
+	assert.Nil(t, prev)
+
+	// the last page after a cursor
+	next, prev = PageCursors(IndexParams{Limit: 3, Cursor: &Cursor{Timestamp: first.Timestamp, ID: uuid.New()}}, []Cursor{first, last})
+	assert.Nil(t, next)
+	assert.Equal(t, encode(first, true), prev)
+
+	// a full page before a cursor
+	next, prev = PageCursors(IndexParams{Limit: 2, Cursor: &Cursor{Timestamp: last.Timestamp, ID: uuid.New(), Previous: true}}, []Cursor{first, last})
+	assert.Equal(t, encode(last, false), next)
+	assert.Equal(t, encode(first, true), prev)
+
+	// an empty page after a cursor
+	next, prev = PageCursors(IndexParams{Limit: 2, Cursor: &last}, nil)
+	assert.Nil(t, next)
+	assert.Equal(t, encode(last, true), prev)
+}
+
+func TestPaginate(t *testing.T) {
+	db, err := gorm.Open(
+		postgres.New(postgres.Config{Conn: &messageThreadTestConnPool{}}),
+		&gorm.Config{DisableAutomaticPing: true, DryRun: true},
+	)
+	require.NoError(t, err)
+
+	cursor := &Cursor{Timestamp: time.Date(2026, 7, 18, 7, 0, 0, 0, time.UTC), ID: uuid.New()}
+	statement := paginate(db.Where("owner = ?", "+18005550199"), "order_timestamp", IndexParams{Limit: 20, Cursor: cursor}).
+		Find(&[]entities.Message{}).Statement
+	assert.Equal(t, `SELECT * FROM "messages" WHERE owner = $1 AND (order_timestamp, id) < ($2, $3) ORDER BY order_timestamp DESC,id DESC LIMIT $4`, statement.SQL.String())
+
+	cursor.Previous = true
+	statement = paginate(db.Where("owner = ?", "+18005550199"), "order_timestamp", IndexParams{Limit: 20, Cursor: cursor}).
+		Find(&[]entities.Message{}).Statement
+	assert.Equal(t, `SELECT * FROM "messages" WHERE owner = $1 AND (order_timestamp, id) > ($2, $3) ORDER BY order_timestamp ASC,id ASC LIMIT $4`, statement.SQL.String())
+
+	statement = paginate(db.Where("owner = ?", "+18005550199"), "order_timestamp", IndexParams{Limit: 20, Skip: 40}).
+		Find(&[]entities.Message{}).Statement
+	assert.Equal(t, `SELECT * FROM "messages" WHERE owner = $1 ORDER BY order_timestamp DESC LIMIT $2 OFFSET $3`, statement.SQL.String())
+}
+
+func TestReversePage(t *testing.T) {
+	messages := []entities.Message{{Content: "older"}, {Content: "newer"}}
+
+	reversePage(IndexParams{Cursor: &Cursor{Previous: true}}, messages)
+	assert.Equal(t, "newer", messages[0].Content)
+
+	reversePage(IndexParams{Cursor: &Cursor{}}, messages)
+	assert.Equal(t, "newer", messages[0].Content)
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// DataKeyRepository loads and persists an entities.DataKey
+type DataKeyRepository interface {
+	// Store a new entities.DataKey
+	Store(ctx context.Context, key *entities.DataKey) error
+
+	// Update an entities.DataKey
+	Update(ctx context.Context, key *entities.DataKey) error
+
+	// Load an entities.DataKey of a user by ID
+	Load(ctx context.Context, userID entities.UserID, keyID uuid.UUID) (*entities.DataKey, error)
+
+	// LoadActive loads the newest entities.DataKey of a user which is not retired
+	LoadActive(ctx context.Context, userID entities.UserID) (*entities.DataKey, error)
+
+	// FetchByOtherMasterKey fetches the entities.DataKey which are not wrapped by the master key with the given ID
+	FetchByOtherMasterKey(ctx context.Context, masterKeyID string, limit int) ([]*entities.DataKey, error)
+
+	// FetchRotationDue fetches the entities.DataKey which are not retired and were created before a timestamp
+	FetchRotationDue(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.DataKey, error)
+
+	// FetchReEncryptionDue fetches the entities.DataKey which are not retired, were created before a timestamp and were not used to re-encrypt the content of the user
+	FetchReEncryptionDue(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.DataKey, error)
+
+	// FetchUsersWithoutKey fetches the IDs of the users who do not have an entities.DataKey
+	FetchUsersWithoutKey(ctx context.Context, limit int) ([]entities.UserID, error)
+
+	// Retire sets the retired timestamp on the entities.DataKey of a user which are not retired except the key with the given ID
+	Retire(ctx context.Context, userID entities.UserID, exceptKeyID uuid.UUID, timestamp time.Time) error
+
+	// DeleteRetired deletes the retired entities.DataKey of a user which were created before a timestamp
+	DeleteRetired(ctx context.Context, userID entities.UserID, createdBefore time.Time) error
+
+	// DeleteAllForUser deletes all entities.DataKey for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
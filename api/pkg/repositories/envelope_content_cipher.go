@@ -0,0 +1,363 @@
+package repositories
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	// encryptedContentPrefix is the prefix of content which is encrypted with a data key.
+	// The format of the encrypted content is $enc$<data key ID>$<base64 of the nonce and the ciphertext>
+	encryptedContentPrefix = "$enc$"
+
+	// dataKeySize is the size in bytes of the master keys and the data keys which are used with AES-256-GCM
+	dataKeySize = 32
+
+	// activeDataKeyTTL is how long the active data key of a user is cached, a rotated key is used by all the servers after this duration
+	activeDataKeyTTL = 5 * time.Minute
+
+	// dataKeyTTL is how long a data key which is used to decrypt content is cached
+	dataKeyTTL = time.Hour
+)
+
+// EnvelopeContentCipher encrypts the content of each user with a data key which is wrapped by a master key.
+// The data key of a user is created when it is first needed and it can be rotated by creating a new key,
+// the older keys are still used to decrypt the content until it is re-encrypted with the new key.
+type EnvelopeContentCipher struct {
+	logger      telemetry.Logger
+	tracer      telemetry.Tracer
+	repository  DataKeyRepository
+	masterKeyID string
+	masterKeys  map[string]cipher.AEAD
+	cache       *ristretto.Cache[string, *unwrappedDataKey]
+}
+
+// unwrappedDataKey is a data key which can encrypt and decrypt content
+type unwrappedDataKey struct {
+	id   uuid.UUID
+	aead cipher.AEAD
+}
+
+// NewEnvelopeContentCipher creates a new EnvelopeContentCipher.
+// masterKey is the base64 encoded key which wraps new data keys and previousMasterKeys are the
+// base64 encoded keys which are only used to unwrap the data keys until they are wrapped by masterKey.
+func NewEnvelopeContentCipher(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository DataKeyRepository,
+	masterKey string,
+	previousMasterKeys []string,
+) (*EnvelopeContentCipher, error) {
+	masterKeys := make(map[string]cipher.AEAD, len(previousMasterKeys)+1)
+	masterKeyID := ""
+	for index, encoded := range append([]string{masterKey}, previousMasterKeys...) {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, stacktrace.Propagatef(err, "cannot decode master key at position [%d] from base64", index)
+		}
+
+		aead, err := newContentAEAD(key)
+		if err != nil {
+			return nil, stacktrace.Propagatef(err, "cannot use master key at position [%d]", index)
+		}
+
+		id := MasterKeyID(key)
+		if index == 0 {
+			masterKeyID = id
+		}
+		masterKeys[id] = aead
+	}
+
+	cache, err := ristretto.NewCache[string, *unwrappedDataKey](&ristretto.Config[string, *unwrappedDataKey]{
+		MaxCost:     10000,
+		NumCounters: 10000 * 10,
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create data key cache")
+	}
+
+	return &EnvelopeContentCipher{
+		logger:      logger.WithService(fmt.Sprintf("%T", &EnvelopeContentCipher{})),
+		tracer:      tracer,
+		repository:  repository,
+		masterKeyID: masterKeyID,
+		masterKeys:  masterKeys,
+		cache:       cache,
+	}, nil
+}
+
+// MasterKeyID returns the ID which is stored with the data keys wrapped by a master key
+func MasterKeyID(masterKey []byte) string {
+	hash := sha256.Sum256(masterKey)
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+// MasterKeyID returns the ID of the master key which wraps new data keys
+func (envelope *EnvelopeContentCipher) MasterKeyID() string {
+	return envelope.masterKeyID
+}
+
+// Encrypt the content of a user with the active data key of the user, empty content is not encrypted
+func (envelope *EnvelopeContentCipher) Encrypt(ctx context.Context, userID entities.UserID, content string) (string, error) {
+	if content == "" {
+		return content, nil
+	}
+
+	key, err := envelope.activeKey(ctx, userID)
+	if err != nil {
+		return "", stacktrace.Propagatef(err, "cannot load active data key for user [%s]", userID)
+	}
+
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", stacktrace.Propagatef(err, "cannot generate nonce to encrypt content for user [%s]", userID)
+	}
+
+	sealed := key.aead.Seal(nonce, nonce, []byte(content), []byte(userID))
+	return envelope.prefix(key.id) + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt content of a user which was returned by Encrypt, content which is not encrypted is returned unchanged
+func (envelope *EnvelopeContentCipher) Decrypt(ctx context.Context, userID entities.UserID, content string) (string, error) {
+	if !strings.HasPrefix(content, encryptedContentPrefix) {
+		return content, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(content, encryptedContentPrefix), "$", 2)
+	if len(parts) != 2 {
+		return "", stacktrace.NewErrorf("encrypted content of user [%s] does not have a data key ID", userID)
+	}
+
+	keyID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return "", stacktrace.Propagatef(err, "cannot parse data key ID [%s] of encrypted content for user [%s]", parts[0], userID)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", stacktrace.Propagatef(err, "cannot decode encrypted content with data key [%s] for user [%s]", keyID, userID)
+	}
+
+	key, err := envelope.loadKey(ctx, userID, keyID)
+	if err != nil {
+		return "", stacktrace.Propagatef(err, "cannot load data key [%s] for user [%s]", keyID, userID)
+	}
+
+	if len(sealed) < key.aead.NonceSize() {
+		return "", stacktrace.NewErrorf("encrypted content with data key [%s] for user [%s] is too short", keyID, userID)
+	}
+
+	plaintext, err := key.aead.Open(nil, sealed[:key.aead.NonceSize()], sealed[key.aead.NonceSize():], []byte(userID))
+	if err != nil {
+		return "", stacktrace.Propagatef(err, "cannot decrypt content with data key [%s] for user [%s]", keyID, userID)
+	}
+
+	return string(plaintext), nil
+}
+
+// ActivePrefix returns the prefix of the content which is encrypted with the active data key of a user
+func (envelope *EnvelopeContentCipher) ActivePrefix(ctx context.Context, userID entities.UserID) (string, error) {
+	key, err := envelope.activeKey(ctx, userID)
+	if err != nil {
+		return "", stacktrace.Propagatef(err, "cannot load active data key for user [%s]", userID)
+	}
+	return envelope.prefix(key.id), nil
+}
+
+// CreateDataKey creates a new data key for a user which becomes the active key of the user
+func (envelope *EnvelopeContentCipher) CreateDataKey(ctx context.Context, userID entities.UserID) (*entities.DataKey, error) {
+	ctx, span := envelope.tracer.Start(ctx)
+	defer span.End()
+
+	plaintext := make([]byte, dataKeySize)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, envelope.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot generate data key for user [%s]", userID))
+	}
+
+	dataKey := &entities.DataKey{
+		ID:          uuid.New(),
+		UserID:      userID,
+		MasterKeyID: envelope.masterKeyID,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	wrapped, err := envelope.wrap(dataKey, plaintext)
+	if err != nil {
+		return nil, envelope.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot wrap data key [%s] for user [%s]", dataKey.ID, userID))
+	}
+	dataKey.WrappedKey = wrapped
+
+	if err = envelope.repository.Store(ctx, dataKey); err != nil {
+		return nil, envelope.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot store data key [%s] for user [%s]", dataKey.ID, userID))
+	}
+
+	aead, err := newContentAEAD(plaintext)
+	if err != nil {
+		return nil, envelope.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot use data key [%s] for user [%s]", dataKey.ID, userID))
+	}
+	envelope.cache.SetWithTTL(envelope.activeCacheKey(userID), &unwrappedDataKey{id: dataKey.ID, aead: aead}, 1, activeDataKeyTTL)
+
+	return dataKey, nil
+}
+
+// Rewrap wraps a data key with the current master key when it was wrapped by a previous master key
+func (envelope *EnvelopeContentCipher) Rewrap(ctx context.Context, dataKey *entities.DataKey) error {
+	ctx, span := envelope.tracer.Start(ctx)
+	defer span.End()
+
+	if dataKey.MasterKeyID == envelope.masterKeyID {
+		return nil
+	}
+
+	plaintext, err := envelope.unwrap(dataKey)
+	if err != nil {
+		return envelope.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot unwrap data key [%s] for user [%s]", dataKey.ID, dataKey.UserID))
+	}
+
+	wrapped, err := envelope.wrap(dataKey, plaintext)
+	if err != nil {
+		return envelope.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot wrap data key [%s] for user [%s]", dataKey.ID, dataKey.UserID))
+	}
+
+	dataKey.WrappedKey = wrapped
+	dataKey.MasterKeyID = envelope.masterKeyID
+	dataKey.UpdatedAt = time.Now().UTC()
+	if err = envelope.repository.Update(ctx, dataKey); err != nil {
+		return envelope.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update data key [%s] for user [%s]", dataKey.ID, dataKey.UserID))
+	}
+
+	return nil
+}
+
+func (envelope *EnvelopeContentCipher) activeKey(ctx context.Context, userID entities.UserID) (*unwrappedDataKey, error) {
+	if key, found := envelope.cache.Get(envelope.activeCacheKey(userID)); found {
+		return key, nil
+	}
+
+	dataKey, err := envelope.repository.LoadActive(ctx, userID)
+	if stacktrace.GetCode(err) == ErrCodeNotFound {
+		if dataKey, err = envelope.CreateDataKey(ctx, userID); err != nil {
+			return nil, stacktrace.Propagatef(err, "cannot create the first data key for user [%s]", userID)
+		}
+	} else if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot load the active data key for user [%s]", userID)
+	}
+
+	key, err := envelope.unwrapDataKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.cache.SetWithTTL(envelope.activeCacheKey(userID), key, 1, activeDataKeyTTL)
+	return key, nil
+}
+
+func (envelope *EnvelopeContentCipher) loadKey(ctx context.Context, userID entities.UserID, keyID uuid.UUID) (*unwrappedDataKey, error) {
+	cacheKey := fmt.Sprintf("key:%s:%s", userID, keyID)
+	if key, found := envelope.cache.Get(cacheKey); found {
+		return key, nil
+	}
+
+	dataKey, err := envelope.repository.Load(ctx, userID, keyID)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot load data key [%s] for user [%s]", keyID, userID)
+	}
+
+	key, err := envelope.unwrapDataKey(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.cache.SetWithTTL(cacheKey, key, 1, dataKeyTTL)
+	return key, nil
+}
+
+func (envelope *EnvelopeContentCipher) unwrapDataKey(dataKey *entities.DataKey) (*unwrappedDataKey, error) {
+	plaintext, err := envelope.unwrap(dataKey)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot unwrap data key [%s] for user [%s]", dataKey.ID, dataKey.UserID)
+	}
+
+	aead, err := newContentAEAD(plaintext)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot use data key [%s] for user [%s]", dataKey.ID, dataKey.UserID)
+	}
+
+	return &unwrappedDataKey{id: dataKey.ID, aead: aead}, nil
+}
+
+// wrap encrypts a data key with the current master key, the ID of the key and the user are authenticated so that a wrapped key cannot be copied to another row
+func (envelope *EnvelopeContentCipher) wrap(dataKey *entities.DataKey, plaintext []byte) ([]byte, error) {
+	aead := envelope.masterKeys[envelope.masterKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot generate nonce to wrap data key [%s]", dataKey.ID)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, envelope.wrapAdditionalData(dataKey)), nil
+}
+
+func (envelope *EnvelopeContentCipher) unwrap(dataKey *entities.DataKey) ([]byte, error) {
+	aead, found := envelope.masterKeys[dataKey.MasterKeyID]
+	if !found {
+		return nil, stacktrace.NewErrorf("master key [%s] which wrapped data key [%s] is not configured", dataKey.MasterKeyID, dataKey.ID)
+	}
+
+	if len(dataKey.WrappedKey) < aead.NonceSize() {
+		return nil, stacktrace.NewErrorf("wrapped data key [%s] is too short", dataKey.ID)
+	}
+
+	plaintext, err := aead.Open(nil, dataKey.WrappedKey[:aead.NonceSize()], dataKey.WrappedKey[aead.NonceSize():], envelope.wrapAdditionalData(dataKey))
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot decrypt data key [%s] with master key [%s]", dataKey.ID, dataKey.MasterKeyID)
+	}
+
+	return plaintext, nil
+}
+
+func (envelope *EnvelopeContentCipher) wrapAdditionalData(dataKey *entities.DataKey) []byte {
+	return []byte(fmt.Sprintf("%s|%s", dataKey.UserID, dataKey.ID))
+}
+
+func (envelope *EnvelopeContentCipher) prefix(keyID uuid.UUID) string {
+	return encryptedContentPrefix + keyID.String() + "$"
+}
+
+func (envelope *EnvelopeContentCipher) activeCacheKey(userID entities.UserID) string {
+	return fmt.Sprintf("active:%s", userID)
+}
+
+func newContentAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeySize {
+		return nil, stacktrace.NewErrorf("key has [%d] bytes instead of [%d]", len(key), dataKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create AES cipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create GCM cipher")
+	}
+
+	return aead, nil
+}
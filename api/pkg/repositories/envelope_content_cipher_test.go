@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dataKeyRepositoryStub struct {
+	DataKeyRepository
+	keys []*entities.DataKey
+}
+
+func (stub *dataKeyRepositoryStub) Store(_ context.Context, key *entities.DataKey) error {
+	stub.keys = append(stub.keys, key)
+	return nil
+}
+
+func (stub *dataKeyRepositoryStub) Update(context.Context, *entities.DataKey) error {
+	return nil
+}
+
+func (stub *dataKeyRepositoryStub) Load(_ context.Context, userID entities.UserID, keyID uuid.UUID) (*entities.DataKey, error) {
+	for _, key := range stub.keys {
+		if key.UserID == userID && key.ID == keyID {
+			return key, nil
+		}
+	}
+	return nil, stacktrace.NewErrorWithCodef(ErrCodeNotFound, "data key [%s] does not exist", keyID)
+}
+
+func (stub *dataKeyRepositoryStub) LoadActive(_ context.Context, userID entities.UserID) (*entities.DataKey, error) {
+	for index := len(stub.keys) - 1; index >= 0; index-- {
+		if stub.keys[index].UserID == userID && stub.keys[index].RetiredAt == nil {
+			return stub.keys[index], nil
+		}
+	}
+	return nil, stacktrace.NewErrorWithCodef(ErrCodeNotFound, "active data key does not exist for user [%s]", userID)
+}
+
+func newTestEnvelopeContentCipher(t *testing.T, repository DataKeyRepository, masterKey string, previousMasterKeys ...string) *EnvelopeContentCipher {
+	logger := &messageThreadTestLogger{}
+	envelope, err := NewEnvelopeContentCipher(logger, telemetry.NewOtelLogger("test", logger), repository, masterKey, previousMasterKeys)
+	require.NoError(t, err)
+	return envelope
+}
+
+func testMasterKey(value byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(value), dataKeySize)))
+}
+
+func TestEnvelopeContentCipherEncryptAndDecrypt(t *testing.T) {
+	repository := &dataKeyRepositoryStub{}
+	envelope := newTestEnvelopeContentCipher(t, repository, testMasterKey('a'))
+	ctx := context.Background()
+
+	encrypted, err := envelope.Encrypt(ctx, "user-1", "This is a sample text message")
+	require.NoError(t, err)
+	require.Len(t, repository.keys, 1)
+
+	prefix, err := envelope.ActivePrefix(ctx, "user-1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, prefix))
+	assert.NotContains(t, encrypted, "sample")
+
+	decrypted, err := envelope.Decrypt(ctx, "user-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "This is a sample text message", decrypted)
+
+	// the content is bound to the user
+	_, err = envelope.Decrypt(ctx, "user-2", encrypted)
+	assert.Error(t, err)
+}
+
+func TestEnvelopeContentCipherKeepsPlaintextContent(t *testing.T) {
+	envelope := newTestEnvelopeContentCipher(t, &dataKeyRepositoryStub{}, testMasterKey('a'))
+
+	encrypted, err := envelope.Encrypt(context.Background(), "user-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "", encrypted)
+
+	decrypted, err := envelope.Decrypt(context.Background(), "user-1", "stored before encryption")
+	require.NoError(t, err)
+	assert.Equal(t, "stored before encryption", decrypted)
+}
+
+func TestEnvelopeContentCipherRewrap(t *testing.T) {
+	repository := &dataKeyRepositoryStub{}
+	ctx := context.Background()
+
+	encrypted, err := newTestEnvelopeContentCipher(t, repository, testMasterKey('a')).Encrypt(ctx, "user-1", "hello")
+	require.NoError(t, err)
+
+	// the data key is still wrapped by the previous master key
+	envelope := newTestEnvelopeContentCipher(t, repository, testMasterKey('b'), testMasterKey('a'))
+	require.NoError(t, envelope.Rewrap(ctx, repository.keys[0]))
+	assert.Equal(t, envelope.MasterKeyID(), repository.keys[0].MasterKeyID)
+
+	// the previous master key is no longer needed
+	envelope = newTestEnvelopeContentCipher(t, repository, testMasterKey('b'))
+	decrypted, err := envelope.Decrypt(ctx, "user-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", decrypted)
+}
+
+func TestEnvelopeContentCipherRotatedKey(t *testing.T) {
+	repository := &dataKeyRepositoryStub{}
+	envelope := newTestEnvelopeContentCipher(t, repository, testMasterKey('a'))
+	ctx := context.Background()
+
+	encrypted, err := envelope.Encrypt(ctx, "user-1", "hello")
+	require.NoError(t, err)
+
+	retiredAt := time.Now().UTC()
+	repository.keys[0].RetiredAt = &retiredAt
+	newKey, err := envelope.CreateDataKey(ctx, "user-1")
+	require.NoError(t, err)
+
+	// a new cipher does not have the active key in the cache
+	envelope = newTestEnvelopeContentCipher(t, repository, testMasterKey('a'))
+	prefix, err := envelope.ActivePrefix(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, encryptedContentPrefix+newKey.ID.String()+"$", prefix)
+	assert.False(t, strings.HasPrefix(encrypted, prefix))
+
+	decrypted, err := envelope.Decrypt(ctx, "user-1", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", decrypted)
+}
+
+func TestNewEnvelopeContentCipherWithInvalidMasterKey(t *testing.T) {
+	logger := &messageThreadTestLogger{}
+	for _, masterKey := range []string{"not base64!", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		_, err := NewEnvelopeContentCipher(logger, telemetry.NewOtelLogger("test", logger), &dataKeyRepositoryStub{}, masterKey, nil)
+		assert.Error(t, err, masterKey)
+	}
+}
@@ -54,6 +54,31 @@ func (s *GoogleCloudStorageAttachmentRepository) Upload(ctx context.Context, pat
 	return nil
 }
 
+// UploadStream stores the contents of a reader at the given path in GCS
+func (s *GoogleCloudStorageAttachmentRepository) UploadStream(ctx context.Context, path string, reader io.Reader, contentType string) error {
+	ctx, span, ctxLogger := s.tracer.StartWithLogger(ctx, s.logger)
+	defer span.End()
+
+	// the object is not created when the context is canceled before the writer is closed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := s.client.Bucket(s.bucket).Object(path).NewWriter(ctx)
+	writer.ContentType = contentType
+
+	size, err := io.Copy(writer, reader)
+	if err != nil {
+		return s.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot write attachment to GCS path [%s]", path))
+	}
+
+	if err = writer.Close(); err != nil {
+		return s.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot close GCS writer for path [%s]", path))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("uploaded attachment to GCS path [%s/%s] with size [%d]", s.bucket, path, size))
+	return nil
+}
+
 // Download retrieves attachment data from the given path in GCS
 func (s *GoogleCloudStorageAttachmentRepository) Download(ctx context.Context, path string) ([]byte, error) {
 	ctx, span, ctxLogger := s.tracer.StartWithLogger(ctx, s.logger)
@@ -77,6 +102,22 @@ func (s *GoogleCloudStorageAttachmentRepository) Download(ctx context.Context, p
 	return data, nil
 }
 
+// DownloadStream opens a reader for the attachment at the given path in GCS
+func (s *GoogleCloudStorageAttachmentRepository) DownloadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	ctx, span := s.tracer.Start(ctx)
+	defer span.End()
+
+	reader, err := s.client.Bucket(s.bucket).Object(path).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, s.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "cannot open GCS reader for path [%s]", path))
+		}
+		return nil, s.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot open GCS reader for path [%s]", path))
+	}
+
+	return reader, nil
+}
+
 // Delete removes an attachment at the given path in GCS
 func (s *GoogleCloudStorageAttachmentRepository) Delete(ctx context.Context, path string) error {
 	ctx, span, ctxLogger := s.tracer.StartWithLogger(ctx, s.logger)
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormAutoReplyRuleRepository is responsible for persisting entities.AutoReplyRule
+type gormAutoReplyRuleRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormAutoReplyRuleRepository creates the GORM version of the AutoReplyRuleRepository
+func NewGormAutoReplyRuleRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) AutoReplyRuleRepository {
+	return &gormAutoReplyRuleRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormAutoReplyRuleRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.AutoReplyRule
+func (repository *gormAutoReplyRuleRepository) Store(ctx context.Context, rule *entities.AutoReplyRule) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(rule).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save auto reply rule with ID [%s]", rule.ID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.AutoReplyRule
+func (repository *gormAutoReplyRuleRepository) Update(ctx context.Context, rule *entities.AutoReplyRule) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update auto reply rule with ID [%s]", rule.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.AutoReplyRule by ID
+func (repository *gormAutoReplyRuleRepository) Load(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) (*entities.AutoReplyRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rule := new(entities.AutoReplyRule)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", ruleID).
+		First(rule).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "auto reply rule with ID [%s] does not exist for user [%s]", ruleID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load auto reply rule with ID [%s] for user [%s]", ruleID, userID))
+	}
+
+	return rule, nil
+}
+
+// Index entities.AutoReplyRule of a user
+func (repository *gormAutoReplyRuleRepository) Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.AutoReplyRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if owner != "" {
+		query.Where("owner = ?", owner)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("pattern ILIKE ?", queryPattern).Or("content ILIKE ?", queryPattern))
+	}
+
+	rules := make([]*entities.AutoReplyRule, 0)
+	if err := query.Order("owner ASC").Order("priority ASC").Order("created_at ASC").Limit(params.Limit).Offset(params.Skip).Find(&rules).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch auto reply rules for user [%s] and params [%+#v]", userID, params))
+	}
+
+	return rules, nil
+}
+
+// FetchForOwner returns all the entities.AutoReplyRule of a phone ordered by priority
+func (repository *gormAutoReplyRuleRepository) FetchForOwner(ctx context.Context, userID entities.UserID, owner string) ([]*entities.AutoReplyRule, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	rules := make([]*entities.AutoReplyRule, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Order("priority ASC").
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch auto reply rules for user [%s] and owner [%s]", userID, owner))
+	}
+
+	return rules, nil
+}
+
+// Delete an entities.AutoReplyRule by ID
+func (repository *gormAutoReplyRuleRepository) Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", ruleID).
+		Delete(&entities.AutoReplyRule{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete auto reply rule with ID [%s] for user [%s]", ruleID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForOwner deletes all entities.AutoReplyRule of a phone
+func (repository *gormAutoReplyRuleRepository) DeleteAllForOwner(ctx context.Context, userID entities.UserID, owner string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Delete(&entities.AutoReplyRule{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s] and owner [%s]", &entities.AutoReplyRule{}, userID, owner))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.AutoReplyRule for a user
+func (repository *gormAutoReplyRuleRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.AutoReplyRule{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.AutoReplyRule{}, userID))
+	}
+
+	return nil
+}
@@ -46,8 +46,8 @@ func (repository *gormBillingUsageRepository) DeleteAllForUser(ctx context.Conte
 	return nil
 }
 
-// RegisterSentMessage registers a message as sent
-func (repository *gormBillingUsageRepository) RegisterSentMessage(ctx context.Context, timestamp time.Time, userID entities.UserID) error {
+// RegisterSentMessage registers a message with its number of SMS segments as sent
+func (repository *gormBillingUsageRepository) RegisterSentMessage(ctx context.Context, timestamp time.Time, userID entities.UserID, segments uint) error {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
@@ -59,13 +59,17 @@ func (repository *gormBillingUsageRepository) RegisterSentMessage(ctx context.Co
 				Where("user_id = ?", userID).
 				Where("start_timestamp <= ?", timestamp).
 				Where("end_timestamp >= ?", timestamp).
-				UpdateColumn("sent_messages", gorm.Expr("sent_messages + ?", 1))
+				UpdateColumns(map[string]any{
+					"sent_messages": gorm.Expr("sent_messages + ?", 1),
+					"sent_segments": gorm.Expr("sent_segments + ?", segments),
+				})
 
 			if result.Error == nil && result.RowsAffected == 0 {
 				usage, err := repository.createBillingUsageForUser(ctx, tx, userID, timestamp, 1, 0)
 				if err != nil {
 					return err
 				}
+				usage.SentSegments = segments
 				return tx.Create(usage).Error
 			}
 			return result.Error
@@ -73,6 +77,26 @@ func (repository *gormBillingUsageRepository) RegisterSentMessage(ctx context.Co
 	)
 }
 
+// UnregisterSentMessage removes a canceled message with its number of SMS segments from the sent messages
+func (repository *gormBillingUsageRepository) UnregisterSentMessage(ctx context.Context, timestamp time.Time, userID entities.UserID, segments uint) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Model(&entities.BillingUsage{}).
+		Where("user_id = ?", userID).
+		Where("start_timestamp <= ?", timestamp).
+		Where("end_timestamp >= ?", timestamp).
+		UpdateColumns(map[string]any{
+			"sent_messages": gorm.Expr("GREATEST(sent_messages - ?, 0)", 1),
+			"sent_segments": gorm.Expr("GREATEST(sent_segments - ?, 0)", segments),
+		}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot unregister sent message for user [%s] at [%s]", userID, timestamp))
+	}
+	return nil
+}
+
 // RegisterReceivedMessage registers a message as received
 func (repository *gormBillingUsageRepository) RegisterReceivedMessage(ctx context.Context, timestamp time.Time, userID entities.UserID) error {
 	ctx, span := repository.tracer.Start(ctx)
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormBulkJobRepository is responsible for persisting entities.BulkJob
+type gormBulkJobRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormBulkJobRepository creates the GORM version of the BulkJobRepository
+func NewGormBulkJobRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) BulkJobRepository {
+	return &gormBulkJobRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormBulkJobRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.BulkJob
+func (repository *gormBulkJobRepository) Store(ctx context.Context, job *entities.BulkJob) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(job).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save bulk job with ID [%s]", job.ID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.BulkJob
+func (repository *gormBulkJobRepository) Update(ctx context.Context, job *entities.BulkJob) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(job).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update bulk job with ID [%s]", job.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.BulkJob by ID
+func (repository *gormBulkJobRepository) Load(ctx context.Context, userID entities.UserID, jobID uuid.UUID) (*entities.BulkJob, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	job := new(entities.BulkJob)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", jobID).
+		First(job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "bulk job with ID [%s] does not exist for user [%s]", jobID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load bulk job with ID [%s] for user [%s]", jobID, userID))
+	}
+
+	return job, nil
+}
+
+// DeleteAllForUser deletes all entities.BulkJob for a user
+func (repository *gormBulkJobRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.BulkJob{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.BulkJob{}, userID))
+	}
+
+	return nil
+}
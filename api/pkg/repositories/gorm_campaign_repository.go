@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormCampaignRepository is responsible for persisting entities.Campaign
+type gormCampaignRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormCampaignRepository creates the GORM version of the CampaignRepository
+func NewGormCampaignRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) CampaignRepository {
+	return &gormCampaignRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormCampaignRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.Campaign
+func (repository *gormCampaignRepository) Store(ctx context.Context, campaign *entities.Campaign) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(campaign).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save campaign with ID [%s]", campaign.ID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.Campaign
+func (repository *gormCampaignRepository) Update(ctx context.Context, campaign *entities.Campaign) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(campaign).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update campaign with ID [%s]", campaign.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.Campaign by ID
+func (repository *gormCampaignRepository) Load(ctx context.Context, userID entities.UserID, campaignID uuid.UUID) (*entities.Campaign, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	campaign := new(entities.Campaign)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", campaignID).
+		First(campaign).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "campaign with ID [%s] does not exist for user [%s]", campaignID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load campaign with ID [%s] for user [%s]", campaignID, userID))
+	}
+
+	return campaign, nil
+}
+
+// LoadByRequestID loads the entities.Campaign of a user with the request ID of its messages
+func (repository *gormCampaignRepository) LoadByRequestID(ctx context.Context, userID entities.UserID, requestID string) (*entities.Campaign, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	campaign := new(entities.Campaign)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("request_id = ?", requestID).
+		First(campaign).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "campaign with request ID [%s] does not exist for user [%s]", requestID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load campaign with request ID [%s] for user [%s]", requestID, userID))
+	}
+
+	return campaign, nil
+}
+
+// Index entities.Campaign of a user
+func (repository *gormCampaignRepository) Index(ctx context.Context, userID entities.UserID, status string, params IndexParams) ([]*entities.Campaign, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query.Where("status = ?", status)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("request_id ILIKE ?", queryPattern).Or("array_to_string(owners, ',') ILIKE ?", queryPattern))
+	}
+
+	campaigns := make([]*entities.Campaign, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&campaigns).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch campaigns for user [%s] and params [%+#v]", userID, params))
+	}
+
+	return campaigns, nil
+}
+
+// DeleteAllForUser deletes all entities.Campaign for a user
+func (repository *gormCampaignRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.Campaign{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.Campaign{}, userID))
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormContactGroupRepository is responsible for persisting entities.ContactGroup
+type gormContactGroupRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormContactGroupRepository creates the GORM version of the ContactGroupRepository
+func NewGormContactGroupRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ContactGroupRepository {
+	return &gormContactGroupRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormContactGroupRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.ContactGroup
+func (repository *gormContactGroupRepository) Store(ctx context.Context, group *entities.ContactGroup) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(group).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save contact group with ID [%s]", group.ID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.ContactGroup
+func (repository *gormContactGroupRepository) Update(ctx context.Context, group *entities.ContactGroup) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(group).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update contact group with ID [%s]", group.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.ContactGroup by ID
+func (repository *gormContactGroupRepository) Load(ctx context.Context, userID entities.UserID, groupID uuid.UUID) (*entities.ContactGroup, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	group := new(entities.ContactGroup)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", groupID).
+		First(group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "contact group with ID [%s] does not exist for user [%s]", groupID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load contact group with ID [%s] for user [%s]", groupID, userID))
+	}
+
+	return group, nil
+}
+
+// Index entities.ContactGroup of a user
+func (repository *gormContactGroupRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.ContactGroup, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("array_to_string(members, ',') ILIKE ?", queryPattern))
+	}
+
+	groups := make([]*entities.ContactGroup, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&groups).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch contact groups for user [%s] and params [%+#v]", userID, params))
+	}
+
+	return groups, nil
+}
+
+// Delete an entities.ContactGroup by ID
+func (repository *gormContactGroupRepository) Delete(ctx context.Context, userID entities.UserID, groupID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", groupID).
+		Delete(&entities.ContactGroup{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete contact group with ID [%s] for user [%s]", groupID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.ContactGroup for a user
+func (repository *gormContactGroupRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.ContactGroup{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.ContactGroup{}, userID))
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormContactRepository is responsible for persisting entities.Contact
+type gormContactRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormContactRepository creates the GORM version of the ContactRepository
+func NewGormContactRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ContactRepository {
+	return &gormContactRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormContactRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.Contact
+func (repository *gormContactRepository) Store(ctx context.Context, contact *entities.Contact) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	organisationID, err := organisationIDOfOwner(ctx, repository.db, contact.UserID)
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save contact with ID [%s]", contact.ID))
+	}
+	contact.OrganisationID = organisationID
+
+	if err = repository.db.WithContext(ctx).Create(contact).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save contact with ID [%s]", contact.ID))
+	}
+
+	return nil
+}
+
+// Upsert creates or updates multiple entities.Contact using the phone number as the key
+func (repository *gormContactRepository) Upsert(ctx context.Context, contacts []*entities.Contact) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if len(contacts) == 0 {
+		return nil
+	}
+
+	// the contacts of an import belong to the same user
+	organisationID, err := organisationIDOfOwner(ctx, repository.db, contacts[0].UserID)
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot upsert [%d] contacts for user [%s]", len(contacts), contacts[0].UserID))
+	}
+	for _, contact := range contacts {
+		contact.OrganisationID = organisationID
+	}
+
+	err = repository.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "phone_number"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "tags", "attributes", "actor_id", "organisation_id", "updated_at"}),
+		}).
+		CreateInBatches(contacts, 500).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot upsert [%d] contacts for user [%s]", len(contacts), contacts[0].UserID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.Contact
+func (repository *gormContactRepository) Update(ctx context.Context, contact *entities.Contact) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(contact).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update contact with ID [%s]", contact.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.Contact by ID
+func (repository *gormContactRepository) Load(ctx context.Context, userID entities.UserID, contactID uuid.UUID) (*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contact := new(entities.Contact)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", contactID).
+		First(contact).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "contact with ID [%s] does not exist for user [%s]", contactID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load contact with ID [%s] for user [%s]", contactID, userID))
+	}
+
+	return contact, nil
+}
+
+// Index entities.Contact of a user
+func (repository *gormContactRepository) Index(ctx context.Context, userID entities.UserID, tag string, params IndexParams) ([]*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID).Scopes(organisationScope(params.OrganisationID))
+	if tag != "" {
+		query.Where("? = ANY(tags)", tag)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(
+			repository.db.Where("name ILIKE ?", queryPattern).
+				Or("phone_number ILIKE ?", queryPattern).
+				Or("array_to_string(tags, ' ') ILIKE ?", queryPattern).
+				Or("attributes::text ILIKE ?", queryPattern),
+		)
+	}
+
+	contacts := make([]*entities.Contact, 0)
+	if err := query.Order("name ASC").Order("phone_number ASC").Limit(params.Limit).Offset(params.Skip).Find(&contacts).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch contacts for user [%s] and params [%+#v]", userID, params))
+	}
+
+	return contacts, nil
+}
+
+// FetchByPhoneNumbers returns the entities.Contact of a user with the given phone numbers
+func (repository *gormContactRepository) FetchByPhoneNumbers(ctx context.Context, userID entities.UserID, phoneNumbers []string) ([]*entities.Contact, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contacts := make([]*entities.Contact, 0)
+	if len(phoneNumbers) == 0 {
+		return contacts, nil
+	}
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("phone_number IN ?", phoneNumbers).
+		Find(&contacts).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch [%d] contacts by phone number for user [%s]", len(phoneNumbers), userID))
+	}
+
+	return contacts, nil
+}
+
+// Delete an entities.Contact by ID
+func (repository *gormContactRepository) Delete(ctx context.Context, userID entities.UserID, contactID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", contactID).
+		Delete(&entities.Contact{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete contact with ID [%s] for user [%s]", contactID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Contact for a user
+func (repository *gormContactRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.Contact{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.Contact{}, userID))
+	}
+
+	return nil
+}
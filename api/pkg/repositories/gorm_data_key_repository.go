@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormDataKeyRepository is responsible for persisting entities.DataKey
+type gormDataKeyRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormDataKeyRepository creates the GORM version of the DataKeyRepository
+func NewGormDataKeyRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) DataKeyRepository {
+	return &gormDataKeyRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormDataKeyRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.DataKey
+func (repository *gormDataKeyRepository) Store(ctx context.Context, key *entities.DataKey) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(key).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save data key with ID [%s] for user [%s]", key.ID, key.UserID))
+	}
+
+	return nil
+}
+
+// Update an entities.DataKey
+func (repository *gormDataKeyRepository) Update(ctx context.Context, key *entities.DataKey) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(key).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update data key with ID [%s] for user [%s]", key.ID, key.UserID))
+	}
+
+	return nil
+}
+
+// Load an entities.DataKey of a user by ID
+func (repository *gormDataKeyRepository) Load(ctx context.Context, userID entities.UserID, keyID uuid.UUID) (*entities.DataKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	key := new(entities.DataKey)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", keyID).First(key).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "data key with ID [%s] does not exist for user [%s]", keyID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load data key with ID [%s] for user [%s]", keyID, userID))
+	}
+
+	return key, nil
+}
+
+// LoadActive loads the newest entities.DataKey of a user which is not retired
+func (repository *gormDataKeyRepository) LoadActive(ctx context.Context, userID entities.UserID) (*entities.DataKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	key := new(entities.DataKey)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("retired_at IS NULL").
+		Order("created_at DESC").
+		First(key).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "active data key does not exist for user [%s]", userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load active data key for user [%s]", userID))
+	}
+
+	return key, nil
+}
+
+// FetchByOtherMasterKey fetches the entities.DataKey which are not wrapped by the master key with the given ID
+func (repository *gormDataKeyRepository) FetchByOtherMasterKey(ctx context.Context, masterKeyID string, limit int) ([]*entities.DataKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	keys := make([]*entities.DataKey, 0, limit)
+	err := repository.db.WithContext(ctx).
+		Where("master_key_id <> ?", masterKeyID).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&keys).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch data keys which are not wrapped by master key [%s]", masterKeyID))
+	}
+
+	return keys, nil
+}
+
+// FetchRotationDue fetches the entities.DataKey which are not retired and were created before a timestamp
+func (repository *gormDataKeyRepository) FetchRotationDue(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.DataKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	keys := make([]*entities.DataKey, 0, limit)
+	err := repository.db.WithContext(ctx).
+		Where("retired_at IS NULL").
+		Where("created_at < ?", createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&keys).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch data keys created before [%s] which are due for rotation", createdBefore))
+	}
+
+	return keys, nil
+}
+
+// FetchReEncryptionDue fetches the entities.DataKey which are not retired, were created before a timestamp and were not used to re-encrypt the content of the user
+func (repository *gormDataKeyRepository) FetchReEncryptionDue(ctx context.Context, createdBefore time.Time, limit int) ([]*entities.DataKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	keys := make([]*entities.DataKey, 0, limit)
+	err := repository.db.WithContext(ctx).
+		Where("retired_at IS NULL").
+		Where("re_encrypted_at IS NULL").
+		Where("created_at < ?", createdBefore).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&keys).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch data keys created before [%s] which are due for re-encryption", createdBefore))
+	}
+
+	return keys, nil
+}
+
+// FetchUsersWithoutKey fetches the IDs of the users who do not have an entities.DataKey
+func (repository *gormDataKeyRepository) FetchUsersWithoutKey(ctx context.Context, limit int) ([]entities.UserID, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	userIDs := make([]entities.UserID, 0, limit)
+	err := repository.db.WithContext(ctx).
+		Model(&entities.User{}).
+		Where("NOT EXISTS (?)", repository.db.Model(&entities.DataKey{}).Select("1").Where("data_keys.user_id = users.id")).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &userIDs).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch users without a data key"))
+	}
+
+	return userIDs, nil
+}
+
+// Retire sets the retired timestamp on the entities.DataKey of a user which are not retired except the key with the given ID
+func (repository *gormDataKeyRepository) Retire(ctx context.Context, userID entities.UserID, exceptKeyID uuid.UUID, timestamp time.Time) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Model(&entities.DataKey{}).
+		Where("user_id = ?", userID).
+		Where("id <> ?", exceptKeyID).
+		Where("retired_at IS NULL").
+		Updates(map[string]any{"retired_at": timestamp, "updated_at": time.Now().UTC()}).
+		Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot retire data keys of user [%s] except [%s]", userID, exceptKeyID))
+	}
+
+	return nil
+}
+
+// DeleteRetired deletes the retired entities.DataKey of a user which were created before a timestamp
+func (repository *gormDataKeyRepository) DeleteRetired(ctx context.Context, userID entities.UserID, createdBefore time.Time) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("retired_at IS NOT NULL").
+		Where("created_at < ?", createdBefore).
+		Delete(&entities.DataKey{}).
+		Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete retired data keys of user [%s] created before [%s]", userID, createdBefore))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.DataKey for a user
+func (repository *gormDataKeyRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.DataKey{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.DataKey{}, userID))
+	}
+
+	return nil
+}
@@ -183,3 +183,35 @@ func (repository *gormHeartbeatMonitorRepository) Exists(ctx context.Context, us
 
 	return exists, nil
 }
+
+// CountPhoneOnline counts the heartbeat monitors of all the users with phones which are online and offline
+func (repository *gormHeartbeatMonitorRepository) CountPhoneOnline(ctx context.Context) (online int64, offline int64, err error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	var counts []struct {
+		PhoneOnline bool
+		Count       int64
+	}
+	err = repository.db.WithContext(ctx).
+		Model(&entities.HeartbeatMonitor{}).
+		Select("phone_online, count(*) AS count").
+		Group("phone_online").
+		Scan(&counts).Error
+	if err != nil {
+		return 0, 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot count heartbeat monitors by phone online status"))
+	}
+
+	for _, count := range counts {
+		if count.PhoneOnline {
+			online = count.Count
+		} else {
+			offline = count.Count
+		}
+	}
+
+	return online, offline, nil
+}
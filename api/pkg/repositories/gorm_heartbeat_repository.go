@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -44,6 +45,25 @@ func (repository *gormHeartbeatRepository) DeleteAllForUser(ctx context.Context,
 	return nil
 }
 
+// DeleteBefore deletes at most limit entities.Heartbeat of a user which are older than the timestamp and returns the number of deleted rows
+func (repository *gormHeartbeatRepository) DeleteBefore(ctx context.Context, userID entities.UserID, timestamp time.Time, limit int) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ids := repository.db.Model(&entities.Heartbeat{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Where("timestamp < ?", timestamp).
+		Limit(limit)
+
+	result := repository.db.WithContext(ctx).Where("id IN (?)", ids).Delete(&entities.Heartbeat{})
+	if result.Error != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(result.Error, "cannot delete [%T] before [%s] for user with ID [%s]", &entities.Heartbeat{}, timestamp, userID))
+	}
+
+	return result.RowsAffected, nil
+}
+
 func (repository *gormHeartbeatRepository) Last(ctx context.Context, userID entities.UserID, owner string) (*entities.Heartbeat, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -83,11 +103,13 @@ func (repository *gormHeartbeatRepository) Index(ctx context.Context, userID ent
 	}
 
 	heartbeats := new([]entities.Heartbeat)
-	err := query.Order("timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&heartbeats).Error
+	err := paginate(query, "timestamp", params).Find(&heartbeats).Error
 	if err != nil {
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch heartbeats with owner [%s] and params [%+#v]", owner, params))
 	}
 
+	reversePage(params, *heartbeats)
+
 	return heartbeats, nil
 }
 
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormMessageExportRepository is responsible for persisting entities.MessageExport
+type gormMessageExportRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormMessageExportRepository creates the GORM version of the MessageExportRepository
+func NewGormMessageExportRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) MessageExportRepository {
+	return &gormMessageExportRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormMessageExportRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.MessageExport
+func (repository *gormMessageExportRepository) Store(ctx context.Context, export *entities.MessageExport) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(export).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save message export with ID [%s]", export.ID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.MessageExport
+func (repository *gormMessageExportRepository) Update(ctx context.Context, export *entities.MessageExport) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(export).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update message export with ID [%s]", export.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.MessageExport by ID
+func (repository *gormMessageExportRepository) Load(ctx context.Context, userID entities.UserID, exportID uuid.UUID) (*entities.MessageExport, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	export := new(entities.MessageExport)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", exportID).
+		First(export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "message export with ID [%s] does not exist for user [%s]", exportID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load message export with ID [%s] for user [%s]", exportID, userID))
+	}
+
+	return export, nil
+}
+
+// LoadByID loads an entities.MessageExport by ID without checking the owner
+func (repository *gormMessageExportRepository) LoadByID(ctx context.Context, exportID uuid.UUID) (*entities.MessageExport, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	export := new(entities.MessageExport)
+	err := repository.db.WithContext(ctx).Where("id = ?", exportID).First(export).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "message export with ID [%s] does not exist", exportID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load message export with ID [%s]", exportID))
+	}
+
+	return export, nil
+}
+
+// DeleteAllForUser deletes all entities.MessageExport for a user
+func (repository *gormMessageExportRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.MessageExport{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.MessageExport{}, userID))
+	}
+
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"gorm.io/gorm/clause"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/NdoleStudio/stacktrace"
 	"github.com/cockroachdb/cockroach-go/v2/crdb/crdbgorm"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
@@ -20,6 +23,7 @@ type gormMessageRepository struct {
 	logger telemetry.Logger
 	tracer telemetry.Tracer
 	db     *gorm.DB
+	cipher ContentCipher
 }
 
 // NewGormMessageRepository creates the GORM version of the MessageRepository
@@ -27,11 +31,13 @@ func NewGormMessageRepository(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	db *gorm.DB,
+	cipher ContentCipher,
 ) MessageRepository {
 	return &gormMessageRepository{
 		logger: logger.WithService(fmt.Sprintf("%T", &gormMessageRepository{})),
 		tracer: tracer,
 		db:     db,
+		cipher: cipher,
 	}
 }
 
@@ -46,6 +52,71 @@ func (repository *gormMessageRepository) DeleteAllForUser(ctx context.Context, u
 	return nil
 }
 
+// FetchBefore fetches at most limit entities.Message of a user in a final status with an order timestamp before the timestamp, the oldest messages are first.
+// When withContent is true, only the messages which still have content or attachments are fetched.
+func (repository *gormMessageRepository) FetchBefore(ctx context.Context, userID entities.UserID, timestamp time.Time, withContent bool, limit int) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("order_timestamp < ?", timestamp).
+		Where("status IN ?", entities.MessageFinalStatuses())
+	if withContent {
+		query = query.Where(repository.db.Where("content <> ?", "").Or("attachments <> ?", "{}"))
+	}
+
+	messages := make([]*entities.Message, 0, limit)
+	if err := query.Order("order_timestamp ASC").Order("id ASC").Limit(limit).Find(&messages).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch messages before [%s] for user with ID [%s]", timestamp, userID))
+	}
+
+	if err := repository.decrypt(ctx, messages...); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
+	return messages, nil
+}
+
+// DeleteByIDs deletes the entities.Message of a user with the given IDs
+func (repository *gormMessageRepository) DeleteByIDs(ctx context.Context, userID entities.UserID, messageIDs []uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id IN ?", messageIDs).
+		Delete(&entities.Message{}).
+		Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [%d] messages for user with ID [%s]", len(messageIDs), userID))
+	}
+
+	return nil
+}
+
+// RemoveContent deletes the content and the attachments of the entities.Message of a user with the given IDs
+func (repository *gormMessageRepository) RemoveContent(ctx context.Context, userID entities.UserID, messageIDs []uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Message{}).
+		Where("user_id = ?", userID).
+		Where("id IN ?", messageIDs).
+		Updates(map[string]any{
+			"content":     "",
+			"attachments": pq.StringArray{},
+			"updated_at":  time.Now().UTC(),
+		}).
+		Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot remove the content of [%d] messages for user with ID [%s]", len(messageIDs), userID))
+	}
+
+	return nil
+}
+
 // DeleteByOwnerAndContact deletes all the messages between and owner and a contact
 func (repository *gormMessageRepository) DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner string, contact string) error {
 	ctx, span := repository.tracer.Start(ctx)
@@ -86,17 +157,26 @@ func (repository *gormMessageRepository) Index(ctx context.Context, userID entit
 		WithContext(ctx).
 		Where("user_id = ?", userID).
 		Where("owner = ?", owner).
-		Where("contact =  ?", contact)
+		Where("contact =  ?", contact).
+		Scopes(organisationScope(params.OrganisationID))
 	if len(params.Query) > 0 {
 		queryPattern := "%" + params.Query + "%"
 		query.Where("content ILIKE ?", queryPattern)
 	}
 
 	messages := new([]entities.Message)
-	if err := query.Order("order_timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&messages).Error; err != nil {
+	if err := paginate(query, "order_timestamp", params).Find(&messages).Error; err != nil {
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch messges with owner [%s] and contact [%s] and params [%+#v]", owner, contact, params))
 	}
 
+	reversePage(params, *messages)
+
+	for index := range *messages {
+		if err := repository.decrypt(ctx, &(*messages)[index]); err != nil {
+			return nil, repository.tracer.WrapErrorSpan(span, err)
+		}
+	}
+
 	return messages, nil
 }
 
@@ -121,10 +201,74 @@ func (repository *gormMessageRepository) LastMessage(ctx context.Context, userID
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot get last message for [%s] with owner [%s] and contact [%s]", userID, owner, contact))
 	}
 
+	if err = repository.decrypt(ctx, message); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
 	return message, nil
 }
 
-func (repository *gormMessageRepository) Search(ctx context.Context, userID entities.UserID, owners []string, types []entities.MessageType, statuses []entities.MessageStatus, params IndexParams) ([]*entities.Message, error) {
+// LastMessageWithOwners fetches the last message between a contact and any of the owners
+func (repository *gormMessageRepository) LastMessageWithOwners(ctx context.Context, userID entities.UserID, owners []string, contact string) (*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	message := new(entities.Message)
+	err := repository.db.
+		WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner IN ?", owners).
+		Where("contact = ?", contact).
+		Order("order_timestamp DESC").
+		First(message).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "cannot get last message for [%s] with owners [%s] and contact [%s]", userID, strings.Join(owners, ","), contact))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot get last message for [%s] with owners [%s] and contact [%s]", userID, strings.Join(owners, ","), contact))
+	}
+
+	if err = repository.decrypt(ctx, message); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
+	return message, nil
+}
+
+// CountOutstanding counts the messages which are still to be sent by each owner
+func (repository *gormMessageRepository) CountOutstanding(ctx context.Context, userID entities.UserID, owners []string) (map[string]int, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var rows []struct {
+		Owner string
+		Count int
+	}
+
+	err := repository.db.
+		WithContext(ctx).
+		Model(&entities.Message{}).
+		Select("owner, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Where("owner IN ?", owners).
+		Where("type = ?", entities.MessageTypeMobileTerminated).
+		Where("status IN ?", []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusScheduled, entities.MessageStatusSending}).
+		Group("owner").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot count outstanding messages for [%s] with owners [%s]", userID, strings.Join(owners, ",")))
+	}
+
+	counts := make(map[string]int, len(owners))
+	for _, row := range rows {
+		counts[row.Owner] = row.Count
+	}
+
+	return counts, nil
+}
+
+func (repository *gormMessageRepository) Search(ctx context.Context, userID entities.UserID, filters MessageSearchFilters, params IndexParams) ([]*entities.Message, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
@@ -132,32 +276,45 @@ func (repository *gormMessageRepository) Search(ctx context.Context, userID enti
 		WithContext(ctx).
 		Where("user_id = ?", userID)
 
-	if len(owners) > 0 {
-		query = query.Where("owner IN ?", owners)
+	if len(filters.Owners) > 0 {
+		query = query.Where("owner IN ?", filters.Owners)
 	}
-	if len(types) > 0 {
-		query = query.Where("type IN ?", types)
+	if len(filters.Contacts) > 0 {
+		query = query.Where("contact IN ?", filters.Contacts)
 	}
-	if len(statuses) > 0 {
-		query = query.Where("status IN ?", statuses)
+	if len(filters.Types) > 0 {
+		query = query.Where("type IN ?", filters.Types)
+	}
+	if len(filters.Statuses) > 0 {
+		query = query.Where("status IN ?", filters.Statuses)
+	}
+	if filters.Since != nil {
+		query = query.Where("order_timestamp >= ?", *filters.Since)
+	}
+	if filters.Until != nil {
+		query = query.Where("order_timestamp < ?", *filters.Until)
 	}
 
-	if len(params.Query) > 0 {
-		queryPattern := "%" + params.Query + "%"
-		subQuery := repository.db.Where("content ILIKE ?", queryPattern).
-			Or("contact ILIKE ?", queryPattern).
-			Or("failure_reason ILIKE ?", queryPattern).
-			Or("request_id ILIKE ?", queryPattern)
-
+	order := clause.OrderBy{Expression: clause.Expr{SQL: repository.order(params, "created_at")}}
+	if tsQuery := messageSearchTSQuery(params.Query); tsQuery != "" {
+		subQuery := repository.db.Where("search_vector @@ to_tsquery('simple', ?)", tsQuery)
 		if _, err := uuid.Parse(params.Query); err == nil {
 			subQuery = subQuery.Or("id = ?", params.Query)
 		}
-
 		query = query.Where(subQuery)
+
+		// the most relevant messages are returned first when the user does not choose the sort order
+		if len(params.SortBy) == 0 {
+			order = clause.OrderBy{Expression: clause.Expr{
+				SQL:                "ts_rank(search_vector, to_tsquery('simple', ?)) DESC, order_timestamp DESC",
+				Vars:               []any{tsQuery},
+				WithoutParentheses: true,
+			}}
+		}
 	}
 
 	messages := make([]*entities.Message, 0, params.Limit)
-	err := query.Order(repository.order(params, "created_at")).
+	err := query.Order(order).
 		Limit(params.Limit).
 		Offset(params.Skip).
 		Find(&messages).
@@ -166,6 +323,10 @@ func (repository *gormMessageRepository) Search(ctx context.Context, userID enti
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot search messages with for user [%s] params [%+#v]", userID, params))
 	}
 
+	if err = repository.decrypt(ctx, messages...); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
 	return messages, nil
 }
 
@@ -185,6 +346,8 @@ func (repository *gormMessageRepository) GetBulkMessages(ctx context.Context, us
 			COUNT(*) FILTER (WHERE status = 'expired') as expired_count,
 			COUNT(*) FILTER (WHERE status = 'sent') as sent_count,
 			COUNT(*) FILTER (WHERE status = 'delivered') as delivered_count,
+			COUNT(*) FILTER (WHERE status = 'paused') as paused_count,
+			COUNT(*) FILTER (WHERE status = 'canceled') as canceled_count,
 			MIN(created_at) as created_at
 		FROM messages
 		WHERE user_id = ? AND request_id LIKE 'bulk-%'
@@ -199,18 +362,352 @@ func (repository *gormMessageRepository) GetBulkMessages(ctx context.Context, us
 	return orders, nil
 }
 
+// GetBulkMessagesByRequestIDs fetches the bulk message summaries for the given request IDs
+func (repository *gormMessageRepository) GetBulkMessagesByRequestIDs(ctx context.Context, userID entities.UserID, requestIDs []string) ([]*entities.BulkMessage, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	orders := make([]*entities.BulkMessage, 0)
+	if len(requestIDs) == 0 {
+		return orders, nil
+	}
+
+	err := repository.db.WithContext(ctx).Raw(`
+		SELECT
+			request_id,
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE status = 'scheduled') as scheduled_count,
+			COUNT(*) FILTER (WHERE status = 'pending') as pending_count,
+			COUNT(*) FILTER (WHERE status = 'failed') as failed_count,
+			COUNT(*) FILTER (WHERE status = 'expired') as expired_count,
+			COUNT(*) FILTER (WHERE status = 'sent') as sent_count,
+			COUNT(*) FILTER (WHERE status = 'delivered') as delivered_count,
+			COUNT(*) FILTER (WHERE status = 'paused') as paused_count,
+			COUNT(*) FILTER (WHERE status = 'canceled') as canceled_count,
+			MIN(created_at) as created_at
+		FROM messages
+		WHERE user_id = ? AND request_id IN ?
+		GROUP BY request_id
+	`, userID, requestIDs).Scan(&orders).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch [%d] bulk message orders for user [%s]", len(requestIDs), userID))
+	}
+
+	return orders, nil
+}
+
+// messageAnalyticsColumns are the aggregates of entities.MessageAnalyticsStats
+const messageAnalyticsColumns = `
+	COUNT(*) AS total,
+	COUNT(*) FILTER (WHERE status = 'pending') AS pending_count,
+	COUNT(*) FILTER (WHERE status = 'scheduled') AS scheduled_count,
+	COUNT(*) FILTER (WHERE status = 'sending') AS sending_count,
+	COUNT(*) FILTER (WHERE status = 'sent') AS sent_count,
+	COUNT(*) FILTER (WHERE status = 'delivered') AS delivered_count,
+	COUNT(*) FILTER (WHERE status = 'failed') AS failed_count,
+	COUNT(*) FILTER (WHERE status = 'expired') AS expired_count,
+	COUNT(*) FILTER (WHERE status = 'received') AS received_count,
+	COUNT(*) FILTER (WHERE status = 'canceled') AS canceled_count,
+	COUNT(*) FILTER (WHERE status = 'paused') AS paused_count,
+	COUNT(*) FILTER (WHERE status = 'delivered')::FLOAT / NULLIF(COUNT(*) FILTER (WHERE status IN ('sent', 'delivered', 'failed', 'expired')), 0) AS delivery_rate,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY send_duration::FLOAT) / 1000000 AS send_duration_p50,
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY send_duration::FLOAT) / 1000000 AS send_duration_p95,
+	percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM delivered_at - sent_at)::FLOAT) * 1000 AS delivery_latency_p50,
+	percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM delivered_at - sent_at)::FLOAT) * 1000 AS delivery_latency_p95`
+
+// messageAnalyticsFailureReasonLimit is the number of the most frequent failure reasons returned by MessageRepository.Analytics
+const messageAnalyticsFailureReasonLimit = 10
+
+// Analytics aggregates the entities.Message of a user by period, by phone and by failure reason
+func (repository *gormMessageRepository) Analytics(ctx context.Context, userID entities.UserID, filters MessageAnalyticsFilters) (*entities.MessageAnalytics, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	analytics := &entities.MessageAnalytics{
+		From:           filters.Since,
+		To:             filters.Until,
+		Owner:          filters.Owner,
+		Granularity:    filters.Granularity,
+		Periods:        make([]*entities.MessageAnalyticsPeriod, 0),
+		Phones:         make([]*entities.MessageAnalyticsPhone, 0),
+		FailureReasons: make([]*entities.MessageAnalyticsFailureReason, 0),
+	}
+
+	if err := repository.analyticsQuery(ctx, userID, filters).Select(messageAnalyticsColumns).Scan(&analytics.Totals).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot aggregate message totals for user [%s] and filters [%+#v]", userID, filters))
+	}
+
+	period := repository.analyticsPeriod(filters.Granularity)
+	err := repository.analyticsQuery(ctx, userID, filters).
+		Select(period + " AS timestamp," + messageAnalyticsColumns).
+		Group(period).
+		Order(period + " ASC").
+		Scan(&analytics.Periods).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot aggregate messages by [%s] for user [%s] and filters [%+#v]", filters.Granularity, userID, filters))
+	}
+
+	err = repository.analyticsQuery(ctx, userID, filters).
+		Select("owner," + messageAnalyticsColumns).
+		Group("owner").
+		Order("owner ASC").
+		Scan(&analytics.Phones).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot aggregate messages by owner for user [%s] and filters [%+#v]", userID, filters))
+	}
+
+	err = repository.analyticsQuery(ctx, userID, filters).
+		Select("failure_reason, COUNT(*) AS count").
+		Where("failure_reason IS NOT NULL").
+		Where("failure_reason <> ?", "").
+		Group("failure_reason").
+		Order("count DESC").
+		Order("failure_reason ASC").
+		Limit(messageAnalyticsFailureReasonLimit).
+		Scan(&analytics.FailureReasons).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot aggregate failure reasons for user [%s] and filters [%+#v]", userID, filters))
+	}
+
+	return analytics, nil
+}
+
+func (repository *gormMessageRepository) analyticsQuery(ctx context.Context, userID entities.UserID, filters MessageAnalyticsFilters) *gorm.DB {
+	query := repository.db.WithContext(ctx).
+		Model(&entities.Message{}).
+		Where("user_id = ?", userID).
+		Where("order_timestamp >= ?", filters.Since).
+		Where("order_timestamp < ?", filters.Until)
+	if filters.Owner != nil {
+		query = query.Where("owner = ?", *filters.Owner)
+	} else if len(filters.Owners) > 0 {
+		query = query.Where("owner IN ?", filters.Owners)
+	}
+	return query
+}
+
+// analyticsPeriod returns the expression which truncates the order timestamp of a message to the start of its period in UTC
+func (repository *gormMessageRepository) analyticsPeriod(granularity entities.MessageAnalyticsGranularity) string {
+	unit := "day"
+	switch granularity {
+	case entities.MessageAnalyticsGranularityHour:
+		unit = "hour"
+	case entities.MessageAnalyticsGranularityWeek:
+		unit = "week"
+	case entities.MessageAnalyticsGranularityMonth:
+		unit = "month"
+	}
+	return fmt.Sprintf("date_trunc('%s', order_timestamp AT TIME ZONE 'UTC')", unit)
+}
+
+// FetchByRequestID fetches the entities.Message with a request ID and one of the statuses in the order they were created
+func (repository *gormMessageRepository) FetchByRequestID(ctx context.Context, userID entities.UserID, requestID string, statuses []entities.MessageStatus) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	messages := make([]*entities.Message, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("request_id = ?", requestID).
+		Where("status IN ?", statuses).
+		Order("created_at ASC").
+		Find(&messages).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch messages with request ID [%s] for user [%s]", requestID, userID))
+	}
+
+	if err = repository.decrypt(ctx, messages...); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
+	return messages, nil
+}
+
+// IndexByRequestID fetches a page of the entities.Message with a request ID in the order they were created
+func (repository *gormMessageRepository) IndexByRequestID(ctx context.Context, userID entities.UserID, requestID string, params IndexParams) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	messages := make([]*entities.Message, 0)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("request_id = ?", requestID).
+		Order("created_at ASC").
+		Order("id ASC").
+		Limit(params.Limit).
+		Offset(params.Skip).
+		Find(&messages).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch messages with request ID [%s] for user [%s] and params [%+#v]", requestID, userID, params))
+	}
+
+	if err = repository.decrypt(ctx, messages...); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
+	return messages, nil
+}
+
+// UpdateStatusByRequestID changes the status of the entities.Message with a request ID which have one of the given statuses
+func (repository *gormMessageRepository) UpdateStatusByRequestID(ctx context.Context, userID entities.UserID, requestID string, statuses []entities.MessageStatus, status entities.MessageStatus) ([]*entities.Message, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	messages := make([]*entities.Message, 0)
+	err := repository.db.WithContext(ctx).
+		Model(&messages).
+		Clauses(clause.Returning{}).
+		Where("user_id = ?", userID).
+		Where("request_id = ?", requestID).
+		Where("status IN ?", statuses).
+		Updates(map[string]any{"status": status, "updated_at": time.Now().UTC()}).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update status of messages with request ID [%s] to [%s] for user [%s]", requestID, status, userID))
+	}
+
+	if err = repository.decrypt(ctx, messages...); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
+	return messages, nil
+}
+
+// RegisterUsage sets the UsageRegisteredAt of an entities.Message which is not canceled or paused.
+// It returns false when the usage of the message was already registered or the message cannot be sent.
+func (repository *gormMessageRepository) RegisterUsage(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (bool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	// the status is checked in the same statement so that a message which is canceled at the same time is not registered
+	result := repository.db.WithContext(ctx).Exec(
+		"UPDATE messages SET usage_registered_at = ? WHERE user_id = ? AND id = ? AND usage_registered_at IS NULL AND status NOT IN ?",
+		timestamp,
+		userID,
+		messageID,
+		[]entities.MessageStatus{entities.MessageStatusCanceled, entities.MessageStatusPaused},
+	)
+	if result.Error != nil {
+		return false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(result.Error, "cannot register usage of message [%s] for user [%s]", messageID, userID))
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UnregisterUsage clears the UsageRegisteredAt of an entities.Message, it returns false when the usage of the message was not registered
+func (repository *gormMessageRepository) UnregisterUsage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (bool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result := repository.db.WithContext(ctx).Exec(
+		"UPDATE messages SET usage_registered_at = NULL WHERE user_id = ? AND id = ? AND usage_registered_at IS NOT NULL",
+		userID,
+		messageID,
+	)
+	if result.Error != nil {
+		return false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(result.Error, "cannot unregister usage of message [%s] for user [%s]", messageID, userID))
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// FetchContacts returns the distinct contacts who exchanged messages of the given types with an owner since a timestamp
+func (repository *gormMessageRepository) FetchContacts(ctx context.Context, userID entities.UserID, owner string, types []entities.MessageType, since time.Time, limit int) ([]string, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	contacts := make([]string, 0)
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Message{}).
+		Distinct("contact").
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("type IN ?", types).
+		Where("order_timestamp >= ?", since).
+		Order("contact ASC").
+		Limit(limit).
+		Pluck("contact", &contacts).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch contacts of owner [%s] for user [%s] since [%s]", owner, userID, since))
+	}
+
+	return contacts, nil
+}
+
 // Store a new entities.Message
 func (repository *gormMessageRepository) Store(ctx context.Context, message *entities.Message) error {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	if err := repository.db.WithContext(ctx).Create(message).Error; err != nil {
+	if err := repository.setOrganisationIDs(ctx, message); err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save message with ID [%s]", message.ID))
+	}
+
+	restore, err := repository.encrypt(ctx, message)
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, err)
+	}
+	defer restore()
+
+	if err = repository.db.WithContext(ctx).Create(message).Error; err != nil {
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save message with ID [%s]", message.ID))
 	}
 
 	return nil
 }
 
+// StoreBatch stores multiple entities.Message and skips messages with an ID which already exists
+func (repository *gormMessageRepository) StoreBatch(ctx context.Context, messages []*entities.Message) (int, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	if err := repository.setOrganisationIDs(ctx, messages...); err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save [%d] messages", len(messages)))
+	}
+
+	restore, err := repository.encrypt(ctx, messages...)
+	if err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, err)
+	}
+	defer restore()
+
+	result := repository.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(messages, 100)
+	if result.Error != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(result.Error, "cannot save [%d] messages", len(messages)))
+	}
+
+	return int(result.RowsAffected), nil
+}
+
+// setOrganisationIDs shares new messages with the organisation of their user, the organisation is loaded once for each user
+func (repository *gormMessageRepository) setOrganisationIDs(ctx context.Context, messages ...*entities.Message) error {
+	organisations := make(map[entities.UserID]*uuid.UUID)
+	for _, message := range messages {
+		if message.OrganisationID != nil {
+			continue
+		}
+
+		organisationID, ok := organisations[message.UserID]
+		if !ok {
+			var err error
+			if organisationID, err = organisationIDOfOwner(ctx, repository.db, message.UserID); err != nil {
+				return err
+			}
+			organisations[message.UserID] = organisationID
+		}
+		message.OrganisationID = organisationID
+	}
+	return nil
+}
+
 // Load an entities.Message by ID
 func (repository *gormMessageRepository) Load(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
 	ctx, span := repository.tracer.Start(ctx)
@@ -226,6 +723,10 @@ func (repository *gormMessageRepository) Load(ctx context.Context, userID entiti
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load message with ID [%s]", messageID))
 	}
 
+	if err = repository.decrypt(ctx, message); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
 	return message, nil
 }
 
@@ -234,7 +735,13 @@ func (repository *gormMessageRepository) Update(ctx context.Context, message *en
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	if err := repository.db.WithContext(ctx).Save(message).Error; err != nil {
+	restore, err := repository.encrypt(ctx, message)
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, err)
+	}
+	defer restore()
+
+	if err = repository.db.WithContext(ctx).Save(message).Error; err != nil {
 		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update message with ID [%s]", message.ID))
 	}
 
@@ -275,9 +782,108 @@ func (repository *gormMessageRepository) GetOutstanding(ctx context.Context, use
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCodef(ErrCodeNotFound, "outstanding message with ID [%s] and userID [%s] does not exist", messageID, userID))
 	}
 
+	if err = repository.decrypt(ctx, message); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
 	return message, nil
 }
 
+// ReEncrypt encrypts the content of at most limit entities.Message of a user which is not encrypted with the active key of the user
+func (repository *gormMessageRepository) ReEncrypt(ctx context.Context, userID entities.UserID, limit int) (int, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	prefix, err := repository.cipher.ActivePrefix(ctx, userID)
+	if err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load the active prefix of the content for user [%s]", userID))
+	}
+
+	if prefix == "" {
+		return 0, nil
+	}
+
+	messages := make([]*entities.Message, 0, limit)
+	err = repository.db.WithContext(ctx).
+		Select("id", "user_id", "content").
+		Where("user_id = ?", userID).
+		Where("content <> ?", "").
+		Where("content NOT LIKE ?", prefix+"%").
+		Limit(limit).
+		Find(&messages).
+		Error
+	if err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch messages to re-encrypt for user [%s]", userID))
+	}
+
+	originals := make([]string, 0, len(messages))
+	for _, message := range messages {
+		originals = append(originals, message.Content)
+	}
+
+	if err = repository.decrypt(ctx, messages...); err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, err)
+	}
+
+	for index, message := range messages {
+		content, err := repository.cipher.Encrypt(ctx, userID, message.Content)
+		if err != nil {
+			return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot encrypt content of message [%s] for user [%s]", message.ID, userID))
+		}
+
+		// the content is only replaced when it was not changed since it was fetched e.g. when the message was purged
+		result := repository.db.WithContext(ctx).
+			Model(&entities.Message{}).
+			Where("user_id = ?", userID).
+			Where("id = ?", message.ID).
+			Where("content = ?", originals[index]).
+			UpdateColumn("content", content)
+		if result.Error != nil {
+			return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(result.Error, "cannot update content of message [%s] for user [%s]", message.ID, userID))
+		}
+
+		if result.RowsAffected == 0 {
+			repository.logger.Info(fmt.Sprintf("content of message [%s] for user [%s] was changed while it was re-encrypted", message.ID, userID))
+		}
+	}
+
+	return len(messages), nil
+}
+
+// encrypt replaces the content of the messages with the encrypted content and returns a function which restores the plaintext content
+func (repository *gormMessageRepository) encrypt(ctx context.Context, messages ...*entities.Message) (func(), error) {
+	contents := make([]string, 0, len(messages))
+	restore := func() {
+		for index, content := range contents {
+			messages[index].Content = content
+		}
+	}
+
+	for _, message := range messages {
+		content, err := repository.cipher.Encrypt(ctx, message.UserID, message.Content)
+		if err != nil {
+			restore()
+			return nil, stacktrace.Propagatef(err, "cannot encrypt content of message [%s] for user [%s]", message.ID, message.UserID)
+		}
+		contents = append(contents, message.Content)
+		message.Content = content
+	}
+
+	return restore, nil
+}
+
+// decrypt replaces the content of messages which were loaded from the database with the plaintext content
+func (repository *gormMessageRepository) decrypt(ctx context.Context, messages ...*entities.Message) error {
+	for _, message := range messages {
+		content, err := repository.cipher.Decrypt(ctx, message.UserID, message.Content)
+		if err != nil {
+			return stacktrace.Propagatef(err, "cannot decrypt content of message [%s] for user [%s]", message.ID, message.UserID)
+		}
+		message.Content = content
+	}
+	return nil
+}
+
 func (repository *gormMessageRepository) order(params IndexParams, defaultSortBy string) string {
 	sortBy := defaultSortBy
 	if len(params.SortBy) > 0 {
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormMessageTemplateRepository is responsible for persisting entities.MessageTemplate
+type gormMessageTemplateRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormMessageTemplateRepository creates the GORM version of the MessageTemplateRepository
+func NewGormMessageTemplateRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) MessageTemplateRepository {
+	return &gormMessageTemplateRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormMessageTemplateRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.MessageTemplate
+func (repository *gormMessageTemplateRepository) Store(ctx context.Context, template *entities.MessageTemplate) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(template).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save message template with ID [%s]", template.ID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.MessageTemplate
+func (repository *gormMessageTemplateRepository) Update(ctx context.Context, template *entities.MessageTemplate) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(template).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update message template with ID [%s]", template.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.MessageTemplate by ID
+func (repository *gormMessageTemplateRepository) Load(ctx context.Context, userID entities.UserID, templateID uuid.UUID) (*entities.MessageTemplate, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	template := new(entities.MessageTemplate)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", templateID).
+		First(template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "message template with ID [%s] does not exist for user [%s]", templateID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load message template with ID [%s] for user [%s]", templateID, userID))
+	}
+
+	return template, nil
+}
+
+// Index entities.MessageTemplate of a user
+func (repository *gormMessageTemplateRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.MessageTemplate, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("content ILIKE ?", queryPattern))
+	}
+
+	templates := make([]*entities.MessageTemplate, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&templates).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch message templates for user [%s] and params [%+#v]", userID, params))
+	}
+
+	return templates, nil
+}
+
+// Delete an entities.MessageTemplate by ID
+func (repository *gormMessageTemplateRepository) Delete(ctx context.Context, userID entities.UserID, templateID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", templateID).
+		Delete(&entities.MessageTemplate{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete message template with ID [%s] for user [%s]", templateID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.MessageTemplate for a user
+func (repository *gormMessageTemplateRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.MessageTemplate{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.MessageTemplate{}, userID))
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ type gormMessageThreadRepository struct {
 	logger telemetry.Logger
 	tracer telemetry.Tracer
 	db     *gorm.DB
+	cipher ContentCipher
 }
 
 // NewGormMessageThreadRepository creates the GORM version of the MessageRepository
@@ -27,11 +28,13 @@ func NewGormMessageThreadRepository(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	db *gorm.DB,
+	cipher ContentCipher,
 ) MessageThreadRepository {
 	return &gormMessageThreadRepository{
 		logger: logger.WithService(fmt.Sprintf("%T", &gormMessageThreadRepository{})),
 		tracer: tracer,
 		db:     db,
+		cipher: cipher,
 	}
 }
 
@@ -74,6 +77,7 @@ func messageThreadStatusUpdates(params MessageThreadStatusUpdate) map[string]any
 			updates["last_read_at"] = params.ReadAt
 		}
 	}
+	updates["actor_id"] = params.ActorID
 	return updates
 }
 
@@ -106,6 +110,14 @@ func (repository *gormMessageThreadRepository) UpdateAfterDeletedMessage(ctx con
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
+	if params.LastMessageContent != nil {
+		content, err := repository.cipher.Encrypt(ctx, params.UserID, *params.LastMessageContent)
+		if err != nil {
+			return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot encrypt last message content for thread [%s]", params.MessageThreadID))
+		}
+		params.LastMessageContent = &content
+	}
+
 	result := repository.db.WithContext(ctx).
 		Model(&entities.MessageThread{}).
 		Where("user_id = ?", params.UserID).
@@ -123,8 +135,22 @@ func (repository *gormMessageThreadRepository) Store(ctx context.Context, thread
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
+	if thread.OrganisationID == nil {
+		organisationID, err := organisationIDOfOwner(ctx, repository.db, thread.UserID)
+		if err != nil {
+			return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save thread with ID [%s]", thread.ID))
+		}
+		thread.OrganisationID = organisationID
+	}
+
+	restore, err := repository.encrypt(ctx, thread)
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, err)
+	}
+	defer restore()
+
 	isRead := thread.IsRead
-	err := repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(thread)
 		thread.IsRead = isRead
 		if result.Error != nil {
@@ -152,6 +178,12 @@ func (repository *gormMessageThreadRepository) UpdateActivity(ctx context.Contex
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
+	content, err := repository.cipher.Encrypt(ctx, params.UserID, params.Content)
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot encrypt last message content for thread [%s]", params.MessageThreadID))
+	}
+	params.Content = content
+
 	result := repository.db.WithContext(ctx).
 		Model(&entities.MessageThread{}).
 		Where("user_id = ?", params.UserID).
@@ -191,6 +223,10 @@ func (repository *gormMessageThreadRepository) UpdateStatus(
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(gorm.ErrRecordNotFound, ErrCodeNotFound, "thread with id [%s] not found for user with ID [%s]", messageThreadID, userID))
 	}
 
+	if err := repository.decrypt(ctx, thread); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
 	return thread, nil
 }
 
@@ -216,6 +252,10 @@ func (repository *gormMessageThreadRepository) LoadByOwnerContact(ctx context.Co
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load thread with owner [%s] and contact [%s]", owner, contact))
 	}
 
+	if err = repository.decrypt(ctx, thread); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
 	return thread, nil
 }
 
@@ -240,6 +280,10 @@ func (repository *gormMessageThreadRepository) Load(ctx context.Context, userID
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "thread with id [%s]", ID))
 	}
 
+	if err = repository.decrypt(ctx, thread); err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, err)
+	}
+
 	return thread, nil
 }
 
@@ -251,7 +295,8 @@ func (repository *gormMessageThreadRepository) Index(ctx context.Context, userID
 	query := repository.db.
 		WithContext(ctx).
 		Where("user_id = ?", userID).
-		Where("owner = ?", owner)
+		Where("owner = ?", owner).
+		Scopes(organisationScope(params.OrganisationID))
 
 	if isArchived {
 		query.Where("is_archived = ?", isArchived)
@@ -269,9 +314,105 @@ func (repository *gormMessageThreadRepository) Index(ctx context.Context, userID
 	}
 
 	threads := new([]entities.MessageThread)
-	if err := query.Order("order_timestamp DESC").Limit(params.Limit).Offset(params.Skip).Find(&threads).Error; err != nil {
+	if err := paginate(query, "order_timestamp", params).Find(&threads).Error; err != nil {
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch message threads with owner [%s] and params [%+#v]", owner, params))
 	}
 
+	reversePage(params, *threads)
+
+	for index := range *threads {
+		if err := repository.decrypt(ctx, &(*threads)[index]); err != nil {
+			return nil, repository.tracer.WrapErrorSpan(span, err)
+		}
+	}
+
 	return threads, nil
 }
+
+// ReEncrypt encrypts the last message content of at most limit entities.MessageThread of a user which is not encrypted with the active key of the user
+func (repository *gormMessageThreadRepository) ReEncrypt(ctx context.Context, userID entities.UserID, limit int) (int, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	prefix, err := repository.cipher.ActivePrefix(ctx, userID)
+	if err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load the active prefix of the content for user [%s]", userID))
+	}
+
+	if prefix == "" {
+		return 0, nil
+	}
+
+	threads := make([]*entities.MessageThread, 0, limit)
+	err = repository.db.WithContext(ctx).
+		Select("id", "user_id", "last_message_content").
+		Where("user_id = ?", userID).
+		Where("last_message_content <> ?", "").
+		Where("last_message_content NOT LIKE ?", prefix+"%").
+		Limit(limit).
+		Find(&threads).
+		Error
+	if err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch threads to re-encrypt for user [%s]", userID))
+	}
+
+	for _, thread := range threads {
+		original := *thread.LastMessageContent
+		if err = repository.decrypt(ctx, thread); err != nil {
+			return 0, repository.tracer.WrapErrorSpan(span, err)
+		}
+
+		content, err := repository.cipher.Encrypt(ctx, userID, *thread.LastMessageContent)
+		if err != nil {
+			return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot encrypt last message content of thread [%s] for user [%s]", thread.ID, userID))
+		}
+
+		// the content is only replaced when no new message was added to the thread since it was fetched
+		result := repository.db.WithContext(ctx).
+			Model(&entities.MessageThread{}).
+			Where("user_id = ?", userID).
+			Where("id = ?", thread.ID).
+			Where("last_message_content = ?", original).
+			UpdateColumn("last_message_content", content)
+		if result.Error != nil {
+			return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(result.Error, "cannot update last message content of thread [%s] for user [%s]", thread.ID, userID))
+		}
+
+		if result.RowsAffected == 0 {
+			repository.logger.Info(fmt.Sprintf("last message content of thread [%s] for user [%s] was changed while it was re-encrypted", thread.ID, userID))
+		}
+	}
+
+	return len(threads), nil
+}
+
+// encrypt replaces the last message content of the thread with the encrypted content and returns a function which restores the plaintext content
+func (repository *gormMessageThreadRepository) encrypt(ctx context.Context, thread *entities.MessageThread) (func(), error) {
+	plaintext := thread.LastMessageContent
+	if plaintext == nil {
+		return func() {}, nil
+	}
+
+	content, err := repository.cipher.Encrypt(ctx, thread.UserID, *plaintext)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot encrypt last message content of thread [%s] for user [%s]", thread.ID, thread.UserID)
+	}
+
+	thread.LastMessageContent = &content
+	return func() { thread.LastMessageContent = plaintext }, nil
+}
+
+// decrypt replaces the last message content of a thread which was loaded from the database with the plaintext content
+func (repository *gormMessageThreadRepository) decrypt(ctx context.Context, thread *entities.MessageThread) error {
+	if thread.LastMessageContent == nil {
+		return nil
+	}
+
+	content, err := repository.cipher.Decrypt(ctx, thread.UserID, *thread.LastMessageContent)
+	if err != nil {
+		return stacktrace.Propagatef(err, "cannot decrypt last message content of thread [%s] for user [%s]", thread.ID, thread.UserID)
+	}
+
+	thread.LastMessageContent = &content
+	return nil
+}
@@ -87,10 +87,12 @@ func TestMessageThreadStorePreservesExplicitUnreadState(t *testing.T) {
 	require.NoError(t, err)
 
 	logger := &messageThreadTestLogger{}
-	repository := NewGormMessageThreadRepository(logger, telemetry.NewOtelLogger("test", logger), db)
+	repository := NewGormMessageThreadRepository(logger, telemetry.NewOtelLogger("test", logger), db, NewPlaintextContentCipher())
+	organisationID := uuid.New()
 	thread := &entities.MessageThread{
-		ID:     uuid.New(),
-		IsRead: false,
+		ID:             uuid.New(),
+		OrganisationID: &organisationID,
+		IsRead:         false,
 	}
 
 	require.NoError(t, repository.Store(context.Background(), thread))
@@ -135,7 +137,7 @@ func TestUpdateActivityMarksUnreadWithOneQuery(t *testing.T) {
 	require.NoError(t, err)
 
 	logger := &messageThreadTestLogger{}
-	repository := NewGormMessageThreadRepository(logger, telemetry.NewOtelLogger("test", logger), db)
+	repository := NewGormMessageThreadRepository(logger, telemetry.NewOtelLogger("test", logger), db, NewPlaintextContentCipher())
 
 	err = repository.UpdateActivity(context.Background(), MessageThreadActivityUpdate{
 		MessageThreadID: uuid.New(),
@@ -187,6 +189,7 @@ func TestMessageThreadStatusUpdatesReadOnly(t *testing.T) {
 	assert.Equal(t, map[string]any{
 		"is_read":      true,
 		"last_read_at": readAt,
+		"actor_id":     (*entities.UserID)(nil),
 	}, updates)
 	assert.NotContains(t, updates, "is_archived")
 }
@@ -198,7 +201,19 @@ func TestMessageThreadStatusUpdatesArchiveOnly(t *testing.T) {
 		IsArchived: &isArchived,
 	})
 
-	assert.Equal(t, map[string]any{"is_archived": true}, updates)
+	assert.Equal(t, map[string]any{"is_archived": true, "actor_id": (*entities.UserID)(nil)}, updates)
 	assert.NotContains(t, updates, "is_read")
 	assert.NotContains(t, updates, "last_read_at")
 }
+
+func TestMessageThreadStatusUpdatesRecordsTheActingMember(t *testing.T) {
+	isArchived := true
+	actorID := entities.UserID("member-id")
+
+	updates := messageThreadStatusUpdates(MessageThreadStatusUpdate{
+		IsArchived: &isArchived,
+		ActorID:    &actorID,
+	})
+
+	assert.Equal(t, &actorID, updates["actor_id"])
+}
@@ -0,0 +1,327 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// organisationMembershipQuery selects an entities.OrganisationMembership
+const organisationMembershipQuery = `
+SELECT organisations.*,
+       organisation_members.id AS member_id,
+       organisation_members.role,
+       organisation_members.accepted_at
+FROM organisation_members
+JOIN organisations ON organisations.id = organisation_members.organisation_id
+`
+
+// organisationTables are the tables of the resources which are shared with the members of an entities.Organisation
+var organisationTables = []string{"phones", "messages", "message_threads", "contacts"}
+
+// gormOrganisationRepository is responsible for persisting entities.Organisation
+type gormOrganisationRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormOrganisationRepository creates the GORM version of the OrganisationRepository
+func NewGormOrganisationRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) OrganisationRepository {
+	return &gormOrganisationRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormOrganisationRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.Organisation with the entities.OrganisationMember of the owner
+func (repository *gormOrganisationRepository) Store(ctx context.Context, organisation *entities.Organisation, owner *entities.OrganisationMember) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organisation).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(owner).Error; err != nil {
+			return err
+		}
+
+		// the existing resources of the owner are shared with the members of the new organisation
+		for _, table := range organisationTables {
+			if err := tx.Table(table).Where("user_id = ?", organisation.OwnerID).Update("organisation_id", organisation.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save organisation with ID [%s]", organisation.ID))
+	}
+
+	return nil
+}
+
+// Update an entities.Organisation
+func (repository *gormOrganisationRepository) Update(ctx context.Context, organisation *entities.Organisation) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(organisation).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update organisation with ID [%s]", organisation.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.Organisation by ID
+func (repository *gormOrganisationRepository) Load(ctx context.Context, organisationID uuid.UUID) (*entities.Organisation, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	organisation := new(entities.Organisation)
+	err := repository.db.WithContext(ctx).Where("id = ?", organisationID).First(organisation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "organisation with ID [%s] does not exist", organisationID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load organisation with ID [%s]", organisationID))
+	}
+
+	return organisation, nil
+}
+
+// LoadByOwner loads the entities.Organisation which is owned by a user
+func (repository *gormOrganisationRepository) LoadByOwner(ctx context.Context, ownerID entities.UserID) (*entities.Organisation, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	organisation := new(entities.Organisation)
+	err := repository.db.WithContext(ctx).Where("owner_id = ?", ownerID).First(organisation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "organisation owned by user [%s] does not exist", ownerID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load organisation owned by user [%s]", ownerID))
+	}
+
+	return organisation, nil
+}
+
+// Delete an entities.Organisation and all its members
+func (repository *gormOrganisationRepository) Delete(ctx context.Context, organisationID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organisation_id = ?", organisationID).Delete(&entities.OrganisationMember{}).Error; err != nil {
+			return err
+		}
+		for _, table := range organisationTables {
+			if err := tx.Table(table).Where("organisation_id = ?", organisationID).Update("organisation_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("id = ?", organisationID).Delete(&entities.Organisation{}).Error
+	})
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete organisation with ID [%s]", organisationID))
+	}
+
+	return nil
+}
+
+// StoreMember stores a new entities.OrganisationMember
+func (repository *gormOrganisationRepository) StoreMember(ctx context.Context, member *entities.OrganisationMember) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(member).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save organisation member with ID [%s]", member.ID))
+	}
+
+	return nil
+}
+
+// UpdateMember updates an entities.OrganisationMember
+func (repository *gormOrganisationRepository) UpdateMember(ctx context.Context, member *entities.OrganisationMember) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(member).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update organisation member with ID [%s]", member.ID))
+	}
+
+	return nil
+}
+
+// LoadMember loads an entities.OrganisationMember by ID
+func (repository *gormOrganisationRepository) LoadMember(ctx context.Context, organisationID uuid.UUID, memberID uuid.UUID) (*entities.OrganisationMember, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	member := new(entities.OrganisationMember)
+	err := repository.db.WithContext(ctx).
+		Where("organisation_id = ?", organisationID).
+		Where("id = ?", memberID).
+		First(member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "member with ID [%s] does not exist in organisation [%s]", memberID, organisationID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load member with ID [%s] in organisation [%s]", memberID, organisationID))
+	}
+
+	return member, nil
+}
+
+// LoadMemberByEmail loads the entities.OrganisationMember which was invited with an email address
+func (repository *gormOrganisationRepository) LoadMemberByEmail(ctx context.Context, organisationID uuid.UUID, email string) (*entities.OrganisationMember, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	member := new(entities.OrganisationMember)
+	err := repository.db.WithContext(ctx).
+		Where("organisation_id = ?", organisationID).
+		Where("email = ?", email).
+		First(member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "member with email [%s] does not exist in organisation [%s]", email, organisationID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load member with email [%s] in organisation [%s]", email, organisationID))
+	}
+
+	return member, nil
+}
+
+// IndexMembers fetches the entities.OrganisationMember of an organisation
+func (repository *gormOrganisationRepository) IndexMembers(ctx context.Context, organisationID uuid.UUID, params IndexParams) ([]*entities.OrganisationMember, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("organisation_id = ?", organisationID)
+	if len(params.Query) > 0 {
+		query = query.Where("email ILIKE ?", "%"+params.Query+"%")
+	}
+
+	members := make([]*entities.OrganisationMember, 0)
+	if err := query.Order("created_at ASC").Limit(params.Limit).Offset(params.Skip).Find(&members).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch members of organisation [%s] with params [%+#v]", organisationID, params))
+	}
+
+	return members, nil
+}
+
+// DeleteMember deletes an entities.OrganisationMember
+func (repository *gormOrganisationRepository) DeleteMember(ctx context.Context, organisationID uuid.UUID, memberID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("organisation_id = ?", organisationID).
+		Where("id = ?", memberID).
+		Delete(&entities.OrganisationMember{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete member with ID [%s] from organisation [%s]", memberID, organisationID))
+	}
+
+	return nil
+}
+
+// LoadMembership loads the entities.OrganisationMembership of a user who accepted the invitation to an organisation
+func (repository *gormOrganisationRepository) LoadMembership(ctx context.Context, organisationID uuid.UUID, userID entities.UserID) (*entities.OrganisationMembership, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var memberships []*entities.OrganisationMembership
+	err := repository.db.WithContext(ctx).
+		Raw(organisationMembershipQuery+"WHERE organisations.id = ? AND organisation_members.user_id = ? AND organisation_members.accepted_at IS NOT NULL", organisationID, userID).
+		Scan(&memberships).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load membership of user [%s] in organisation [%s]", userID, organisationID))
+	}
+
+	if len(memberships) == 0 {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCodef(ErrCodeNotFound, "user [%s] is not a member of organisation [%s]", userID, organisationID))
+	}
+
+	return memberships[0], nil
+}
+
+// FetchMemberships fetches the organisations of a user and the pending invitations to the email address of the user
+func (repository *gormOrganisationRepository) FetchMemberships(ctx context.Context, userID entities.UserID, email string) ([]*entities.OrganisationMembership, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	memberships := make([]*entities.OrganisationMembership, 0)
+	err := repository.db.WithContext(ctx).
+		Raw(organisationMembershipQuery+"WHERE organisation_members.user_id = ? OR (organisation_members.accepted_at IS NULL AND organisation_members.email = ?) ORDER BY organisations.name ASC", userID, email).
+		Scan(&memberships).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch memberships of user [%s]", userID))
+	}
+
+	return memberships, nil
+}
+
+// DeleteAllForUser deletes the organisations owned by a user and the memberships of the user
+func (repository *gormOrganisationRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owned := tx.Model(&entities.Organisation{}).Select("id").Where("owner_id = ?", userID)
+		if err := tx.Where("organisation_id IN (?)", owned).Delete(&entities.OrganisationMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&entities.OrganisationMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("owner_id = ?", userID).Delete(&entities.Organisation{}).Error
+	})
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete organisations and memberships of user [%s]", userID))
+	}
+
+	return nil
+}
+
+// organisationIDOfOwner fetches the ID of the entities.Organisation which is owned by a user so that new resources of the user are shared with its members.
+// It is nil when the user does not own an organisation.
+func organisationIDOfOwner(ctx context.Context, db *gorm.DB, userID entities.UserID) (*uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := db.WithContext(ctx).Model(&entities.Organisation{}).Where("owner_id = ?", userID).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot load the organisation owned by user [%s]", userID)
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return &ids[0], nil
+}
+
+// organisationScope limits a query to the resources of an entities.Organisation, queries which are not made in an organisation are not limited
+func organisationScope(organisationID *uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if organisationID == nil {
+			return db
+		}
+		return db.Where("organisation_id = ?", *organisationID)
+	}
+}
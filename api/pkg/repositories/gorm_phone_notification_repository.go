@@ -78,6 +78,48 @@ func (repository *gormPhoneNotificationRepository) DeleteByMessageID(ctx context
 	return nil
 }
 
+// DeleteBefore deletes at most limit entities.PhoneNotification of a user which were created before the timestamp and returns the number of deleted rows
+func (repository *gormPhoneNotificationRepository) DeleteBefore(ctx context.Context, userID entities.UserID, timestamp time.Time, limit int) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ids := repository.db.Model(&entities.PhoneNotification{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Where("created_at < ?", timestamp).
+		Limit(limit)
+
+	result := repository.db.WithContext(ctx).Where("id IN (?)", ids).Delete(&entities.PhoneNotification{})
+	if result.Error != nil {
+		return 0, repository.tracer.WrapErrorSpan(
+			span,
+			stacktrace.Propagatef(result.Error, "cannot delete [%T] before [%s] for user [%s]", &entities.PhoneNotification{}, timestamp, userID),
+		)
+	}
+
+	return result.RowsAffected, nil
+}
+
+// Exists checks if an entities.PhoneNotification has not been deleted
+func (repository *gormPhoneNotificationRepository) Exists(ctx context.Context, userID entities.UserID, notificationID uuid.UUID) (bool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	var exists bool
+	err := repository.db.WithContext(ctx).
+		Model(&entities.PhoneNotification{}).
+		Select("count(*) > 0").
+		Where("user_id = ?", userID).
+		Where("id = ?", notificationID).
+		Find(&exists).
+		Error
+	if err != nil {
+		return false, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot check if notification [%s] exists for user [%s]", notificationID, userID))
+	}
+
+	return exists, nil
+}
+
 // UpdateStatus updates the status of a phone notification.
 func (repository *gormPhoneNotificationRepository) UpdateStatus(ctx context.Context, notificationID uuid.UUID, status entities.PhoneNotificationStatus) error {
 	ctx, span := repository.tracer.Start(ctx)
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormPhonePoolRepository is responsible for persisting entities.PhonePool
+type gormPhonePoolRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormPhonePoolRepository creates the GORM version of the PhonePoolRepository
+func NewGormPhonePoolRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) PhonePoolRepository {
+	return &gormPhonePoolRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormPhonePoolRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.PhonePool
+func (repository *gormPhonePoolRepository) Store(ctx context.Context, pool *entities.PhonePool) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(pool).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save phone pool with ID [%s]", pool.ID))
+	}
+
+	return nil
+}
+
+// Update an existing entities.PhonePool
+func (repository *gormPhonePoolRepository) Update(ctx context.Context, pool *entities.PhonePool) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Save(pool).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update phone pool with ID [%s]", pool.ID))
+	}
+
+	return nil
+}
+
+// Load an entities.PhonePool by ID
+func (repository *gormPhonePoolRepository) Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.PhonePool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	pool := new(entities.PhonePool)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", poolID).
+		First(pool).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "phone pool with ID [%s] does not exist for user [%s]", poolID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load phone pool with ID [%s] for user [%s]", poolID, userID))
+	}
+
+	return pool, nil
+}
+
+// Index entities.PhonePool of a user
+func (repository *gormPhonePoolRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.PhonePool, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("name ILIKE ?", queryPattern).Or("array_to_string(phone_numbers, ',') ILIKE ?", queryPattern))
+	}
+
+	pools := make([]*entities.PhonePool, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&pools).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch phone pools for user [%s] and params [%+#v]", userID, params))
+	}
+
+	return pools, nil
+}
+
+// RemovePhone removes a phone number from all the entities.PhonePool of a user
+func (repository *gormPhonePoolRepository) RemovePhone(ctx context.Context, userID entities.UserID, phoneNumber string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := `
+UPDATE phone_pools
+SET phone_numbers = array_remove(phone_numbers, ?)
+WHERE user_id = ? AND array_position(phone_numbers, ?) IS NOT NULL;
+`
+	if err := repository.db.WithContext(ctx).Exec(query, phoneNumber, userID, phoneNumber).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot remove phone [%s] from phone pools of user [%s]", phoneNumber, userID))
+	}
+
+	return nil
+}
+
+// Delete an entities.PhonePool by ID
+func (repository *gormPhonePoolRepository) Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", poolID).
+		Delete(&entities.PhonePool{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete phone pool with ID [%s] for user [%s]", poolID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.PhonePool for a user
+func (repository *gormPhonePoolRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.PhonePool{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.PhonePool{}, userID))
+	}
+
+	return nil
+}
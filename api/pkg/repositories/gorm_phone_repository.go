@@ -110,6 +110,14 @@ func (repository *gormPhoneRepository) Save(ctx context.Context, phone *entities
 	ctx, span, ctxLogger := repository.tracer.StartWithLogger(ctx, repository.logger)
 	defer span.End()
 
+	if phone.OrganisationID == nil {
+		organisationID, err := organisationIDOfOwner(ctx, repository.db, phone.UserID)
+		if err != nil {
+			return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save phone with ID [%s]", phone.ID))
+		}
+		phone.OrganisationID = organisationID
+	}
+
 	err := repository.db.WithContext(ctx).Save(phone).Error
 	if errors.Is(err, gorm.ErrDuplicatedKey) {
 		ctxLogger.Info(fmt.Sprintf("phone with user [%s] and number[%s] already exists", phone.UserID, phone.PhoneNumber))
@@ -159,7 +167,7 @@ func (repository *gormPhoneRepository) Index(ctx context.Context, userID entitie
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
 
-	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID).Scopes(organisationScope(params.OrganisationID))
 	if len(params.Query) > 0 {
 		queryPattern := "%" + params.Query + "%"
 		query.Where("phone_number ILIKE ?", queryPattern)
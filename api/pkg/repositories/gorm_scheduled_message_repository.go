@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// scheduledMessageClaimExpiry is the time after which an entities.ScheduledMessage which was claimed but not deleted can be claimed again
+const scheduledMessageClaimExpiry = 10 * time.Minute
+
+// gormScheduledMessageRepository is responsible for persisting entities.ScheduledMessage
+type gormScheduledMessageRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormScheduledMessageRepository creates the GORM version of the ScheduledMessageRepository
+func NewGormScheduledMessageRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) ScheduledMessageRepository {
+	return &gormScheduledMessageRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormScheduledMessageRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.ScheduledMessage
+func (repository *gormScheduledMessageRepository) Store(ctx context.Context, message *entities.ScheduledMessage) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "message_id"}, {Name: "event_type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"event", "send_at", "claimed_at", "updated_at"}),
+		}).
+		Create(message).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save [%s] scheduled message for message [%s]", message.EventType, message.MessageID))
+	}
+
+	return nil
+}
+
+// Claim marks the entities.ScheduledMessage which must be sent before a time as claimed and returns them
+func (repository *gormScheduledMessageRepository) Claim(ctx context.Context, before time.Time, limit int) ([]*entities.ScheduledMessage, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	now := time.Now().UTC()
+	messages := make([]*entities.ScheduledMessage, 0, limit)
+	err := repository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("send_at <= ?", before).
+			Where("claimed_at IS NULL OR claimed_at <= ?", now.Add(-scheduledMessageClaimExpiry)).
+			Order("send_at ASC").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(messages))
+		for _, message := range messages {
+			message.ClaimedAt = &now
+			ids = append(ids, message.ID)
+		}
+
+		return tx.Model(&entities.ScheduledMessage{}).Where("id IN ?", ids).UpdateColumn("claimed_at", now).Error
+	})
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot claim scheduled messages before [%s]", before))
+	}
+
+	return messages, nil
+}
+
+// Delete a claimed entities.ScheduledMessage
+func (repository *gormScheduledMessageRepository) Delete(ctx context.Context, message *entities.ScheduledMessage) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("id = ?", message.ID).
+		Where("claimed_at = ?", message.ClaimedAt).
+		Delete(&entities.ScheduledMessage{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete scheduled message [%s] for message [%s]", message.ID, message.MessageID))
+	}
+
+	return nil
+}
+
+// DeleteByMessageID deletes the entities.ScheduledMessage of a message
+func (repository *gormScheduledMessageRepository) DeleteByMessageID(ctx context.Context, userID entities.UserID, messageID uuid.UUID, eventType string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("message_id = ?", messageID)
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	if err := query.Delete(&entities.ScheduledMessage{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete scheduled messages for message [%s] and user [%s]", messageID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.ScheduledMessage for a user
+func (repository *gormScheduledMessageRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.ScheduledMessage{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.ScheduledMessage{}, userID))
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormSuppressionRepository is responsible for persisting entities.Suppression
+type gormSuppressionRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	db     *gorm.DB
+}
+
+// NewGormSuppressionRepository creates the GORM version of the SuppressionRepository
+func NewGormSuppressionRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+) SuppressionRepository {
+	return &gormSuppressionRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormSuppressionRepository{})),
+		tracer: tracer,
+		db:     db,
+	}
+}
+
+// Store a new entities.Suppression
+func (repository *gormSuppressionRepository) Store(ctx context.Context, suppression *entities.Suppression) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "owner"}, {Name: "contact"}},
+			DoUpdates: clause.AssignmentColumns([]string{"source", "keyword", "updated_at"}),
+		}).
+		Clauses(clause.Returning{}).
+		Create(suppression).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save suppression for owner [%s] and contact [%s]", suppression.Owner, suppression.Contact))
+	}
+
+	return nil
+}
+
+// Load an entities.Suppression by ID
+func (repository *gormSuppressionRepository) Load(ctx context.Context, userID entities.UserID, suppressionID uuid.UUID) (*entities.Suppression, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	suppression := new(entities.Suppression)
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", suppressionID).
+		First(suppression).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "suppression with ID [%s] does not exist for user [%s]", suppressionID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load suppression with ID [%s] for user [%s]", suppressionID, userID))
+	}
+
+	return suppression, nil
+}
+
+// Index entities.Suppression of a user
+func (repository *gormSuppressionRepository) Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.Suppression, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if owner != "" {
+		query.Where("owner = ?", owner)
+	}
+
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where(repository.db.Where("contact ILIKE ?", queryPattern).Or("keyword ILIKE ?", queryPattern))
+	}
+
+	suppressions := make([]*entities.Suppression, 0)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(&suppressions).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch suppressions for user [%s] and params [%+#v]", userID, params))
+	}
+
+	return suppressions, nil
+}
+
+// FindContacts returns the contacts in the list which are suppressed for an owner
+func (repository *gormSuppressionRepository) FindContacts(ctx context.Context, userID entities.UserID, owner string, contacts []string) ([]string, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	result := make([]string, 0)
+	if len(contacts) == 0 {
+		return result, nil
+	}
+
+	err := repository.db.WithContext(ctx).
+		Model(&entities.Suppression{}).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("contact IN ?", contacts).
+		Pluck("contact", &result).Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot find suppressed contacts for user [%s] and owner [%s]", userID, owner))
+	}
+
+	return result, nil
+}
+
+// Delete an entities.Suppression by ID
+func (repository *gormSuppressionRepository) Delete(ctx context.Context, userID entities.UserID, suppressionID uuid.UUID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("id = ?", suppressionID).
+		Delete(&entities.Suppression{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete suppression with ID [%s] for user [%s]", suppressionID, userID))
+	}
+
+	return nil
+}
+
+// DeleteByOwnerAndContact removes the entities.Suppression for an owner and contact
+func (repository *gormSuppressionRepository) DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner string, contact string) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	err := repository.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("owner = ?", owner).
+		Where("contact = ?", contact).
+		Delete(&entities.Suppression{}).Error
+	if err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete suppression for user [%s] with owner [%s] and contact [%s]", userID, owner, contact))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Suppression for a user
+func (repository *gormSuppressionRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.Suppression{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.Suppression{}, userID))
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// gormUserAPIKeyRepository is responsible for persisting entities.UserAPIKey
+type gormUserAPIKeyRepository struct {
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+	cache  *ristretto.Cache[string, entities.AuthContext]
+	db     *gorm.DB
+}
+
+// NewGormUserAPIKeyRepository creates the GORM version of the UserAPIKeyRepository
+func NewGormUserAPIKeyRepository(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	db *gorm.DB,
+	cache *ristretto.Cache[string, entities.AuthContext],
+) UserAPIKeyRepository {
+	return &gormUserAPIKeyRepository{
+		logger: logger.WithService(fmt.Sprintf("%T", &gormUserAPIKeyRepository{})),
+		tracer: tracer,
+		cache:  cache,
+		db:     db,
+	}
+}
+
+// Create a new entities.UserAPIKey
+func (repository *gormUserAPIKeyRepository) Create(ctx context.Context, userAPIKey *entities.UserAPIKey) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Create(userAPIKey).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save user API key with ID [%s] for user with ID [%s]", userAPIKey.ID, userAPIKey.UserID))
+	}
+
+	return nil
+}
+
+// Load an entities.UserAPIKey based on the entities.UserID
+func (repository *gormUserAPIKeyRepository) Load(ctx context.Context, userID entities.UserID, userAPIKeyID uuid.UUID) (*entities.UserAPIKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	userAPIKey := new(entities.UserAPIKey)
+	err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Where("id = ?", userAPIKeyID).First(userAPIKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "[%T] with ID [%s] for user with ID [%s] does not exist", userAPIKey, userAPIKeyID, userID))
+	}
+
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load [%T] with ID [%s] for user with ID [%s]", userAPIKey, userAPIKeyID, userID))
+	}
+
+	return userAPIKey, nil
+}
+
+// LoadAuthContext fetches an entities.AuthContext by apiKey and records when the key was last used
+func (repository *gormUserAPIKeyRepository) LoadAuthContext(ctx context.Context, apiKey string) (entities.AuthContext, error) {
+	ctx, span, ctxLogger := repository.tracer.StartWithLogger(ctx, repository.logger)
+	defer span.End()
+
+	if authContext, found := repository.cache.Get(apiKey); found {
+		return authContext, nil
+	}
+
+	userAPIKey := new(entities.UserAPIKey)
+	err := repository.db.WithContext(ctx).Where("api_key = ?", apiKey).First(userAPIKey).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.AuthContext{}, repository.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, ErrCodeNotFound, "user api key [%s] does not exist", apiKey))
+	}
+
+	if err != nil {
+		return entities.AuthContext{}, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load user api key [%s]", apiKey))
+	}
+
+	now := time.Now().UTC()
+	if userAPIKey.IsExpired(now) {
+		return entities.AuthContext{}, repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("user api key [%s] expired at [%s]", userAPIKey.ID, userAPIKey.ExpiresAt))
+	}
+
+	// the key is cached so the last used timestamp is updated at most once per cache TTL
+	if err = repository.db.WithContext(ctx).Model(userAPIKey).UpdateColumn("last_used_at", now).Error; err != nil {
+		ctxLogger.Error(repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update last used time of user api key [%s]", userAPIKey.ID)))
+	}
+
+	authUser := entities.AuthContext{
+		ID:           userAPIKey.UserID,
+		Email:        userAPIKey.UserEmail,
+		PhoneNumbers: userAPIKey.PhoneNumbers,
+		UserAPIKeyID: &userAPIKey.ID,
+		Scopes:       userAPIKey.ScopeList(),
+	}
+
+	ttl := 15 * time.Second
+	if userAPIKey.ExpiresAt != nil && userAPIKey.ExpiresAt.Sub(now) < ttl {
+		ttl = userAPIKey.ExpiresAt.Sub(now)
+	}
+
+	if result := repository.cache.SetWithTTL(apiKey, authUser, 1, ttl); !result {
+		ctxLogger.Error(repository.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("cannot cache [%T] with ID [%s] and result [%t]", authUser, userAPIKey.ID, result)))
+	}
+
+	return authUser, nil
+}
+
+// Index entities.UserAPIKey of a user
+func (repository *gormUserAPIKeyRepository) Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.UserAPIKey, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	query := repository.db.WithContext(ctx).Where("user_id = ?", userID)
+	if len(params.Query) > 0 {
+		queryPattern := "%" + params.Query + "%"
+		query.Where("name ILIKE ?", queryPattern)
+	}
+
+	userAPIKeys := new([]*entities.UserAPIKey)
+	if err := query.Order("created_at DESC").Limit(params.Limit).Offset(params.Skip).Find(userAPIKeys).Error; err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch user API Keys with userID [%s] and params [%+#v]", userID, params))
+	}
+
+	return *userAPIKeys, nil
+}
+
+// Delete an entities.UserAPIKey
+func (repository *gormUserAPIKeyRepository) Delete(ctx context.Context, userAPIKey *entities.UserAPIKey) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Delete(userAPIKey).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete user API key with ID [%s] and userID [%s]", userAPIKey.ID, userAPIKey.UserID))
+	}
+
+	repository.cache.Del(userAPIKey.APIKey)
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.UserAPIKey for a user
+func (repository *gormUserAPIKeyRepository) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	if err := repository.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&entities.UserAPIKey{}).Error; err != nil {
+		return repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user with ID [%s]", &entities.UserAPIKey{}, userID))
+	}
+
+	return nil
+}
@@ -132,6 +132,26 @@ func (repository *gormUserRepository) LoadByEmail(ctx context.Context, email str
 	return user, nil
 }
 
+// IndexWithRetention fetches the entities.User which have a retention setting sorted by ID, starting after the afterID
+func (repository *gormUserRepository) IndexWithRetention(ctx context.Context, afterID entities.UserID, limit int) ([]*entities.User, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	users := make([]*entities.User, 0, limit)
+	err := repository.db.WithContext(ctx).
+		Where(repository.db.Where("message_content_retention_days > 0").Or("data_retention_days > 0")).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).
+		Error
+	if err != nil {
+		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch users with retention after ID [%s]", afterID))
+	}
+
+	return users, nil
+}
+
 func (repository *gormUserRepository) Store(ctx context.Context, user *entities.User) error {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -28,6 +28,9 @@ type HeartbeatMonitorRepository interface {
 	// UpdatePhoneOnline updates the phone online status of a monitor
 	UpdatePhoneOnline(ctx context.Context, userID entities.UserID, monitorID uuid.UUID, online bool) error
 
+	// CountPhoneOnline counts the entities.HeartbeatMonitor of all the users with phones which are online and offline
+	CountPhoneOnline(ctx context.Context) (online int64, offline int64, err error)
+
 	// DeleteAllForUser deletes all entities.HeartbeatMonitor for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
 }
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 )
@@ -19,4 +20,7 @@ type HeartbeatRepository interface {
 
 	// DeleteAllForUser deletes all entities.Heartbeat for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+
+	// DeleteBefore deletes at most limit entities.Heartbeat of a user which are older than the timestamp and returns the number of deleted rows
+	DeleteBefore(ctx context.Context, userID entities.UserID, timestamp time.Time, limit int) (int64, error)
 }
@@ -1,8 +1,10 @@
 package repositories
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -37,6 +39,15 @@ func (s *MemoryAttachmentRepository) Upload(ctx context.Context, path string, da
 	return nil
 }
 
+// UploadStream stores the contents of a reader at the given path
+func (s *MemoryAttachmentRepository) UploadStream(ctx context.Context, path string, reader io.Reader, contentType string) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return stacktrace.Propagatef(err, "cannot read attachment for path [%s]", path)
+	}
+	return s.Upload(ctx, path, data, contentType)
+}
+
 // Download retrieves attachment data from the given path
 func (s *MemoryAttachmentRepository) Download(ctx context.Context, path string) ([]byte, error) {
 	_, span, _ := s.tracer.StartWithLogger(ctx, s.logger)
@@ -49,6 +60,15 @@ func (s *MemoryAttachmentRepository) Download(ctx context.Context, path string)
 	return value.([]byte), nil
 }
 
+// DownloadStream opens a reader for the attachment at the given path
+func (s *MemoryAttachmentRepository) DownloadStream(ctx context.Context, path string) (io.ReadCloser, error) {
+	data, err := s.Download(ctx, path)
+	if err != nil {
+		return nil, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot download attachment at path [%s]", path)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
 // Delete removes an attachment at the given path
 func (s *MemoryAttachmentRepository) Delete(ctx context.Context, path string) error {
 	_, span, ctxLogger := s.tracer.StartWithLogger(ctx, s.logger)
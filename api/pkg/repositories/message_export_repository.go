@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// MessageExportRepository loads and persists an entities.MessageExport
+type MessageExportRepository interface {
+	// Store a new entities.MessageExport
+	Store(ctx context.Context, export *entities.MessageExport) error
+
+	// Update an existing entities.MessageExport
+	Update(ctx context.Context, export *entities.MessageExport) error
+
+	// Load an entities.MessageExport by ID
+	Load(ctx context.Context, userID entities.UserID, exportID uuid.UUID) (*entities.MessageExport, error)
+
+	// LoadByID loads an entities.MessageExport by ID without checking the owner
+	LoadByID(ctx context.Context, exportID uuid.UUID) (*entities.MessageExport, error)
+
+	// DeleteAllForUser deletes all entities.MessageExport for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -2,16 +2,43 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/google/uuid"
 )
 
+// MessageSearchFilters are the conditions which the entities.Message returned by MessageRepository.Search must match
+type MessageSearchFilters struct {
+	Owners   []string
+	Contacts []string
+	Types    []entities.MessageType
+	Statuses []entities.MessageStatus
+	// Since and Until limit the time range of the order timestamp of the messages
+	Since *time.Time
+	Until *time.Time
+}
+
+// MessageAnalyticsFilters are the conditions which the entities.Message aggregated by MessageRepository.Analytics must match
+type MessageAnalyticsFilters struct {
+	Owner *string
+	// Owners limit the messages to these phone numbers when Owner is nil
+	Owners []string
+	// Since and Until limit the time range of the order timestamp of the messages
+	Since       time.Time
+	Until       time.Time
+	Granularity entities.MessageAnalyticsGranularity
+}
+
 // MessageRepository loads and persists an entities.Message
 type MessageRepository interface {
 	// Store a new entities.Message
 	Store(ctx context.Context, message *entities.Message) error
 
+	// StoreBatch stores multiple entities.Message and skips messages with an ID which already exists.
+	// It returns the number of messages which were stored.
+	StoreBatch(ctx context.Context, messages []*entities.Message) (int, error)
+
 	// Update a new entities.Message
 	Update(ctx context.Context, message *entities.Message) error
 
@@ -24,12 +51,40 @@ type MessageRepository interface {
 	// LastMessage fetches the last message between an owner and a contact
 	LastMessage(ctx context.Context, userID entities.UserID, owner string, contact string) (*entities.Message, error)
 
+	// LastMessageWithOwners fetches the last message between a contact and any of the owners
+	LastMessageWithOwners(ctx context.Context, userID entities.UserID, owners []string, contact string) (*entities.Message, error)
+
+	// CountOutstanding counts the messages which are still to be sent by each owner
+	CountOutstanding(ctx context.Context, userID entities.UserID, owners []string) (map[string]int, error)
+
 	// Search entities.Message for a user
-	Search(ctx context.Context, userID entities.UserID, owners []string, types []entities.MessageType, statuses []entities.MessageStatus, params IndexParams) ([]*entities.Message, error)
+	Search(ctx context.Context, userID entities.UserID, filters MessageSearchFilters, params IndexParams) ([]*entities.Message, error)
 
 	// GetBulkMessages fetches the last bulk message summaries for a user
 	GetBulkMessages(ctx context.Context, userID entities.UserID, limit int) ([]*entities.BulkMessage, error)
 
+	// GetBulkMessagesByRequestIDs fetches the bulk message summaries for the given request IDs
+	GetBulkMessagesByRequestIDs(ctx context.Context, userID entities.UserID, requestIDs []string) ([]*entities.BulkMessage, error)
+
+	// FetchByRequestID fetches the entities.Message with a request ID and one of the statuses in the order they were created
+	FetchByRequestID(ctx context.Context, userID entities.UserID, requestID string, statuses []entities.MessageStatus) ([]*entities.Message, error)
+
+	// IndexByRequestID fetches a page of the entities.Message with a request ID in the order they were created
+	IndexByRequestID(ctx context.Context, userID entities.UserID, requestID string, params IndexParams) ([]*entities.Message, error)
+
+	// UpdateStatusByRequestID changes the status of the entities.Message with a request ID which have one of the given statuses
+	UpdateStatusByRequestID(ctx context.Context, userID entities.UserID, requestID string, statuses []entities.MessageStatus, status entities.MessageStatus) ([]*entities.Message, error)
+
+	// RegisterUsage sets the UsageRegisteredAt of an entities.Message which is not canceled or paused.
+	// It returns false when the usage of the message was already registered or the message cannot be sent.
+	RegisterUsage(ctx context.Context, userID entities.UserID, messageID uuid.UUID, timestamp time.Time) (bool, error)
+
+	// UnregisterUsage clears the UsageRegisteredAt of an entities.Message, it returns false when the usage of the message was not registered
+	UnregisterUsage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) (bool, error)
+
+	// FetchContacts returns the distinct contacts who exchanged messages of the given types with an owner since a timestamp
+	FetchContacts(ctx context.Context, userID entities.UserID, owner string, types []entities.MessageType, since time.Time, limit int) ([]string, error)
+
 	// GetOutstanding fetches an entities.Message which is outstanding
 	GetOutstanding(ctx context.Context, userID entities.UserID, messageID uuid.UUID, phoneNumbers []string) (*entities.Message, error)
 
@@ -41,4 +96,21 @@ type MessageRepository interface {
 
 	// DeleteAllForUser deletes all entities.Message for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+
+	// FetchBefore fetches at most limit entities.Message of a user in a final status with an order timestamp before the timestamp, the oldest messages are first.
+	// When withContent is true, only the messages which still have content or attachments are fetched.
+	FetchBefore(ctx context.Context, userID entities.UserID, timestamp time.Time, withContent bool, limit int) ([]*entities.Message, error)
+
+	// DeleteByIDs deletes the entities.Message of a user with the given IDs
+	DeleteByIDs(ctx context.Context, userID entities.UserID, messageIDs []uuid.UUID) error
+
+	// RemoveContent deletes the content and the attachments of the entities.Message of a user with the given IDs
+	RemoveContent(ctx context.Context, userID entities.UserID, messageIDs []uuid.UUID) error
+
+	// Analytics aggregates the entities.Message of a user by period, by phone and by failure reason
+	Analytics(ctx context.Context, userID entities.UserID, filters MessageAnalyticsFilters) (*entities.MessageAnalytics, error)
+
+	// ReEncrypt encrypts the content of at most limit entities.Message of a user which is not encrypted with the active key of the user.
+	// It returns the number of messages which were re-encrypted.
+	ReEncrypt(ctx context.Context, userID entities.UserID, limit int) (int, error)
 }
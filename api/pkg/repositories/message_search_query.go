@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MessageSearchTerms splits the search query of a user into groups of lowercase words.
+// Words in double quotes are grouped together as a phrase and every other word is a group with a single word.
+// Characters which are not letters or digits are removed so the words are safe to use in a Postgres tsquery.
+func MessageSearchTerms(query string) [][]string {
+	var terms [][]string
+	for index, part := range strings.Split(query, `"`) {
+		words := strings.FieldsFunc(strings.ToLower(part), func(character rune) bool {
+			return !unicode.IsLetter(character) && !unicode.IsDigit(character)
+		})
+		if len(words) == 0 {
+			continue
+		}
+
+		// the parts with an odd index are between double quotes
+		if index%2 == 1 {
+			terms = append(terms, words)
+			continue
+		}
+
+		for _, word := range words {
+			terms = append(terms, []string{word})
+		}
+	}
+	return terms
+}
+
+// messageSearchTSQuery converts the search query of a user into a Postgres tsquery.
+// Single words match as a prefix so that "ord-1234" finds "ORD-123456" and phrases match the words in order.
+func messageSearchTSQuery(query string) string {
+	var groups []string
+	for _, words := range MessageSearchTerms(query) {
+		if len(words) == 1 {
+			groups = append(groups, words[0]+":*")
+			continue
+		}
+		groups = append(groups, "("+strings.Join(words, " <-> ")+")")
+	}
+	return strings.Join(groups, " & ")
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageSearchTerms(t *testing.T) {
+	assert.Equal(t, [][]string{{"ord", "123456"}, {"shipped"}}, MessageSearchTerms(`"ORD-123456" shipped`))
+	assert.Equal(t, [][]string{{"hello"}, {"world"}}, MessageSearchTerms(`hello, world!`))
+	assert.Nil(t, MessageSearchTerms(`  "" & | ! `))
+}
+
+func TestMessageSearchTSQuery(t *testing.T) {
+	assert.Equal(t, "ord:* & 1234:*", messageSearchTSQuery("ord-1234"))
+	assert.Equal(t, "(out <-> for <-> delivery) & ups:*", messageSearchTSQuery(`"out for delivery" UPS`))
+	assert.Equal(t, "drop:* & table:*", messageSearchTSQuery(`drop'); table:*`))
+	assert.Equal(t, "", messageSearchTSQuery(`:* <-> &`))
+}
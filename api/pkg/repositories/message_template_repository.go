@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// MessageTemplateRepository loads and persists an entities.MessageTemplate
+type MessageTemplateRepository interface {
+	// Store a new entities.MessageTemplate
+	Store(ctx context.Context, template *entities.MessageTemplate) error
+
+	// Update an existing entities.MessageTemplate
+	Update(ctx context.Context, template *entities.MessageTemplate) error
+
+	// Load an entities.MessageTemplate by ID
+	Load(ctx context.Context, userID entities.UserID, templateID uuid.UUID) (*entities.MessageTemplate, error)
+
+	// Index entities.MessageTemplate of a user
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.MessageTemplate, error)
+
+	// Delete an entities.MessageTemplate by ID
+	Delete(ctx context.Context, userID entities.UserID, templateID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.MessageTemplate for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -26,6 +26,8 @@ type MessageThreadStatusUpdate struct {
 	IsArchived *bool
 	IsRead     *bool
 	ReadAt     time.Time
+	// ActorID is the member of an organisation who changed the status, it is nil outside an organisation
+	ActorID *entities.UserID
 }
 
 type MessageThreadDeletedUpdate struct {
@@ -64,4 +66,8 @@ type MessageThreadRepository interface {
 
 	// DeleteAllForUser deletes all entities.MessageThread for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+
+	// ReEncrypt encrypts the last message content of at most limit entities.MessageThread of a user which is not encrypted with the active key of the user.
+	// It returns the number of threads which were re-encrypted.
+	ReEncrypt(ctx context.Context, userID entities.UserID, limit int) (int, error)
 }
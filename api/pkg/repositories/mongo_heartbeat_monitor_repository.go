@@ -172,3 +172,22 @@ func (repository *mongoHeartbeatMonitorRepository) DeleteAllForUser(ctx context.
 
 	return nil
 }
+
+// CountPhoneOnline counts the heartbeat monitors of all the users with phones which are online and offline
+func (repository *mongoHeartbeatMonitorRepository) CountPhoneOnline(ctx context.Context) (online int64, offline int64, err error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	if online, err = repository.collection.CountDocuments(ctx, bson.D{{Key: "phone_online", Value: true}}); err != nil {
+		return 0, 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot count heartbeat monitors with phones which are online"))
+	}
+
+	if offline, err = repository.collection.CountDocuments(ctx, bson.D{{Key: "phone_online", Value: false}}); err != nil {
+		return 0, 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot count heartbeat monitors with phones which are offline"))
+	}
+
+	return online, offline, nil
+}
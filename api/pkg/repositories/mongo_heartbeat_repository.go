@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -69,6 +70,10 @@ func (repository *mongoHeartbeatRepository) Index(ctx context.Context, userID en
 		SetSkip(int64(params.Skip)).
 		SetLimit(int64(params.Limit))
 
+	if params.Cursor != nil {
+		filter, opts = repository.paginate(filter, params)
+	}
+
 	cursor, err := repository.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch heartbeats with owner [%s] and params [%+#v]", owner, params))
@@ -84,9 +89,32 @@ func (repository *mongoHeartbeatRepository) Index(ctx context.Context, userID en
 		heartbeats = make([]entities.Heartbeat, 0)
 	}
 
+	reversePage(params, heartbeats)
 	return &heartbeats, nil
 }
 
+// paginate filters the heartbeats after or before the cursor in IndexParams and sorts them by (timestamp, _id)
+func (repository *mongoHeartbeatRepository) paginate(filter bson.D, params IndexParams) (bson.D, *options.FindOptionsBuilder) {
+	operator, order := "$lt", -1
+	if params.Cursor.Previous {
+		operator, order = "$gt", 1
+	}
+
+	filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: "timestamp", Value: bson.D{{Key: operator, Value: params.Cursor.Timestamp}}}},
+		bson.D{
+			{Key: "timestamp", Value: params.Cursor.Timestamp},
+			{Key: "_id", Value: bson.D{{Key: operator, Value: params.Cursor.ID}}},
+		},
+	}})
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: order}, {Key: "_id", Value: order}}).
+		SetLimit(int64(params.Limit))
+
+	return filter, opts
+}
+
 func (repository *mongoHeartbeatRepository) Last(ctx context.Context, userID entities.UserID, owner string) (*entities.Heartbeat, error) {
 	ctx, span := repository.tracer.Start(ctx)
 	defer span.End()
@@ -127,3 +155,43 @@ func (repository *mongoHeartbeatRepository) DeleteAllForUser(ctx context.Context
 
 	return nil
 }
+
+func (repository *mongoHeartbeatRepository) DeleteBefore(ctx context.Context, userID entities.UserID, timestamp time.Time, limit int) (int64, error) {
+	ctx, span := repository.tracer.Start(ctx)
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, dbOperationDuration)
+	defer cancel()
+
+	filter := bson.D{
+		{Key: "user_id", Value: string(userID)},
+		{Key: "timestamp", Value: bson.D{{Key: "$lt", Value: timestamp}}},
+	}
+
+	cursor, err := repository.collection.Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch [%T] before [%s] for user with ID [%s]", &entities.Heartbeat{}, timestamp, userID))
+	}
+	defer cursor.Close(ctx)
+
+	var documents []bson.D
+	if err = cursor.All(ctx, &documents); err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot decode [%T] IDs for user with ID [%s]", &entities.Heartbeat{}, userID))
+	}
+
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	ids := make(bson.A, 0, len(documents))
+	for _, document := range documents {
+		ids = append(ids, document[0].Value)
+	}
+
+	result, err := repository.collection.DeleteMany(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+	if err != nil {
+		return 0, repository.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [%T] before [%s] for user with ID [%s]", &entities.Heartbeat{}, timestamp, userID))
+	}
+
+	return result.DeletedCount, nil
+}
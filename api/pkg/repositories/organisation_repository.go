@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// OrganisationRepository loads and persists an entities.Organisation and its entities.OrganisationMember
+type OrganisationRepository interface {
+	// Store a new entities.Organisation with the entities.OrganisationMember of the owner
+	Store(ctx context.Context, organisation *entities.Organisation, owner *entities.OrganisationMember) error
+
+	// Update an entities.Organisation
+	Update(ctx context.Context, organisation *entities.Organisation) error
+
+	// Load an entities.Organisation by ID
+	Load(ctx context.Context, organisationID uuid.UUID) (*entities.Organisation, error)
+
+	// LoadByOwner loads the entities.Organisation which is owned by a user
+	LoadByOwner(ctx context.Context, ownerID entities.UserID) (*entities.Organisation, error)
+
+	// Delete an entities.Organisation and all its members
+	Delete(ctx context.Context, organisationID uuid.UUID) error
+
+	// StoreMember stores a new entities.OrganisationMember
+	StoreMember(ctx context.Context, member *entities.OrganisationMember) error
+
+	// UpdateMember updates an entities.OrganisationMember
+	UpdateMember(ctx context.Context, member *entities.OrganisationMember) error
+
+	// LoadMember loads an entities.OrganisationMember by ID
+	LoadMember(ctx context.Context, organisationID uuid.UUID, memberID uuid.UUID) (*entities.OrganisationMember, error)
+
+	// LoadMemberByEmail loads the entities.OrganisationMember which was invited with an email address
+	LoadMemberByEmail(ctx context.Context, organisationID uuid.UUID, email string) (*entities.OrganisationMember, error)
+
+	// IndexMembers fetches the entities.OrganisationMember of an organisation
+	IndexMembers(ctx context.Context, organisationID uuid.UUID, params IndexParams) ([]*entities.OrganisationMember, error)
+
+	// DeleteMember deletes an entities.OrganisationMember
+	DeleteMember(ctx context.Context, organisationID uuid.UUID, memberID uuid.UUID) error
+
+	// LoadMembership loads the entities.OrganisationMembership of a user who accepted the invitation to an organisation
+	LoadMembership(ctx context.Context, organisationID uuid.UUID, userID entities.UserID) (*entities.OrganisationMembership, error)
+
+	// FetchMemberships fetches the organisations of a user and the pending invitations to the email address of the user
+	FetchMemberships(ctx context.Context, userID entities.UserID, email string) ([]*entities.OrganisationMembership, error)
+
+	// DeleteAllForUser deletes the organisations owned by a user and the memberships of the user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -17,12 +18,18 @@ type PhoneNotificationRepository interface {
 	// bypassing rate-limit and schedule window logic.
 	ScheduleExact(ctx context.Context, notification *entities.PhoneNotification) error
 
+	// Exists checks if an entities.PhoneNotification has not been deleted
+	Exists(ctx context.Context, userID entities.UserID, notificationID uuid.UUID) (bool, error)
+
 	// UpdateStatus of a notification
 	UpdateStatus(ctx context.Context, notificationID uuid.UUID, status entities.PhoneNotificationStatus) error
 
 	// DeleteAllForUser deletes all entities.PhoneNotification for a user
 	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
 
+	// DeleteBefore deletes at most limit entities.PhoneNotification of a user which were created before the timestamp and returns the number of deleted rows
+	DeleteBefore(ctx context.Context, userID entities.UserID, timestamp time.Time, limit int) (int64, error)
+
 	// DeleteByMessageID deletes entities.PhoneNotification for a message and user
 	DeleteByMessageID(ctx context.Context, userID entities.UserID, messageID uuid.UUID) error
 }
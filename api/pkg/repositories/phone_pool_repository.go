@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// PhonePoolRepository loads and persists an entities.PhonePool
+type PhonePoolRepository interface {
+	// Store a new entities.PhonePool
+	Store(ctx context.Context, pool *entities.PhonePool) error
+
+	// Update an existing entities.PhonePool
+	Update(ctx context.Context, pool *entities.PhonePool) error
+
+	// Load an entities.PhonePool by ID
+	Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.PhonePool, error)
+
+	// Index entities.PhonePool of a user
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.PhonePool, error)
+
+	// RemovePhone removes a phone number from all the entities.PhonePool of a user
+	RemovePhone(ctx context.Context, userID entities.UserID, phoneNumber string) error
+
+	// Delete an entities.PhonePool by ID
+	Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error
+
+	// DeleteAllForUser deletes all entities.PhonePool for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
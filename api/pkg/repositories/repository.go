@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
 )
 
 // IndexParams parameters for indexing a database table
@@ -13,6 +14,10 @@ type IndexParams struct {
 	SortDescending bool   `json:"sort_descending"`
 	Query          string `json:"query"`
 	Limit          int    `json:"take"`
+	// Cursor is used instead of Skip to fetch the page after or before an entity
+	Cursor *Cursor `json:"cursor"`
+	// OrganisationID limits the entities to the resources of the active organisation of the request
+	OrganisationID *uuid.UUID `json:"-"`
 }
 
 const (
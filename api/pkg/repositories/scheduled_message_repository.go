@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// ScheduledMessageRepository loads and persists an entities.ScheduledMessage
+type ScheduledMessageRepository interface {
+	// Store a new entities.ScheduledMessage, an existing row for the same message and event type is replaced
+	Store(ctx context.Context, message *entities.ScheduledMessage) error
+
+	// Claim marks the entities.ScheduledMessage which must be sent before a time as claimed and returns them.
+	// A claimed entities.ScheduledMessage which is not deleted is claimed again after the claim expires.
+	Claim(ctx context.Context, before time.Time, limit int) ([]*entities.ScheduledMessage, error)
+
+	// Delete a claimed entities.ScheduledMessage, it is not deleted when it was updated after it was claimed
+	Delete(ctx context.Context, message *entities.ScheduledMessage) error
+
+	// DeleteByMessageID deletes the entities.ScheduledMessage of a message, all event types are deleted if eventType is empty
+	DeleteByMessageID(ctx context.Context, userID entities.UserID, messageID uuid.UUID, eventType string) error
+
+	// DeleteAllForUser deletes all entities.ScheduledMessage for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// SuppressionRepository loads and persists an entities.Suppression
+type SuppressionRepository interface {
+	// Store a new entities.Suppression, an existing suppression for the same owner and contact is replaced
+	Store(ctx context.Context, suppression *entities.Suppression) error
+
+	// Load an entities.Suppression by ID
+	Load(ctx context.Context, userID entities.UserID, suppressionID uuid.UUID) (*entities.Suppression, error)
+
+	// Index entities.Suppression of a user
+	Index(ctx context.Context, userID entities.UserID, owner string, params IndexParams) ([]*entities.Suppression, error)
+
+	// FindContacts returns the contacts in the list which are suppressed for an owner
+	FindContacts(ctx context.Context, userID entities.UserID, owner string, contacts []string) ([]string, error)
+
+	// Delete an entities.Suppression by ID
+	Delete(ctx context.Context, userID entities.UserID, suppressionID uuid.UUID) error
+
+	// DeleteByOwnerAndContact removes the entities.Suppression for an owner and contact
+	DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner string, contact string) error
+
+	// DeleteAllForUser deletes all entities.Suppression for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
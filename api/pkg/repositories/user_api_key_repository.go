@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+)
+
+// UserAPIKeyRepository loads and persists an entities.UserAPIKey
+type UserAPIKeyRepository interface {
+	// Create a new entities.UserAPIKey
+	Create(ctx context.Context, userAPIKey *entities.UserAPIKey) error
+
+	// Load an entities.UserAPIKey by userID and userAPIKeyID
+	Load(ctx context.Context, userID entities.UserID, userAPIKeyID uuid.UUID) (*entities.UserAPIKey, error)
+
+	// LoadAuthContext fetches an entities.AuthContext by apiKey and records when the key was last used
+	LoadAuthContext(ctx context.Context, apiKey string) (entities.AuthContext, error)
+
+	// Index entities.UserAPIKey of a user
+	Index(ctx context.Context, userID entities.UserID, params IndexParams) ([]*entities.UserAPIKey, error)
+
+	// Delete an entities.UserAPIKey
+	Delete(ctx context.Context, userAPIKey *entities.UserAPIKey) error
+
+	// DeleteAllForUser deletes all entities.UserAPIKey for a user
+	DeleteAllForUser(ctx context.Context, userID entities.UserID) error
+}
@@ -32,6 +32,9 @@ type UserRepository interface {
 	// LoadByEmail loads a user based on the email
 	LoadByEmail(ctx context.Context, email string) (*entities.User, error)
 
+	// IndexWithRetention fetches the entities.User which have a retention setting sorted by ID, starting after the afterID
+	IndexWithRetention(ctx context.Context, afterID entities.UserID, limit int) ([]*entities.User, error)
+
 	// Delete an entities.User by entities.UserID
 	Delete(ctx context.Context, user *entities.User) error
 }
@@ -0,0 +1,87 @@
+package requests
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// AutoReplyRuleStore is the payload for creating or updating an entities.AutoReplyRule
+type AutoReplyRuleStore struct {
+	request
+	// Owner is the phone number which receives the inbound messages and sends the reply
+	Owner    string `json:"owner" example:"+18005550199"`
+	Name     string `json:"name" example:"Opening Hours"`
+	Priority int    `json:"priority" example:"1"`
+	// Trigger is one of message or missed_call, rules with the missed_call trigger reply to every missed call
+	Trigger string `json:"trigger" example:"message"`
+	// MatchType is one of exact, prefix, regex or any
+	MatchType string `json:"match_type" example:"exact"`
+	// Pattern is compared with the content of the inbound message, it is ignored when the match type is any
+	Pattern string `json:"pattern" example:"HOURS"`
+	Content string `json:"content" example:"We are open Monday to Friday from 9am to 5pm."`
+	// Timezone of the windows e.g Europe/London, it is required when there are windows
+	Timezone string `json:"timezone" example:"Europe/London"`
+	// Windows are the times when the rule is active, the rule is always active when there are no windows
+	Windows []MessageSendScheduleWindow `json:"windows"`
+	// OutsideWindows makes the rule active only outside the windows e.g an out-of-office reply
+	OutsideWindows bool `json:"outside_windows" example:"false"`
+	// CooldownSeconds is the minimum time between 2 replies of the rule to the same contact, values below 60 are raised to 60
+	CooldownSeconds uint `json:"cooldown_seconds" example:"3600"`
+}
+
+// Sanitize sets defaults to AutoReplyRuleStore
+func (input *AutoReplyRuleStore) Sanitize() AutoReplyRuleStore {
+	input.Owner = input.sanitizeAddress(input.Owner)
+	input.Name = strings.TrimSpace(input.Name)
+	input.MatchType = strings.ToLower(strings.TrimSpace(input.MatchType))
+	input.Timezone = strings.TrimSpace(input.Timezone)
+
+	input.Trigger = strings.ToLower(strings.TrimSpace(input.Trigger))
+	if input.Trigger == "" {
+		input.Trigger = string(entities.AutoReplyTriggerMessage)
+	}
+	if input.Trigger == string(entities.AutoReplyTriggerMissedCall) {
+		input.MatchType = string(entities.AutoReplyMatchTypeAny)
+	}
+
+	if input.MatchType == string(entities.AutoReplyMatchTypeAny) {
+		input.Pattern = ""
+	}
+
+	windows := make([]MessageSendScheduleWindow, 0, len(input.Windows))
+	windows = append(windows, input.Windows...)
+	sort.SliceStable(windows, func(i, j int) bool {
+		if windows[i].DayOfWeek == windows[j].DayOfWeek {
+			return windows[i].StartMinute < windows[j].StartMinute
+		}
+		return windows[i].DayOfWeek < windows[j].DayOfWeek
+	})
+	input.Windows = windows
+	return *input
+}
+
+// ToUpsertParams converts AutoReplyRuleStore to services.AutoReplyRuleUpsertParams
+func (input *AutoReplyRuleStore) ToUpsertParams(userID entities.UserID) *services.AutoReplyRuleUpsertParams {
+	windows := make([]entities.MessageSendScheduleWindow, 0, len(input.Windows))
+	for _, item := range input.Windows {
+		windows = append(windows, entities.MessageSendScheduleWindow{DayOfWeek: item.DayOfWeek, StartMinute: item.StartMinute, EndMinute: item.EndMinute})
+	}
+
+	return &services.AutoReplyRuleUpsertParams{
+		UserID:          userID,
+		Owner:           input.Owner,
+		Name:            input.Name,
+		Priority:        input.Priority,
+		Trigger:         entities.AutoReplyTrigger(input.Trigger),
+		MatchType:       entities.AutoReplyMatchType(input.MatchType),
+		Pattern:         input.Pattern,
+		Content:         input.Content,
+		Timezone:        input.Timezone,
+		Windows:         windows,
+		OutsideWindows:  input.OutsideWindows,
+		CooldownSeconds: input.CooldownSeconds,
+	}
+}
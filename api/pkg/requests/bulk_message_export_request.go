@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// BulkMessageExport is the payload for exporting the messages of a bulk SMS request
+type BulkMessageExport struct {
+	request
+	Format string `json:"format" query:"format"`
+
+	RequestID string `json:"requestID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to BulkMessageExport
+func (input *BulkMessageExport) Sanitize() BulkMessageExport {
+	input.RequestID = strings.TrimSpace(input.RequestID)
+	input.Format = strings.ToLower(strings.TrimSpace(input.Format))
+	if input.Format == "" {
+		input.Format = string(entities.MessageExportFormatCSV)
+	}
+	return *input
+}
+
+// ExportFormat returns the entities.MessageExportFormat of the export
+func (input *BulkMessageExport) ExportFormat() entities.MessageExportFormat {
+	return entities.MessageExportFormat(input.Format)
+}
@@ -18,6 +18,10 @@ type BulkMessage struct {
 	Content         string `csv:"Content"`
 	SendTime        string `csv:"SendTime(optional)"`
 	AttachmentURLs  string `csv:"AttachmentURLs(optional)" validate:"optional"` // Comma separated list of URLs
+	TemplateID      string `csv:"TemplateID(optional)" validate:"optional"`
+
+	// Variables contains the extra columns of the row which are substituted into the message template
+	Variables map[string]string `csv:"-"`
 }
 
 // GetSendTime parses the raw SendTime string into a *time.Time.
@@ -59,6 +63,7 @@ func (input *BulkMessage) Sanitize() *BulkMessage {
 	input.ToPhoneNumber = input.sanitizeAddress(input.ToPhoneNumber)
 	input.Content = strings.TrimSpace(input.Content)
 	input.FromPhoneNumber = input.sanitizeAddress(input.FromPhoneNumber)
+	input.TemplateID = strings.TrimSpace(input.TemplateID)
 
 	var attachments []string
 	for _, attachment := range strings.Split(input.AttachmentURLs, ",") {
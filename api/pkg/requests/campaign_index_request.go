@@ -0,0 +1,39 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// CampaignIndex is the payload for fetching entities.Campaign of a user
+type CampaignIndex struct {
+	request
+	Skip   string `json:"skip" query:"skip"`
+	Query  string `json:"query" query:"query"`
+	Limit  string `json:"limit" query:"limit"`
+	Status string `json:"status" query:"status"`
+}
+
+// Sanitize sets defaults to CampaignIndex
+func (input *CampaignIndex) Sanitize() CampaignIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "20"
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.Status = strings.ToLower(strings.TrimSpace(input.Status))
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// ToIndexParams converts CampaignIndex to repositories.IndexParams
+func (input *CampaignIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Query: input.Query,
+		Limit: input.getInt(input.Limit),
+	}
+}
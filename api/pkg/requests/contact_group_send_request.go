@@ -0,0 +1,46 @@
+package requests
+
+import (
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/nyaruka/phonenumbers"
+)
+
+// ContactGroupSend is the payload for sending a message to every member of an entities.ContactGroup
+type ContactGroupSend struct {
+	request
+	From    string `json:"from" example:"+18005550199"`
+	Content string `json:"content" example:"This is a sample text message"`
+
+	// Attachments are optional. When you provide a list of attachments, the message will be sent out as an MMS
+	Attachments []string `json:"attachments" validate:"optional" example:"https://example.com/image.jpg,https://example.com/video.mp4"`
+
+	// SendAt is an optional parameter used to schedule the messages to be sent in the future.
+	SendAt *time.Time `json:"send_at" example:"2025-12-19T16:39:57-08:00" validate:"optional"`
+}
+
+// Sanitize sets defaults to ContactGroupSend
+func (input *ContactGroupSend) Sanitize() ContactGroupSend {
+	input.From = input.sanitizeAddress(input.From)
+	input.Attachments = input.removeEmptyStrings(input.Attachments)
+	return *input
+}
+
+// ToMessageSendParams converts ContactGroupSend to services.MessageSendParams for a member of the group
+func (input *ContactGroupSend) ToMessageSendParams(userID entities.UserID, requestID string, source string, contact string, index int) services.MessageSendParams {
+	from, _ := phonenumbers.Parse(input.From, phonenumbers.UNKNOWN_REGION)
+	return services.MessageSendParams{
+		Source:            source,
+		Owner:             from,
+		RequestID:         input.sanitizeStringPointer(requestID),
+		UserID:            userID,
+		SendAt:            input.SendAt,
+		RequestReceivedAt: time.Now().UTC(),
+		Contact:           contact,
+		Content:           input.Content,
+		Attachments:       input.Attachments,
+		Index:             index,
+	}
+}
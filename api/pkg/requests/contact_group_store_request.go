@@ -0,0 +1,58 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ContactGroupStore is the payload for creating or updating an entities.ContactGroup
+type ContactGroupStore struct {
+	request
+	Name string `json:"name" example:"Newsletter"`
+	// Type is either "static" for a fixed list of members or "dynamic" for members computed from the message history of the owner
+	Type string `json:"type" example:"static"`
+	// Members are the phone numbers of a static group in E.164 format
+	Members []string `json:"members" example:"+18005550199,+18005550100" validate:"optional"`
+	// Owner is the phone number whose message history is used to compute the members of a dynamic group
+	Owner string `json:"owner" example:"+18005550100" validate:"optional"`
+	// Direction filters the messages of a dynamic group and it is one of "received", "sent" or "any"
+	Direction string `json:"direction" example:"received" validate:"optional"`
+	// WithinDays is the number of days of message history used to compute the members of a dynamic group
+	WithinDays uint `json:"within_days" example:"90" validate:"optional"`
+}
+
+// Sanitize sets defaults to ContactGroupStore
+func (input *ContactGroupStore) Sanitize() ContactGroupStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Type = strings.ToLower(strings.TrimSpace(input.Type))
+	if input.Type == "" {
+		input.Type = string(entities.ContactGroupTypeStatic)
+	}
+
+	input.Members = input.removeStringDuplicates(input.sanitizeAddresses(input.removeEmptyStrings(input.Members)))
+	if input.Owner != "" {
+		input.Owner = input.sanitizeAddress(input.Owner)
+	}
+
+	input.Direction = strings.ToLower(strings.TrimSpace(input.Direction))
+	if input.Direction == "" {
+		input.Direction = string(entities.ContactGroupDirectionAny)
+	}
+	return *input
+}
+
+// ToUpsertParams converts ContactGroupStore to services.ContactGroupUpsertParams
+func (input *ContactGroupStore) ToUpsertParams(userID entities.UserID) *services.ContactGroupUpsertParams {
+	direction := entities.ContactGroupDirection(input.Direction)
+	return &services.ContactGroupUpsertParams{
+		UserID:     userID,
+		Name:       input.Name,
+		Type:       entities.ContactGroupType(input.Type),
+		Members:    input.Members,
+		Owner:      &input.Owner,
+		Direction:  &direction,
+		WithinDays: &input.WithinDays,
+	}
+}
@@ -0,0 +1,38 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ContactImport is a single row in a CSV file of contacts
+type ContactImport struct {
+	request
+	PhoneNumber string `csv:"PhoneNumber"`
+	Name        string `csv:"Name"`
+	Tags        string `csv:"Tags(optional)" validate:"optional"` // Comma separated list of tags
+
+	// Attributes contains the extra columns of the row using the column headers as names
+	Attributes map[string]any `csv:"-"`
+}
+
+// Sanitize sets defaults to ContactImport
+func (input *ContactImport) Sanitize() *ContactImport {
+	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
+	input.Name = strings.TrimSpace(input.Name)
+	input.Tags = strings.Join(input.sanitizeTags(strings.Split(input.Tags, ",")), ",")
+	return input
+}
+
+// ToUpsertParams converts ContactImport to services.ContactUpsertParams
+func (input *ContactImport) ToUpsertParams(userID entities.UserID) *services.ContactUpsertParams {
+	return &services.ContactUpsertParams{
+		UserID:      userID,
+		PhoneNumber: input.PhoneNumber,
+		Name:        input.Name,
+		Tags:        input.sanitizeTags(strings.Split(input.Tags, ",")),
+		Attributes:  input.Attributes,
+	}
+}
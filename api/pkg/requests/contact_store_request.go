@@ -0,0 +1,47 @@
+package requests
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// ContactStore is the payload for creating or updating an entities.Contact
+type ContactStore struct {
+	request
+	PhoneNumber string   `json:"phone_number" example:"+18005550100"`
+	Name        string   `json:"name" example:"Jane Doe"`
+	Tags        []string `json:"tags" example:"customer,vip"`
+	// Attributes are arbitrary custom fields of the contact e.g {"company": "Acme"}
+	Attributes map[string]any `json:"attributes" swaggertype:"object"`
+}
+
+// Sanitize sets defaults to ContactStore
+func (input *ContactStore) Sanitize() ContactStore {
+	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
+	input.Name = strings.TrimSpace(input.Name)
+	input.Tags = input.sanitizeTags(input.Tags)
+	return *input
+}
+
+// ToUpsertParams converts ContactStore to services.ContactUpsertParams
+func (input *ContactStore) ToUpsertParams(userID entities.UserID) *services.ContactUpsertParams {
+	return &services.ContactUpsertParams{
+		UserID:      userID,
+		PhoneNumber: input.PhoneNumber,
+		Name:        input.Name,
+		Tags:        input.Tags,
+		Attributes:  input.Attributes,
+	}
+}
+
+func (input *request) sanitizeTags(tags []string) []string {
+	result := input.removeStringDuplicates(input.removeEmptyStrings(tags))
+	sort.Strings(result)
+	if result == nil {
+		return []string{}
+	}
+	return result
+}
@@ -9,10 +9,11 @@ import (
 // HeartbeatIndex is the payload for fetching entities.Heartbeat of a phone number
 type HeartbeatIndex struct {
 	request
-	Skip  string `json:"skip" query:"skip"`
-	Owner string `json:"owner" query:"owner"`
-	Query string `json:"query" query:"query"`
-	Limit string `json:"limit" query:"limit"`
+	Skip   string `json:"skip" query:"skip"`
+	Owner  string `json:"owner" query:"owner"`
+	Query  string `json:"query" query:"query"`
+	Limit  string `json:"limit" query:"limit"`
+	Cursor string `json:"cursor" query:"cursor"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -21,6 +22,7 @@ func (input *HeartbeatIndex) Sanitize() HeartbeatIndex {
 		input.Limit = "1"
 	}
 	input.Query = strings.TrimSpace(input.Query)
+	input.Cursor = strings.TrimSpace(input.Cursor)
 	input.Owner = input.sanitizeAddress(input.Owner)
 	input.Skip = strings.TrimSpace(input.Skip)
 	if input.Skip == "" {
@@ -32,8 +34,9 @@ func (input *HeartbeatIndex) Sanitize() HeartbeatIndex {
 // ToIndexParams converts HeartbeatIndex to repositories.IndexParams
 func (input *HeartbeatIndex) ToIndexParams() repositories.IndexParams {
 	return repositories.IndexParams{
-		Skip:  input.getInt(input.Skip),
-		Query: input.Query,
-		Limit: input.getInt(input.Limit),
+		Skip:   input.getInt(input.Skip),
+		Query:  input.Query,
+		Limit:  input.getInt(input.Limit),
+		Cursor: input.getCursor(input.Cursor),
 	}
 }
@@ -0,0 +1,78 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// messageAnalyticsDefaultRange is the time range of the analytics when the from field is empty
+const messageAnalyticsDefaultRange = 30 * 24 * time.Hour
+
+// MessageAnalytics is the payload for fetching the entities.MessageAnalytics of a user
+type MessageAnalytics struct {
+	request
+	From        string `json:"from" query:"from"`
+	To          string `json:"to" query:"to"`
+	Owner       string `json:"owner" query:"owner"`
+	Granularity string `json:"granularity" query:"granularity"`
+}
+
+// Sanitize sets defaults to MessageAnalytics
+func (input *MessageAnalytics) Sanitize() MessageAnalytics {
+	input.From = strings.TrimSpace(input.From)
+	input.To = strings.TrimSpace(input.To)
+	input.Owner = input.sanitizeAddress(input.Owner)
+
+	input.Granularity = strings.ToLower(strings.TrimSpace(input.Granularity))
+	if input.Granularity == "" {
+		input.Granularity = string(entities.MessageAnalyticsGranularityDay)
+	}
+
+	return *input
+}
+
+// FromTime parses the start of the time range of the analytics.
+// The value can be an RFC3339 timestamp or a date like 2024-01-31.
+func (input *MessageAnalytics) FromTime() *time.Time {
+	return input.getTime(input.From, false)
+}
+
+// ToTime parses the end of the time range of the analytics.
+// A date like 2024-01-31 includes all the messages on that day.
+func (input *MessageAnalytics) ToTime() *time.Time {
+	return input.getTime(input.To, true)
+}
+
+// TimeRange returns the start and the end of the time range of the analytics.
+// The end defaults to now and the start defaults to 30 days before the end.
+func (input *MessageAnalytics) TimeRange(now time.Time) (time.Time, time.Time) {
+	to := now
+	if value := input.ToTime(); value != nil {
+		to = *value
+	}
+
+	from := to.Add(-messageAnalyticsDefaultRange)
+	if value := input.FromTime(); value != nil {
+		from = *value
+	}
+
+	return from.UTC(), to.UTC()
+}
+
+// ToAnalyticsParams converts MessageAnalytics to services.MessageAnalyticsParams
+func (input *MessageAnalytics) ToAnalyticsParams(userID entities.UserID, now time.Time) *services.MessageAnalyticsParams {
+	from, to := input.TimeRange(now)
+	return &services.MessageAnalyticsParams{
+		MessageAnalyticsFilters: repositories.MessageAnalyticsFilters{
+			Owner:       input.sanitizeStringPointer(input.Owner),
+			Since:       from,
+			Until:       to,
+			Granularity: entities.MessageAnalyticsGranularity(input.Granularity),
+		},
+		UserID: userID,
+	}
+}
@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageAnalyticsTimeRangeDefaults(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	input := MessageAnalytics{}
+	input.Sanitize()
+
+	from, to := input.TimeRange(now)
+
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, now, to)
+	assert.Equal(t, string(entities.MessageAnalyticsGranularityDay), input.Granularity)
+}
+
+func TestMessageAnalyticsToAnalyticsParams(t *testing.T) {
+	input := MessageAnalytics{From: "2024-01-01", To: "2024-01-31", Owner: " 18005550199", Granularity: " Week "}
+	input.Sanitize()
+
+	params := input.ToAnalyticsParams("user-id", time.Now())
+
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), params.Since)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), params.Until)
+	assert.Equal(t, "+18005550199", *params.Owner)
+	assert.Equal(t, entities.MessageAnalyticsGranularityWeek, params.Granularity)
+	assert.Equal(t, entities.UserID("user-id"), params.UserID)
+}
@@ -0,0 +1,54 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageExportStore is the payload for exporting the message history of a user
+type MessageExportStore struct {
+	request
+	Format   string   `json:"format" example:"csv"`
+	Owners   []string `json:"owners" example:"+18005550199" validate:"optional"`
+	Contacts []string `json:"contacts" example:"+18005550100" validate:"optional"`
+	Types    []string `json:"types" example:"mobile-terminated" validate:"optional"`
+	Statuses []string `json:"statuses" example:"delivered" validate:"optional"`
+
+	// Since is the optional start of the time range of the exported messages
+	Since *time.Time `json:"since" example:"2024-01-01T00:00:00Z" validate:"optional"`
+
+	// Until is the optional end of the time range of the exported messages
+	Until *time.Time `json:"until" example:"2024-04-01T00:00:00Z" validate:"optional"`
+}
+
+// Sanitize sets defaults to MessageExportStore
+func (input *MessageExportStore) Sanitize() MessageExportStore {
+	input.Format = strings.ToLower(strings.TrimSpace(input.Format))
+	if input.Format == "" {
+		input.Format = string(entities.MessageExportFormatCSV)
+	}
+
+	input.Owners = input.removeStringDuplicates(input.sanitizeAddresses(input.removeEmptyStrings(input.Owners)))
+	input.Contacts = input.removeStringDuplicates(input.sanitizeAddresses(input.removeEmptyStrings(input.Contacts)))
+	input.Types = input.removeStringDuplicates(input.removeEmptyStrings(input.Types))
+	input.Statuses = input.removeStringDuplicates(input.removeEmptyStrings(input.Statuses))
+	return *input
+}
+
+// ToStoreParams converts MessageExportStore to services.MessageExportStoreParams
+func (input *MessageExportStore) ToStoreParams(userID entities.UserID, source string) *services.MessageExportStoreParams {
+	return &services.MessageExportStoreParams{
+		UserID:   userID,
+		Format:   entities.MessageExportFormat(input.Format),
+		Owners:   input.Owners,
+		Contacts: input.Contacts,
+		Types:    input.Types,
+		Statuses: input.Statuses,
+		Since:    input.Since,
+		Until:    input.Until,
+		Source:   source,
+	}
+}
@@ -0,0 +1,109 @@
+package requests
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+const (
+	// messageImportTypeReceived is the type of messages in the inbox of an SMS Backup & Restore file
+	messageImportTypeReceived = "1"
+
+	// messageImportTypeSent is the type of sent messages in an SMS Backup & Restore file
+	messageImportTypeSent = "2"
+
+	// messageImportTypeFailed is the type of messages which could not be sent in an SMS Backup & Restore file
+	messageImportTypeFailed = "5"
+
+	// messageImportStatusComplete is the status of a sent message which has been delivered
+	messageImportStatusComplete = "0"
+
+	// messageImportStatusFailed is the status of a sent message which could not be delivered
+	messageImportStatusFailed = "64"
+)
+
+// MessageImportStore is the payload for importing an "SMS Backup & Restore" XML file
+type MessageImportStore struct {
+	request
+	// Owner is the phone number of the android phone which created the backup
+	Owner string `json:"owner" form:"owner" example:"+18005550199"`
+}
+
+// Sanitize sets defaults to MessageImportStore
+func (input *MessageImportStore) Sanitize() MessageImportStore {
+	input.Owner = input.sanitizeAddress(input.Owner)
+	return *input
+}
+
+// MessageImport is a single <sms> element in an "SMS Backup & Restore" XML file
+type MessageImport struct {
+	request
+	Address string `xml:"address,attr"`
+	Date    string `xml:"date,attr"` // milliseconds since the unix epoch
+	Type    string `xml:"type,attr"`
+	Body    string `xml:"body,attr"`
+	Status  string `xml:"status,attr"`
+}
+
+// Sanitize sets defaults to MessageImport
+func (input *MessageImport) Sanitize(owner string) *MessageImport {
+	input.Address = strings.TrimSpace(input.Address)
+	if number := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(input.Address); input.isDigits(strings.TrimPrefix(number, "+")) {
+		input.Address = number
+	}
+
+	if strings.HasPrefix(input.Address, "+") {
+		input.Address = input.sanitizeAddress(input.Address)
+	} else {
+		input.Address = input.sanitizeContact(owner, input.Address)
+	}
+
+	input.Date = strings.TrimSpace(input.Date)
+	input.Type = strings.TrimSpace(input.Type)
+	input.Status = strings.TrimSpace(input.Status)
+	return input
+}
+
+// IsImportable checks if the element is a received, sent or failed message.
+// Drafts and messages which are still in the outbox are not imported.
+func (input *MessageImport) IsImportable() bool {
+	return input.Type == messageImportTypeReceived || input.Type == messageImportTypeSent || input.Type == messageImportTypeFailed
+}
+
+// Timestamp is the time when the message was sent or received
+func (input *MessageImport) Timestamp() (time.Time, bool) {
+	milliseconds, err := strconv.ParseInt(input.Date, 10, 64)
+	if err != nil || milliseconds <= 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(milliseconds).UTC(), true
+}
+
+// ToImportParams converts MessageImport to services.MessageImportParams
+func (input *MessageImport) ToImportParams(owner string) *services.MessageImportParams {
+	timestamp, _ := input.Timestamp()
+	params := &services.MessageImportParams{
+		Owner:     owner,
+		Contact:   input.Address,
+		Content:   input.Body,
+		Type:      entities.MessageTypeMobileTerminated,
+		Status:    entities.MessageStatusSent,
+		Timestamp: timestamp,
+	}
+
+	switch {
+	case input.Type == messageImportTypeReceived:
+		params.Type = entities.MessageTypeMobileOriginated
+		params.Status = entities.MessageStatusReceived
+	case input.Type == messageImportTypeFailed || input.Status == messageImportStatusFailed:
+		params.Status = entities.MessageStatusFailed
+	case input.Status == messageImportStatusComplete:
+		params.Status = entities.MessageStatusDelivered
+	}
+
+	return params
+}
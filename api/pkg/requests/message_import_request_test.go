@@ -0,0 +1,52 @@
+package requests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageImportToImportParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       MessageImport
+		messageType entities.MessageType
+		status      entities.MessageStatus
+	}{
+		{"received", MessageImport{Type: "1", Status: "-1"}, entities.MessageTypeMobileOriginated, entities.MessageStatusReceived},
+		{"sent", MessageImport{Type: "2", Status: "-1"}, entities.MessageTypeMobileTerminated, entities.MessageStatusSent},
+		{"delivered", MessageImport{Type: "2", Status: "0"}, entities.MessageTypeMobileTerminated, entities.MessageStatusDelivered},
+		{"not delivered", MessageImport{Type: "2", Status: "64"}, entities.MessageTypeMobileTerminated, entities.MessageStatusFailed},
+		{"failed", MessageImport{Type: "5", Status: "-1"}, entities.MessageTypeMobileTerminated, entities.MessageStatusFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.input.Address = "+18005550100"
+			test.input.Date = "1704110400000"
+			test.input.Body = "Hello"
+
+			params := test.input.ToImportParams("+18005550199")
+
+			assert.Equal(t, test.messageType, params.Type)
+			assert.Equal(t, test.status, params.Status)
+			assert.Equal(t, "+18005550199", params.Owner)
+			assert.Equal(t, "+18005550100", params.Contact)
+			assert.Equal(t, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), params.Timestamp)
+		})
+	}
+}
+
+func TestMessageImportSanitizeFormatsTheAddress(t *testing.T) {
+	assert.Equal(t, "+18005550100", (&MessageImport{Address: "(800) 555-0100"}).Sanitize("+18005550199").Address)
+	assert.Equal(t, "+18005550100", (&MessageImport{Address: "+1 800-555-0100"}).Sanitize("+18005550199").Address)
+	assert.Equal(t, "MPESA", (&MessageImport{Address: " MPESA "}).Sanitize("+18005550199").Address)
+}
+
+func TestMessageImportIsImportable(t *testing.T) {
+	assert.True(t, (&MessageImport{Type: "1"}).IsImportable())
+	assert.False(t, (&MessageImport{Type: "3"}).IsImportable())
+	assert.False(t, (&MessageImport{Type: "4"}).IsImportable())
+}
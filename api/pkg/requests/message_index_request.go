@@ -13,11 +13,13 @@ import (
 
 // MessageIndex is the payload fetching entities.Message sent between 2 numbers
 type MessageIndex struct {
+	request
 	Skip    string `json:"skip" query:"skip"`
 	Contact string `json:"contact" query:"contact"`
 	Owner   string `json:"owner" query:"owner"`
 	Query   string `json:"query" query:"query"`
 	Limit   string `json:"limit" query:"limit"`
+	Cursor  string `json:"cursor" query:"cursor"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -27,6 +29,7 @@ func (input *MessageIndex) Sanitize() MessageIndex {
 	}
 
 	input.Query = strings.TrimSpace(input.Query)
+	input.Cursor = strings.TrimSpace(input.Cursor)
 
 	input.Owner = input.sanitizeAddress(input.Owner)
 	input.Contact = input.sanitizeAddress(input.Contact)
@@ -43,9 +46,10 @@ func (input *MessageIndex) Sanitize() MessageIndex {
 func (input *MessageIndex) ToGetParams(userID entities.UserID) services.MessageGetParams {
 	return services.MessageGetParams{
 		IndexParams: repositories.IndexParams{
-			Skip:  input.getInt(input.Skip),
-			Query: input.Query,
-			Limit: input.getInt(input.Limit),
+			Skip:   input.getInt(input.Skip),
+			Query:  input.Query,
+			Limit:  input.getInt(input.Limit),
+			Cursor: input.getCursor(input.Cursor),
 		},
 		UserID:  userID,
 		Owner:   input.Owner,
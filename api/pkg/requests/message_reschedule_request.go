@@ -0,0 +1,22 @@
+package requests
+
+import (
+	"strings"
+	"time"
+)
+
+// MessageReschedule is the payload for changing the send time of a message
+type MessageReschedule struct {
+	request
+
+	// SendAt is the new time when the message will be sent, it must be in the future.
+	SendAt *time.Time `json:"send_at" example:"2025-12-19T16:39:57-08:00"`
+
+	MessageID string `json:"messageID" swaggerignore:"true"` // used internally for validation
+}
+
+// Sanitize sets defaults to MessageReschedule
+func (input *MessageReschedule) Sanitize() MessageReschedule {
+	input.MessageID = strings.TrimSpace(input.MessageID)
+	return *input
+}
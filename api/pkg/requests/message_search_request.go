@@ -2,6 +2,7 @@ package requests
 
 import (
 	"strings"
+	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
@@ -15,9 +16,12 @@ type MessageSearch struct {
 	request
 	Skip           string   `json:"skip" query:"skip"`
 	Owners         []string `json:"owners" query:"owners"`
+	Contacts       []string `json:"contacts" query:"contacts"`
 	Types          []string `json:"types" query:"types"`
 	Statuses       []string `json:"statuses" query:"statuses"`
 	Query          string   `json:"query" query:"query"`
+	From           string   `json:"from" query:"from"`
+	To             string   `json:"to" query:"to"`
 	SortBy         string   `json:"sort_by" query:"sort_by"`
 	SortDescending bool     `json:"sort_descending" query:"sort_descending"`
 	Limit          string   `json:"limit" query:"limit"`
@@ -33,6 +37,9 @@ func (input *MessageSearch) Sanitize() MessageSearch {
 	}
 
 	input.Query = strings.TrimSpace(input.Query)
+	input.From = strings.TrimSpace(input.From)
+	input.To = strings.TrimSpace(input.To)
+	input.Contacts = input.sanitizeAddresses(input.removeEmptyStrings(input.Contacts))
 
 	input.Skip = strings.TrimSpace(input.Skip)
 	if input.Skip == "" {
@@ -62,9 +69,26 @@ func (input *MessageSearch) ToSearchParams(userID entities.UserID) *services.Mes
 			SortDescending: input.SortDescending,
 			Limit:          input.getInt(input.Limit),
 		},
-		UserID:   userID,
-		Owners:   input.Owners,
-		Types:    types,
-		Statuses: statuses,
+		MessageSearchFilters: repositories.MessageSearchFilters{
+			Owners:   input.Owners,
+			Contacts: input.Contacts,
+			Types:    types,
+			Statuses: statuses,
+			Since:    input.FromTime(),
+			Until:    input.ToTime(),
+		},
+		UserID: userID,
 	}
 }
+
+// FromTime parses the start of the time range of the search.
+// The value can be an RFC3339 timestamp or a date like 2024-01-31.
+func (input *MessageSearch) FromTime() *time.Time {
+	return input.getTime(input.From, false)
+}
+
+// ToTime parses the end of the time range of the search.
+// A date like 2024-01-31 includes all the messages on that day.
+func (input *MessageSearch) ToTime() *time.Time {
+	return input.getTime(input.To, true)
+}
@@ -0,0 +1,25 @@
+package requests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageSearchTimeRange(t *testing.T) {
+	input := MessageSearch{From: "2024-01-01", To: "2024-01-31"}
+
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), *input.FromTime())
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), *input.ToTime())
+}
+
+func TestMessageSearchTimeRangeWithTimestamps(t *testing.T) {
+	input := MessageSearch{From: "2024-01-01T09:00:00Z", To: "2024-01-01T18:00:00+01:00", Contacts: []string{" +1 800 555 0100 ", ""}}
+	input.Sanitize()
+
+	assert.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), input.FromTime().UTC())
+	assert.Equal(t, time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC), input.ToTime().UTC())
+	assert.Equal(t, []string{"+18005550100"}, input.Contacts)
+	assert.Nil(t, (&MessageSearch{From: "yesterday"}).FromTime())
+}
@@ -0,0 +1,12 @@
+package requests
+
+// MessageSegments is the payload for previewing the SMS segments of a message
+type MessageSegments struct {
+	request
+	Content string `json:"content" example:"This is a sample text message 😀"`
+}
+
+// Sanitize sets defaults to MessageSegments
+func (input *MessageSegments) Sanitize() MessageSegments {
+	return *input
+}
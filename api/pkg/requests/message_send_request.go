@@ -14,7 +14,9 @@ import (
 // MessageSend is the payload for sending and SMS message
 type MessageSend struct {
 	request
-	From    string `json:"from" example:"+18005550199"`
+	From string `json:"from" example:"+18005550199"`
+	// PoolID is an optional ID of a phone pool which is used to pick the sending phone instead of the from parameter
+	PoolID  string `json:"pool_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
 	To      string `json:"to" example:"+18005550100"`
 	Content string `json:"content" example:"This is a sample text message"`
 
@@ -25,14 +27,21 @@ type MessageSend struct {
 	Encrypted bool `json:"encrypted" example:"false" validate:"optional"`
 	// RequestID is an optional parameter used to track a request from the client's perspective
 	RequestID string `json:"request_id" example:"153554b5-ae44-44a0-8f4f-7bbac5657ad4" validate:"optional"`
-	// SendAt is an optional parameter used to schedule a message to be sent in the future. The time is considered to be in your profile's local timezone and you can queue messages for up to 20 days (480 hours) in the future.
+	// SendAt is an optional parameter used to schedule a message to be sent in the future. The time is considered to be in your profile's local timezone and messages can be scheduled any time in the future.
 	SendAt *time.Time `json:"send_at" example:"2025-12-19T16:39:57-08:00" validate:"optional"`
+
+	// TemplateID is an optional ID of a message template which is used as the content of the message instead of the content parameter
+	TemplateID string `json:"template_id" example:"32343a19-da5e-4b1b-a767-3298a73703cb" validate:"optional"`
+	// Variables are substituted into the message template when the template_id parameter is set
+	Variables map[string]string `json:"variables" example:"name:John" validate:"optional"`
 }
 
 // Sanitize sets defaults to MessageReceive
 func (input *MessageSend) Sanitize() MessageSend {
 	input.To = input.sanitizeAddress(input.To)
 	input.RequestID = strings.TrimSpace(input.RequestID)
+	input.TemplateID = strings.TrimSpace(input.TemplateID)
+	input.PoolID = strings.TrimSpace(input.PoolID)
 	input.From = input.sanitizeAddress(input.From)
 	var attachments []string
 	for _, attachment := range input.Attachments {
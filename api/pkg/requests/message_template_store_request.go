@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// MessageTemplateStore is the payload for creating or updating an entities.MessageTemplate
+type MessageTemplateStore struct {
+	request
+	Name string `json:"name" example:"Order Shipped"`
+	// Content of the template, variables are written in double curly braces e.g {{name}}
+	Content string `json:"content" example:"Hello {{name}}, your order {{order_id}} has been shipped."`
+}
+
+// Sanitize sets defaults to MessageTemplateStore
+func (input *MessageTemplateStore) Sanitize() MessageTemplateStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Content = strings.TrimSpace(input.Content)
+	return *input
+}
+
+// ToUpsertParams converts MessageTemplateStore to services.MessageTemplateUpsertParams
+func (input *MessageTemplateStore) ToUpsertParams(userID entities.UserID) *services.MessageTemplateUpsertParams {
+	return &services.MessageTemplateUpsertParams{
+		UserID:  userID,
+		Name:    input.Name,
+		Content: input.Content,
+	}
+}
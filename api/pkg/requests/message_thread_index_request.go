@@ -18,6 +18,7 @@ type MessageThreadIndex struct {
 	Query      string `json:"query" query:"query"`
 	Limit      string `json:"limit" query:"limit"`
 	Owner      string `json:"owner" query:"owner"`
+	Cursor     string `json:"cursor" query:"cursor"`
 }
 
 // Sanitize sets defaults to MessageOutstanding
@@ -32,6 +33,7 @@ func (input *MessageThreadIndex) Sanitize() MessageThreadIndex {
 
 	input.IsArchived = input.sanitizeBool(input.IsArchived)
 	input.Query = strings.TrimSpace(input.Query)
+	input.Cursor = strings.TrimSpace(input.Cursor)
 	input.Owner = input.sanitizeAddress(input.Owner)
 
 	input.Skip = strings.TrimSpace(input.Skip)
@@ -46,9 +48,10 @@ func (input *MessageThreadIndex) Sanitize() MessageThreadIndex {
 func (input *MessageThreadIndex) ToGetParams(userID entities.UserID) services.MessageThreadGetParams {
 	return services.MessageThreadGetParams{
 		IndexParams: repositories.IndexParams{
-			Skip:  input.getInt(input.Skip),
-			Query: input.Query,
-			Limit: input.getInt(input.Limit),
+			Skip:   input.getInt(input.Skip),
+			Query:  input.Query,
+			Limit:  input.getInt(input.Limit),
+			Cursor: input.getCursor(input.Cursor),
 		},
 		UserID:     userID,
 		IsArchived: input.getBool(input.IsArchived),
@@ -0,0 +1,34 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// OrganisationMemberStore is the payload for inviting a member to an entities.Organisation
+type OrganisationMemberStore struct {
+	request
+	Email string `json:"email" example:"agent@example.com"`
+	// Role is one of admin, agent or read-only
+	Role string `json:"role" example:"agent"`
+}
+
+// Sanitize sets defaults to OrganisationMemberStore
+func (input *OrganisationMemberStore) Sanitize() OrganisationMemberStore {
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+	input.Role = strings.ToLower(strings.TrimSpace(input.Role))
+	return *input
+}
+
+// ToInviteParams converts OrganisationMemberStore to services.OrganisationInviteParams
+func (input *OrganisationMemberStore) ToInviteParams(organisation *entities.Organisation, authUser entities.AuthContext) *services.OrganisationInviteParams {
+	return &services.OrganisationInviteParams{
+		Organisation: organisation,
+		InvitedBy:    authUser.ActorID(),
+		InviterEmail: authUser.Email,
+		Email:        input.Email,
+		Role:         entities.OrganisationRole(input.Role),
+	}
+}
@@ -0,0 +1,25 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+)
+
+// OrganisationMemberUpdate is the payload for changing the role of an entities.OrganisationMember
+type OrganisationMemberUpdate struct {
+	request
+	// Role is one of admin, agent or read-only
+	Role string `json:"role" example:"read-only"`
+}
+
+// Sanitize sets defaults to OrganisationMemberUpdate
+func (input *OrganisationMemberUpdate) Sanitize() OrganisationMemberUpdate {
+	input.Role = strings.ToLower(strings.TrimSpace(input.Role))
+	return *input
+}
+
+// OrganisationRole returns the entities.OrganisationRole of the request
+func (input *OrganisationMemberUpdate) OrganisationRole() entities.OrganisationRole {
+	return entities.OrganisationRole(input.Role)
+}
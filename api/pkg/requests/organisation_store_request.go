@@ -0,0 +1,29 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// OrganisationStore is the payload for creating or updating an entities.Organisation
+type OrganisationStore struct {
+	request
+	Name string `json:"name" example:"Acme Support"`
+}
+
+// Sanitize sets defaults to OrganisationStore
+func (input *OrganisationStore) Sanitize() OrganisationStore {
+	input.Name = strings.TrimSpace(input.Name)
+	return *input
+}
+
+// ToStoreParams converts OrganisationStore to services.OrganisationStoreParams
+func (input *OrganisationStore) ToStoreParams(userID entities.UserID, email string) *services.OrganisationStoreParams {
+	return &services.OrganisationStoreParams{
+		UserID: userID,
+		Email:  strings.ToLower(email),
+		Name:   input.Name,
+	}
+}
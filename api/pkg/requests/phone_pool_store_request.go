@@ -0,0 +1,32 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// PhonePoolStore is the payload for creating or updating an entities.PhonePool
+type PhonePoolStore struct {
+	request
+	Name string `json:"name" example:"Customer Support"`
+	// PhoneNumbers are the numbers of the phones which send messages for the pool
+	PhoneNumbers []string `json:"phone_numbers" example:"+18005550199,+18005550100"`
+}
+
+// Sanitize sets defaults to PhonePoolStore
+func (input *PhonePoolStore) Sanitize() PhonePoolStore {
+	input.Name = strings.TrimSpace(input.Name)
+	input.PhoneNumbers = input.removeStringDuplicates(input.sanitizeAddresses(input.removeEmptyStrings(input.PhoneNumbers)))
+	return *input
+}
+
+// ToUpsertParams converts PhonePoolStore to services.PhonePoolUpsertParams
+func (input *PhonePoolStore) ToUpsertParams(userID entities.UserID) *services.PhonePoolUpsertParams {
+	return &services.PhonePoolUpsertParams{
+		UserID:       userID,
+		Name:         input.Name,
+		PhoneNumbers: input.PhoneNumbers,
+	}
+}
@@ -32,6 +32,9 @@ type PhoneUpsert struct {
 	// UnarchiveThread moves an archived thread back to the inbox when a new message is received on this phone.
 	UnarchiveThread bool `json:"unarchive_thread" example:"false"`
 
+	// FallbackPhoneNumbers are phones which send the expired messages of this phone when it is offline, in order of preference.
+	FallbackPhoneNumbers []string `json:"fallback_phone_numbers" example:"+18005550100" validate:"optional"`
+
 	// SIM is the SIM slot of the phone in case the phone has more than 1 SIM slot
 	SIM string `json:"sim" example:"SIM1"`
 
@@ -43,6 +46,7 @@ func (input *PhoneUpsert) Sanitize() PhoneUpsert {
 	input.FcmToken = strings.TrimSpace(input.FcmToken)
 	input.PhoneNumber = input.sanitizeAddress(input.PhoneNumber)
 	input.SIM = input.sanitizeSIM(input.SIM)
+	input.FallbackPhoneNumbers = input.removeStringDuplicates(input.sanitizeAddresses(input.removeEmptyStrings(input.FallbackPhoneNumbers)))
 	if input.MissedCallAutoReply != nil {
 		input.MissedCallAutoReply = input.sanitizeStringPointer(*input.MissedCallAutoReply)
 	}
@@ -83,6 +87,11 @@ func (input *PhoneUpsert) ToUpsertParams(user entities.AuthContext, source strin
 		unarchiveThread = &input.UnarchiveThread
 	}
 
+	var fallbackPhoneNumbers *[]string
+	if _, exists := fields["fallback_phone_numbers"]; exists {
+		fallbackPhoneNumbers = &input.FallbackPhoneNumbers
+	}
+
 	var scheduleID *uuid.UUID
 	if _, exists := fields["message_send_schedule_id"]; exists {
 		if parsed, err := uuid.Parse(strings.TrimSpace(input.MessageSendScheduleID)); err == nil {
@@ -99,8 +108,10 @@ func (input *PhoneUpsert) ToUpsertParams(user entities.AuthContext, source strin
 		MaxSendAttempts:           maxSendAttempts,
 		FcmToken:                  fcmToken,
 		UserID:                    user.ID,
+		ActorID:                   user.OrganisationActorID(),
 		SIM:                       entities.SIM(input.SIM),
 		UnarchiveThread:           unarchiveThread,
+		FallbackPhoneNumbers:      fallbackPhoneNumbers,
 		MessageSendScheduleID:     scheduleID,
 	}
 }
@@ -4,9 +4,11 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 
 	"github.com/nyaruka/phonenumbers"
 )
@@ -145,6 +147,37 @@ func (input *request) getInt(value string) int {
 	return val
 }
 
+// getCursor decodes an opaque pagination cursor and returns nil when the value is empty or invalid
+func (input *request) getCursor(value string) *repositories.Cursor {
+	if value == "" {
+		return nil
+	}
+	cursor, _ := repositories.DecodeCursor(value)
+	return cursor
+}
+
+// getTime parses an RFC3339 timestamp or a date like 2024-01-31 and returns nil when the value is empty or invalid.
+// When endOfDay is true, a date is the start of the next day so that the range includes the whole day.
+func (input *request) getTime(value string, endOfDay bool) *time.Time {
+	if value == "" {
+		return nil
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return &timestamp
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil
+	}
+
+	if endOfDay {
+		date = date.AddDate(0, 0, 1)
+	}
+	return &date
+}
+
 func (input *request) isDigits(value string) bool {
 	for _, c := range value {
 		if !unicode.IsDigit(c) {
@@ -0,0 +1,41 @@
+package requests
+
+import (
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+)
+
+// SuppressionIndex is the payload for fetching entities.Suppression of a user
+type SuppressionIndex struct {
+	request
+	Owner string `json:"owner" query:"owner"`
+	Skip  string `json:"skip" query:"skip"`
+	Query string `json:"query" query:"query"`
+	Limit string `json:"limit" query:"limit"`
+}
+
+// Sanitize sets defaults to SuppressionIndex
+func (input *SuppressionIndex) Sanitize() SuppressionIndex {
+	if strings.TrimSpace(input.Limit) == "" {
+		input.Limit = "100"
+	}
+	if strings.TrimSpace(input.Owner) != "" {
+		input.Owner = input.sanitizeAddress(input.Owner)
+	}
+	input.Query = strings.TrimSpace(input.Query)
+	input.Skip = strings.TrimSpace(input.Skip)
+	if input.Skip == "" {
+		input.Skip = "0"
+	}
+	return *input
+}
+
+// ToIndexParams converts SuppressionIndex to repositories.IndexParams
+func (input *SuppressionIndex) ToIndexParams() repositories.IndexParams {
+	return repositories.IndexParams{
+		Skip:  input.getInt(input.Skip),
+		Query: input.Query,
+		Limit: input.getInt(input.Limit),
+	}
+}
@@ -0,0 +1,30 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// SuppressionStore is the payload for adding contacts to the suppression list of a phone
+type SuppressionStore struct {
+	request
+	Owner    string   `json:"owner" example:"+18005550199"`
+	Contacts []string `json:"contacts" example:"+18005550100,+18005550101"`
+}
+
+// Sanitize sets defaults to SuppressionStore
+func (input *SuppressionStore) Sanitize() SuppressionStore {
+	input.Owner = input.sanitizeAddress(input.Owner)
+	input.Contacts = input.removeStringDuplicates(input.sanitizeAddresses(input.removeEmptyStrings(input.Contacts)))
+	return *input
+}
+
+// ToStoreParams converts SuppressionStore to services.SuppressionStoreParams
+func (input *SuppressionStore) ToStoreParams(userID entities.UserID) *services.SuppressionStoreParams {
+	return &services.SuppressionStoreParams{
+		UserID:   userID,
+		Owner:    input.Owner,
+		Contacts: input.Contacts,
+		Source:   entities.SuppressionSourceAPI,
+	}
+}
@@ -0,0 +1,51 @@
+package requests
+
+import (
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// UserAPIKeyStore is the payload for creating an entities.UserAPIKey
+type UserAPIKeyStore struct {
+	request
+	Name string `json:"name" example:"Analytics Job"`
+	// Scopes are the permissions of the API key e.g messages:read, messages:send, phones:read, webhooks:write
+	Scopes []string `json:"scopes" example:"messages:read,phones:read"`
+	// PhoneNumbers are optional owner phone numbers which the API key can use, the key can use all your phones when it is empty
+	PhoneNumbers []string `json:"phone_numbers" example:"+18005550199"`
+	// ExpiresAt is an optional time after which the API key can no longer be used
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-12-19T16:39:57-08:00" validate:"optional"`
+}
+
+// Sanitize sets defaults to UserAPIKeyStore
+func (input *UserAPIKeyStore) Sanitize() UserAPIKeyStore {
+	input.Name = strings.TrimSpace(input.Name)
+
+	var scopes []string
+	for _, scope := range input.Scopes {
+		scopes = append(scopes, strings.ToLower(strings.TrimSpace(scope)))
+	}
+	input.Scopes = input.removeStringDuplicates(input.removeEmptyStrings(scopes))
+	input.PhoneNumbers = input.removeStringDuplicates(input.sanitizeAddresses(input.removeEmptyStrings(input.PhoneNumbers)))
+	return *input
+}
+
+// ToCreateParams converts UserAPIKeyStore to services.UserAPIKeyCreateParams
+func (input *UserAPIKeyStore) ToCreateParams(authUser entities.AuthContext) *services.UserAPIKeyCreateParams {
+	phoneNumbers := input.PhoneNumbers
+	if phoneNumbers == nil {
+		phoneNumbers = []string{}
+	}
+
+	return &services.UserAPIKeyCreateParams{
+		UserID:       authUser.ID,
+		UserEmail:    authUser.Email,
+		Name:         input.Name,
+		Scopes:       input.Scopes,
+		PhoneNumbers: phoneNumbers,
+		ExpiresAt:    input.ExpiresAt,
+	}
+}
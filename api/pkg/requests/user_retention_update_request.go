@@ -0,0 +1,22 @@
+package requests
+
+import (
+	"github.com/NdoleStudio/httpsms/pkg/services"
+)
+
+// UserRetentionUpdate is the payload for updating the data retention settings of a user
+type UserRetentionUpdate struct {
+	request
+	// MessageContentRetentionDays is the number of days after which the content and attachments of messages are deleted, null keeps the content forever
+	MessageContentRetentionDays *uint `json:"message_content_retention_days" example:"30" validate:"optional"`
+	// DataRetentionDays is the number of days after which messages, heartbeats and phone notifications are deleted, null keeps the data forever
+	DataRetentionDays *uint `json:"data_retention_days" example:"365" validate:"optional"`
+}
+
+// ToUserRetentionUpdateParams converts UserRetentionUpdate to services.UserRetentionUpdateParams
+func (input *UserRetentionUpdate) ToUserRetentionUpdateParams() *services.UserRetentionUpdateParams {
+	return &services.UserRetentionUpdateParams{
+		MessageContentRetentionDays: input.MessageContentRetentionDays,
+		DataRetentionDays:           input.DataRetentionDays,
+	}
+}
@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// BulkJobResponse is the payload containing entities.BulkJob
+type BulkJobResponse struct {
+	response
+	Data entities.BulkJob `json:"data"`
+}
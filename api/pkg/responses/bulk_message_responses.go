@@ -7,3 +7,9 @@ type BulkMessagesResponse struct {
 	response
 	Data []*entities.BulkMessage `json:"data"`
 }
+
+// BulkMessageReportResponse is the payload containing *entities.BulkMessageReport
+type BulkMessageReportResponse struct {
+	response
+	Data *entities.BulkMessageReport `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// ContactGroupResponse is the payload containing entities.ContactGroup
+type ContactGroupResponse struct {
+	response
+	Data entities.ContactGroup `json:"data"`
+}
+
+// ContactGroupsResponse is the payload containing []entities.ContactGroup
+type ContactGroupsResponse struct {
+	response
+	Data []entities.ContactGroup `json:"data"`
+}
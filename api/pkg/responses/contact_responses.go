@@ -0,0 +1,23 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// ContactResponse is the payload containing entities.Contact
+type ContactResponse struct {
+	response
+	Data entities.Contact `json:"data"`
+}
+
+// ContactsResponse is the payload containing []entities.Contact
+type ContactsResponse struct {
+	response
+	Data []entities.Contact `json:"data"`
+}
+
+// ContactImportResponse is the payload containing the number of imported entities.Contact
+type ContactImportResponse struct {
+	response
+	Data struct {
+		Count int `json:"count" example:"20"`
+	} `json:"data"`
+}
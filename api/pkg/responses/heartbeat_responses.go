@@ -5,6 +5,7 @@ import "github.com/NdoleStudio/httpsms/pkg/entities"
 // HeartbeatsResponse is the payload containing []entities.Heartbeat
 type HeartbeatsResponse struct {
 	response
+	cursors
 	Data []entities.Heartbeat `json:"data"`
 }
 
@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// MessageExportResponse is the payload containing entities.MessageExport
+type MessageExportResponse struct {
+	response
+	Data entities.MessageExport `json:"data"`
+}
@@ -8,8 +8,36 @@ type MessageResponse struct {
 	Data entities.Message `json:"data"`
 }
 
+// MessageSegmentsResponse is the payload containing entities.MessageSegments
+type MessageSegmentsResponse struct {
+	response
+	Data entities.MessageSegments `json:"data"`
+}
+
 // MessagesResponse is the payload containing []entities.Message
 type MessagesResponse struct {
 	response
+	cursors
 	Data []entities.Message `json:"data"`
 }
+
+// MessageSearchResponse is the payload containing []entities.MessageSearchResult
+type MessageSearchResponse struct {
+	response
+	Data []entities.MessageSearchResult `json:"data"`
+}
+
+// MessageAnalyticsResponse is the payload containing entities.MessageAnalytics
+type MessageAnalyticsResponse struct {
+	response
+	Data entities.MessageAnalytics `json:"data"`
+}
+
+// MessageImportResponse is the payload containing the number of imported entities.Message
+type MessageImportResponse struct {
+	response
+	Data struct {
+		Count      int `json:"count" example:"1500"`
+		Duplicates int `json:"duplicates" example:"20"`
+	} `json:"data"`
+}
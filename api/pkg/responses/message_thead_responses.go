@@ -5,6 +5,7 @@ import "github.com/NdoleStudio/httpsms/pkg/entities"
 // MessageThreadsResponse is the payload containing []entities.MessageThread
 type MessageThreadsResponse struct {
 	response
+	cursors
 	Data []entities.MessageThread `json:"data"`
 }
 
@@ -0,0 +1,27 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// OrganisationResponse is the payload containing entities.Organisation
+type OrganisationResponse struct {
+	response
+	Data entities.Organisation `json:"data"`
+}
+
+// OrganisationMembershipsResponse is the payload containing []entities.OrganisationMembership
+type OrganisationMembershipsResponse struct {
+	response
+	Data []entities.OrganisationMembership `json:"data"`
+}
+
+// OrganisationMemberResponse is the payload containing entities.OrganisationMember
+type OrganisationMemberResponse struct {
+	response
+	Data entities.OrganisationMember `json:"data"`
+}
+
+// OrganisationMembersResponse is the payload containing []entities.OrganisationMember
+type OrganisationMembersResponse struct {
+	response
+	Data []entities.OrganisationMember `json:"data"`
+}
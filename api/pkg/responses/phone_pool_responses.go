@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// PhonePoolResponse is the payload containing entities.PhonePool
+type PhonePoolResponse struct {
+	response
+	Data entities.PhonePool `json:"data"`
+}
+
+// PhonePoolsResponse is the payload containing []entities.PhonePool
+type PhonePoolsResponse struct {
+	response
+	Data []entities.PhonePool `json:"data"`
+}
@@ -5,6 +5,12 @@ type response struct {
 	Message string `json:"message" example:"Request handled successfully"`
 }
 
+// cursors are the opaque cursors for fetching the next and the previous pages of a list. They are null when there is no page.
+type cursors struct {
+	NextCursor *string `json:"next_cursor" example:"eyJ0IjoiMjAyMi0wNi0wNVQxNDoyNjowOS41Mjc5NzYrMDM6MDAiLCJpIjoiMzIzNDNhMTktZGE1ZS00YjFiLWE3NjctMzI5OGE3MzcwM2NiIn0"`
+	PrevCursor *string `json:"prev_cursor" example:"eyJ0IjoiMjAyMi0wNi0wNVQxNDoyNjowOS41Mjc5NzYrMDM6MDAiLCJpIjoiMzIzNDNhMTktZGE1ZS00YjFiLWE3NjctMzI5OGE3MzcwM2NiIiwicCI6dHJ1ZX0"`
+}
+
 // InternalServerError is the response with status code is 500
 type InternalServerError struct {
 	Status  string `json:"status" example:"error"`
@@ -44,6 +50,13 @@ type PaymentRequired struct {
 	Message string `json:"message" example:"You have reached the maximum number of allowed resources. Please upgrade your plan."`
 }
 
+// Conflict is the response with status code is 409
+type Conflict struct {
+	Status  string `json:"status" example:"error"`
+	Message string `json:"message" example:"The idempotency key has already been used"`
+	Data    string `json:"data" example:"The [Idempotency-Key] header [c1d2e3] has already been used with a different request body"`
+}
+
 // NoContent is the response when status code is 204
 type NoContent struct {
 	Status  string `json:"status" example:"success"`
@@ -0,0 +1,9 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// SuppressionsResponse is the payload containing []entities.Suppression
+type SuppressionsResponse struct {
+	response
+	Data []entities.Suppression `json:"data"`
+}
@@ -0,0 +1,15 @@
+package responses
+
+import "github.com/NdoleStudio/httpsms/pkg/entities"
+
+// UserAPIKeyResponse is the payload containing an entities.UserAPIKey
+type UserAPIKeyResponse struct {
+	response
+	Data entities.UserAPIKey `json:"data"`
+}
+
+// UserAPIKeysResponse is the payload containing []entities.UserAPIKey
+type UserAPIKeysResponse struct {
+	response
+	Data []entities.UserAPIKey `json:"data"`
+}
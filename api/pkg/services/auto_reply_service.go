@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+)
+
+// AutoReplyService manages the auto reply rules of a phone and responds to inbound messages
+type AutoReplyService struct {
+	service
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	repository         repositories.AutoReplyRuleRepository
+	messageService     *MessageService
+	phoneService       *PhoneService
+	suppressionService *SuppressionService
+	cache              cache.Cache
+}
+
+// NewAutoReplyService creates a new AutoReplyService
+func NewAutoReplyService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.AutoReplyRuleRepository,
+	messageService *MessageService,
+	phoneService *PhoneService,
+	suppressionService *SuppressionService,
+	cache cache.Cache,
+) (s *AutoReplyService) {
+	return &AutoReplyService{
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		repository:         repository,
+		messageService:     messageService,
+		phoneService:       phoneService,
+		suppressionService: suppressionService,
+		cache:              cache,
+	}
+}
+
+// AutoReplyRuleUpsertParams are parameters for creating or updating an entities.AutoReplyRule
+type AutoReplyRuleUpsertParams struct {
+	UserID          entities.UserID
+	Owner           string
+	Name            string
+	Priority        int
+	Trigger         entities.AutoReplyTrigger
+	MatchType       entities.AutoReplyMatchType
+	Pattern         string
+	Content         string
+	Timezone        string
+	Windows         []entities.MessageSendScheduleWindow
+	OutsideWindows  bool
+	CooldownSeconds uint
+}
+
+// Index fetches the entities.AutoReplyRule of a user
+func (service *AutoReplyService) Index(ctx context.Context, userID entities.UserID, owner string, params repositories.IndexParams) ([]*entities.AutoReplyRule, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	rules, err := service.repository.Index(ctx, userID, owner, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch auto reply rules for user [%s] with params [%+#v]", userID, params))
+	}
+
+	return rules, nil
+}
+
+// Load an entities.AutoReplyRule of a user
+func (service *AutoReplyService) Load(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) (*entities.AutoReplyRule, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	rule, err := service.repository.Load(ctx, userID, ruleID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load auto reply rule [%s] for user [%s]", ruleID, userID))
+	}
+
+	return rule, nil
+}
+
+// Store a new entities.AutoReplyRule
+func (service *AutoReplyService) Store(ctx context.Context, params *AutoReplyRuleUpsertParams) (*entities.AutoReplyRule, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rule := &entities.AutoReplyRule{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		CreatedAt: time.Now().UTC(),
+	}
+	service.applyParams(rule, params)
+
+	if err := service.repository.Store(ctx, rule); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save auto reply rule for user [%s]", params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("auto reply rule saved with id [%s] for owner [%s] and user [%s]", rule.ID, rule.Owner, rule.UserID))
+	return rule, nil
+}
+
+// Update an existing entities.AutoReplyRule
+func (service *AutoReplyService) Update(ctx context.Context, ruleID uuid.UUID, params *AutoReplyRuleUpsertParams) (*entities.AutoReplyRule, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	rule, err := service.repository.Load(ctx, params.UserID, ruleID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load auto reply rule [%s] for user [%s]", ruleID, params.UserID))
+	}
+
+	service.applyParams(rule, params)
+
+	if err = service.repository.Update(ctx, rule); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update auto reply rule [%s] for user [%s]", ruleID, params.UserID))
+	}
+
+	return rule, nil
+}
+
+// Delete an entities.AutoReplyRule
+func (service *AutoReplyService) Delete(ctx context.Context, userID entities.UserID, ruleID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, ruleID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load auto reply rule [%s] for user [%s]", ruleID, userID))
+	}
+
+	if err := service.repository.Delete(ctx, userID, ruleID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete auto reply rule [%s] for user [%s]", ruleID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForOwner deletes all entities.AutoReplyRule of a phone
+func (service *AutoReplyService) DeleteAllForOwner(ctx context.Context, userID entities.UserID, owner string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForOwner(ctx, userID, owner); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.AutoReplyRule] for user with ID [%s] and owner [%s]", userID, owner))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.AutoReplyRule] for user with ID [%s] and owner [%s]", userID, owner))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.AutoReplyRule for an entities.UserID.
+func (service *AutoReplyService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.AutoReplyRule] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.AutoReplyRule] for user with ID [%s]", userID))
+	return nil
+}
+
+// Respond sends the reply of the first active entities.AutoReplyRule which matches an inbound message.
+// Rules are evaluated in order of priority and only the first matching rule is used.
+func (service *AutoReplyService) Respond(ctx context.Context, source string, payload *events.MessagePhoneReceivedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if payload.Encrypted || service.suppressionService.IsKeyword(payload.Content) {
+		return nil
+	}
+
+	rules, err := service.repository.FetchForOwner(ctx, payload.UserID, payload.Owner)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch auto reply rules for owner [%s] and user [%s]", payload.Owner, payload.UserID))
+	}
+
+	rule := service.matchingRule(rules, entities.AutoReplyTriggerMessage, payload.Content, payload.Timestamp)
+	if rule == nil {
+		return nil
+	}
+
+	if err = service.reply(ctx, source, rule, payload.UserID, payload.Contact, fmt.Sprintf("auto-reply-%s", payload.MessageID)); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot reply to message [%s] with rule [%s] for user [%s]", payload.MessageID, rule.ID, payload.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("handled auto reply rule [%s] for message [%s] and user [%s]", rule.ID, payload.MessageID, payload.UserID))
+	return nil
+}
+
+// RespondToMissedCall sends the reply of the first active entities.AutoReplyRule with the entities.AutoReplyTriggerMissedCall trigger.
+// The entities.Phone MissedCallAutoReply is used when the phone has no active rule for missed calls.
+func (service *AutoReplyService) RespondToMissedCall(ctx context.Context, source string, payload *events.MessageCallMissedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	rules, err := service.repository.FetchForOwner(ctx, payload.UserID, payload.Owner)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch auto reply rules for owner [%s] and user [%s]", payload.Owner, payload.UserID))
+	}
+
+	rule := service.matchingRule(rules, entities.AutoReplyTriggerMissedCall, "", payload.Timestamp)
+	if rule == nil {
+		if rule, err = service.phoneMissedCallRule(ctx, payload.UserID, payload.Owner); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load missed call reply of phone [%s] for user [%s]", payload.Owner, payload.UserID))
+		}
+	}
+
+	if rule == nil {
+		ctxLogger.Info(fmt.Sprintf("no auto reply set for phone [%s] for message [%s] with user [%s]", payload.Owner, payload.MessageID, payload.UserID))
+		return nil
+	}
+
+	if err = service.reply(ctx, source, rule, payload.UserID, payload.Contact, fmt.Sprintf("missed-call-%s", payload.MessageID)); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot reply to missed call [%s] with rule [%s] for user [%s]", payload.MessageID, rule.ID, payload.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("handled auto reply rule [%s] for missed call [%s] and user [%s]", rule.ID, payload.MessageID, payload.UserID))
+	return nil
+}
+
+// phoneMissedCallRule converts the MissedCallAutoReply of an entities.Phone into an entities.AutoReplyRule
+func (service *AutoReplyService) phoneMissedCallRule(ctx context.Context, userID entities.UserID, owner string) (*entities.AutoReplyRule, error) {
+	phone, err := service.phoneService.Load(ctx, userID, owner)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot find phone with owner [%s] for user with ID [%s]", owner, userID)
+	}
+
+	if phone.MissedCallAutoReply == nil || strings.TrimSpace(*phone.MissedCallAutoReply) == "" {
+		return nil, nil
+	}
+
+	return &entities.AutoReplyRule{
+		ID:        phone.ID,
+		UserID:    phone.UserID,
+		Owner:     phone.PhoneNumber,
+		Trigger:   entities.AutoReplyTriggerMissedCall,
+		MatchType: entities.AutoReplyMatchTypeAny,
+		Content:   *phone.MissedCallAutoReply,
+	}, nil
+}
+
+// reply sends the content of an entities.AutoReplyRule to a contact which is not suppressed and is not in the cooldown of the rule
+func (service *AutoReplyService) reply(ctx context.Context, source string, rule *entities.AutoReplyRule, userID entities.UserID, contact string, requestID string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	suppressed, err := service.suppressionService.SuppressedContacts(ctx, userID, rule.Owner, []string{contact})
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot check if contact [%s] is suppressed for owner [%s]", contact, rule.Owner))
+	}
+
+	if len(suppressed) > 0 {
+		ctxLogger.Info(fmt.Sprintf("contact [%s] is suppressed, skipping auto reply rule [%s] for request [%s]", contact, rule.ID, requestID))
+		return nil
+	}
+
+	started, err := service.startCooldown(ctx, rule, contact)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot start the cooldown of auto reply rule [%s] for contact [%s]", rule.ID, contact))
+	}
+
+	if !started {
+		ctxLogger.Info(fmt.Sprintf("auto reply rule [%s] is cooling down for contact [%s] and request [%s]", rule.ID, contact, requestID))
+		return nil
+	}
+
+	owner, _ := phonenumbers.Parse(rule.Owner, phonenumbers.UNKNOWN_REGION)
+	message, err := service.messageService.SendMessage(ctx, MessageSendParams{
+		Owner:             owner,
+		Contact:           contact,
+		Encrypted:         false,
+		Content:           rule.Content,
+		Source:            source,
+		SendAt:            nil,
+		RequestID:         &requestID,
+		UserID:            userID,
+		RequestReceivedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		service.stopCooldown(ctx, rule, contact)
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot send auto reply with rule [%s] for request [%s] and user [%s]", rule.ID, requestID, userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("created auto reply message with ID [%s] using rule [%s] for request [%s] and user [%s]", message.ID, rule.ID, requestID, message.UserID))
+	return nil
+}
+
+func (service *AutoReplyService) matchingRule(rules []*entities.AutoReplyRule, trigger entities.AutoReplyTrigger, content string, timestamp time.Time) *entities.AutoReplyRule {
+	for _, rule := range rules {
+		if rule.IsTriggeredBy(trigger) && rule.IsActive(timestamp) && rule.Matches(content) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func (service *AutoReplyService) getCacheKey(rule *entities.AutoReplyRule, contact string) string {
+	return fmt.Sprintf("auto-reply.%s.%s", rule.ID, contact)
+}
+
+// startCooldown atomically starts the cooldown of a rule for a contact, it returns false when the rule is already cooling down
+func (service *AutoReplyService) startCooldown(ctx context.Context, rule *entities.AutoReplyRule, contact string) (bool, error) {
+	cacheKey := service.getCacheKey(rule, contact)
+	started, err := service.cache.SetNX(ctx, cacheKey, rule.ID.String(), rule.Cooldown())
+	if err != nil {
+		return false, stacktrace.Propagatef(err, "cannot set item in cache with key [%s] for auto reply rule [%s]", cacheKey, rule.ID)
+	}
+	return started, nil
+}
+
+// stopCooldown removes the cooldown of a rule for a contact when the reply could not be sent
+func (service *AutoReplyService) stopCooldown(ctx context.Context, rule *entities.AutoReplyRule, contact string) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	cacheKey := service.getCacheKey(rule, contact)
+	if err := service.cache.Delete(ctx, cacheKey); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete item in cache with key [%s] for auto reply rule [%s]", cacheKey, rule.ID))
+	}
+}
+
+func (service *AutoReplyService) applyParams(rule *entities.AutoReplyRule, params *AutoReplyRuleUpsertParams) {
+	rule.Owner = params.Owner
+	rule.Name = params.Name
+	rule.Priority = params.Priority
+	rule.Trigger = params.Trigger
+	rule.MatchType = params.MatchType
+	rule.Pattern = params.Pattern
+	rule.Content = params.Content
+	rule.Timezone = params.Timezone
+	rule.Windows = params.Windows
+	rule.OutsideWindows = params.OutsideWindows
+	rule.CooldownSeconds = params.CooldownSeconds
+	rule.UpdatedAt = time.Now().UTC()
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type autoReplyCacheStub struct {
+	items map[string]time.Duration
+}
+
+func (stub *autoReplyCacheStub) Set(_ context.Context, key string, _ string, ttl time.Duration) error {
+	stub.items[key] = ttl
+	return nil
+}
+
+func (stub *autoReplyCacheStub) Get(_ context.Context, key string) (string, error) {
+	if _, ok := stub.items[key]; ok {
+		return "", nil
+	}
+	return "", errors.New("cache miss")
+}
+
+func (stub *autoReplyCacheStub) SetNX(_ context.Context, key string, _ string, ttl time.Duration) (bool, error) {
+	if _, ok := stub.items[key]; ok {
+		return false, nil
+	}
+	stub.items[key] = ttl
+	return true, nil
+}
+
+func (stub *autoReplyCacheStub) Delete(_ context.Context, key string) error {
+	delete(stub.items, key)
+	return nil
+}
+
+func newAutoReplyServiceForTest(cache *autoReplyCacheStub) *AutoReplyService {
+	logger := &noopLogger{}
+	return NewAutoReplyService(logger, telemetry.NewOtelLogger("test", logger), nil, nil, nil, nil, cache)
+}
+
+func TestAutoReplyServiceMatchingRule(t *testing.T) {
+	service := newAutoReplyServiceForTest(&autoReplyCacheStub{items: map[string]time.Duration{}})
+
+	// 2024-01-01 is a Monday
+	monday := time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)
+	businessHours := []entities.MessageSendScheduleWindow{{DayOfWeek: 1, StartMinute: 9 * 60, EndMinute: 17 * 60}}
+
+	hours := &entities.AutoReplyRule{ID: uuid.New(), MatchType: entities.AutoReplyMatchTypeExact, Pattern: "HOURS"}
+	outOfOffice := &entities.AutoReplyRule{ID: uuid.New(), MatchType: entities.AutoReplyMatchTypeAny, Timezone: "UTC", Windows: businessHours, OutsideWindows: true}
+	rules := []*entities.AutoReplyRule{hours, outOfOffice}
+
+	assert.Equal(t, hours, service.matchingRule(rules, entities.AutoReplyTriggerMessage, "hours", monday))
+	assert.Equal(t, outOfOffice, service.matchingRule(rules, entities.AutoReplyTriggerMessage, "hello", monday))
+	assert.Nil(t, service.matchingRule(rules, entities.AutoReplyTriggerMessage, "hello", monday.Add(-4*time.Hour)))
+}
+
+func TestAutoReplyServiceMatchingRuleForMissedCalls(t *testing.T) {
+	service := newAutoReplyServiceForTest(&autoReplyCacheStub{items: map[string]time.Duration{}})
+
+	anyRule := &entities.AutoReplyRule{ID: uuid.New(), MatchType: entities.AutoReplyMatchTypeAny}
+	missedCall := &entities.AutoReplyRule{ID: uuid.New(), Trigger: entities.AutoReplyTriggerMissedCall, MatchType: entities.AutoReplyMatchTypeAny}
+	rules := []*entities.AutoReplyRule{anyRule, missedCall}
+
+	assert.Equal(t, anyRule, service.matchingRule(rules, entities.AutoReplyTriggerMessage, "hello", time.Now()))
+	assert.Equal(t, missedCall, service.matchingRule(rules, entities.AutoReplyTriggerMissedCall, "", time.Now()))
+}
+
+func TestAutoReplyServiceCooldown(t *testing.T) {
+	cache := &autoReplyCacheStub{items: map[string]time.Duration{}}
+	service := newAutoReplyServiceForTest(cache)
+	rule := &entities.AutoReplyRule{ID: uuid.New(), CooldownSeconds: 3600}
+
+	started, err := service.startCooldown(context.Background(), rule, "+18005550100")
+	require.NoError(t, err)
+	assert.True(t, started)
+
+	started, err = service.startCooldown(context.Background(), rule, "+18005550100")
+	require.NoError(t, err)
+	assert.False(t, started)
+
+	started, err = service.startCooldown(context.Background(), rule, "+18005550199")
+	require.NoError(t, err)
+	assert.True(t, started)
+	assert.Equal(t, time.Hour, cache.items[service.getCacheKey(rule, "+18005550100")])
+
+	service.stopCooldown(context.Background(), rule, "+18005550100")
+
+	started, err = service.startCooldown(context.Background(), rule, "+18005550100")
+	require.NoError(t, err)
+	assert.True(t, started)
+}
+
+func TestAutoReplyServiceWithoutCooldownUsesTheMinimumCooldown(t *testing.T) {
+	cache := &autoReplyCacheStub{items: map[string]time.Duration{}}
+	service := newAutoReplyServiceForTest(cache)
+	rule := &entities.AutoReplyRule{ID: uuid.New(), MatchType: entities.AutoReplyMatchTypeAny}
+
+	started, err := service.startCooldown(context.Background(), rule, "+18005550100")
+	require.NoError(t, err)
+	assert.True(t, started)
+
+	started, err = service.startCooldown(context.Background(), rule, "+18005550100")
+	require.NoError(t, err)
+	assert.False(t, started)
+	assert.Equal(t, entities.AutoReplyMinCooldown, cache.items[service.getCacheKey(rule, "+18005550100")])
+}
@@ -24,6 +24,7 @@ type BillingService struct {
 	emailFactory           emails.UserEmailFactory
 	mailer                 emails.Mailer
 	userRepository         repositories.UserRepository
+	messageRepository      repositories.MessageRepository
 	billingUsageRepository repositories.BillingUsageRepository
 }
 
@@ -36,6 +37,7 @@ func NewBillingService(
 	emailFactory emails.UserEmailFactory,
 	usageRepository repositories.BillingUsageRepository,
 	userRepository repositories.UserRepository,
+	messageRepository repositories.MessageRepository,
 ) (s *BillingService) {
 	return &BillingService{
 		logger:                 logger.WithService(fmt.Sprintf("%T", s)),
@@ -44,6 +46,7 @@ func NewBillingService(
 		emailFactory:           emailFactory,
 		mailer:                 mailer,
 		userRepository:         userRepository,
+		messageRepository:      messageRepository,
 		billingUsageRepository: usageRepository,
 	}
 }
@@ -133,14 +136,24 @@ func (service *BillingService) GetUsageHistory(ctx context.Context, userID entit
 	return service.billingUsageRepository.GetHistory(ctx, userID, params)
 }
 
-// RegisterSentMessage records the billing usage for a sent message
-func (service *BillingService) RegisterSentMessage(ctx context.Context, messageID uuid.UUID, timestamp time.Time, userID entities.UserID) error {
+// RegisterSentMessage records the billing usage for a sent message, messages without segments e.g. encrypted messages count as 1 segment
+func (service *BillingService) RegisterSentMessage(ctx context.Context, messageID uuid.UUID, timestamp time.Time, userID entities.UserID, segments uint) error {
 	ctx, span := service.tracer.Start(ctx)
 	defer span.End()
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
-	if err := service.billingUsageRepository.RegisterSentMessage(ctx, timestamp, userID); err != nil {
+	registered, err := service.messageRepository.RegisterUsage(ctx, userID, messageID, time.Now().UTC())
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not mark the usage of message with ID [%s] for user with ID [%s]", messageID, userID))
+	}
+
+	if !registered {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] for user [%s] is already registered or it is canceled or paused", messageID, userID))
+		return nil
+	}
+
+	if err = service.billingUsageRepository.RegisterSentMessage(ctx, timestamp, userID, max(segments, 1)); err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not register [sent] message with ID [%s] for user with ID [%s]", messageID, userID))
 	}
 
@@ -149,6 +162,45 @@ func (service *BillingService) RegisterSentMessage(ctx context.Context, messageI
 	return nil
 }
 
+// UnregisterSentMessage removes the billing usage of a message which was canceled before it was sent
+func (service *BillingService) UnregisterSentMessage(ctx context.Context, messageID uuid.UUID, timestamp time.Time, userID entities.UserID, segments uint) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	unregistered, err := service.messageRepository.UnregisterUsage(ctx, userID, messageID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not clear the usage of message with ID [%s] for user with ID [%s]", messageID, userID))
+	}
+
+	if !unregistered {
+		ctxLogger.Info(fmt.Sprintf("message with ID [%s] for user [%s] was not registered so there is no usage to remove", messageID, userID))
+		return nil
+	}
+
+	if err = service.billingUsageRepository.UnregisterSentMessage(ctx, timestamp, userID, max(segments, 1)); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not unregister [canceled] message with ID [%s] for user with ID [%s]", messageID, userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("unregistered [canceled] message with ID [%s] for user [%s]", messageID, userID))
+	return nil
+}
+
+// RegisterRescheduledMessage records the billing usage of a message which was paused when it was sent through the API
+func (service *BillingService) RegisterRescheduledMessage(ctx context.Context, userID entities.UserID, messageID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	message, err := service.messageRepository.Load(ctx, userID, messageID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load message with ID [%s] for user with ID [%s]", messageID, userID))
+	}
+
+	if err = service.RegisterSentMessage(ctx, message.ID, message.RequestReceivedAt, message.UserID, message.Segments); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not register rescheduled message with ID [%s] for user with ID [%s]", messageID, userID))
+	}
+	return nil
+}
+
 // RegisterReceivedMessage records the billing usage for a received message
 func (service *BillingService) RegisterReceivedMessage(ctx context.Context, messageID uuid.UUID, timestamp time.Time, userID entities.UserID) error {
 	ctx, span := service.tracer.Start(ctx)
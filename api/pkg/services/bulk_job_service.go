@@ -0,0 +1,626 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/xuri/excelize/v2"
+)
+
+// bulkJobBatchSize is the number of valid rows which are queued at the same time
+const bulkJobBatchSize = 500
+
+// bulkJobRowsPerEvent is the number of rows which are processed by a single event before the progress is saved
+const bulkJobRowsPerEvent = 5000
+
+const (
+	bulkJobColumnFrom    = "FromPhoneNumber"
+	bulkJobColumnTo      = "ToPhoneNumber"
+	bulkJobColumnContent = "Content"
+)
+
+// BulkJobRowParser validates a row in the file of an entities.BulkJob with the same rules as the bulk SMS endpoint
+type BulkJobRowParser interface {
+	// ParseRow converts a row into MessageSendParams or returns the errors of the row when it cannot be sent
+	ParseRow(ctx context.Context, user *entities.User, header []string, record []string, templates map[string]*entities.MessageTemplate) (*MessageSendParams, []string)
+}
+
+// BulkJobService processes large bulk SMS files in the background
+type BulkJobService struct {
+	service
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	repository         repositories.BulkJobRepository
+	storage            repositories.AttachmentRepository
+	dispatcher         *EventDispatcher
+	userService        *UserService
+	phoneService       *PhoneService
+	suppressionService *SuppressionService
+	parser             BulkJobRowParser
+	billingService     *BillingService
+	messageService     *MessageService
+	campaignService    *CampaignService
+}
+
+// NewBulkJobService creates a new BulkJobService
+func NewBulkJobService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.BulkJobRepository,
+	storage repositories.AttachmentRepository,
+	dispatcher *EventDispatcher,
+	userService *UserService,
+	phoneService *PhoneService,
+	suppressionService *SuppressionService,
+	parser BulkJobRowParser,
+	billingService *BillingService,
+	messageService *MessageService,
+	campaignService *CampaignService,
+) (s *BulkJobService) {
+	return &BulkJobService{
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		repository:         repository,
+		storage:            storage,
+		dispatcher:         dispatcher,
+		userService:        userService,
+		phoneService:       phoneService,
+		suppressionService: suppressionService,
+		parser:             parser,
+		billingService:     billingService,
+		messageService:     messageService,
+		campaignService:    campaignService,
+	}
+}
+
+// BulkJobStoreParams are parameters for creating an entities.BulkJob
+type BulkJobStoreParams struct {
+	UserID  entities.UserID
+	ActorID *entities.UserID
+	// PhoneNumbers limit the owners of the rows when the file is uploaded with a restricted API key
+	PhoneNumbers []string
+	Filename     string
+	RequestID    string
+	Content      io.Reader
+	Source       string
+}
+
+// Store uploads the file of a new entities.BulkJob and dispatches it to be processed in the background
+func (service *BulkJobService) Store(ctx context.Context, params *BulkJobStoreParams) (*entities.BulkJob, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	jobID := uuid.New()
+	job := &entities.BulkJob{
+		ID:           jobID,
+		UserID:       params.UserID,
+		ActorID:      params.ActorID,
+		PhoneNumbers: pq.StringArray(params.PhoneNumbers),
+		Filename:     params.Filename,
+		Status:       entities.BulkJobStatusPending,
+		Path:         fmt.Sprintf("bulk-jobs/%s/%s%s", params.UserID, jobID, strings.ToLower(filepath.Ext(params.Filename))),
+		RequestID:    params.RequestID,
+		Errors:       []entities.BulkJobError{},
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := service.storage.UploadStream(ctx, job.Path, params.Content, "application/octet-stream"); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot upload file [%s] of bulk job [%s] to path [%s]", params.Filename, job.ID, job.Path))
+	}
+
+	if err := service.repository.Store(ctx, job); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save bulk job [%s] for user [%s]", job.ID, job.UserID))
+	}
+
+	event, err := service.createEvent(events.BulkJobCreated, params.Source, &events.BulkJobCreatedPayload{
+		BulkJobID: job.ID,
+		UserID:    job.UserID,
+		Source:    params.Source,
+		Timestamp: job.CreatedAt,
+	})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for bulk job [%s]", events.BulkJobCreated, job.ID))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch [%s] event for bulk job [%s]", event.Type(), job.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("bulk job saved with id [%s] and file [%s] for user [%s]", job.ID, job.Filename, job.UserID))
+	return job, nil
+}
+
+// Load an entities.BulkJob of a user
+func (service *BulkJobService) Load(ctx context.Context, userID entities.UserID, jobID uuid.UUID) (*entities.BulkJob, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	job, err := service.repository.Load(ctx, userID, jobID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load bulk job [%s] for user [%s]", jobID, userID))
+	}
+
+	return job, nil
+}
+
+// DeleteAllForUser deletes all entities.BulkJob for a user
+func (service *BulkJobService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete bulk jobs for user [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all bulk jobs for user [%s]", userID))
+	return nil
+}
+
+// BulkJobProcessParams are parameters for processing the next batch of rows of an entities.BulkJob
+type BulkJobProcessParams struct {
+	UserID    entities.UserID
+	BulkJobID uuid.UUID
+	Source    string
+	RowOffset int
+}
+
+// Process validates and queues the next bulkJobRowsPerEvent rows in the file of an entities.BulkJob.
+// The progress is saved after each batch and the following rows are processed by a new events.BulkJobProcessing event,
+// so a job which is interrupted resumes from the last saved row when the event is delivered again.
+func (service *BulkJobService) Process(ctx context.Context, params *BulkJobProcessParams) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	job, err := service.repository.Load(ctx, params.UserID, params.BulkJobID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load bulk job [%s] for user [%s]", params.BulkJobID, params.UserID))
+	}
+
+	// the events can be delivered more than once and the rows of a batch must only be sent by the event which was dispatched for it
+	if job.IsFinished() || job.RowOffset != params.RowOffset {
+		ctxLogger.Info(fmt.Sprintf("bulk job [%s] has status [%s] and row offset [%d] so the batch at row offset [%d] will not be processed", job.ID, job.Status, job.RowOffset, params.RowOffset))
+		return nil
+	}
+
+	if job.IsPending() {
+		if err = service.repository.Update(ctx, job.Processing(time.Now().UTC())); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot mark bulk job [%s] as processing", job.ID))
+		}
+	}
+
+	done, failure := service.processRows(ctx, ctxLogger, job, params.Source)
+	switch {
+	case failure != nil:
+		job.Failed(time.Now().UTC(), *failure)
+	case done:
+		service.startCampaign(ctx, ctxLogger, job)
+		job.Completed(time.Now().UTC())
+	default:
+		job.UpdatedAt = time.Now().UTC()
+	}
+
+	if err = service.repository.Update(ctx, job); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save the progress of bulk job [%s] with status [%s] at row offset [%d]", job.ID, job.Status, job.RowOffset))
+	}
+
+	if !job.IsFinished() {
+		return service.dispatchProcessing(ctx, job, params.Source)
+	}
+
+	if err = service.storage.Delete(ctx, job.Path); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete file [%s] of bulk job [%s]", job.Path, job.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("bulk job [%s] finished with status [%s], [%d] queued rows and [%d] failed rows", job.ID, job.Status, job.QueuedRows, job.FailedRows))
+	return nil
+}
+
+func (service *BulkJobService) dispatchProcessing(ctx context.Context, job *entities.BulkJob, source string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	event, err := service.createEvent(events.BulkJobProcessing, source, &events.BulkJobProcessingPayload{
+		BulkJobID: job.ID,
+		UserID:    job.UserID,
+		Source:    source,
+		RowOffset: job.RowOffset,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for bulk job [%s]", events.BulkJobProcessing, job.ID))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch [%s] event for bulk job [%s]", event.Type(), job.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("dispatched [%s] event for bulk job [%s] at row offset [%d]", event.Type(), job.ID, job.RowOffset))
+	return nil
+}
+
+// bulkJobRow is a valid row in the file of an entities.BulkJob
+type bulkJobRow struct {
+	Row    int
+	Params *MessageSendParams
+}
+
+// bulkJobState contains the values which are cached while the rows of an entities.BulkJob are processed
+type bulkJobState struct {
+	user      *entities.User
+	source    string
+	header    []string
+	phones    map[string]bool
+	templates map[string]*entities.MessageTemplate
+}
+
+// processRows validates and queues the rows of the file after the entities.BulkJob RowOffset.
+// It returns true when the end of the file has been reached.
+func (service *BulkJobService) processRows(ctx context.Context, ctxLogger telemetry.Logger, job *entities.BulkJob, source string) (bool, *string) {
+	user, err := service.userService.GetByID(ctx, job.UserID)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load user [%s] for bulk job [%s]", job.UserID, job.ID))
+		return false, service.failure("Cannot load your account. Please upload the file again.")
+	}
+
+	file, err := service.storage.DownloadStream(ctx, job.Path)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot download file [%s] of bulk job [%s]", job.Path, job.ID))
+		return false, service.failure("Cannot read the uploaded file. Please upload it again.")
+	}
+	defer func() {
+		if err = file.Close(); err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot close file [%s] of bulk job [%s]", job.Path, job.ID))
+		}
+	}()
+
+	reader, err := newBulkJobReader(job.Filename, file)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot read file [%s] of bulk job [%s]", job.Filename, job.ID))
+		return false, service.failure(fmt.Sprintf("The file [%s] is not a valid CSV or Excel file.", job.Filename))
+	}
+	defer func() {
+		if err = reader.Close(); err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot close reader for file [%s] of bulk job [%s]", job.Filename, job.ID))
+		}
+	}()
+
+	header, err := reader.Read()
+	if err != nil {
+		return false, service.failure("The uploaded file doesn't contain any records. Make sure you are using the official httpSMS template.")
+	}
+
+	if missing := bulkJobMissingColumns(header); len(missing) > 0 {
+		return false, service.failure(fmt.Sprintf("The uploaded file doesn't have the columns [%s]. Make sure you are using the official httpSMS template.", strings.Join(missing, ", ")))
+	}
+
+	for row := 0; row < job.RowOffset; row++ {
+		if _, err = reader.Read(); err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot skip row [%d] of file [%s] for bulk job [%s] at row offset [%d]", row+2, job.Filename, job.ID, job.RowOffset))
+			return false, service.failure(fmt.Sprintf("Row [%d]: Cannot read the contents of the row. The rows after it have not been sent.", row+2))
+		}
+	}
+
+	state := &bulkJobState{
+		user:      user,
+		source:    source,
+		header:    header,
+		phones:    map[string]bool{},
+		templates: map[string]*entities.MessageTemplate{},
+	}
+
+	batch := make([]*bulkJobRow, 0, bulkJobBatchSize)
+	for count := 0; count < bulkJobRowsPerEvent; count++ {
+		row := job.RowOffset + 2
+		record, err := reader.Read()
+		if err == io.EOF {
+			if failure := service.sendBatch(ctx, ctxLogger, job, state, batch); failure != nil {
+				return false, failure
+			}
+			if job.TotalRows == 0 {
+				return false, service.failure("The uploaded file doesn't contain any records. Make sure you are using the official httpSMS template.")
+			}
+			return true, nil
+		}
+		if err != nil {
+			ctxLogger.Warn(stacktrace.Propagatef(err, "cannot read row [%d] of file [%s] for bulk job [%s]", row, job.Filename, job.ID))
+			return false, service.failure(fmt.Sprintf("Row [%d]: Cannot read the contents of the row. The rows after it have not been sent.", row))
+		}
+
+		job.RowOffset++
+		if bulkJobIsEmpty(record) {
+			continue
+		}
+
+		job.TotalRows++
+		params, message := service.parseRow(ctx, ctxLogger, job, state, record)
+		if message != "" {
+			job.AddError(row, message)
+			continue
+		}
+
+		if batch = append(batch, &bulkJobRow{Row: row, Params: params}); len(batch) < bulkJobBatchSize {
+			continue
+		}
+
+		if failure := service.sendBatch(ctx, ctxLogger, job, state, batch); failure != nil {
+			return false, failure
+		}
+		batch = batch[:0]
+	}
+
+	return false, service.sendBatch(ctx, ctxLogger, job, state, batch)
+}
+
+// sendBatch removes the rows with suppressed contacts from a batch and queues the remaining messages
+func (service *BulkJobService) sendBatch(ctx context.Context, ctxLogger telemetry.Logger, job *entities.BulkJob, state *bulkJobState, batch []*bulkJobRow) *string {
+	rows := service.withoutSuppressed(ctx, ctxLogger, job, batch)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if message := service.billingService.IsEntitledWithCount(ctx, job.UserID, uint(len(rows))); message != nil {
+		ctxLogger.Warn(stacktrace.NewErrorf("user [%s] is not entitled to send [%d] messages for bulk job [%s]", job.UserID, len(rows), job.ID))
+		return message
+	}
+
+	if failure := service.storeCampaign(ctx, ctxLogger, job, rows); failure != nil {
+		return failure
+	}
+
+	if job.SendIndexes == nil {
+		job.SendIndexes = map[string]int{}
+	}
+
+	wg := sync.WaitGroup{}
+	mutex := sync.Mutex{}
+	for _, row := range rows {
+		owner := phonenumbers.Format(row.Params.Owner, phonenumbers.E164)
+
+		params := *row.Params
+		params.Source = state.source
+		params.ActorID = job.ActorID
+		params.RequestID = &job.RequestID
+		params.RequestReceivedAt = time.Now().UTC()
+		params.Index = 0
+		if _, ok := job.SendIndexes[owner]; !ok {
+			job.SendIndexes[owner] = 0
+		}
+		if params.SendAt == nil {
+			params.Index = job.SendIndexes[owner]
+			job.SendIndexes[owner]++
+		}
+
+		wg.Add(1)
+		go func(row int, params MessageSendParams) {
+			defer wg.Done()
+			_, err := service.messageService.SendMessage(ctx, params)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot send message in row [%d] of bulk job [%s]", row, job.ID))
+				job.AddError(row, "Cannot add the message to the queue. Please send it again.")
+				return
+			}
+			job.QueuedRows++
+		}(row.Row, params)
+	}
+	wg.Wait()
+
+	return nil
+}
+func (service *BulkJobService) withoutSuppressed(ctx context.Context, ctxLogger telemetry.Logger, job *entities.BulkJob, batch []*bulkJobRow) []*bulkJobRow {
+	contacts := map[string][]string{}
+	for _, row := range batch {
+		owner := phonenumbers.Format(row.Params.Owner, phonenumbers.E164)
+		contacts[owner] = append(contacts[owner], row.Params.Contact)
+	}
+
+	suppressed := map[string][]string{}
+	for owner, numbers := range contacts {
+		values, err := service.suppressionService.SuppressedContacts(ctx, job.UserID, owner, numbers)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot load suppressed contacts for owner [%s] in bulk job [%s]", owner, job.ID))
+		}
+		suppressed[owner] = values
+	}
+
+	rows := make([]*bulkJobRow, 0, len(batch))
+	for _, row := range batch {
+		owner := phonenumbers.Format(row.Params.Owner, phonenumbers.E164)
+		if slices.Contains(suppressed[owner], row.Params.Contact) {
+			job.AddError(row.Row, fmt.Sprintf("The ToPhoneNumber [%s] has opted out of receiving messages from [%s]", row.Params.Contact, owner))
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (service *BulkJobService) storeCampaign(ctx context.Context, ctxLogger telemetry.Logger, job *entities.BulkJob, rows []*bulkJobRow) *string {
+	if job.CampaignID != nil {
+		return nil
+	}
+
+	campaign, err := service.campaignService.Store(ctx, &CampaignStoreParams{
+		UserID:    job.UserID,
+		Name:      job.Filename,
+		RequestID: job.RequestID,
+		Owners:    []string{phonenumbers.Format(rows[0].Params.Owner, phonenumbers.E164)},
+	})
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot store campaign for bulk job [%s]", job.ID))
+		return service.failure("Cannot create the campaign for the messages. Please upload the file again.")
+	}
+
+	job.CampaignID = &campaign.ID
+	return nil
+}
+
+func (service *BulkJobService) startCampaign(ctx context.Context, ctxLogger telemetry.Logger, job *entities.BulkJob) {
+	if job.CampaignID == nil {
+		return
+	}
+
+	owners := make([]string, 0, len(job.SendIndexes))
+	for owner := range job.SendIndexes {
+		owners = append(owners, owner)
+	}
+	slices.Sort(owners)
+
+	if err := service.campaignService.UpdateOwners(ctx, job.UserID, *job.CampaignID, owners); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot update owners of campaign [%s] for bulk job [%s]", *job.CampaignID, job.ID))
+	}
+
+	if _, err := service.campaignService.Start(ctx, job.UserID, *job.CampaignID); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot start campaign [%s] for bulk job [%s]", *job.CampaignID, job.ID))
+	}
+}
+
+// parseRow converts a record into MessageSendParams and returns the validation error of the row when it cannot be sent
+func (service *BulkJobService) parseRow(ctx context.Context, ctxLogger telemetry.Logger, job *entities.BulkJob, state *bulkJobState, record []string) (*MessageSendParams, string) {
+	params, errors := service.parser.ParseRow(ctx, state.user, state.header, record, state.templates)
+	if len(errors) > 0 {
+		return nil, strings.Join(errors, " ")
+	}
+
+	owner := phonenumbers.Format(params.Owner, phonenumbers.E164)
+	if !job.CanUsePhoneNumber(owner) {
+		return nil, fmt.Sprintf("The FromPhoneNumber [%s] cannot be used with your API key", owner)
+	}
+
+	if message := service.validateOwner(ctx, ctxLogger, state, owner); message != "" {
+		return nil, message
+	}
+
+	return params, ""
+}
+
+func (service *BulkJobService) validateOwner(ctx context.Context, ctxLogger telemetry.Logger, state *bulkJobState, owner string) string {
+	if registered, ok := state.phones[owner]; ok {
+		if !registered {
+			return fmt.Sprintf("The FromPhoneNumber [%s] is not registered on your account", owner)
+		}
+		return ""
+	}
+
+	_, err := service.phoneService.Load(ctx, state.user.ID, owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		state.phones[owner] = false
+		return fmt.Sprintf("The FromPhoneNumber [%s] is not registered on your account", owner)
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load phone [%s] for user [%s]", owner, state.user.ID))
+		return fmt.Sprintf("Cannot validate the FromPhoneNumber [%s]. Please send the message again.", owner)
+	}
+
+	state.phones[owner] = true
+	return ""
+}
+
+func (service *BulkJobService) failure(message string) *string {
+	return &message
+}
+
+// bulkJobMissingColumns returns the required columns which are not in the header of a bulk SMS file
+func bulkJobMissingColumns(header []string) []string {
+	var result []string
+	for _, name := range []string{bulkJobColumnFrom, bulkJobColumnTo, bulkJobColumnContent} {
+		if !slices.ContainsFunc(header, func(column string) bool {
+			return strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")) == name
+		}) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// bulkJobIsEmpty checks if all the cells of a record are blank
+func bulkJobIsEmpty(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// bulkJobReader reads the records of a bulk SMS file one at a time
+type bulkJobReader interface {
+	// Read returns the next record or io.EOF when there are no more records
+	Read() ([]string, error)
+	Close() error
+}
+
+func newBulkJobReader(filename string, content io.Reader) (bulkJobReader, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(content)
+		reader.FieldsPerRecord = -1
+		return &csvBulkJobReader{reader: reader}, nil
+	case ".xlsx":
+		file, err := excelize.OpenReader(content)
+		if err != nil {
+			return nil, stacktrace.Propagatef(err, "cannot open excel file [%s]", filename)
+		}
+
+		rows, err := file.Rows(file.GetSheetName(0))
+		if err != nil {
+			return nil, stacktrace.Propagatef(err, "cannot read rows of excel file [%s]", filename)
+		}
+		return &xlsxBulkJobReader{file: file, rows: rows}, nil
+	default:
+		return nil, stacktrace.NewErrorf("the file [%s] is not a CSV or excel file", filename)
+	}
+}
+
+type csvBulkJobReader struct {
+	reader *csv.Reader
+}
+
+func (reader *csvBulkJobReader) Read() ([]string, error) {
+	return reader.reader.Read()
+}
+
+func (reader *csvBulkJobReader) Close() error {
+	return nil
+}
+
+type xlsxBulkJobReader struct {
+	file *excelize.File
+	rows *excelize.Rows
+}
+
+func (reader *xlsxBulkJobReader) Read() ([]string, error) {
+	if !reader.rows.Next() {
+		if err := reader.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return reader.rows.Columns()
+}
+
+func (reader *xlsxBulkJobReader) Close() error {
+	if err := reader.rows.Close(); err != nil {
+		return err
+	}
+	return reader.file.Close()
+}
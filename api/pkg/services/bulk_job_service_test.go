@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/nyaruka/phonenumbers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bulkJobRepositoryStub struct {
+	repositories.BulkJobRepository
+	job     entities.BulkJob
+	updates []entities.BulkJob
+}
+
+func (stub *bulkJobRepositoryStub) Load(context.Context, entities.UserID, uuid.UUID) (*entities.BulkJob, error) {
+	job := stub.job
+	return &job, nil
+}
+
+func (stub *bulkJobRepositoryStub) Update(_ context.Context, job *entities.BulkJob) error {
+	stub.job = *job
+	stub.updates = append(stub.updates, *job)
+	return nil
+}
+
+type userRepositoryStub struct {
+	repositories.UserRepository
+}
+
+func (stub *userRepositoryStub) Load(_ context.Context, userID entities.UserID) (*entities.User, error) {
+	return &entities.User{ID: userID}, nil
+}
+
+// bulkJobRowParserStub rejects every row so that the rows are processed without sending messages
+type bulkJobRowParserStub struct{}
+
+func (stub *bulkJobRowParserStub) ParseRow(_ context.Context, _ *entities.User, _ []string, record []string, _ map[string]*entities.MessageTemplate) (*MessageSendParams, []string) {
+	return nil, []string{fmt.Sprintf("The ToPhoneNumber [%s] is not a valid E.164 phone number", record[1])}
+}
+
+func newBulkJobServiceForTest(t *testing.T, job entities.BulkJob, rows int, queue *pushQueueStub) (*BulkJobService, *bulkJobRepositoryStub, repositories.AttachmentRepository) {
+	logger := &noopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+
+	content := strings.Builder{}
+	content.WriteString("FromPhoneNumber,ToPhoneNumber,Content\n")
+	for row := 0; row < rows; row++ {
+		content.WriteString(fmt.Sprintf("+18005550199,row-%d,Hello\n", row+2))
+	}
+
+	storage := repositories.NewMemoryAttachmentRepository(logger, tracer)
+	require.NoError(t, storage.Upload(context.Background(), job.Path, []byte(content.String()), "text/csv"))
+
+	repository := &bulkJobRepositoryStub{job: job}
+	service := NewBulkJobService(
+		logger,
+		tracer,
+		repository,
+		storage,
+		newEventDispatcherForTest(queue),
+		&UserService{logger: logger, tracer: tracer, repository: &userRepositoryStub{}},
+		nil,
+		nil,
+		&bulkJobRowParserStub{},
+		nil,
+		nil,
+		nil,
+	)
+	return service, repository, storage
+}
+
+func newBulkJobForTest(status entities.BulkJobStatus, rowOffset int) entities.BulkJob {
+	return entities.BulkJob{
+		ID:        uuid.New(),
+		UserID:    "user-id",
+		Filename:  "messages.csv",
+		Status:    status,
+		Path:      "bulk-jobs/user-id/messages.csv",
+		RowOffset: rowOffset,
+	}
+}
+
+func TestBulkJobServiceProcess_SavesTheProgressAndDispatchesTheNextBatch(t *testing.T) {
+	queue := &pushQueueStub{}
+	job := newBulkJobForTest(entities.BulkJobStatusPending, 0)
+	service, repository, storage := newBulkJobServiceForTest(t, job, bulkJobRowsPerEvent+1, queue)
+
+	err := service.Process(context.Background(), &BulkJobProcessParams{UserID: job.UserID, BulkJobID: job.ID, Source: "test"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.BulkJobStatusProcessing, repository.job.Status)
+	assert.Equal(t, bulkJobRowsPerEvent, repository.job.RowOffset)
+	assert.Equal(t, bulkJobRowsPerEvent, repository.job.TotalRows)
+	assert.Equal(t, bulkJobRowsPerEvent, repository.job.FailedRows)
+	require.Equal(t, []string{events.BulkJobProcessing}, queue.types())
+
+	var payload events.BulkJobProcessingPayload
+	require.NoError(t, queue.events[0].DataAs(&payload))
+	assert.Equal(t, bulkJobRowsPerEvent, payload.RowOffset)
+
+	err = service.Process(context.Background(), &BulkJobProcessParams{UserID: job.UserID, BulkJobID: job.ID, Source: "test", RowOffset: payload.RowOffset})
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.BulkJobStatusCompleted, repository.job.Status)
+	assert.Equal(t, bulkJobRowsPerEvent+1, repository.job.TotalRows)
+	assert.Equal(t, bulkJobRowsPerEvent+1, repository.job.FailedRows)
+	assert.Len(t, queue.events, 1)
+
+	_, err = storage.Download(context.Background(), job.Path)
+	assert.Equal(t, repositories.ErrCodeNotFound, stacktrace.GetCode(err))
+}
+
+func TestBulkJobServiceProcess_ResumesFromTheSavedRowOffset(t *testing.T) {
+	queue := &pushQueueStub{}
+	job := newBulkJobForTest(entities.BulkJobStatusProcessing, 2)
+	job.TotalRows, job.FailedRows = 2, 2
+	service, repository, _ := newBulkJobServiceForTest(t, job, 3, queue)
+
+	err := service.Process(context.Background(), &BulkJobProcessParams{UserID: job.UserID, BulkJobID: job.ID, Source: "test", RowOffset: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.BulkJobStatusCompleted, repository.job.Status)
+	assert.Equal(t, 3, repository.job.RowOffset)
+	assert.Equal(t, 3, repository.job.TotalRows)
+	assert.Equal(t, []entities.BulkJobError{{Row: 4, Message: "The ToPhoneNumber [row-4] is not a valid E.164 phone number"}}, repository.job.Errors)
+	assert.Empty(t, queue.events)
+}
+
+func TestBulkJobServiceProcess_SkipsEventsForAnotherRowOffset(t *testing.T) {
+	queue := &pushQueueStub{}
+	job := newBulkJobForTest(entities.BulkJobStatusProcessing, bulkJobRowsPerEvent)
+	service, repository, _ := newBulkJobServiceForTest(t, job, 3, queue)
+
+	err := service.Process(context.Background(), &BulkJobProcessParams{UserID: job.UserID, BulkJobID: job.ID, Source: "test", RowOffset: 0})
+
+	require.NoError(t, err)
+	assert.Empty(t, repository.updates)
+	assert.Empty(t, queue.events)
+}
+
+func TestBulkJobMissingColumns(t *testing.T) {
+	assert.Empty(t, bulkJobMissingColumns([]string{"\ufeffFromPhoneNumber", "ToPhoneNumber", " Content ", "name"}))
+	assert.Equal(t, []string{"ToPhoneNumber", "Content"}, bulkJobMissingColumns([]string{"FromPhoneNumber"}))
+	assert.True(t, bulkJobIsEmpty([]string{"", " "}))
+}
+
+func TestNewBulkJobReader(t *testing.T) {
+	reader, err := newBulkJobReader("messages.CSV", strings.NewReader("FromPhoneNumber,ToPhoneNumber,Content\n+18005550199,+18005550100,\"Hello, world\",extra\n"))
+	assert.Nil(t, err)
+
+	record, err := reader.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"FromPhoneNumber", "ToPhoneNumber", "Content"}, record)
+
+	record, err = reader.Read()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"+18005550199", "+18005550100", "Hello, world", "extra"}, record)
+
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+	assert.Nil(t, reader.Close())
+
+	_, err = newBulkJobReader("messages.txt", strings.NewReader("hello"))
+	assert.NotNil(t, err)
+}
+
+func TestBulkJobServiceParseRow_RejectsOwnersWhichTheAPIKeyCannotUse(t *testing.T) {
+	service := &BulkJobService{parser: &bulkJobRowParserOwnerStub{}}
+	job := newBulkJobForTest(entities.BulkJobStatusProcessing, 0)
+	job.PhoneNumbers = []string{"+18005550100"}
+
+	params, message := service.parseRow(context.Background(), &noopLogger{}, &job, &bulkJobState{phones: map[string]bool{}}, []string{"+18005550199", "+18005550101", "Hello"})
+
+	assert.Nil(t, params)
+	assert.Equal(t, "The FromPhoneNumber [+18005550199] cannot be used with your API key", message)
+}
+
+// bulkJobRowParserOwnerStub accepts every row and sends it from the phone number in the first column
+type bulkJobRowParserOwnerStub struct{}
+
+func (stub *bulkJobRowParserOwnerStub) ParseRow(_ context.Context, _ *entities.User, _ []string, record []string, _ map[string]*entities.MessageTemplate) (*MessageSendParams, []string) {
+	owner, _ := phonenumbers.Parse(record[0], phonenumbers.UNKNOWN_REGION)
+	return &MessageSendParams{Owner: owner, Contact: record[1], Content: record[2]}, nil
+}
@@ -0,0 +1,425 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CampaignService manages the bulk message campaigns of a user
+type CampaignService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.CampaignRepository
+	messageRepository repositories.MessageRepository
+	messageService    *MessageService
+	phoneService      *PhoneService
+	eventDispatcher   *EventDispatcher
+}
+
+// NewCampaignService creates a new CampaignService
+func NewCampaignService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.CampaignRepository,
+	messageRepository repositories.MessageRepository,
+	messageService *MessageService,
+	phoneService *PhoneService,
+	eventDispatcher *EventDispatcher,
+) (s *CampaignService) {
+	return &CampaignService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		repository:        repository,
+		messageRepository: messageRepository,
+		messageService:    messageService,
+		phoneService:      phoneService,
+		eventDispatcher:   eventDispatcher,
+	}
+}
+
+// CampaignStoreParams are parameters for creating an entities.Campaign
+type CampaignStoreParams struct {
+	UserID      entities.UserID
+	Name        string
+	RequestID   string
+	Owners      []string
+	ScheduledAt *time.Time
+}
+
+// Store a new entities.Campaign as a draft while its messages are queued
+func (service *CampaignService) Store(ctx context.Context, params *CampaignStoreParams) (*entities.Campaign, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	campaign := &entities.Campaign{
+		ID:          uuid.New(),
+		UserID:      params.UserID,
+		Name:        params.Name,
+		Status:      entities.CampaignStatusDraft,
+		RequestID:   params.RequestID,
+		Owners:      pq.StringArray(params.Owners),
+		ScheduledAt: params.ScheduledAt,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if err := service.repository.Store(ctx, campaign); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save campaign with request ID [%s] for user [%s]", params.RequestID, params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("campaign saved with id [%s] and request ID [%s] for user [%s]", campaign.ID, campaign.RequestID, campaign.UserID))
+	return campaign, nil
+}
+
+// Start marks an entities.Campaign as running after its messages have been queued
+func (service *CampaignService) Start(ctx context.Context, userID entities.UserID, campaignID uuid.UUID) (*entities.Campaign, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// the campaign is loaded again because it could have been canceled while the messages were queued
+	campaign, err := service.repository.Load(ctx, userID, campaignID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load campaign [%s] for user [%s]", campaignID, userID))
+	}
+
+	if campaign.Status != entities.CampaignStatusDraft {
+		ctxLogger.Info(fmt.Sprintf("campaign [%s] has status [%s] and will not be started", campaign.ID, campaign.Status))
+		return campaign, nil
+	}
+
+	if err = service.repository.Update(ctx, campaign.Running(time.Now().UTC())); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot start campaign [%s] for user [%s]", campaign.ID, campaign.UserID))
+	}
+
+	return campaign, nil
+}
+
+// UpdateOwners sets the phone numbers which send the messages of an entities.Campaign
+func (service *CampaignService) UpdateOwners(ctx context.Context, userID entities.UserID, campaignID uuid.UUID, owners []string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	campaign, err := service.repository.Load(ctx, userID, campaignID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load campaign [%s] for user [%s]", campaignID, userID))
+	}
+
+	campaign.Owners = pq.StringArray(owners)
+	campaign.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Update(ctx, campaign); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update owners of campaign [%s] for user [%s]", campaign.ID, campaign.UserID))
+	}
+
+	return nil
+}
+
+// Index fetches the entities.Campaign of a user
+func (service *CampaignService) Index(ctx context.Context, userID entities.UserID, status string, params repositories.IndexParams) ([]*entities.Campaign, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	campaigns, err := service.repository.Index(ctx, userID, status, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch campaigns for user [%s] with params [%+#v]", userID, params))
+	}
+
+	if err = service.addCounters(ctx, userID, campaigns); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not add counters to [%d] campaigns for user [%s]", len(campaigns), userID))
+	}
+
+	return campaigns, nil
+}
+
+// Load an entities.Campaign of a user
+func (service *CampaignService) Load(ctx context.Context, userID entities.UserID, campaignID uuid.UUID) (*entities.Campaign, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	campaign, err := service.repository.Load(ctx, userID, campaignID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load campaign [%s] for user [%s]", campaignID, userID))
+	}
+
+	if err = service.addCounters(ctx, userID, []*entities.Campaign{campaign}); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not add counters to campaign [%s] for user [%s]", campaignID, userID))
+	}
+
+	return campaign, nil
+}
+
+// Pause stops the phones from sending the messages of a running entities.Campaign
+func (service *CampaignService) Pause(ctx context.Context, source string, campaign *entities.Campaign) (*entities.Campaign, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if !campaign.CanBePaused() {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("campaign with ID [%s] has status [%s] and cannot be paused", campaign.ID, campaign.Status))
+	}
+
+	// the messages are paused before the campaign so that a failure does not leave a paused campaign with messages which are still sent
+	messages, err := service.messageService.PauseMessages(ctx, source, campaign.UserID, campaign.RequestID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot pause messages of campaign [%s]", campaign.ID))
+	}
+
+	if err = service.repository.Update(ctx, campaign.Paused(time.Now().UTC())); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update campaign [%s] as paused", campaign.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("campaign [%s] has been paused with [%d] messages on hold for user [%s]", campaign.ID, len(messages), campaign.UserID))
+	return service.Load(ctx, campaign.UserID, campaign.ID)
+}
+
+// Resume marks a paused entities.Campaign as running, the paused messages are rescheduled in the background by ResumeMessages
+func (service *CampaignService) Resume(ctx context.Context, source string, campaign *entities.Campaign) (*entities.Campaign, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if !campaign.CanBeResumed() {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("campaign with ID [%s] has status [%s] and cannot be resumed", campaign.ID, campaign.Status))
+	}
+
+	if err := service.repository.Update(ctx, campaign.Running(time.Now().UTC())); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update campaign [%s] as running", campaign.ID))
+	}
+
+	event, err := service.createEvent(events.CampaignAPIResumed, source, &events.CampaignAPIResumedPayload{
+		CampaignID: campaign.ID,
+		UserID:     campaign.UserID,
+		RequestID:  campaign.RequestID,
+		Timestamp:  time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for campaign [%s]", events.CampaignAPIResumed, campaign.ID))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch [%s] event for campaign [%s]", event.Type(), campaign.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("campaign [%s] has been resumed for user [%s]", campaign.ID, campaign.UserID))
+	return service.Load(ctx, campaign.UserID, campaign.ID)
+}
+
+// ResumeMessages reschedules the paused messages of a resumed entities.Campaign at the sending rate of each phone
+func (service *CampaignService) ResumeMessages(ctx context.Context, source string, payload *events.CampaignAPIResumedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	campaign, err := service.repository.Load(ctx, payload.UserID, payload.CampaignID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load campaign [%s] for user [%s]", payload.CampaignID, payload.UserID))
+	}
+
+	if campaign.Status != entities.CampaignStatusRunning {
+		ctxLogger.Info(fmt.Sprintf("campaign [%s] has status [%s] and its messages will not be resumed", campaign.ID, campaign.Status))
+		return nil
+	}
+
+	messages, err := service.messageRepository.FetchByRequestID(ctx, campaign.UserID, campaign.RequestID, []entities.MessageStatus{entities.MessageStatusPaused})
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch paused messages of campaign [%s]", campaign.ID))
+	}
+
+	sendTimes := service.sendTimes(time.Now().UTC(), messages, service.messagesPerMinute(ctx, ctxLogger, campaign.UserID, messages))
+	for _, message := range messages {
+		// resuming a large campaign takes time so the first send times can be in the past when the message is rescheduled
+		sendAt := sendTimes[message.ID]
+		if now := time.Now().UTC(); sendAt.Before(now) {
+			sendAt = now
+		}
+
+		if _, err = service.messageService.RescheduleMessage(ctx, source, message, sendAt); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot resume message [%s] of campaign [%s]", message.ID, campaign.ID))
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("resumed [%d] messages of campaign [%s] for user [%s]", len(messages), campaign.ID, campaign.UserID))
+	return nil
+}
+
+// Cancel stops the messages of an entities.Campaign which have not yet been sent by the phones, the messages are canceled in the background by CancelMessages
+func (service *CampaignService) Cancel(ctx context.Context, source string, campaign *entities.Campaign) (*entities.Campaign, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if !campaign.CanBeCanceled() {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("campaign with ID [%s] has status [%s] and cannot be canceled", campaign.ID, campaign.Status))
+	}
+
+	// the messages are paused first so that the phones stop sending them while each message is canceled
+	messages, err := service.messageService.PauseMessages(ctx, source, campaign.UserID, campaign.RequestID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot pause messages of campaign [%s]", campaign.ID))
+	}
+
+	if err = service.repository.Update(ctx, campaign.Canceled(time.Now().UTC())); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update campaign [%s] as canceled", campaign.ID))
+	}
+
+	event, err := service.createEvent(events.CampaignAPICanceled, source, &events.CampaignAPICanceledPayload{
+		CampaignID: campaign.ID,
+		UserID:     campaign.UserID,
+		RequestID:  campaign.RequestID,
+		Timestamp:  time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for campaign [%s]", events.CampaignAPICanceled, campaign.ID))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch [%s] event for campaign [%s]", event.Type(), campaign.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("campaign [%s] has been canceled with [%d] messages on hold for user [%s]", campaign.ID, len(messages), campaign.UserID))
+	return service.Load(ctx, campaign.UserID, campaign.ID)
+}
+
+// CancelMessages cancels the paused messages of a canceled entities.Campaign
+func (service *CampaignService) CancelMessages(ctx context.Context, source string, payload *events.CampaignAPICanceledPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	messages, err := service.messageRepository.FetchByRequestID(ctx, payload.UserID, payload.RequestID, []entities.MessageStatus{entities.MessageStatusPaused})
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch paused messages of campaign [%s]", payload.CampaignID))
+	}
+
+	for _, message := range messages {
+		if _, err = service.messageService.CancelMessage(ctx, source, message); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot cancel message [%s] of campaign [%s]", message.ID, payload.CampaignID))
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("canceled [%d] messages of campaign [%s] for user [%s]", len(messages), payload.CampaignID, payload.UserID))
+	return nil
+}
+
+// Complete marks the running entities.Campaign with a request ID as completed when the phones have processed all its messages
+func (service *CampaignService) Complete(ctx context.Context, userID entities.UserID, requestID *string) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if requestID == nil || *requestID == "" {
+		return nil
+	}
+
+	campaign, err := service.repository.LoadByRequestID(ctx, userID, *requestID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load campaign with request ID [%s] for user [%s]", *requestID, userID))
+	}
+
+	if campaign.Status != entities.CampaignStatusRunning {
+		return nil
+	}
+
+	if err = service.addCounters(ctx, userID, []*entities.Campaign{campaign}); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not add counters to campaign [%s] for user [%s]", campaign.ID, userID))
+	}
+
+	if !campaign.IsFinished() {
+		return nil
+	}
+
+	if err = service.repository.Update(ctx, campaign.Completed(time.Now().UTC())); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update campaign [%s] as completed", campaign.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("campaign [%s] has been completed for user [%s]", campaign.ID, userID))
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Campaign for an entities.UserID.
+func (service *CampaignService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.Campaign] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Campaign] for user with ID [%s]", userID))
+	return nil
+}
+
+// addCounters sets the message counters of the campaigns
+func (service *CampaignService) addCounters(ctx context.Context, userID entities.UserID, campaigns []*entities.Campaign) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	requestIDs := make([]string, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		requestIDs = append(requestIDs, campaign.RequestID)
+	}
+
+	counters, err := service.messageRepository.GetBulkMessagesByRequestIDs(ctx, userID, requestIDs)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch counters of [%d] campaigns", len(campaigns)))
+	}
+
+	lookup := make(map[string]*entities.BulkMessage, len(counters))
+	for _, counter := range counters {
+		lookup[counter.RequestID] = counter
+	}
+
+	for _, campaign := range campaigns {
+		campaign.Counters = lookup[campaign.RequestID]
+	}
+
+	return nil
+}
+
+// messagesPerMinute returns the sending rate of each phone which sends the messages
+func (service *CampaignService) messagesPerMinute(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, messages []*entities.Message) map[string]uint {
+	rates := map[string]uint{}
+	for _, message := range messages {
+		if _, ok := rates[message.Owner]; ok {
+			continue
+		}
+
+		rates[message.Owner] = 0
+		phone, err := service.phoneService.Load(ctx, userID, message.Owner)
+		if err != nil {
+			ctxLogger.Warn(stacktrace.Propagatef(err, "cannot load phone [%s] for user [%s]. messages will not be spaced out", message.Owner, userID))
+			continue
+		}
+		rates[message.Owner] = phone.MessagesPerMinute
+	}
+	return rates
+}
+
+// sendTimes spaces out the messages of each phone starting from now in the same way as MessageService.getSendDelay.
+// Messages which were scheduled for a time in the future keep their send time.
+func (service *CampaignService) sendTimes(now time.Time, messages []*entities.Message, messagesPerMinute map[string]uint) map[uuid.UUID]time.Time {
+	indexes := map[string]int{}
+	result := make(map[uuid.UUID]time.Time, len(messages))
+	for _, message := range messages {
+		if message.ScheduledSendTime != nil && message.ScheduledSendTime.After(now) {
+			result[message.ID] = *message.ScheduledSendTime
+			continue
+		}
+
+		sendAt := now
+		if rate := messagesPerMinute[message.Owner]; rate > 0 {
+			sendAt = now.Add(time.Duration(indexes[message.Owner]) * (time.Minute / time.Duration(rate)))
+		}
+		indexes[message.Owner]++
+		result[message.ID] = sendAt
+	}
+	return result
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type campaignRepositoryStub struct {
+	repositories.CampaignRepository
+	campaign *entities.Campaign
+	calls    *[]string
+}
+
+func (stub *campaignRepositoryStub) Load(context.Context, entities.UserID, uuid.UUID) (*entities.Campaign, error) {
+	return stub.campaign, nil
+}
+
+func (stub *campaignRepositoryStub) LoadByRequestID(context.Context, entities.UserID, string) (*entities.Campaign, error) {
+	return stub.campaign, nil
+}
+
+func (stub *campaignRepositoryStub) Update(_ context.Context, campaign *entities.Campaign) error {
+	*stub.calls = append(*stub.calls, "campaign."+string(campaign.Status))
+	return nil
+}
+
+type campaignMessageRepositoryStub struct {
+	repositories.MessageRepository
+	counters *entities.BulkMessage
+	calls    *[]string
+}
+
+func (stub *campaignMessageRepositoryStub) UpdateStatusByRequestID(_ context.Context, _ entities.UserID, _ string, _ []entities.MessageStatus, status entities.MessageStatus) ([]*entities.Message, error) {
+	*stub.calls = append(*stub.calls, "messages."+string(status))
+	return []*entities.Message{}, nil
+}
+
+func (stub *campaignMessageRepositoryStub) FetchByRequestID(context.Context, entities.UserID, string, []entities.MessageStatus) ([]*entities.Message, error) {
+	*stub.calls = append(*stub.calls, "messages.fetched")
+	return []*entities.Message{}, nil
+}
+
+func (stub *campaignMessageRepositoryStub) GetBulkMessagesByRequestIDs(context.Context, entities.UserID, []string) ([]*entities.BulkMessage, error) {
+	if stub.counters == nil {
+		return []*entities.BulkMessage{}, nil
+	}
+	return []*entities.BulkMessage{stub.counters}, nil
+}
+
+func newCampaignServiceForTest(campaign *entities.Campaign, counters *entities.BulkMessage, queue *pushQueueStub) (*CampaignService, *[]string) {
+	logger := &noopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	calls := &[]string{}
+	messageRepository := &campaignMessageRepositoryStub{counters: counters, calls: calls}
+	dispatcher := newEventDispatcherForTest(queue)
+	messageService := &MessageService{logger: logger, tracer: tracer, repository: messageRepository, eventDispatcher: dispatcher}
+	return NewCampaignService(logger, tracer, &campaignRepositoryStub{campaign: campaign, calls: calls}, messageRepository, messageService, nil, dispatcher), calls
+}
+
+func TestCampaignServiceSendTimes(t *testing.T) {
+	logger := &noopLogger{}
+	service := NewCampaignService(logger, telemetry.NewOtelLogger("test", logger), nil, nil, nil, nil, nil)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	first := &entities.Message{ID: uuid.New(), Owner: "+18005550199"}
+	scheduled := &entities.Message{ID: uuid.New(), Owner: "+18005550199", ScheduledSendTime: &future}
+	second := &entities.Message{ID: uuid.New(), Owner: "+18005550199", ScheduledSendTime: &past}
+	third := &entities.Message{ID: uuid.New(), Owner: "+18005550199"}
+	other := &entities.Message{ID: uuid.New(), Owner: "+18005550100"}
+	unlimited := &entities.Message{ID: uuid.New(), Owner: "+18005550111"}
+
+	result := service.sendTimes(
+		now,
+		[]*entities.Message{first, scheduled, second, third, other, unlimited, unlimited},
+		map[string]uint{"+18005550199": 2, "+18005550100": 1},
+	)
+
+	assert.Equal(t, now, result[first.ID])
+	assert.Equal(t, future, result[scheduled.ID])
+	assert.Equal(t, now.Add(30*time.Second), result[second.ID])
+	assert.Equal(t, now.Add(time.Minute), result[third.ID])
+	assert.Equal(t, now, result[other.ID])
+	assert.Equal(t, now, result[unlimited.ID])
+}
+
+func TestCampaignServicePause_PausesTheMessagesBeforeTheCampaign(t *testing.T) {
+	campaign := &entities.Campaign{ID: uuid.New(), UserID: "user-id", RequestID: "bulk-request", Status: entities.CampaignStatusRunning}
+	service, calls := newCampaignServiceForTest(campaign, nil, &pushQueueStub{})
+
+	_, err := service.Pause(context.Background(), "/v1/campaigns", campaign)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"messages." + entities.MessageStatusPaused, "campaign." + string(entities.CampaignStatusPaused)}, *calls)
+}
+
+func TestCampaignServiceResume_ReschedulesTheMessagesInTheBackground(t *testing.T) {
+	campaign := &entities.Campaign{ID: uuid.New(), UserID: "user-id", RequestID: "bulk-request", Status: entities.CampaignStatusPaused}
+	queue := &pushQueueStub{}
+	service, calls := newCampaignServiceForTest(campaign, nil, queue)
+
+	_, err := service.Resume(context.Background(), "/v1/campaigns", campaign)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"campaign." + string(entities.CampaignStatusRunning)}, *calls)
+	assert.Equal(t, []string{events.CampaignAPIResumed}, queue.types())
+}
+
+func TestCampaignServiceCancel_PausesTheMessagesAndCancelsThemInTheBackground(t *testing.T) {
+	campaign := &entities.Campaign{ID: uuid.New(), UserID: "user-id", RequestID: "bulk-request", Status: entities.CampaignStatusRunning}
+	queue := &pushQueueStub{}
+	service, calls := newCampaignServiceForTest(campaign, nil, queue)
+
+	_, err := service.Cancel(context.Background(), "/v1/campaigns", campaign)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"messages." + entities.MessageStatusPaused, "campaign." + string(entities.CampaignStatusCanceled)}, *calls)
+	assert.Equal(t, []string{events.CampaignAPICanceled}, queue.types())
+}
+
+func TestCampaignServiceComplete_CompletesRunningCampaignsWithoutOutstandingMessages(t *testing.T) {
+	requestID := "bulk-request"
+
+	finished := &entities.Campaign{ID: uuid.New(), UserID: "user-id", RequestID: requestID, Status: entities.CampaignStatusRunning}
+	service, calls := newCampaignServiceForTest(finished, &entities.BulkMessage{RequestID: requestID, Total: 2, SentCount: 1, CanceledCount: 1}, &pushQueueStub{})
+	require.NoError(t, service.Complete(context.Background(), "user-id", &requestID))
+	assert.Equal(t, []string{"campaign." + string(entities.CampaignStatusCompleted)}, *calls)
+
+	outstanding := &entities.Campaign{ID: uuid.New(), UserID: "user-id", RequestID: requestID, Status: entities.CampaignStatusRunning}
+	service, calls = newCampaignServiceForTest(outstanding, &entities.BulkMessage{RequestID: requestID, Total: 2, SentCount: 1, PendingCount: 1}, &pushQueueStub{})
+	require.NoError(t, service.Complete(context.Background(), "user-id", &requestID))
+	assert.Empty(t, *calls)
+
+	paused := &entities.Campaign{ID: uuid.New(), UserID: "user-id", RequestID: requestID, Status: entities.CampaignStatusPaused}
+	service, calls = newCampaignServiceForTest(paused, &entities.BulkMessage{RequestID: requestID, Total: 2, SentCount: 2}, &pushQueueStub{})
+	require.NoError(t, service.Complete(context.Background(), "user-id", &requestID))
+	assert.Empty(t, *calls)
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MaxContactGroupRecipients is the maximum number of recipients which receive a message sent to an entities.ContactGroup
+const MaxContactGroupRecipients = 1000
+
+// ContactGroupService manages the recipient groups of a user
+type ContactGroupService struct {
+	service
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	repository         repositories.ContactGroupRepository
+	messageRepository  repositories.MessageRepository
+	suppressionService *SuppressionService
+}
+
+// NewContactGroupService creates a new ContactGroupService
+func NewContactGroupService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ContactGroupRepository,
+	messageRepository repositories.MessageRepository,
+	suppressionService *SuppressionService,
+) (s *ContactGroupService) {
+	return &ContactGroupService{
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		repository:         repository,
+		messageRepository:  messageRepository,
+		suppressionService: suppressionService,
+	}
+}
+
+// ContactGroupUpsertParams are parameters for creating or updating an entities.ContactGroup
+type ContactGroupUpsertParams struct {
+	UserID     entities.UserID
+	Name       string
+	Type       entities.ContactGroupType
+	Members    []string
+	Owner      *string
+	Direction  *entities.ContactGroupDirection
+	WithinDays *uint
+}
+
+// Index fetches the entities.ContactGroup of a user
+func (service *ContactGroupService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.ContactGroup, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	groups, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch contact groups for user [%s] with params [%+#v]", userID, params))
+	}
+
+	return groups, nil
+}
+
+// Load an entities.ContactGroup of a user
+func (service *ContactGroupService) Load(ctx context.Context, userID entities.UserID, groupID uuid.UUID) (*entities.ContactGroup, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	group, err := service.repository.Load(ctx, userID, groupID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load contact group [%s] for user [%s]", groupID, userID))
+	}
+
+	return group, nil
+}
+
+// Store a new entities.ContactGroup
+func (service *ContactGroupService) Store(ctx context.Context, params *ContactGroupUpsertParams) (*entities.ContactGroup, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	group := &entities.ContactGroup{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		CreatedAt: time.Now().UTC(),
+	}
+	service.apply(group, params)
+
+	if err := service.repository.Store(ctx, group); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save contact group for user [%s]", params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact group saved with id [%s] for user [%s]", group.ID, group.UserID))
+	return group, nil
+}
+
+// Update an existing entities.ContactGroup
+func (service *ContactGroupService) Update(ctx context.Context, groupID uuid.UUID, params *ContactGroupUpsertParams) (*entities.ContactGroup, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	group, err := service.repository.Load(ctx, params.UserID, groupID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load contact group [%s] for user [%s]", groupID, params.UserID))
+	}
+
+	service.apply(group, params)
+
+	if err = service.repository.Update(ctx, group); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update contact group [%s] for user [%s]", groupID, params.UserID))
+	}
+
+	return group, nil
+}
+
+// Delete an entities.ContactGroup
+func (service *ContactGroupService) Delete(ctx context.Context, userID entities.UserID, groupID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, groupID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load contact group [%s] for user [%s]", groupID, userID))
+	}
+
+	if err := service.repository.Delete(ctx, userID, groupID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete contact group [%s] for user [%s]", groupID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.ContactGroup for an entities.UserID.
+func (service *ContactGroupService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.ContactGroup] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.ContactGroup] for user with ID [%s]", userID))
+	return nil
+}
+
+// Recipients returns the phone numbers which receive a message sent by the owner to an entities.ContactGroup.
+// Contacts who opted out of receiving messages from the owner are excluded.
+func (service *ContactGroupService) Recipients(ctx context.Context, group *entities.ContactGroup, owner string) ([]string, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	members := []string(group.Members)
+	if group.IsDynamic() && group.Owner != nil {
+		contacts, err := service.messageRepository.FetchContacts(ctx, group.UserID, *group.Owner, group.MessageTypes(), group.Since(time.Now().UTC()), MaxContactGroupRecipients+1)
+		if err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch members of dynamic contact group [%s] for user [%s]", group.ID, group.UserID))
+		}
+		members = contacts
+	}
+
+	suppressed, err := service.suppressionService.SuppressedContacts(ctx, group.UserID, owner, members)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch suppressed members of contact group [%s] for user [%s]", group.ID, group.UserID))
+	}
+
+	recipients := service.withoutContacts(members, suppressed)
+	ctxLogger.Info(fmt.Sprintf("contact group [%s] has [%d] recipients for owner [%s] after excluding [%d] suppressed contacts", group.ID, len(recipients), owner, len(members)-len(recipients)))
+	return recipients, nil
+}
+
+func (service *ContactGroupService) apply(group *entities.ContactGroup, params *ContactGroupUpsertParams) {
+	group.Name = params.Name
+	group.Type = params.Type
+	group.Members = pq.StringArray{}
+	group.Owner = nil
+	group.Direction = nil
+	group.WithinDays = nil
+	group.UpdatedAt = time.Now().UTC()
+
+	if params.Type == entities.ContactGroupTypeDynamic {
+		group.Owner = params.Owner
+		group.Direction = params.Direction
+		group.WithinDays = params.WithinDays
+		return
+	}
+
+	if params.Members != nil {
+		group.Members = pq.StringArray(params.Members)
+	}
+}
+
+// withoutContacts removes the excluded contacts from the members while keeping the order of the members
+func (service *ContactGroupService) withoutContacts(members []string, excluded []string) []string {
+	lookup := make(map[string]struct{}, len(excluded))
+	for _, contact := range excluded {
+		lookup[contact] = struct{}{}
+	}
+
+	result := make([]string, 0, len(members))
+	for _, member := range members {
+		if _, ok := lookup[member]; !ok {
+			result = append(result, member)
+		}
+	}
+	return result
+}
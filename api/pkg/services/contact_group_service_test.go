@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func newContactGroupServiceForTest() *ContactGroupService {
+	logger := &noopLogger{}
+	return NewContactGroupService(logger, telemetry.NewOtelLogger("test", logger), nil, nil, nil)
+}
+
+func TestContactGroupServiceWithoutContacts(t *testing.T) {
+	service := newContactGroupServiceForTest()
+
+	members := []string{"+18005550100", "+18005550101", "+18005550102"}
+
+	assert.Equal(t, []string{"+18005550100", "+18005550102"}, service.withoutContacts(members, []string{"+18005550101"}))
+	assert.Equal(t, members, service.withoutContacts(members, nil))
+	assert.Empty(t, service.withoutContacts(members, members))
+}
+
+func TestContactGroupServiceApply(t *testing.T) {
+	service := newContactGroupServiceForTest()
+
+	owner := "+18005550199"
+	direction := entities.ContactGroupDirectionReceived
+	days := uint(90)
+
+	group := &entities.ContactGroup{Members: []string{"+18005550100"}}
+	service.apply(group, &ContactGroupUpsertParams{
+		Name:       "Recent customers",
+		Type:       entities.ContactGroupTypeDynamic,
+		Members:    []string{"+18005550101"},
+		Owner:      &owner,
+		Direction:  &direction,
+		WithinDays: &days,
+	})
+
+	assert.Equal(t, "Recent customers", group.Name)
+	assert.Empty(t, group.Members)
+	assert.Equal(t, &owner, group.Owner)
+	assert.Equal(t, &days, group.WithinDays)
+
+	service.apply(group, &ContactGroupUpsertParams{Name: "VIP", Type: entities.ContactGroupTypeStatic, Members: []string{"+18005550101"}})
+
+	assert.Equal(t, []string{"+18005550101"}, []string(group.Members))
+	assert.Nil(t, group.Owner)
+	assert.Nil(t, group.Direction)
+	assert.Nil(t, group.WithinDays)
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+)
+
+// ContactService manages the address book of a user
+type ContactService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.ContactRepository
+}
+
+// NewContactService creates a new ContactService
+func NewContactService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ContactRepository,
+) (s *ContactService) {
+	return &ContactService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// ContactUpsertParams are parameters for creating or updating an entities.Contact
+type ContactUpsertParams struct {
+	UserID      entities.UserID
+	PhoneNumber string
+	Name        string
+	Tags        []string
+	Attributes  map[string]any
+	ActorID     *entities.UserID
+}
+
+// Index fetches the entities.Contact of a user
+func (service *ContactService) Index(ctx context.Context, userID entities.UserID, tag string, params repositories.IndexParams) ([]*entities.Contact, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	contacts, err := service.repository.Index(ctx, userID, tag, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch contacts for user [%s] with params [%+#v]", userID, params))
+	}
+
+	return contacts, nil
+}
+
+// Load an entities.Contact of a user
+func (service *ContactService) Load(ctx context.Context, userID entities.UserID, contactID uuid.UUID) (*entities.Contact, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	contact, err := service.repository.Load(ctx, userID, contactID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load contact [%s] for user [%s]", contactID, userID))
+	}
+
+	return contact, nil
+}
+
+// FindByPhoneNumber returns the entities.Contact of a user with a phone number, nil is returned when there is no contact
+func (service *ContactService) FindByPhoneNumber(ctx context.Context, userID entities.UserID, phoneNumber string) (*entities.Contact, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	contacts, err := service.repository.FetchByPhoneNumbers(ctx, userID, []string{phoneNumber})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot find contact with phone number [%s] for user [%s]", phoneNumber, userID))
+	}
+
+	if len(contacts) == 0 {
+		return nil, nil
+	}
+
+	return contacts[0], nil
+}
+
+// Name returns the name of the entities.Contact of a user with a phone number, nil is returned when the contact has no name
+func (service *ContactService) Name(ctx context.Context, userID entities.UserID, phoneNumber string) *string {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	contact, err := service.FindByPhoneNumber(ctx, userID, phoneNumber)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot find contact with phone number [%s] for user [%s]", phoneNumber, userID))
+		return nil
+	}
+
+	if contact == nil || contact.Name == "" {
+		return nil
+	}
+
+	return &contact.Name
+}
+
+// Names returns the names of the entities.Contact of a user indexed by phone number
+func (service *ContactService) Names(ctx context.Context, userID entities.UserID, phoneNumbers []string) (map[string]string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	contacts, err := service.repository.FetchByPhoneNumbers(ctx, userID, phoneNumbers)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch names of [%d] contacts for user [%s]", len(phoneNumbers), userID))
+	}
+
+	names := make(map[string]string, len(contacts))
+	for _, contact := range contacts {
+		if contact.Name != "" {
+			names[contact.PhoneNumber] = contact.Name
+		}
+	}
+
+	return names, nil
+}
+
+// Store a new entities.Contact
+func (service *ContactService) Store(ctx context.Context, params *ContactUpsertParams) (*entities.Contact, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	contact := service.newContact(params)
+	if err := service.repository.Store(ctx, contact); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save contact for user [%s]", params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("contact saved with id [%s] for user [%s]", contact.ID, contact.UserID))
+	return contact, nil
+}
+
+// Import creates or updates multiple entities.Contact, an existing contact with the same phone number is overwritten
+func (service *ContactService) Import(ctx context.Context, userID entities.UserID, params []*ContactUpsertParams) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// the last row wins when a phone number is repeated because postgres cannot update the same row twice in an upsert
+	indexes := make(map[string]int, len(params))
+	contacts := make([]*entities.Contact, 0, len(params))
+	for _, item := range params {
+		contact := service.newContact(item)
+		if index, ok := indexes[contact.PhoneNumber]; ok {
+			contacts[index] = contact
+			continue
+		}
+		indexes[contact.PhoneNumber] = len(contacts)
+		contacts = append(contacts, contact)
+	}
+
+	if err := service.repository.Upsert(ctx, contacts); err != nil {
+		return 0, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot import [%d] contacts for user [%s]", len(contacts), userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("imported [%d] contacts for user [%s]", len(contacts), userID))
+	return len(contacts), nil
+}
+
+// Update an existing entities.Contact
+func (service *ContactService) Update(ctx context.Context, contactID uuid.UUID, params *ContactUpsertParams) (*entities.Contact, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	contact, err := service.repository.Load(ctx, params.UserID, contactID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load contact [%s] for user [%s]", contactID, params.UserID))
+	}
+
+	contact.PhoneNumber = params.PhoneNumber
+	contact.Name = params.Name
+	contact.Tags = service.tags(params.Tags)
+	contact.Attributes = service.attributes(params.Attributes)
+	contact.ActorID = params.ActorID
+	contact.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Update(ctx, contact); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update contact [%s] for user [%s]", contactID, params.UserID))
+	}
+
+	return contact, nil
+}
+
+// Delete an entities.Contact
+func (service *ContactService) Delete(ctx context.Context, userID entities.UserID, contactID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, contactID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load contact [%s] for user [%s]", contactID, userID))
+	}
+
+	if err := service.repository.Delete(ctx, userID, contactID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete contact [%s] for user [%s]", contactID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Contact for an entities.UserID.
+func (service *ContactService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.Contact] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Contact] for user with ID [%s]", userID))
+	return nil
+}
+
+func (service *ContactService) newContact(params *ContactUpsertParams) *entities.Contact {
+	return &entities.Contact{
+		ID:          uuid.New(),
+		UserID:      params.UserID,
+		PhoneNumber: params.PhoneNumber,
+		Name:        params.Name,
+		Tags:        service.tags(params.Tags),
+		Attributes:  service.attributes(params.Attributes),
+		ActorID:     params.ActorID,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+}
+
+func (service *ContactService) tags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func (service *ContactService) attributes(attributes map[string]any) map[string]any {
+	if attributes == nil {
+		return map[string]any{}
+	}
+	return attributes
+}
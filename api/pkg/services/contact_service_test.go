@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contactRepositoryStub struct {
+	contacts []*entities.Contact
+	upserted []*entities.Contact
+}
+
+func (stub *contactRepositoryStub) Store(context.Context, *entities.Contact) error {
+	return nil
+}
+
+func (stub *contactRepositoryStub) Upsert(_ context.Context, contacts []*entities.Contact) error {
+	stub.upserted = contacts
+	return nil
+}
+
+func (stub *contactRepositoryStub) Update(context.Context, *entities.Contact) error {
+	return nil
+}
+
+func (stub *contactRepositoryStub) Load(context.Context, entities.UserID, uuid.UUID) (*entities.Contact, error) {
+	return nil, nil
+}
+
+func (stub *contactRepositoryStub) Index(context.Context, entities.UserID, string, repositories.IndexParams) ([]*entities.Contact, error) {
+	return nil, nil
+}
+
+func (stub *contactRepositoryStub) FetchByPhoneNumbers(_ context.Context, _ entities.UserID, phoneNumbers []string) ([]*entities.Contact, error) {
+	result := make([]*entities.Contact, 0)
+	for _, contact := range stub.contacts {
+		for _, phoneNumber := range phoneNumbers {
+			if contact.PhoneNumber == phoneNumber {
+				result = append(result, contact)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (stub *contactRepositoryStub) Delete(context.Context, entities.UserID, uuid.UUID) error {
+	return nil
+}
+
+func (stub *contactRepositoryStub) DeleteAllForUser(context.Context, entities.UserID) error {
+	return nil
+}
+
+func newContactServiceForTest(repository repositories.ContactRepository) *ContactService {
+	logger := &noopLogger{}
+	return NewContactService(logger, telemetry.NewOtelLogger("test", logger), repository)
+}
+
+func TestContactServiceImportKeepsLastDuplicate(t *testing.T) {
+	repository := &contactRepositoryStub{}
+	service := newContactServiceForTest(repository)
+
+	count, err := service.Import(context.Background(), "user-id", []*ContactUpsertParams{
+		{UserID: "user-id", PhoneNumber: "+18005550100", Name: "Jane"},
+		{UserID: "user-id", PhoneNumber: "+18005550199", Name: "John"},
+		{UserID: "user-id", PhoneNumber: "+18005550100", Name: "Jane Doe"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, repository.upserted, 2)
+	assert.Equal(t, "Jane Doe", repository.upserted[0].Name)
+	assert.Equal(t, "John", repository.upserted[1].Name)
+	assert.NotNil(t, repository.upserted[0].Tags)
+	assert.NotNil(t, repository.upserted[0].Attributes)
+}
+
+func TestContactServiceImportRecordsTheActingMember(t *testing.T) {
+	repository := &contactRepositoryStub{}
+	service := newContactServiceForTest(repository)
+	actorID := entities.UserID("member-id")
+
+	_, err := service.Import(context.Background(), "owner-id", []*ContactUpsertParams{
+		{UserID: "owner-id", PhoneNumber: "+18005550100", Name: "Jane", ActorID: &actorID},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, repository.upserted, 1)
+	assert.Equal(t, &actorID, repository.upserted[0].ActorID)
+}
+
+func TestContactServiceNamesSkipsContactsWithoutName(t *testing.T) {
+	repository := &contactRepositoryStub{contacts: []*entities.Contact{
+		{PhoneNumber: "+18005550100", Name: "Jane Doe"},
+		{PhoneNumber: "+18005550199"},
+	}}
+	service := newContactServiceForTest(repository)
+
+	names, err := service.Names(context.Background(), "user-id", []string{"+18005550100", "+18005550199", "+18005550111"})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"+18005550100": "Jane Doe"}, names)
+}
+
+func TestContactServiceNameReturnsNilForContactsWithoutName(t *testing.T) {
+	repository := &contactRepositoryStub{contacts: []*entities.Contact{
+		{PhoneNumber: "+18005550100", Name: "Jane Doe"},
+		{PhoneNumber: "+18005550199"},
+	}}
+	service := newContactServiceForTest(repository)
+
+	require.NotNil(t, service.Name(context.Background(), "user-id", "+18005550100"))
+	assert.Equal(t, "Jane Doe", *service.Name(context.Background(), "user-id", "+18005550100"))
+	assert.Nil(t, service.Name(context.Background(), "user-id", "+18005550199"))
+	assert.Nil(t, service.Name(context.Background(), "user-id", "+18005550111"))
+}
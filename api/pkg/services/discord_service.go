@@ -287,7 +287,7 @@ func (service *DiscordService) createDiscordMessage(ctxLogger telemetry.Logger,
 				"fields": []fiber.Map{
 					{
 						"name":   "From:",
-						"value":  service.getFormattedNumber(ctxLogger, payload.Contact),
+						"value":  service.getFormattedContact(ctxLogger, payload.Contact, payload.ContactName),
 						"inline": true,
 					},
 					{
@@ -20,6 +20,7 @@ type EmailNotificationService struct {
 	logger         telemetry.Logger
 	tracer         telemetry.Tracer
 	userRepository repositories.UserRepository
+	contactService *ContactService
 	factory        emails.NotificationEmailFactory
 	mailer         emails.Mailer
 	cache          cache.Cache
@@ -35,6 +36,7 @@ func NewEmailNotificationService(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	userRepository repositories.UserRepository,
+	contactService *ContactService,
 	factory emails.NotificationEmailFactory,
 	mailer emails.Mailer,
 	cache cache.Cache,
@@ -43,6 +45,7 @@ func NewEmailNotificationService(
 		logger:         logger.WithService(fmt.Sprintf("%T", &EmailNotificationService{})),
 		tracer:         tracer,
 		userRepository: userRepository,
+		contactService: contactService,
 		factory:        factory,
 		mailer:         mailer,
 		cache:          cache,
@@ -74,7 +77,7 @@ func (service *EmailNotificationService) NotifyMessageExpired(ctx context.Contex
 		return nil
 	}
 
-	email, err := service.factory.MessageExpired(user, payload)
+	email, err := service.factory.MessageExpired(user, payload, service.contactService.Name(ctx, payload.UserID, payload.Contact))
 	if err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create email for user with ID [%s] and for expired message with ID [%s]", payload.UserID, payload.MessageID))
 	}
@@ -109,7 +112,7 @@ func (service *EmailNotificationService) NotifyMessageFailed(ctx context.Context
 		return nil
 	}
 
-	email, err := service.factory.MessageFailed(user, payload)
+	email, err := service.factory.MessageFailed(user, payload, service.contactService.Name(ctx, payload.UserID, payload.Contact))
 	if err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create email for user with ID [%s] for [%s] message with ID [%s]", payload.UserID, events.EventTypeMessageSendFailed, payload.ID))
 	}
@@ -14,10 +14,11 @@ import (
 )
 
 type emulatorPushQueue struct {
-	config PushQueueConfig
-	client *http.Client
-	logger telemetry.Logger
-	tracer telemetry.Tracer
+	config  PushQueueConfig
+	client  *http.Client
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	metrics *MetricsService
 }
 
 // EmulatorPushQueue creates a new googlePushQueue
@@ -26,12 +27,14 @@ func EmulatorPushQueue(
 	tracer telemetry.Tracer,
 	client *http.Client,
 	config PushQueueConfig,
+	metrics *MetricsService,
 ) PushQueue {
 	return &emulatorPushQueue{
-		tracer: tracer,
-		logger: logger.WithService(fmt.Sprintf("%T", emulatorPushQueue{})),
-		client: client,
-		config: config,
+		tracer:  tracer,
+		logger:  logger.WithService(fmt.Sprintf("%T", emulatorPushQueue{})),
+		client:  client,
+		config:  config,
+		metrics: metrics,
 	}
 }
 
@@ -40,6 +43,8 @@ func (queue *emulatorPushQueue) Enqueue(ctx context.Context, task *PushQueueTask
 	ctx, span, ctxLogger := queue.tracer.StartWithLogger(ctx, queue.logger)
 	defer span.End()
 
+	defer queue.metrics.RecordEnqueue(ctx, queue.config.Name, time.Now(), nil)
+
 	queueID = uuid.New().String()
 
 	time.AfterFunc(timeout, queue.push(*task, queueID))
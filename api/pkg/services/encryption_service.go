@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+)
+
+const (
+	// encryptionKeyBatchSize is the number of entities.DataKey which are loaded at the same time
+	encryptionKeyBatchSize = 100
+
+	// encryptionContentBatchSize is the number of messages or threads which are re-encrypted at the same time
+	encryptionContentBatchSize = 500
+
+	// encryptionReEncryptionGracePeriod is how long to wait after a data key is created before the content is re-encrypted with it.
+	// It is longer than the time the active data key is cached so that all the servers encrypt new content with the key.
+	encryptionReEncryptionGracePeriod = time.Hour
+)
+
+// EncryptionService manages the data keys which encrypt the message content at rest
+type EncryptionService struct {
+	service
+	logger                  telemetry.Logger
+	tracer                  telemetry.Tracer
+	cipher                  *repositories.EnvelopeContentCipher
+	dataKeyRepository       repositories.DataKeyRepository
+	messageRepository       repositories.MessageRepository
+	messageThreadRepository repositories.MessageThreadRepository
+	rotationPeriod          time.Duration
+}
+
+// NewEncryptionService creates a new EncryptionService
+func NewEncryptionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	cipher *repositories.EnvelopeContentCipher,
+	dataKeyRepository repositories.DataKeyRepository,
+	messageRepository repositories.MessageRepository,
+	messageThreadRepository repositories.MessageThreadRepository,
+	rotationPeriod time.Duration,
+) (s *EncryptionService) {
+	return &EncryptionService{
+		logger:                  logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:                  tracer,
+		cipher:                  cipher,
+		dataKeyRepository:       dataKeyRepository,
+		messageRepository:       messageRepository,
+		messageThreadRepository: messageThreadRepository,
+		rotationPeriod:          rotationPeriod,
+	}
+}
+
+// Rotate wraps the data keys with the current master key, creates new data keys for the users whose key is older than
+// the rotation period and re-encrypts the message content of the users with their new data key.
+func (service *EncryptionService) Rotate(ctx context.Context) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.rewrapKeys(ctx, ctxLogger); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot wrap data keys with master key [%s]", service.cipher.MasterKeyID()))
+	}
+
+	if err := service.createMissingKeys(ctx); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create data keys for users without a key"))
+	}
+
+	if err := service.rotateKeys(ctx, ctxLogger, time.Now().UTC()); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot rotate data keys older than [%s]", service.rotationPeriod))
+	}
+
+	if err := service.reEncrypt(ctx, ctxLogger, time.Now().UTC()); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot re-encrypt message content"))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes the data keys of a user so that any copy of the encrypted content cannot be decrypted
+func (service *EncryptionService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.dataKeyRepository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [entities.DataKey] for user [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.DataKey] for user [%s]", userID))
+	return nil
+}
+
+// rewrapKeys wraps the data keys which were wrapped by a previous master key with the current master key
+func (service *EncryptionService) rewrapKeys(ctx context.Context, ctxLogger telemetry.Logger) error {
+	for {
+		keys, err := service.dataKeyRepository.FetchByOtherMasterKey(ctx, service.cipher.MasterKeyID(), encryptionKeyBatchSize)
+		if err != nil {
+			return stacktrace.Propagatef(err, "cannot fetch data keys which are not wrapped by master key [%s]", service.cipher.MasterKeyID())
+		}
+
+		for _, key := range keys {
+			if err = service.cipher.Rewrap(ctx, key); err != nil {
+				return stacktrace.Propagatef(err, "cannot wrap data key [%s] of user [%s] with master key [%s]", key.ID, key.UserID, service.cipher.MasterKeyID())
+			}
+		}
+
+		if len(keys) > 0 {
+			ctxLogger.Info(fmt.Sprintf("wrapped [%d] data keys with master key [%s]", len(keys), service.cipher.MasterKeyID()))
+		}
+
+		if len(keys) < encryptionKeyBatchSize {
+			return nil
+		}
+	}
+}
+
+// createMissingKeys creates data keys for the users without a key so that their existing content is encrypted
+func (service *EncryptionService) createMissingKeys(ctx context.Context) error {
+	for {
+		userIDs, err := service.dataKeyRepository.FetchUsersWithoutKey(ctx, encryptionKeyBatchSize)
+		if err != nil {
+			return stacktrace.Propagatef(err, "cannot fetch users without a data key")
+		}
+
+		for _, userID := range userIDs {
+			if _, err = service.cipher.CreateDataKey(ctx, userID); err != nil {
+				return stacktrace.Propagatef(err, "cannot create data key for user [%s]", userID)
+			}
+		}
+
+		if len(userIDs) < encryptionKeyBatchSize {
+			return nil
+		}
+	}
+}
+
+// rotateKeys creates a new data key for the users whose active key is older than the rotation period and retires their other keys
+func (service *EncryptionService) rotateKeys(ctx context.Context, ctxLogger telemetry.Logger, now time.Time) error {
+	for {
+		keys, err := service.dataKeyRepository.FetchRotationDue(ctx, now.Add(-service.rotationPeriod), encryptionKeyBatchSize)
+		if err != nil {
+			return stacktrace.Propagatef(err, "cannot fetch data keys which are due for rotation")
+		}
+
+		rotated := make(map[entities.UserID]bool)
+		for _, key := range keys {
+			if rotated[key.UserID] {
+				continue
+			}
+
+			newKey, err := service.cipher.CreateDataKey(ctx, key.UserID)
+			if err != nil {
+				return stacktrace.Propagatef(err, "cannot create data key to rotate key [%s] of user [%s]", key.ID, key.UserID)
+			}
+
+			if err = service.dataKeyRepository.Retire(ctx, key.UserID, newKey.ID, now); err != nil {
+				return stacktrace.Propagatef(err, "cannot retire data keys of user [%s] except [%s]", key.UserID, newKey.ID)
+			}
+
+			rotated[key.UserID] = true
+			ctxLogger.Info(fmt.Sprintf("rotated data key [%s] of user [%s] to [%s]", key.ID, key.UserID, newKey.ID))
+		}
+
+		if len(keys) < encryptionKeyBatchSize {
+			return nil
+		}
+	}
+}
+
+// reEncrypt encrypts the content of the users with their active data key and deletes their retired keys
+func (service *EncryptionService) reEncrypt(ctx context.Context, ctxLogger telemetry.Logger, now time.Time) error {
+	for {
+		keys, err := service.dataKeyRepository.FetchReEncryptionDue(ctx, now.Add(-encryptionReEncryptionGracePeriod), encryptionKeyBatchSize)
+		if err != nil {
+			return stacktrace.Propagatef(err, "cannot fetch data keys which are due for re-encryption")
+		}
+
+		failed := 0
+		for _, key := range keys {
+			if err = service.reEncryptUser(ctx, key, now); err != nil {
+				// the key is fetched again on the next run so that the content is re-encrypted when the error is fixed
+				failed++
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot re-encrypt content of user [%s] with data key [%s]", key.UserID, key.ID))
+			}
+		}
+
+		if len(keys) < encryptionKeyBatchSize || failed == len(keys) {
+			return nil
+		}
+	}
+}
+
+func (service *EncryptionService) reEncryptUser(ctx context.Context, key *entities.DataKey, now time.Time) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	messages, err := service.reEncryptInBatches(func() (int, error) {
+		return service.messageRepository.ReEncrypt(ctx, key.UserID, encryptionContentBatchSize)
+	})
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot re-encrypt messages of user [%s]", key.UserID))
+	}
+
+	threads, err := service.reEncryptInBatches(func() (int, error) {
+		return service.messageThreadRepository.ReEncrypt(ctx, key.UserID, encryptionContentBatchSize)
+	})
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot re-encrypt message threads of user [%s]", key.UserID))
+	}
+
+	key.ReEncryptedAt = &now
+	key.UpdatedAt = time.Now().UTC()
+	if err = service.dataKeyRepository.Update(ctx, key); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update data key [%s] of user [%s]", key.ID, key.UserID))
+	}
+
+	// the content which was encrypted with the keys created before the active key is now encrypted with the active key
+	if err = service.dataKeyRepository.DeleteRetired(ctx, key.UserID, key.CreatedAt); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete retired data keys of user [%s]", key.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("re-encrypted [%d] messages and [%d] threads of user [%s] with data key [%s]", messages, threads, key.UserID, key.ID))
+	return nil
+}
+
+func (service *EncryptionService) reEncryptInBatches(reEncryptBatch func() (int, error)) (int, error) {
+	total := 0
+	for {
+		count, err := reEncryptBatch()
+		if err != nil {
+			return total, err
+		}
+
+		total += count
+		if count < encryptionContentBatchSize {
+			return total, nil
+		}
+	}
+}
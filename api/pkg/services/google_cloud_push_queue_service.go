@@ -20,6 +20,7 @@ type googlePushQueue struct {
 	logger      telemetry.Logger
 	tracer      telemetry.Tracer
 	client      *cloudtasks.Client
+	metrics     *MetricsService
 }
 
 // NewGooglePushQueue creates a new googlePushQueue
@@ -28,17 +29,22 @@ func NewGooglePushQueue(
 	tracer telemetry.Tracer,
 	client *cloudtasks.Client,
 	queueConfig PushQueueConfig,
+	metrics *MetricsService,
 ) PushQueue {
 	return &googlePushQueue{
 		client:      client,
 		tracer:      tracer,
 		logger:      logger,
 		queueConfig: queueConfig,
+		metrics:     metrics,
 	}
 }
 
 // Enqueue a task to the queue
 func (queue *googlePushQueue) Enqueue(ctx context.Context, task *PushQueueTask, timeout time.Duration) (queueID string, err error) {
+	start := time.Now()
+	defer func() { queue.metrics.RecordEnqueue(ctx, queue.queueConfig.Name, start, err) }()
+
 	err = retry.New(retry.Attempts(3)).Do(func() error {
 		queueID, err = queue.enqueueImpl(ctx, task, timeout)
 		return err
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/cache"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+)
+
+// Job is a task which runs periodically through the push queue
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// JobService runs periodic jobs by dispatching an events.JobRun event which schedules the next run of the job.
+// Every instance schedules the jobs when it starts, the cache makes sure that a job runs only once in each interval.
+type JobService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	cache      cache.Cache
+	dispatcher *EventDispatcher
+	jobs       map[string]*Job
+}
+
+// NewJobService creates a new JobService
+func NewJobService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	cache cache.Cache,
+	dispatcher *EventDispatcher,
+	jobs ...*Job,
+) (s *JobService) {
+	s = &JobService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		cache:      cache,
+		dispatcher: dispatcher,
+		jobs:       make(map[string]*Job, len(jobs)),
+	}
+
+	for _, job := range jobs {
+		s.jobs[job.Name] = job
+	}
+	return s
+}
+
+// Start schedules the next run of every job
+func (service *JobService) Start(ctx context.Context, source string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	for _, job := range service.jobs {
+		if err := service.schedule(ctx, source, job, time.Now().UTC().Truncate(job.Interval).Add(job.Interval)); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot start job [%s]", job.Name))
+		}
+	}
+
+	return nil
+}
+
+// Run a job and schedule its next run, the run is skipped when the job already ran for the scheduled time
+func (service *JobService) Run(ctx context.Context, source string, payload *events.JobRunPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	job, ok := service.jobs[payload.Name]
+	if !ok {
+		ctxLogger.Info(fmt.Sprintf("job [%s] is not registered", payload.Name))
+		return nil
+	}
+
+	scheduledAt := payload.ScheduledAt.UTC().Truncate(job.Interval)
+	locked, err := service.cache.SetNX(ctx, fmt.Sprintf("job.%s.%d", job.Name, scheduledAt.Unix()), scheduledAt.String(), job.Interval)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot lock job [%s] scheduled at [%s]", job.Name, scheduledAt))
+	}
+
+	if !locked {
+		ctxLogger.Info(fmt.Sprintf("job [%s] scheduled at [%s] has already run", job.Name, scheduledAt))
+		return nil
+	}
+
+	if err = service.schedule(ctx, source, job, scheduledAt.Add(job.Interval)); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot schedule the next run of job [%s]", job.Name))
+	}
+
+	if err = job.Run(ctx); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot run job [%s] scheduled at [%s]", job.Name, scheduledAt))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("job [%s] scheduled at [%s] has run", job.Name, scheduledAt))
+	return nil
+}
+
+func (service *JobService) schedule(ctx context.Context, source string, job *Job, scheduledAt time.Time) error {
+	event, err := service.createEvent(events.JobRun, source, &events.JobRunPayload{Name: job.Name, ScheduledAt: scheduledAt})
+	if err != nil {
+		return stacktrace.Propagatef(err, "cannot create [%s] event for job [%s]", events.JobRun, job.Name)
+	}
+
+	timeout := time.Until(scheduledAt)
+	if timeout <= 0 {
+		timeout = time.Nanosecond
+	}
+
+	if _, err = service.dispatcher.DispatchWithTimeout(ctx, event, timeout); err != nil {
+		return stacktrace.Propagatef(err, "cannot dispatch [%s] event for job [%s]", event.Type(), job.Name)
+	}
+	return nil
+}
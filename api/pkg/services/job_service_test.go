@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newJobServiceForTest(queue *pushQueueStub, job *Job) *JobService {
+	logger := &noopLogger{}
+	return NewJobService(logger, telemetry.NewOtelLogger("test", logger), &autoReplyCacheStub{items: map[string]time.Duration{}}, newEventDispatcherForTest(queue), job)
+}
+
+func TestJobServiceRun_SchedulesTheNextRunAndRunsTheJobOnce(t *testing.T) {
+	runs := 0
+	job := &Job{Name: "test", Interval: time.Hour, Run: func(_ context.Context) error {
+		runs++
+		return nil
+	}}
+
+	queue := &pushQueueStub{}
+	service := newJobServiceForTest(queue, job)
+	scheduledAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, service.Run(context.Background(), "/v1/jobs", &events.JobRunPayload{Name: job.Name, ScheduledAt: scheduledAt}))
+	require.NoError(t, service.Run(context.Background(), "/v1/jobs", &events.JobRunPayload{Name: job.Name, ScheduledAt: scheduledAt.Add(time.Minute)}))
+
+	assert.Equal(t, 1, runs)
+	require.Len(t, queue.events, 1)
+
+	payload := new(events.JobRunPayload)
+	require.NoError(t, queue.events[0].DataAs(payload))
+	assert.Equal(t, &events.JobRunPayload{Name: job.Name, ScheduledAt: scheduledAt.Add(time.Hour)}, payload)
+}
+
+func TestJobServiceStart_SchedulesEveryJob(t *testing.T) {
+	queue := &pushQueueStub{}
+	service := newJobServiceForTest(queue, &Job{Name: "test", Interval: time.Hour, Run: func(_ context.Context) error { return nil }})
+
+	require.NoError(t, service.Start(context.Background(), "/v1/jobs"))
+
+	assert.Equal(t, []string{events.JobRun}, queue.types())
+}
@@ -0,0 +1,354 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/emails"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// MessageExportService creates files with the message history of a user in the background
+type MessageExportService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.MessageExportRepository
+	messageRepository repositories.MessageRepository
+	storage           repositories.AttachmentRepository
+	dispatcher        *EventDispatcher
+	userService       *UserService
+	mailer            emails.Mailer
+	emailFactory      emails.UserEmailFactory
+	apiBaseURL        string
+}
+
+// NewMessageExportService creates a new MessageExportService
+func NewMessageExportService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.MessageExportRepository,
+	messageRepository repositories.MessageRepository,
+	storage repositories.AttachmentRepository,
+	dispatcher *EventDispatcher,
+	userService *UserService,
+	mailer emails.Mailer,
+	emailFactory emails.UserEmailFactory,
+	apiBaseURL string,
+) (s *MessageExportService) {
+	return &MessageExportService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		repository:        repository,
+		messageRepository: messageRepository,
+		storage:           storage,
+		dispatcher:        dispatcher,
+		userService:       userService,
+		mailer:            mailer,
+		emailFactory:      emailFactory,
+		apiBaseURL:        apiBaseURL,
+	}
+}
+
+// MessageExportStoreParams are parameters for creating an entities.MessageExport
+type MessageExportStoreParams struct {
+	UserID   entities.UserID
+	Format   entities.MessageExportFormat
+	Owners   []string
+	Contacts []string
+	Types    []string
+	Statuses []string
+	Since    *time.Time
+	Until    *time.Time
+	Source   string
+}
+
+// Store creates a new entities.MessageExport.
+// Exports with less than one page of messages are completed immediately, larger exports are processed in the background.
+func (service *MessageExportService) Store(ctx context.Context, params *MessageExportStoreParams) (*entities.MessageExport, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	exportID := uuid.New()
+	export := &entities.MessageExport{
+		ID:        exportID,
+		UserID:    params.UserID,
+		Format:    params.Format,
+		Status:    entities.MessageExportStatusPending,
+		Owners:    pq.StringArray(params.Owners),
+		Contacts:  pq.StringArray(params.Contacts),
+		Types:     pq.StringArray(params.Types),
+		Statuses:  pq.StringArray(params.Statuses),
+		Since:     params.Since,
+		Until:     params.Until,
+		Path:      fmt.Sprintf("message-exports/%s/%s.%s", params.UserID, exportID, params.Format),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := service.repository.Store(ctx, export); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save message export [%s] for user [%s]", export.ID, export.UserID))
+	}
+
+	messages, err := service.messageRepository.Search(ctx, export.UserID, service.filters(export), repositories.IndexParams{Limit: messageExportPageSize})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot count the messages of export [%s]", export.ID))
+	}
+
+	if len(messages) < messageExportPageSize {
+		if err = service.export(ctx, ctxLogger, export); err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot export messages of export [%s]", export.ID))
+		}
+		ctxLogger.Info(fmt.Sprintf("message export [%s] finished with status [%s] and [%d] messages for user [%s]", export.ID, export.Status, export.MessageCount, export.UserID))
+		return export, nil
+	}
+
+	event, err := service.createEvent(events.MessageExportCreated, params.Source, &events.MessageExportCreatedPayload{
+		MessageExportID: export.ID,
+		UserID:          export.UserID,
+		Source:          params.Source,
+		Timestamp:       export.CreatedAt,
+	})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for message export [%s]", events.MessageExportCreated, export.ID))
+	}
+
+	if err = service.dispatcher.Dispatch(ctx, event); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch [%s] event for message export [%s]", event.Type(), export.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message export saved with id [%s] to be processed in the background for user [%s]", export.ID, export.UserID))
+	return export, nil
+}
+
+// Load an entities.MessageExport of a user
+func (service *MessageExportService) Load(ctx context.Context, userID entities.UserID, exportID uuid.UUID) (*entities.MessageExport, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	export, err := service.repository.Load(ctx, userID, exportID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load message export [%s] for user [%s]", exportID, userID))
+	}
+
+	return export, nil
+}
+
+// Download opens the file of an entities.MessageExport when the token of the download link is valid and has not expired.
+// The reader of the file must be closed by the caller.
+func (service *MessageExportService) Download(ctx context.Context, exportID uuid.UUID, token string) (*entities.MessageExport, io.ReadCloser, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	export, err := service.repository.LoadByID(ctx, exportID)
+	if err != nil {
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load message export [%s]", exportID))
+	}
+
+	if subtle.ConstantTimeCompare([]byte(export.Token), []byte(token)) != 1 || !export.IsDownloadable(time.Now().UTC()) {
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "the download link of message export [%s] is invalid or has expired", exportID))
+	}
+
+	content, err := service.storage.DownloadStream(ctx, export.Path)
+	if err != nil {
+		return nil, nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot download file [%s] of message export [%s]", export.Path, exportID))
+	}
+
+	return export, content, nil
+}
+
+// DeleteAllForUser deletes all entities.MessageExport for a user
+func (service *MessageExportService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete message exports for user [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all message exports for user [%s]", userID))
+	return nil
+}
+
+// Process creates the file of a large entities.MessageExport and emails the download link to the user
+func (service *MessageExportService) Process(ctx context.Context, payload *events.MessageExportCreatedPayload) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	export, err := service.repository.Load(ctx, payload.UserID, payload.MessageExportID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load message export [%s] for user [%s]", payload.MessageExportID, payload.UserID))
+	}
+
+	// the event can be delivered more than once and the user must not get the email twice.
+	// An export which is still processing was interrupted and the file is created again.
+	if !export.IsPending() && !export.IsProcessing() {
+		ctxLogger.Info(fmt.Sprintf("message export [%s] has status [%s] and will not be processed", export.ID, export.Status))
+		return nil
+	}
+
+	if err = service.export(ctx, ctxLogger, export); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot export messages of export [%s]", export.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message export [%s] finished with status [%s] and [%d] messages for user [%s]", export.ID, export.Status, export.MessageCount, export.UserID))
+	if export.Status != entities.MessageExportStatusCompleted {
+		return nil
+	}
+
+	user, err := service.userService.GetByID(ctx, export.UserID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load user [%s] of message export [%s]", export.UserID, export.ID))
+	}
+
+	email, err := service.emailFactory.MessageExportCompleted(user, export)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create message export email for user [%s]", export.UserID))
+	}
+
+	if err = service.mailer.Send(ctx, email); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot send message export email to user [%s]", export.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message export email sent successfully to [%s] with user ID [%s]", user.Email, user.ID))
+	return nil
+}
+
+// export writes the messages of an entities.MessageExport to a file and marks the export as completed or failed
+func (service *MessageExportService) export(ctx context.Context, ctxLogger telemetry.Logger, export *entities.MessageExport) error {
+	if err := service.repository.Update(ctx, export.Processing(time.Now().UTC())); err != nil {
+		return stacktrace.Propagatef(err, "cannot mark message export [%s] as processing", export.ID)
+	}
+
+	if failure := service.writeFile(ctx, ctxLogger, export); failure != nil {
+		export.Failed(time.Now().UTC(), *failure)
+	} else {
+		export.Completed(time.Now().UTC(), service.downloadURL(export))
+	}
+
+	if err := service.repository.Update(ctx, export); err != nil {
+		return stacktrace.Propagatef(err, "cannot mark message export [%s] as [%s]", export.ID, export.Status)
+	}
+
+	return nil
+}
+
+// writeFile streams the messages of an entities.MessageExport to the storage without loading the whole file into memory
+func (service *MessageExportService) writeFile(ctx context.Context, ctxLogger telemetry.Logger, export *entities.MessageExport) *string {
+	reader, writer := io.Pipe()
+	failures := make(chan *string, 1)
+	go func() {
+		failure := service.writeMessages(ctx, ctxLogger, export, writer)
+		if failure != nil {
+			_ = writer.CloseWithError(stacktrace.NewErrorf("%s", *failure))
+		} else {
+			_ = writer.Close()
+		}
+		failures <- failure
+	}()
+
+	err := service.storage.UploadStream(ctx, export.Path, reader, export.Format.ContentType())
+
+	// the messages stop being written when the upload fails before the end of the file
+	_ = reader.CloseWithError(err)
+	if failure := <-failures; failure != nil {
+		return failure
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot upload file of message export [%s] to path [%s]", export.ID, export.Path))
+		return service.failure("Cannot save the file. Please create the export again.")
+	}
+
+	token, err := service.generateToken()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot generate download token for message export [%s]", export.ID))
+		return service.failure("Cannot create the download link. Please create the export again.")
+	}
+
+	export.Token = token
+	return nil
+}
+
+// writeMessages writes the messages of an entities.MessageExport to the writer page by page
+func (service *MessageExportService) writeMessages(ctx context.Context, ctxLogger telemetry.Logger, export *entities.MessageExport, writer io.Writer) *string {
+	exporter, err := newMessageExporter(export.Format, writer)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot create exporter for message export [%s]", export.ID))
+		return service.failure(fmt.Sprintf("Cannot export messages with the [%s] format.", export.Format))
+	}
+
+	export.MessageCount = 0
+	filters := service.filters(export)
+	params := repositories.IndexParams{Skip: 0, Limit: messageExportPageSize, SortBy: "created_at"}
+	for {
+		messages, err := service.messageRepository.Search(ctx, export.UserID, filters, params)
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot fetch messages of export [%s] with params [%+#v]", export.ID, params))
+			return service.failure("Cannot fetch the messages. Please create the export again.")
+		}
+
+		if err = exporter.Write(messages); err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot write [%d] messages of export [%s]", len(messages), export.ID))
+			return service.failure("Cannot write the messages to the file. Please create the export again.")
+		}
+
+		export.MessageCount += len(messages)
+		if len(messages) < params.Limit {
+			break
+		}
+		params.Skip += params.Limit
+	}
+
+	if err = exporter.Close(); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot close [%s] file of message export [%s]", export.Format, export.ID))
+		return service.failure("Cannot write the messages to the file. Please create the export again.")
+	}
+
+	return nil
+}
+
+// filters converts the filters of an entities.MessageExport into repositories.MessageSearchFilters
+func (service *MessageExportService) filters(export *entities.MessageExport) repositories.MessageSearchFilters {
+	filters := repositories.MessageSearchFilters{
+		Owners:   export.Owners,
+		Contacts: export.Contacts,
+		Since:    export.Since,
+		Until:    export.Until,
+	}
+	for _, messageType := range export.Types {
+		filters.Types = append(filters.Types, entities.MessageType(messageType))
+	}
+	for _, status := range export.Statuses {
+		filters.Statuses = append(filters.Statuses, entities.MessageStatus(status))
+	}
+	return filters
+}
+
+func (service *MessageExportService) downloadURL(export *entities.MessageExport) string {
+	return fmt.Sprintf("%s/v1/message-exports/%s/download?token=%s", service.apiBaseURL, export.ID, url.QueryEscape(export.Token))
+}
+
+func (service *MessageExportService) generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", stacktrace.Propagatef(err, "cannot generate [%d] random bytes", len(b))
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (service *MessageExportService) failure(message string) *string {
+	return &message
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type messageExportRepositoryStub struct {
+	repositories.MessageExportRepository
+	statuses []entities.MessageExportStatus
+}
+
+func (stub *messageExportRepositoryStub) Update(_ context.Context, export *entities.MessageExport) error {
+	stub.statuses = append(stub.statuses, export.Status)
+	return nil
+}
+
+type messageSearchRepositoryStub struct {
+	repositories.MessageRepository
+	messages []*entities.Message
+}
+
+func (stub *messageSearchRepositoryStub) Search(_ context.Context, _ entities.UserID, _ repositories.MessageSearchFilters, params repositories.IndexParams) ([]*entities.Message, error) {
+	if params.Skip >= len(stub.messages) {
+		return []*entities.Message{}, nil
+	}
+	return stub.messages[params.Skip:min(params.Skip+params.Limit, len(stub.messages))], nil
+}
+
+func TestMessageExportServiceExport_StreamsTheFileOfAnInterruptedExportToTheStorage(t *testing.T) {
+	logger := &noopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	storage := repositories.NewMemoryAttachmentRepository(logger, tracer)
+	repository := &messageExportRepositoryStub{}
+
+	messages := make([]*entities.Message, 0, messageExportPageSize+1)
+	for index := 0; index <= messageExportPageSize; index++ {
+		messages = append(messages, testExportMessage())
+	}
+
+	service := NewMessageExportService(logger, tracer, repository, &messageSearchRepositoryStub{messages: messages}, storage, nil, nil, nil, nil, "https://api.httpsms.com")
+	export := &entities.MessageExport{ID: uuid.New(), UserID: "user-id", Format: entities.MessageExportFormatCSV, Status: entities.MessageExportStatusProcessing, Path: "message-exports/user-id/export.csv"}
+
+	require.NoError(t, service.export(context.Background(), logger, export))
+
+	assert.Equal(t, []entities.MessageExportStatus{entities.MessageExportStatusProcessing, entities.MessageExportStatusCompleted}, repository.statuses)
+	assert.Equal(t, messageExportPageSize+1, export.MessageCount)
+	assert.NotEmpty(t, export.Token)
+
+	content, err := storage.Download(context.Background(), export.Path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"), messageExportPageSize+2)
+}
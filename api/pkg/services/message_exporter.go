@@ -0,0 +1,205 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/xuri/excelize/v2"
+)
+
+// messageExportPageSize is the number of messages which are loaded from the database at the same time during an export
+const messageExportPageSize = 1000
+
+// messageExportHeader contains the columns of an exported message
+var messageExportHeader = []string{
+	"ID",
+	"FromPhoneNumber",
+	"ToPhoneNumber",
+	"Type",
+	"Status",
+	"Content",
+	"Segments",
+	"ScheduledSendTime",
+	"SentAt",
+	"DeliveredAt",
+	"FailedAt",
+	"ExpiredAt",
+	"CanceledAt",
+	"ReceivedAt",
+	"FailureReason",
+	"CreatedAt",
+}
+
+// messageExporter writes messages to a file
+type messageExporter interface {
+	// Write adds rows for the messages to the file
+	Write(messages []*entities.Message) error
+
+	// Close writes the remaining content of the file
+	Close() error
+}
+
+func newMessageExporter(format entities.MessageExportFormat, writer io.Writer) (messageExporter, error) {
+	switch format {
+	case entities.MessageExportFormatCSV:
+		exporter := &csvMessageExporter{writer: csv.NewWriter(writer)}
+		return exporter, exporter.writer.Write(messageExportHeader)
+	case entities.MessageExportFormatNDJSON:
+		return &ndjsonMessageExporter{encoder: json.NewEncoder(writer)}, nil
+	case entities.MessageExportFormatXLSX:
+		return newXlsxMessageExporter(writer)
+	default:
+		return nil, stacktrace.NewErrorf("cannot export messages with format [%s]", format)
+	}
+}
+
+// messageExportNumber matches values like phone numbers which start with a sign but are not formulas
+var messageExportNumber = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?$`)
+
+// messageExportRow converts a message into the columns of messageExportHeader
+func messageExportRow(message *entities.Message) []string {
+	row := []string{
+		message.ID.String(),
+		message.Owner,
+		message.Contact,
+		string(message.Type),
+		string(message.Status),
+		message.Content,
+		fmt.Sprintf("%d", message.Segments),
+		messageExportTime(message.ScheduledSendTime),
+		messageExportTime(message.SentAt),
+		messageExportTime(message.DeliveredAt),
+		messageExportTime(message.FailedAt),
+		messageExportTime(message.ExpiredAt),
+		messageExportTime(message.CanceledAt),
+		messageExportTime(message.ReceivedAt),
+		messageExportString(message.FailureReason),
+		message.CreatedAt.UTC().Format(time.RFC3339),
+	}
+
+	for index, value := range row {
+		row[index] = messageExportCell(value)
+	}
+	return row
+}
+
+// messageExportCell prefixes values which spreadsheet applications evaluate as formulas with a quote
+func messageExportCell(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) || messageExportNumber.MatchString(value) {
+		return value
+	}
+	return "'" + value
+}
+
+func messageExportTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+func messageExportString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+type csvMessageExporter struct {
+	writer *csv.Writer
+}
+
+func (exporter *csvMessageExporter) Write(messages []*entities.Message) error {
+	for _, message := range messages {
+		if err := exporter.writer.Write(messageExportRow(message)); err != nil {
+			return stacktrace.Propagatef(err, "cannot write message [%s] to CSV file", message.ID)
+		}
+	}
+
+	// the rows are flushed after each page so that the file is streamed to the writer
+	exporter.writer.Flush()
+	return exporter.writer.Error()
+}
+
+func (exporter *csvMessageExporter) Close() error {
+	exporter.writer.Flush()
+	return exporter.writer.Error()
+}
+
+type ndjsonMessageExporter struct {
+	encoder *json.Encoder
+}
+
+func (exporter *ndjsonMessageExporter) Write(messages []*entities.Message) error {
+	for _, message := range messages {
+		if err := exporter.encoder.Encode(message); err != nil {
+			return stacktrace.Propagatef(err, "cannot encode message [%s] as JSON", message.ID)
+		}
+	}
+	return nil
+}
+
+func (exporter *ndjsonMessageExporter) Close() error {
+	return nil
+}
+
+type xlsxMessageExporter struct {
+	writer io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXlsxMessageExporter(writer io.Writer) (*xlsxMessageExporter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter(file.GetSheetName(0))
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create excel stream writer")
+	}
+
+	exporter := &xlsxMessageExporter{writer: writer, file: file, stream: stream}
+	return exporter, exporter.writeRow(messageExportHeader)
+}
+
+func (exporter *xlsxMessageExporter) Write(messages []*entities.Message) error {
+	for _, message := range messages {
+		if err := exporter.writeRow(messageExportRow(message)); err != nil {
+			return stacktrace.Propagatef(err, "cannot write message [%s] to excel file", message.ID)
+		}
+	}
+	return nil
+}
+
+func (exporter *xlsxMessageExporter) writeRow(values []string) error {
+	exporter.row++
+	cell, err := excelize.CoordinatesToCellName(1, exporter.row)
+	if err != nil {
+		return stacktrace.Propagatef(err, "cannot get cell name for row [%d]", exporter.row)
+	}
+
+	row := make([]any, 0, len(values))
+	for _, value := range values {
+		row = append(row, value)
+	}
+	return exporter.stream.SetRow(cell, row)
+}
+
+func (exporter *xlsxMessageExporter) Close() error {
+	defer func() { _ = exporter.file.Close() }()
+
+	if err := exporter.stream.Flush(); err != nil {
+		return stacktrace.Propagatef(err, "cannot flush excel stream writer")
+	}
+
+	if _, err := exporter.file.WriteTo(exporter.writer); err != nil {
+		return stacktrace.Propagatef(err, "cannot write excel file")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func testExportMessage() *entities.Message {
+	failedAt := time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)
+	reason := "RESULT_ERROR_GENERIC_FAILURE"
+	return &entities.Message{
+		ID:            uuid.MustParse("32343a19-da5e-4b1b-a767-3298a73703cb"),
+		Owner:         "+18005550199",
+		Contact:       "+18005550100",
+		Type:          entities.MessageTypeMobileTerminated,
+		Status:        entities.MessageStatusFailed,
+		Content:       "Hello, world",
+		Segments:      1,
+		FailedAt:      &failedAt,
+		FailureReason: &reason,
+		CreatedAt:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestMessageExporterCSV(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	exporter, err := newMessageExporter(entities.MessageExportFormatCSV, buffer)
+	assert.Nil(t, err)
+
+	assert.Nil(t, exporter.Write([]*entities.Message{testExportMessage()}))
+	assert.Nil(t, exporter.Close())
+
+	assert.Equal(
+		t,
+		"ID,FromPhoneNumber,ToPhoneNumber,Type,Status,Content,Segments,ScheduledSendTime,SentAt,DeliveredAt,FailedAt,ExpiredAt,CanceledAt,ReceivedAt,FailureReason,CreatedAt\n"+
+			"32343a19-da5e-4b1b-a767-3298a73703cb,+18005550199,+18005550100,mobile-terminated,failed,\"Hello, world\",1,,,,2024-01-01T12:05:00Z,,,,RESULT_ERROR_GENERIC_FAILURE,2024-01-01T12:00:00Z\n",
+		buffer.String(),
+	)
+}
+
+func TestMessageExporterEscapesFormulas(t *testing.T) {
+	message := testExportMessage()
+	message.Content = "=HYPERLINK(\"https://example.com\")"
+	reason := "@SUM(A1:A2)"
+	message.FailureReason = &reason
+
+	row := messageExportRow(message)
+
+	assert.Equal(t, "'=HYPERLINK(\"https://example.com\")", row[5])
+	assert.Equal(t, "'@SUM(A1:A2)", row[14])
+	assert.Equal(t, "+18005550199", row[1])
+	assert.Equal(t, "'-2+3", messageExportCell("-2+3"))
+	assert.Equal(t, "-10.5", messageExportCell("-10.5"))
+}
+
+func TestMessageExporterXLSX(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	exporter, err := newMessageExporter(entities.MessageExportFormatXLSX, buffer)
+	assert.Nil(t, err)
+
+	assert.Nil(t, exporter.Write([]*entities.Message{testExportMessage()}))
+	assert.Nil(t, exporter.Close())
+
+	file, err := excelize.OpenReader(buffer)
+	assert.Nil(t, err)
+
+	rows, err := file.GetRows(file.GetSheetName(0))
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, messageExportHeader, rows[0])
+	assert.Equal(t, "+18005550100", rows[1][2])
+	assert.Equal(t, "RESULT_ERROR_GENERIC_FAILURE", rows[1][14])
+}
+
+func TestMessageExporterInvalidFormat(t *testing.T) {
+	_, err := newMessageExporter(entities.MessageExportFormat("pdf"), new(bytes.Buffer))
+	assert.NotNil(t, err)
+}
+
+func TestMessageExporterNDJSON(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	exporter, err := newMessageExporter(entities.MessageExportFormatNDJSON, buffer)
+	assert.Nil(t, err)
+
+	assert.Nil(t, exporter.Write([]*entities.Message{testExportMessage()}))
+	assert.Nil(t, exporter.Write([]*entities.Message{testExportMessage()}))
+	assert.Nil(t, exporter.Close())
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+
+	message := new(entities.Message)
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), message))
+	assert.Equal(t, testExportMessage().ID, message.ID)
+	assert.Equal(t, "Hello, world", message.Content)
+	assert.Equal(t, entities.MessageStatus(entities.MessageStatusFailed), message.Status)
+}
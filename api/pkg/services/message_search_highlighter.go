@@ -0,0 +1,101 @@
+package services
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	// messageHighlightWordsBefore is the number of words in a highlight before the first matching word
+	messageHighlightWordsBefore = 8
+
+	// messageHighlightLength is the maximum number of words in a highlight
+	messageHighlightLength = 30
+)
+
+// messageHighlightWord is the position of a word in the content of a message
+type messageHighlightWord struct {
+	start int
+	end   int
+	match bool
+}
+
+// messageHighlight returns a snippet of the content around the first word which starts with one of the search terms.
+// The content is HTML escaped and the matching words are wrapped in <mark> tags.
+func messageHighlight(content string, terms [][]string) *string {
+	words := messageHighlightWords(content, terms)
+
+	first := -1
+	for index, word := range words {
+		if word.match {
+			first = index
+			break
+		}
+	}
+	if first == -1 {
+		return nil
+	}
+
+	start := max(0, first-messageHighlightWordsBefore)
+	end := min(len(words), start+messageHighlightLength)
+
+	result := strings.Builder{}
+	offset := 0
+	if start > 0 {
+		result.WriteString("…")
+		offset = words[start].start
+	}
+
+	for _, word := range words[start:end] {
+		result.WriteString(html.EscapeString(content[offset:word.start]))
+		if word.match {
+			result.WriteString("<mark>" + html.EscapeString(content[word.start:word.end]) + "</mark>")
+		} else {
+			result.WriteString(html.EscapeString(content[word.start:word.end]))
+		}
+		offset = word.end
+	}
+
+	if end < len(words) {
+		result.WriteString("…")
+	} else {
+		result.WriteString(html.EscapeString(content[offset:]))
+	}
+
+	snippet := result.String()
+	return &snippet
+}
+
+// messageHighlightWords splits the content into words using the same rules as repositories.MessageSearchTerms
+func messageHighlightWords(content string, terms [][]string) []messageHighlightWord {
+	var prefixes []string
+	for _, words := range terms {
+		prefixes = append(prefixes, words...)
+	}
+
+	var words []messageHighlightWord
+	start := -1
+	for index, character := range content + " " {
+		isWord := unicode.IsLetter(character) || unicode.IsDigit(character)
+		if isWord && start == -1 {
+			start = index
+		}
+		if isWord || start == -1 {
+			continue
+		}
+
+		word := strings.ToLower(content[start:index])
+		match := false
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(word, prefix) {
+				match = true
+				break
+			}
+		}
+
+		words = append(words, messageHighlightWord{start: start, end: index, match: match})
+		start = -1
+	}
+	return words
+}
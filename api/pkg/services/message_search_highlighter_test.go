@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageHighlight(t *testing.T) {
+	highlight := messageHighlight("Your package ORD-123456 has been shipped", [][]string{{"ord"}, {"12345"}})
+
+	assert.Equal(t, "Your package <mark>ORD</mark>-<mark>123456</mark> has been shipped", *highlight)
+}
+
+func TestMessageHighlightEscapesHTML(t *testing.T) {
+	highlight := messageHighlight("<script>alert('order')</script>", [][]string{{"order"}})
+
+	assert.Equal(t, "&lt;script&gt;alert(&#39;<mark>order</mark>&#39;)&lt;/script&gt;", *highlight)
+}
+
+func TestMessageHighlightTruncatesLongContent(t *testing.T) {
+	content := "one two three four five six seven eight nine ten eleven twelve match " +
+		"a b c d e f g h i j k l m n o p q r s t u v w x y z"
+
+	highlight := messageHighlight(content, [][]string{{"match"}})
+
+	assert.Equal(t, "…five six seven eight nine ten eleven twelve <mark>match</mark> a b c d e f g h i j k l m n o p q r s t u…", *highlight)
+}
+
+func TestMessageHighlightWithoutMatch(t *testing.T) {
+	assert.Nil(t, messageHighlight("Hello world", [][]string{{"order"}}))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/NdoleStudio/stacktrace"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -31,13 +34,18 @@ type ServiceAttachment struct {
 // MessageService is handles message requests
 type MessageService struct {
 	service
-	logger               telemetry.Logger
-	tracer               telemetry.Tracer
-	eventDispatcher      *EventDispatcher
-	phoneService         *PhoneService
-	repository           repositories.MessageRepository
-	attachmentRepository repositories.AttachmentRepository
-	apiBaseURL           string
+	logger                  telemetry.Logger
+	tracer                  telemetry.Tracer
+	eventDispatcher         *EventDispatcher
+	phoneService            *PhoneService
+	suppressionService      *SuppressionService
+	monitorRepository       repositories.HeartbeatMonitorRepository
+	scheduledMessageService *ScheduledMessageService
+	contactService          *ContactService
+	threadService           *MessageThreadService
+	repository              repositories.MessageRepository
+	attachmentRepository    repositories.AttachmentRepository
+	apiBaseURL              string
 }
 
 // NewMessageService creates a new MessageService
@@ -47,17 +55,27 @@ func NewMessageService(
 	repository repositories.MessageRepository,
 	eventDispatcher *EventDispatcher,
 	phoneService *PhoneService,
+	suppressionService *SuppressionService,
+	monitorRepository repositories.HeartbeatMonitorRepository,
+	scheduledMessageService *ScheduledMessageService,
+	contactService *ContactService,
+	threadService *MessageThreadService,
 	attachmentRepository repositories.AttachmentRepository,
 	apiBaseURL string,
 ) (s *MessageService) {
 	return &MessageService{
-		logger:               logger.WithService(fmt.Sprintf("%T", s)),
-		tracer:               tracer,
-		repository:           repository,
-		phoneService:         phoneService,
-		eventDispatcher:      eventDispatcher,
-		attachmentRepository: attachmentRepository,
-		apiBaseURL:           apiBaseURL,
+		logger:                  logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:                  tracer,
+		repository:              repository,
+		phoneService:            phoneService,
+		suppressionService:      suppressionService,
+		monitorRepository:       monitorRepository,
+		scheduledMessageService: scheduledMessageService,
+		contactService:          contactService,
+		threadService:           threadService,
+		eventDispatcher:         eventDispatcher,
+		attachmentRepository:    attachmentRepository,
+		apiBaseURL:              apiBaseURL,
 	}
 }
 
@@ -133,6 +151,171 @@ func (service *MessageService) GetBulkMessages(ctx context.Context, userID entit
 	return orders, nil
 }
 
+// GetBulkMessage fetches the summary of the messages with a bulk request ID
+func (service *MessageService) GetBulkMessage(ctx context.Context, userID entities.UserID, requestID string) (*entities.BulkMessage, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	orders, err := service.repository.GetBulkMessagesByRequestIDs(ctx, userID, []string{requestID})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch bulk message with request ID [%s] for user [%s]", requestID, userID))
+	}
+
+	if len(orders) == 0 {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "bulk message with request ID [%s] does not exist for user [%s]", requestID, userID))
+	}
+
+	return orders[0], nil
+}
+
+// ExportBulkMessages writes every message with a bulk request ID to a CSV or Excel file
+func (service *MessageService) ExportBulkMessages(ctx context.Context, writer io.Writer, userID entities.UserID, requestID string, format entities.MessageExportFormat) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	exporter, err := newMessageExporter(format, writer)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create exporter for bulk message [%s]", requestID))
+	}
+
+	count := 0
+	params := repositories.IndexParams{Skip: 0, Limit: messageExportPageSize}
+	for {
+		messages, err := service.repository.IndexByRequestID(ctx, userID, requestID, params)
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch messages with request ID [%s] and params [%+#v]", requestID, params))
+		}
+
+		if err = exporter.Write(messages); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot export [%d] messages with request ID [%s]", len(messages), requestID))
+		}
+
+		count += len(messages)
+		if len(messages) < params.Limit {
+			break
+		}
+		params.Skip += params.Limit
+	}
+
+	if err = exporter.Close(); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot close [%s] export of bulk message [%s]", format, requestID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("exported [%d] messages with request ID [%s] as [%s] for user [%s]", count, requestID, format, userID))
+	return nil
+}
+
+// messageImportBatchSize is the number of imported messages which are stored at the same time
+const messageImportBatchSize = 500
+
+// messageImportNamespace is used to generate the same ID when a message is imported more than once
+var messageImportNamespace = uuid.MustParse("5c6f1a0e-8f0b-4d47-9a4e-2f3d9c1b7e21")
+
+// MessageImportParams are parameters for importing a historical entities.Message
+type MessageImportParams struct {
+	Owner     string
+	Contact   string
+	Content   string
+	Type      entities.MessageType
+	Status    entities.MessageStatus
+	Timestamp time.Time
+}
+
+// Import stores historical messages and rebuilds the threads of the imported conversations.
+// Messages which have already been imported are skipped. No events are dispatched for imported messages so they
+// are not counted for billing and are not forwarded to webhooks. It returns the number of messages which were stored.
+func (service *MessageService) Import(ctx context.Context, userID entities.UserID, params []*MessageImportParams) (int, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	ids := map[uuid.UUID]bool{}
+	var threads []*MessageImportParams
+	threadKeys := map[string]bool{}
+
+	messages := make([]*entities.Message, 0, len(params))
+	for _, param := range params {
+		message := service.importMessage(userID, param)
+		if ids[message.ID] {
+			continue
+		}
+		ids[message.ID] = true
+		messages = append(messages, message)
+
+		if key := param.Owner + "|" + param.Contact; !threadKeys[key] {
+			threadKeys[key] = true
+			threads = append(threads, param)
+		}
+	}
+
+	count := 0
+	for _, batch := range slices.Collect(slices.Chunk(messages, messageImportBatchSize)) {
+		stored, err := service.repository.StoreBatch(ctx, batch)
+		if err != nil {
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot import [%d] messages for user [%s]", len(batch), userID))
+		}
+		count += stored
+	}
+
+	// the thread is updated with the last message of the conversation which can be an existing message
+	for _, thread := range threads {
+		message, err := service.repository.LastMessage(ctx, userID, thread.Owner, thread.Contact)
+		if err != nil {
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load last message with owner [%s] and contact [%s] for user [%s]", thread.Owner, thread.Contact, userID))
+		}
+
+		err = service.threadService.UpdateThread(ctx, MessageThreadUpdateParams{
+			Owner:     message.Owner,
+			Status:    message.Status,
+			Contact:   message.Contact,
+			Content:   message.Content,
+			UserID:    message.UserID,
+			MessageID: message.ID,
+			Timestamp: message.OrderTimestamp,
+		})
+		if err != nil {
+			return count, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update thread with owner [%s] and contact [%s] for user [%s]", thread.Owner, thread.Contact, userID))
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("imported [%d] out of [%d] messages in [%d] threads for user [%s]", count, len(params), len(threads), userID))
+	return count, nil
+}
+
+func (service *MessageService) importMessage(userID entities.UserID, params *MessageImportParams) *entities.Message {
+	segments := entities.CalculateMessageSegments(params.Content)
+	message := &entities.Message{
+		ID:                uuid.NewSHA1(messageImportNamespace, []byte(fmt.Sprintf("%s|%s|%s|%s|%d|%s", userID, params.Owner, params.Contact, params.Type, params.Timestamp.UnixMilli(), params.Content))),
+		Owner:             params.Owner,
+		UserID:            userID,
+		Contact:           params.Contact,
+		Content:           params.Content,
+		Attachments:       pq.StringArray{},
+		Type:              params.Type,
+		Status:            params.Status,
+		Encoding:          segments.Encoding,
+		Segments:          segments.Segments,
+		RequestReceivedAt: params.Timestamp,
+		CreatedAt:         params.Timestamp,
+		UpdatedAt:         time.Now().UTC(),
+		OrderTimestamp:    params.Timestamp,
+	}
+
+	timestamp := params.Timestamp
+	switch params.Status {
+	case entities.MessageStatusReceived:
+		message.ReceivedAt = &timestamp
+	case entities.MessageStatusFailed:
+		message.FailedAt = &timestamp
+	case entities.MessageStatusDelivered:
+		message.SentAt = &timestamp
+		message.DeliveredAt = &timestamp
+	default:
+		message.SentAt = &timestamp
+	}
+
+	return message
+}
+
 // DeleteMessage deletes a message from the database
 func (service *MessageService) DeleteMessage(ctx context.Context, source string, message *entities.Message) error {
 	ctx, span := service.tracer.Start(ctx)
@@ -181,54 +364,153 @@ func (service *MessageService) DeleteMessage(ctx context.Context, source string,
 	return nil
 }
 
-// DeleteByOwnerAndContact deletes all the messages between an owner and a contact
-func (service *MessageService) DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner, contact string) error {
-	ctx, span := service.tracer.Start(ctx)
+// CancelMessage cancels a message which has not yet been sent by the mobile phone
+func (service *MessageService) CancelMessage(ctx context.Context, source string, message *entities.Message) (*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	ctxLogger := service.tracer.CtxLogger(service.logger, span)
+	if !message.CanBeCanceled() {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("message with ID [%s] has status [%s] and cannot be canceled", message.ID, message.Status))
+	}
 
-	if err := service.repository.DeleteByOwnerAndContact(ctx, userID, owner, contact); err != nil {
-		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "could not all delete messages for user with ID [%s] between owner [%s] and contact [%s] ", userID, owner, contact))
+	if err := service.repository.Update(ctx, message.Canceled(time.Now().UTC())); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update message with ID [%s] as canceled", message.ID))
 	}
 
-	ctxLogger.Info(fmt.Sprintf("deleted all messages for user with ID [%s] between owner [%s] and contact [%s] ", userID, owner, contact))
-	return nil
+	event, err := service.createEvent(events.MessageAPICanceled, source, &events.MessageAPICanceledPayload{
+		MessageID:         message.ID,
+		UserID:            message.UserID,
+		Owner:             message.Owner,
+		RequestID:         message.RequestID,
+		Contact:           message.Contact,
+		Timestamp:         *message.CanceledAt,
+		Content:           message.Content,
+		Encrypted:         message.Encrypted,
+		SIM:               message.SIM,
+		Segments:          message.Segments,
+		RequestReceivedAt: message.RequestReceivedAt,
+	})
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for message with ID [%s]", events.MessageAPICanceled, message.ID))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message [%s] has been canceled for user [%s]", message.ID, message.UserID))
+	return message, nil
 }
 
-// RespondToMissedCall creates an SMS response to a missed phone call on the android phone
-func (service *MessageService) RespondToMissedCall(ctx context.Context, source string, payload *events.MessageCallMissedPayload) error {
+// rescheduleClockSkew is how far in the past a send time which was validated a moment ago can be when the message is rescheduled
+const rescheduleClockSkew = 5 * time.Second
+
+// RescheduleMessage changes the time when a message which has not yet been sent will be sent by the mobile phone
+func (service *MessageService) RescheduleMessage(ctx context.Context, source string, message *entities.Message, sendAt time.Time) (*entities.Message, error) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
-	phone, err := service.phoneService.Load(ctx, payload.UserID, payload.Owner)
-	if err != nil {
-		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot find phone with owner [%s] for user with ID [%s] when handling missed phone call message [%s]", payload.Owner, payload.UserID, payload.MessageID))
+	if !message.CanBeRescheduled() {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("message with ID [%s] has status [%s] and cannot be rescheduled", message.ID, message.Status))
 	}
 
-	if phone.MissedCallAutoReply == nil || strings.TrimSpace(*phone.MissedCallAutoReply) == "" {
-		ctxLogger.Info(fmt.Sprintf("no auto reply set for phone [%s] for message [%s] with user [%s]", payload.Owner, payload.MessageID, payload.UserID))
-		return nil
+	if sendAt.Before(time.Now().UTC().Add(-rescheduleClockSkew)) {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("message with ID [%s] cannot be rescheduled to [%s] which is in the past", message.ID, sendAt))
 	}
 
-	requestID := fmt.Sprintf("missed-call-%s", payload.MessageID)
-	owner, _ := phonenumbers.Parse(payload.Owner, phonenumbers.UNKNOWN_REGION)
-	message, err := service.SendMessage(ctx, MessageSendParams{
-		Owner:             owner,
-		Contact:           payload.Contact,
-		Encrypted:         false,
-		Content:           *phone.MissedCallAutoReply,
-		Source:            source,
-		SendAt:            nil,
-		RequestID:         &requestID,
-		UserID:            payload.UserID,
-		RequestReceivedAt: time.Now().UTC(),
+	if err := service.scheduledMessageService.DeleteByMessageID(ctx, message.UserID, message.ID, events.MessageAPIRescheduled); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete previous reschedules of message with ID [%s]", message.ID))
+	}
+
+	if err := service.repository.Update(ctx, message.SendAtChanged(sendAt.UTC())); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update send time of message with ID [%s]", message.ID))
+	}
+
+	event, err := service.createEvent(events.MessageAPIRescheduled, source, &events.MessageAPIRescheduledPayload{
+		MessageID:         message.ID,
+		UserID:            message.UserID,
+		Owner:             message.Owner,
+		RequestID:         message.RequestID,
+		Contact:           message.Contact,
+		Timestamp:         time.Now().UTC(),
+		ScheduledSendTime: *message.ScheduledSendTime,
+		Content:           message.Content,
+		Encrypted:         message.Encrypted,
+		SIM:               message.SIM,
 	})
 	if err != nil {
-		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot send auto response message for owner [%s] for user with ID [%s] when handling missed phone call message [%s]", payload.Owner, payload.UserID, payload.MessageID))
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for message with ID [%s]", events.MessageAPIRescheduled, message.ID))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.ID))
+	}
+
+	// the phone notification is scheduled when the deferred event is enqueued inside the queue horizon
+	if isBeyondQueueHorizon(*message.ScheduledSendTime) {
+		err = service.scheduledMessageService.Dispatch(ctx, &ScheduledMessageDispatchParams{
+			UserID:    message.UserID,
+			MessageID: message.ID,
+			Event:     event,
+			SendAt:    *message.ScheduledSendTime,
+		})
+		if err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot schedule event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.ID))
+		}
 	}
 
-	ctxLogger.Info(fmt.Sprintf("created response message with ID [%s] for missed call event [%s] for user [%s]", message.ID, payload.MessageID, message.UserID))
+	ctxLogger.Info(fmt.Sprintf("message [%s] has been rescheduled to [%s] for user [%s]", message.ID, message.ScheduledSendTime, message.UserID))
+	return message, nil
+}
+
+// PauseMessages pauses the messages with a request ID which have not yet been sent by the mobile phone
+func (service *MessageService) PauseMessages(ctx context.Context, source string, userID entities.UserID, requestID string) ([]*entities.Message, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	// the statuses are changed in a single query so that the phone cannot pick up any of the messages after the pause
+	messages, err := service.repository.UpdateStatusByRequestID(ctx, userID, requestID, []entities.MessageStatus{entities.MessageStatusPending, entities.MessageStatusScheduled}, entities.MessageStatusPaused)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot pause messages with request ID [%s] for user [%s]", requestID, userID))
+	}
+
+	for _, message := range messages {
+		event, err := service.createEvent(events.MessageAPIPaused, source, &events.MessageAPIPausedPayload{
+			MessageID: message.ID,
+			UserID:    message.UserID,
+			Owner:     message.Owner,
+			RequestID: message.RequestID,
+			Contact:   message.Contact,
+			Timestamp: time.Now().UTC(),
+			Content:   message.Content,
+			Encrypted: message.Encrypted,
+			SIM:       message.SIM,
+		})
+		if err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for message with ID [%s]", events.MessageAPIPaused, message.ID))
+		}
+
+		if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.ID))
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("paused [%d] messages with request ID [%s] for user [%s]", len(messages), requestID, userID))
+	return messages, nil
+}
+
+// DeleteByOwnerAndContact deletes all the messages between an owner and a contact
+func (service *MessageService) DeleteByOwnerAndContact(ctx context.Context, userID entities.UserID, owner, contact string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	ctxLogger := service.tracer.CtxLogger(service.logger, span)
+
+	if err := service.repository.DeleteByOwnerAndContact(ctx, userID, owner, contact); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "could not all delete messages for user with ID [%s] between owner [%s] and contact [%s] ", userID, owner, contact))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all messages for user with ID [%s] between owner [%s] and contact [%s] ", userID, owner, contact))
 	return nil
 }
 
@@ -339,6 +621,7 @@ func (service *MessageService) ReceiveMessage(ctx context.Context, params *Messa
 		Encrypted:   params.Encrypted,
 		Owner:       owner,
 		Contact:     params.Contact,
+		ContactName: service.contactService.Name(ctx, params.UserID, params.Contact),
 		Timestamp:   params.Timestamp,
 		Content:     params.Content,
 		SIM:         params.SIM,
@@ -359,7 +642,16 @@ func (service *MessageService) ReceiveMessage(ctx context.Context, params *Messa
 	}
 	ctxLogger.Info(fmt.Sprintf("event [%s] dispatched successfully", event.ID()))
 
-	return service.storeReceivedMessage(ctx, eventPayload)
+	message, err := service.storeReceivedMessage(ctx, eventPayload)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot store received message with ID [%s]", eventPayload.MessageID))
+	}
+
+	if err = service.suppressionService.HandleKeyword(ctx, message); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot handle opt-out keyword for message [%s]", message.ID))
+	}
+
+	return message, nil
 }
 
 func (service *MessageService) handleMessageSentEvent(ctx context.Context, params MessageStoreEventParams, message *entities.Message) error {
@@ -376,6 +668,8 @@ func (service *MessageService) handleMessageSentEvent(ctx context.Context, param
 		Encrypted: message.Encrypted,
 		Content:   message.Content,
 		SIM:       message.SIM,
+		Encoding:  message.Encoding,
+		Segments:  message.Segments,
 	})
 	if err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create event [%s] for message [%s]", events.EventTypeMessagePhoneSent, message.ID))
@@ -401,6 +695,8 @@ func (service *MessageService) handleMessageDeliveredEvent(ctx context.Context,
 		Contact:   message.Contact,
 		Content:   message.Content,
 		SIM:       message.SIM,
+		Encoding:  message.Encoding,
+		Segments:  message.Segments,
 	})
 	if err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create event [%s] for message [%s]", events.EventTypeMessagePhoneSent, message.ID))
@@ -432,6 +728,8 @@ func (service *MessageService) handleMessageFailedEvent(ctx context.Context, par
 		UserID:       message.UserID,
 		Content:      message.Content,
 		SIM:          message.SIM,
+		Encoding:     message.Encoding,
+		Segments:     message.Segments,
 	})
 	if err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create event [%s] for message [%s]", events.EventTypeMessageSendFailed, message.ID))
@@ -454,6 +752,7 @@ type MessageSendParams struct {
 	SendAt            *time.Time
 	RequestID         *string
 	UserID            entities.UserID
+	ActorID           *entities.UserID
 	RequestReceivedAt time.Time
 	Index             int
 }
@@ -470,6 +769,7 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 	eventPayload := events.MessageAPISentPayload{
 		MessageID:         uuid.New(),
 		UserID:            params.UserID,
+		ActorID:           params.ActorID,
 		Encrypted:         params.Encrypted,
 		MaxSendAttempts:   sendAttempts,
 		RequestID:         params.RequestID,
@@ -483,6 +783,11 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 		SIM:               sim,
 	}
 
+	if !params.Encrypted {
+		segments := entities.CalculateMessageSegments(params.Content)
+		eventPayload.Encoding, eventPayload.Segments = segments.Encoding, segments.Segments
+	}
+
 	event, err := service.createMessageAPISentEvent(params.Source, eventPayload)
 	if err != nil {
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create %T from payload with message id [%s]", event, eventPayload.MessageID))
@@ -495,7 +800,19 @@ func (service *MessageService) SendMessage(ctx context.Context, params MessageSe
 	}
 
 	timeout := service.getSendDelay(ctxLogger, eventPayload, params, messagesPerMinute)
-	if _, err = service.eventDispatcher.DispatchWithTimeout(ctx, event, timeout); err != nil {
+	sendAt := time.Now().UTC().Add(timeout)
+	if params.SendAt != nil {
+		sendAt = *params.SendAt
+	}
+
+	err = service.scheduledMessageService.Dispatch(ctx, &ScheduledMessageDispatchParams{
+		UserID:    params.UserID,
+		MessageID: eventPayload.MessageID,
+		Event:     event,
+		SendAt:    sendAt,
+		Timeout:   timeout,
+	})
+	if err != nil {
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch event type [%s] and id [%s]", event.Type(), event.ID()))
 	}
 
@@ -835,7 +1152,16 @@ func (service *MessageService) HandleMessageExpired(ctx context.Context, params
 
 	ctxLogger.Info(fmt.Sprintf("message with id [%s] has been updated to status [%s]", message.ID, message.Status))
 
-	if !message.CanBeRescheduled() {
+	rerouted, err := service.rerouteExpiredMessage(ctx, params.Source, message)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot reroute expired message with ID [%s] from owner [%s]", message.ID, message.Owner))
+	}
+
+	if rerouted {
+		return nil
+	}
+
+	if !message.CanBeRetried() {
 		return nil
 	}
 
@@ -861,6 +1187,98 @@ func (service *MessageService) HandleMessageExpired(ctx context.Context, params
 	return nil
 }
 
+// rerouteExpiredMessage moves an expired message to the first online fallback phone when the phone of the message is offline
+func (service *MessageService) rerouteExpiredMessage(ctx context.Context, source string, message *entities.Message) (bool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if message.Type != entities.MessageTypeMobileTerminated || !service.phoneIsOffline(ctx, ctxLogger, message.UserID, message.Owner) {
+		return false, nil
+	}
+
+	phone, err := service.phoneService.Load(ctx, message.UserID, message.Owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load phone [%s] for user [%s]", message.Owner, message.UserID))
+	}
+
+	var fallback *entities.Phone
+	for _, phoneNumber := range phone.FallbackPhoneNumbers {
+		if message.ReroutedFrom != nil && *message.ReroutedFrom == phoneNumber {
+			continue
+		}
+
+		candidate, err := service.phoneService.Load(ctx, message.UserID, phoneNumber)
+		if err != nil {
+			ctxLogger.Warn(stacktrace.Propagatef(err, "cannot load fallback phone [%s] of owner [%s] for user [%s]", phoneNumber, message.Owner, message.UserID))
+			continue
+		}
+
+		suppressed, err := service.suppressionService.SuppressedContacts(ctx, message.UserID, phoneNumber, []string{message.Contact})
+		if err != nil {
+			return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot check if contact [%s] opted out of fallback phone [%s]", message.Contact, phoneNumber))
+		}
+
+		if len(suppressed) > 0 {
+			ctxLogger.Info(fmt.Sprintf("skipping fallback phone [%s] for message [%s] because the contact [%s] opted out of receiving messages from it", phoneNumber, message.ID, message.Contact))
+			continue
+		}
+
+		if monitor, err := service.monitorRepository.Load(ctx, message.UserID, phoneNumber); err == nil && monitor.PhoneOnline {
+			fallback = candidate
+			break
+		}
+	}
+
+	if fallback == nil {
+		ctxLogger.Info(fmt.Sprintf("no online fallback phone found for expired message [%s] with owner [%s]", message.ID, message.Owner))
+		return false, nil
+	}
+
+	if err = service.repository.Update(ctx, message.Rerouted(time.Now().UTC(), fallback)); err != nil {
+		return false, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update message with id [%s] as rerouted", message.ID))
+	}
+
+	event, err := service.createMessageSendReroutedEvent(source, &events.MessageSendReroutedPayload{
+		MessageID:     message.ID,
+		PreviousOwner: *message.ReroutedFrom,
+		Owner:         message.Owner,
+		Contact:       message.Contact,
+		RequestID:     message.RequestID,
+		Encrypted:     message.Encrypted,
+		UserID:        message.UserID,
+		Timestamp:     *message.ReroutedAt,
+		Content:       message.Content,
+		SIM:           message.SIM,
+		Encoding:      message.Encoding,
+		Segments:      message.Segments,
+	})
+	if err != nil {
+		return true, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create [%s] event for message with ID [%s]", events.EventTypeMessageSendRerouted, message.ID))
+	}
+
+	if err = service.eventDispatcher.Dispatch(ctx, event); err != nil {
+		return true, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch [%s] event for message with ID [%s]", event.Type(), message.ID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("rerouted expired message [%s] from offline phone [%s] to [%s]", message.ID, *message.ReroutedFrom, message.Owner))
+	return true, nil
+}
+
+func (service *MessageService) phoneIsOffline(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, owner string) bool {
+	monitor, err := service.monitorRepository.Load(ctx, userID, owner)
+	if err != nil {
+		if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot load heartbeat monitor for owner [%s] and user [%s]", owner, userID))
+		}
+		return false
+	}
+	return monitor.PhoneIsOffline()
+}
+
 // MessageScheduleExpirationParams are parameters for scheduling the expiration of a message event
 type MessageScheduleExpirationParams struct {
 	MessageID                 uuid.UUID
@@ -941,6 +1359,8 @@ func (service *MessageService) CheckExpired(ctx context.Context, params MessageC
 		Timestamp:        time.Now().UTC(),
 		Content:          message.Content,
 		SIM:              message.SIM,
+		Encoding:         message.Encoding,
+		Segments:         message.Segments,
 	})
 	if err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create event [%s] for message with id [%s]", events.EventTypeMessageSendExpired, params.MessageID))
@@ -957,26 +1377,54 @@ func (service *MessageService) CheckExpired(ctx context.Context, params MessageC
 // MessageSearchParams are parameters for searching messages
 type MessageSearchParams struct {
 	repositories.IndexParams
-	UserID   entities.UserID
-	Owners   []string
-	Types    []entities.MessageType
-	Statuses []entities.MessageStatus
+	repositories.MessageSearchFilters
+	UserID entities.UserID
 }
 
 // SearchMessages fetches all the messages for a user
-func (service *MessageService) SearchMessages(ctx context.Context, params *MessageSearchParams) ([]*entities.Message, error) {
+func (service *MessageService) SearchMessages(ctx context.Context, params *MessageSearchParams) ([]*entities.MessageSearchResult, error) {
 	ctx, span := service.tracer.Start(ctx)
 	defer span.End()
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
-	messages, err := service.repository.Search(ctx, params.UserID, params.Owners, params.Types, params.Statuses, params.IndexParams)
+	messages, err := service.repository.Search(ctx, params.UserID, params.MessageSearchFilters, params.IndexParams)
 	if err != nil {
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not search messages with parms [%+#v]", params))
 	}
 
+	terms := repositories.MessageSearchTerms(params.Query)
+	results := make([]*entities.MessageSearchResult, 0, len(messages))
+	for _, message := range messages {
+		result := &entities.MessageSearchResult{Message: *message}
+		if len(terms) > 0 && !message.Encrypted {
+			result.Highlight = messageHighlight(message.Content, terms)
+		}
+		results = append(results, result)
+	}
+
 	ctxLogger.Info(fmt.Sprintf("fetched [%d] messages with prams [%+#v]", len(messages), params))
-	return messages, nil
+	return results, nil
+}
+
+// MessageAnalyticsParams are the parameters for fetching entities.MessageAnalytics
+type MessageAnalyticsParams struct {
+	repositories.MessageAnalyticsFilters
+	UserID entities.UserID
+}
+
+// Analytics aggregates the messages of a user to measure the delivery rates, latency and failures of the phones
+func (service *MessageService) Analytics(ctx context.Context, params *MessageAnalyticsParams) (*entities.MessageAnalytics, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	analytics, err := service.repository.Analytics(ctx, params.UserID, params.MessageAnalyticsFilters)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch message analytics with params [%+#v]", params))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("aggregated [%d] messages in [%d] periods for user [%s] with params [%+#v]", analytics.Totals.Total, len(analytics.Periods), params.UserID, params))
+	return analytics, nil
 }
 
 func (service *MessageService) phoneSettings(ctx context.Context, userID entities.UserID, owner string) (uint, entities.SIM, uint) {
@@ -1011,10 +1459,13 @@ func (service *MessageService) storeSentMessage(ctx context.Context, payload eve
 		Owner:             payload.Owner,
 		Contact:           payload.Contact,
 		UserID:            payload.UserID,
+		ActorID:           payload.ActorID,
 		Content:           payload.Content,
 		Attachments:       payload.Attachments,
 		RequestID:         payload.RequestID,
 		SIM:               payload.SIM,
+		Encoding:          payload.Encoding,
+		Segments:          payload.Segments,
 		Encrypted:         payload.Encrypted,
 		ScheduledSendTime: payload.ScheduledSendTime,
 		Type:              entities.MessageTypeMobileTerminated,
@@ -1105,3 +1556,7 @@ func (service *MessageService) createMessagePhoneDeliveredEvent(source string, p
 func (service *MessageService) createMessageSendRetryEvent(source string, payload *events.MessageSendRetryPayload) (cloudevents.Event, error) {
 	return service.createEvent(events.EventTypeMessageSendRetry, source, payload)
 }
+
+func (service *MessageService) createMessageSendReroutedEvent(source string, payload *events.MessageSendReroutedPayload) (cloudevents.Event, error) {
+	return service.createEvent(events.EventTypeMessageSendRerouted, source, payload)
+}
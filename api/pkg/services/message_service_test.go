@@ -1,13 +1,20 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
+	"github.com/NdoleStudio/httpsms/pkg/entities"
 	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -103,3 +110,203 @@ func (l *noopLogger) Warn(_ error)                                  {}
 func (l *noopLogger) Debug(_ string)                                {}
 func (l *noopLogger) Fatal(_ error)                                 {}
 func (l *noopLogger) Printf(_ string, _ ...interface{})             {}
+
+// pushQueueStub records the events which are dispatched with an EventDispatcher
+type pushQueueStub struct {
+	events []cloudevents.Event
+}
+
+func (stub *pushQueueStub) Enqueue(_ context.Context, task *PushQueueTask, _ time.Duration) (string, error) {
+	event := cloudevents.NewEvent()
+	if err := json.Unmarshal(task.Body, &event); err != nil {
+		return "", err
+	}
+	stub.events = append(stub.events, event)
+	return event.ID(), nil
+}
+
+func (stub *pushQueueStub) types() []string {
+	types := make([]string, 0, len(stub.events))
+	for _, event := range stub.events {
+		types = append(types, event.Type())
+	}
+	return types
+}
+
+func newEventDispatcherForTest(queue *pushQueueStub) *EventDispatcher {
+	logger := &noopLogger{}
+	return NewEventDispatcher(logger, telemetry.NewOtelLogger("test", logger), nil, queue, PushQueueConfig{})
+}
+
+type messageRepositoryStub struct {
+	repositories.MessageRepository
+	updated  []*entities.Message
+	messages map[uuid.UUID]*entities.Message
+}
+
+func (stub *messageRepositoryStub) Load(_ context.Context, _ entities.UserID, messageID uuid.UUID) (*entities.Message, error) {
+	if message, ok := stub.messages[messageID]; ok {
+		return message, nil
+	}
+	return nil, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "message not found")
+}
+
+func (stub *messageRepositoryStub) Update(_ context.Context, message *entities.Message) error {
+	stub.updated = append(stub.updated, message)
+	return nil
+}
+
+type phoneRepositoryStub struct {
+	repositories.PhoneRepository
+	phones map[string]*entities.Phone
+}
+
+func (stub *phoneRepositoryStub) Load(_ context.Context, _ entities.UserID, owner string) (*entities.Phone, error) {
+	if phone, ok := stub.phones[owner]; ok {
+		return phone, nil
+	}
+	return nil, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "phone not found")
+}
+
+func newRerouteMessageServiceForTest(queue *pushQueueStub, repository *messageRepositoryStub, suppressed map[string][]string) *MessageService {
+	logger := &noopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	return &MessageService{
+		logger:          logger,
+		tracer:          tracer,
+		repository:      repository,
+		eventDispatcher: newEventDispatcherForTest(queue),
+		phoneService: NewPhoneService(logger, tracer, &phoneRepositoryStub{phones: map[string]*entities.Phone{
+			"+18005550100": {PhoneNumber: "+18005550100", FallbackPhoneNumbers: []string{"+18005550101", "+18005550102"}},
+			"+18005550101": {PhoneNumber: "+18005550101", MaxSendAttempts: 2},
+			"+18005550102": {PhoneNumber: "+18005550102", MaxSendAttempts: 3},
+		}}, nil),
+		suppressionService: newSuppressionServiceForTest(&suppressionRepositoryStub{suppressed: suppressed}),
+		monitorRepository: &heartbeatMonitorRepositoryStub{monitors: map[string]*entities.HeartbeatMonitor{
+			"+18005550100": {PhoneOnline: false},
+			"+18005550101": {PhoneOnline: true},
+			"+18005550102": {PhoneOnline: true},
+		}},
+	}
+}
+
+func TestMessageServiceRerouteExpiredMessage_SkipsFallbackPhonesTheContactOptedOutOf(t *testing.T) {
+	queue := &pushQueueStub{}
+	repository := &messageRepositoryStub{}
+	service := newRerouteMessageServiceForTest(queue, repository, map[string][]string{"+18005550101": {"+18005550199"}})
+	message := &entities.Message{ID: uuid.New(), Owner: "+18005550100", Contact: "+18005550199", Type: entities.MessageTypeMobileTerminated, SendAttemptCount: 2, MaxSendAttempts: 2}
+
+	rerouted, err := service.rerouteExpiredMessage(context.Background(), "test", message)
+
+	require.NoError(t, err)
+	assert.True(t, rerouted)
+	assert.Equal(t, "+18005550102", message.Owner)
+	assert.Equal(t, "+18005550100", *message.ReroutedFrom)
+	assert.Equal(t, uint(2), message.SendAttemptCount)
+	assert.Equal(t, uint(5), message.MaxSendAttempts)
+	assert.Equal(t, []*entities.Message{message}, repository.updated)
+	assert.Equal(t, []string{events.EventTypeMessageSendRerouted}, queue.types())
+}
+
+func TestMessageServiceRerouteExpiredMessage_DoesNotRerouteWhenTheContactOptedOutOfAllFallbackPhones(t *testing.T) {
+	queue := &pushQueueStub{}
+	repository := &messageRepositoryStub{}
+	service := newRerouteMessageServiceForTest(queue, repository, map[string][]string{"+18005550101": {"+18005550199"}, "+18005550102": {"+18005550199"}})
+	message := &entities.Message{ID: uuid.New(), Owner: "+18005550100", Contact: "+18005550199", Type: entities.MessageTypeMobileTerminated}
+
+	rerouted, err := service.rerouteExpiredMessage(context.Background(), "test", message)
+
+	require.NoError(t, err)
+	assert.False(t, rerouted)
+	assert.Equal(t, "+18005550100", message.Owner)
+	assert.Empty(t, repository.updated)
+	assert.Empty(t, queue.events)
+}
+
+func TestMessageServiceCancelMessage_DispatchesTheBillingDetails(t *testing.T) {
+	queue := &pushQueueStub{}
+	repository := &messageRepositoryStub{}
+	logger := &noopLogger{}
+	service := &MessageService{logger: logger, tracer: telemetry.NewOtelLogger("test", logger), repository: repository, eventDispatcher: newEventDispatcherForTest(queue)}
+	requestReceivedAt := time.Date(2026, 7, 18, 6, 59, 0, 0, time.UTC)
+	message := &entities.Message{ID: uuid.New(), Status: entities.MessageStatusPending, Segments: 3, RequestReceivedAt: requestReceivedAt}
+
+	_, err := service.CancelMessage(context.Background(), "test", message)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.MessageStatus(entities.MessageStatusCanceled), message.Status)
+	require.Equal(t, []string{events.MessageAPICanceled}, queue.types())
+
+	var payload events.MessageAPICanceledPayload
+	require.NoError(t, queue.events[0].DataAs(&payload))
+	assert.Equal(t, uint(3), payload.Segments)
+	assert.Equal(t, requestReceivedAt, payload.RequestReceivedAt)
+}
+
+func newRescheduleMessageServiceForTest(queue *pushQueueStub, repository *messageRepositoryStub) *MessageService {
+	logger := &noopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	return &MessageService{
+		logger:                  logger,
+		tracer:                  tracer,
+		repository:              repository,
+		eventDispatcher:         newEventDispatcherForTest(queue),
+		scheduledMessageService: NewScheduledMessageService(logger, tracer, &scheduledMessageRepositoryStub{}, repository, newEventDispatcherForTest(queue)),
+	}
+}
+
+func TestMessageServiceRescheduleMessage_BeforeTheAPISentEventIsProcessed(t *testing.T) {
+	queue := &pushQueueStub{}
+	repository := &messageRepositoryStub{}
+	service := newRescheduleMessageServiceForTest(queue, repository)
+	originalSendAt := time.Now().UTC().Add(time.Minute)
+	message := &entities.Message{ID: uuid.New(), UserID: "user-id", Status: entities.MessageStatusPending, ScheduledSendTime: &originalSendAt}
+	sendAt := time.Now().UTC().Add(time.Hour)
+
+	_, err := service.RescheduleMessage(context.Background(), "test", message, sendAt)
+
+	require.NoError(t, err)
+	require.Len(t, repository.updated, 1)
+	assert.Equal(t, sendAt, *repository.updated[0].ScheduledSendTime)
+	assert.Equal(t, []string{events.MessageAPIRescheduled}, queue.types())
+
+	// the phone notification of the original [message.api.sent] event is dropped when it arrives after the reschedule
+	stale := (&PhoneNotificationService{}).isStaleSchedule(message, &PhoneNotificationScheduleParams{MessageID: message.ID, ExactSendTime: true, ScheduledSendTime: &originalSendAt})
+	assert.True(t, stale)
+}
+
+func TestMessageServiceRescheduleMessage_RejectsSendTimeInThePast(t *testing.T) {
+	queue := &pushQueueStub{}
+	repository := &messageRepositoryStub{}
+	service := newRescheduleMessageServiceForTest(queue, repository)
+	message := &entities.Message{ID: uuid.New(), UserID: "user-id", Status: entities.MessageStatusPending}
+
+	_, err := service.RescheduleMessage(context.Background(), "test", message, time.Now().UTC().Add(-time.Hour))
+
+	require.Error(t, err)
+	assert.Empty(t, repository.updated)
+	assert.Empty(t, queue.events)
+}
+
+func TestMessageServiceRescheduleMessage_RejectsMessageWhichWasSent(t *testing.T) {
+	queue := &pushQueueStub{}
+	repository := &messageRepositoryStub{}
+	service := newRescheduleMessageServiceForTest(queue, repository)
+	message := &entities.Message{ID: uuid.New(), UserID: "user-id", Status: entities.MessageStatusSent}
+
+	_, err := service.RescheduleMessage(context.Background(), "test", message, time.Now().UTC().Add(time.Hour))
+
+	require.Error(t, err)
+	assert.Empty(t, repository.updated)
+}
+
+func TestMessageServiceImportMessage_UsesTheTimestampOfTheImportedMessage(t *testing.T) {
+	service := &MessageService{}
+	timestamp := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	message := service.importMessage("user-id", &MessageImportParams{Owner: "+18005550199", Contact: "+18005550100", Content: "Hello", Type: entities.MessageTypeMobileOriginated, Status: entities.MessageStatusReceived, Timestamp: timestamp})
+
+	assert.Equal(t, timestamp, message.CreatedAt)
+	assert.Equal(t, timestamp, message.OrderTimestamp)
+	assert.Equal(t, timestamp, *message.ReceivedAt)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+)
+
+// MessageTemplateService manages the message templates of a user
+type MessageTemplateService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.MessageTemplateRepository
+}
+
+// NewMessageTemplateService creates a new MessageTemplateService
+func NewMessageTemplateService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.MessageTemplateRepository,
+) (s *MessageTemplateService) {
+	return &MessageTemplateService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// MessageTemplateUpsertParams are parameters for creating or updating an entities.MessageTemplate
+type MessageTemplateUpsertParams struct {
+	UserID  entities.UserID
+	Name    string
+	Content string
+}
+
+// Index fetches the entities.MessageTemplate of a user
+func (service *MessageTemplateService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.MessageTemplate, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	templates, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch message templates for user [%s] with params [%+#v]", userID, params))
+	}
+
+	return templates, nil
+}
+
+// Load an entities.MessageTemplate of a user
+func (service *MessageTemplateService) Load(ctx context.Context, userID entities.UserID, templateID uuid.UUID) (*entities.MessageTemplate, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	template, err := service.repository.Load(ctx, userID, templateID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load message template [%s] for user [%s]", templateID, userID))
+	}
+
+	return template, nil
+}
+
+// Store a new entities.MessageTemplate
+func (service *MessageTemplateService) Store(ctx context.Context, params *MessageTemplateUpsertParams) (*entities.MessageTemplate, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	template := &entities.MessageTemplate{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		Name:      params.Name,
+		Content:   params.Content,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err := service.repository.Store(ctx, template); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save message template for user [%s]", params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("message template saved with id [%s] for user [%s]", template.ID, template.UserID))
+	return template, nil
+}
+
+// Update an existing entities.MessageTemplate
+func (service *MessageTemplateService) Update(ctx context.Context, templateID uuid.UUID, params *MessageTemplateUpsertParams) (*entities.MessageTemplate, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	template, err := service.repository.Load(ctx, params.UserID, templateID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load message template [%s] for user [%s]", templateID, params.UserID))
+	}
+
+	template.Name = params.Name
+	template.Content = params.Content
+	template.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Update(ctx, template); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update message template [%s] for user [%s]", templateID, params.UserID))
+	}
+
+	return template, nil
+}
+
+// Delete an entities.MessageTemplate
+func (service *MessageTemplateService) Delete(ctx context.Context, userID entities.UserID, templateID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, templateID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load message template [%s] for user [%s]", templateID, userID))
+	}
+
+	if err := service.repository.Delete(ctx, userID, templateID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete message template [%s] for user [%s]", templateID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.MessageTemplate for an entities.UserID.
+func (service *MessageTemplateService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.MessageTemplate] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.MessageTemplate] for user with ID [%s]", userID))
+	return nil
+}
+
+// Render loads an entities.MessageTemplate and substitutes the variables in its content
+func (service *MessageTemplateService) Render(ctx context.Context, userID entities.UserID, templateID uuid.UUID, variables map[string]string) (string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	template, err := service.Load(ctx, userID, templateID)
+	if err != nil {
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot render message template [%s]", templateID))
+	}
+
+	content, missing := template.Render(variables)
+	if len(missing) > 0 {
+		return "", service.tracer.WrapErrorSpan(span, stacktrace.NewErrorf("message template [%s] is missing the variables [%s]", templateID, strings.Join(missing, ", ")))
+	}
+
+	return content, nil
+}
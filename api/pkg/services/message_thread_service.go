@@ -22,6 +22,7 @@ type MessageThreadService struct {
 	tracer          telemetry.Tracer
 	repository      repositories.MessageThreadRepository
 	phoneRepository repositories.PhoneRepository
+	contactService  *ContactService
 	eventDispatcher *EventDispatcher
 }
 
@@ -31,6 +32,7 @@ func NewMessageThreadService(
 	tracer telemetry.Tracer,
 	repository repositories.MessageThreadRepository,
 	phoneRepository repositories.PhoneRepository,
+	contactService *ContactService,
 	eventDispatcher *EventDispatcher,
 ) (s *MessageThreadService) {
 	return &MessageThreadService{
@@ -39,6 +41,7 @@ func NewMessageThreadService(
 		eventDispatcher: eventDispatcher,
 		repository:      repository,
 		phoneRepository: phoneRepository,
+		contactService:  contactService,
 	}
 }
 
@@ -139,6 +142,7 @@ type MessageThreadStatusParams struct {
 	IsRead          *bool
 	UserID          entities.UserID
 	MessageThreadID uuid.UUID
+	ActorID         *entities.UserID
 }
 
 // UpdateStatus updates a thread between an owner and a contact
@@ -150,6 +154,7 @@ func (service *MessageThreadService) UpdateStatus(ctx context.Context, params Me
 		IsArchived: params.IsArchived,
 		IsRead:     params.IsRead,
 		ReadAt:     time.Now().UTC(),
+		ActorID:    params.ActorID,
 	}
 	thread, err := service.repository.UpdateStatus(ctx, params.UserID, params.MessageThreadID, update)
 	if err != nil {
@@ -282,10 +287,36 @@ func (service *MessageThreadService) GetThreads(ctx context.Context, params Mess
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch messages threads for params [%+#v]", params))
 	}
 
+	service.addContactNames(ctx, ctxLogger, params.UserID, *threads)
+
 	ctxLogger.Info(fmt.Sprintf("fetched [%d] threads with params [%+#v]", len(*threads), params))
 	return threads, nil
 }
 
+// addContactNames sets the name of the contact of each thread from the address book of the user
+func (service *MessageThreadService) addContactNames(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, threads []entities.MessageThread) {
+	if len(threads) == 0 {
+		return
+	}
+
+	phoneNumbers := make([]string, 0, len(threads))
+	for _, thread := range threads {
+		phoneNumbers = append(phoneNumbers, thread.Contact)
+	}
+
+	names, err := service.contactService.Names(ctx, userID, phoneNumbers)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot fetch contact names for [%d] threads of user [%s]", len(threads), userID))
+		return
+	}
+
+	for index := range threads {
+		if name, ok := names[threads[index].Contact]; ok {
+			threads[index].ContactName = &name
+		}
+	}
+}
+
 // GetThread fetches an entities.MessageThread  message thread by the ID
 func (service *MessageThreadService) GetThread(ctx context.Context, userID entities.UserID, messageThreadID uuid.UUID) (*entities.MessageThread, error) {
 	ctx, span := service.tracer.Start(ctx)
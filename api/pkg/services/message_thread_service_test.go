@@ -69,10 +69,14 @@ func (stub *messageThreadRepositoryStub) DeleteAllForUser(context.Context, entit
 	return nil
 }
 
+func (stub *messageThreadRepositoryStub) ReEncrypt(context.Context, entities.UserID, int) (int, error) {
+	return 0, nil
+}
+
 func newMessageThreadServiceForTest(repository repositories.MessageThreadRepository) *MessageThreadService {
 	logger := &noopLogger{}
 	tracer := telemetry.NewOtelLogger("test", logger)
-	return NewMessageThreadService(logger, tracer, repository, nil, nil)
+	return NewMessageThreadService(logger, tracer, repository, nil, nil, nil)
 }
 
 func TestUpdateThreadPassesUnreadWatermarkForInboundActivity(t *testing.T) {
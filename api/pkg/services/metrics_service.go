@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"firebase.google.com/go/messaging"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
+)
+
+// MetricsService records the business metrics of the messages, push queues, webhooks, FCM notifications and heartbeats
+type MetricsService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	monitorRepository repositories.HeartbeatMonitorRepository
+	messageStatus     metric.Int64Counter
+	enqueueDuration   metric.Float64Histogram
+	enqueueFailures   metric.Int64Counter
+	webhookDeliveries metric.Int64Counter
+	fcmSendErrors     metric.Int64Counter
+}
+
+// NewMetricsService creates a new MetricsService and registers the instruments with the meter
+func NewMetricsService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	meter metric.Meter,
+	monitorRepository repositories.HeartbeatMonitorRepository,
+) (s *MetricsService, err error) {
+	s = &MetricsService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		monitorRepository: monitorRepository,
+	}
+
+	if s.messageStatus, err = meter.Int64Counter(
+		"httpsms.message.status",
+		metric.WithUnit("{message}"),
+		metric.WithDescription("counts the messages which changed to a status per owner phone number"),
+	); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create message status counter")
+	}
+
+	if s.enqueueDuration, err = meter.Float64Histogram(
+		"httpsms.push_queue.enqueue.duration",
+		metric.WithUnit("ms"),
+		metric.WithDescription("measures the duration of adding a task to a push queue"),
+	); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create push queue enqueue duration histogram")
+	}
+
+	if s.enqueueFailures, err = meter.Int64Counter(
+		"httpsms.push_queue.enqueue.failures",
+		metric.WithUnit("{task}"),
+		metric.WithDescription("counts the tasks which could not be added to a push queue"),
+	); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create push queue enqueue failures counter")
+	}
+
+	if s.webhookDeliveries, err = meter.Int64Counter(
+		"httpsms.webhook.deliveries",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("counts the webhook requests per event type and response status code, the status code is 0 when there is no response"),
+	); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create webhook deliveries counter")
+	}
+
+	if s.fcmSendErrors, err = meter.Int64Counter(
+		"httpsms.fcm.send.errors",
+		metric.WithUnit("{notification}"),
+		metric.WithDescription("counts the FCM notifications which could not be sent to a phone"),
+	); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create FCM send errors counter")
+	}
+
+	if _, err = meter.Int64ObservableGauge(
+		"httpsms.heartbeat.phones",
+		metric.WithUnit("{phone}"),
+		metric.WithDescription("counts the phones with a heartbeat monitor which are online and offline"),
+		metric.WithInt64Callback(s.observePhoneOnline),
+	); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot create heartbeat phones gauge")
+	}
+
+	return s, nil
+}
+
+// RecordMessageStatus counts a message which changed to a status
+func (service *MetricsService) RecordMessageStatus(ctx context.Context, owner string, status entities.MessageStatus) {
+	service.messageStatus.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("owner", owner),
+		attribute.String("status", string(status)),
+	))
+}
+
+// RecordEnqueue measures the duration of adding a task to a push queue and counts the failures
+func (service *MetricsService) RecordEnqueue(ctx context.Context, queue string, start time.Time, err error) {
+	attributes := metric.WithAttributes(attribute.String("queue", queue))
+	service.enqueueDuration.Record(ctx, float64(time.Since(start).Milliseconds()), attributes)
+	if err != nil {
+		service.enqueueFailures.Add(ctx, 1, attributes)
+	}
+}
+
+// RecordWebhookDelivery counts a webhook request with the status code of the response
+func (service *MetricsService) RecordWebhookDelivery(ctx context.Context, eventType string, statusCode int) {
+	service.webhookDeliveries.Add(ctx, 1, metric.WithAttributes(
+		semconv.CloudeventsEventType(eventType),
+		semconv.HTTPStatusCode(statusCode),
+	))
+}
+
+// RecordFCMSendError counts an FCM notification which could not be sent
+func (service *MetricsService) RecordFCMSendError(ctx context.Context, notification string, err error) {
+	service.fcmSendErrors.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("notification", notification),
+		attribute.String("reason", service.fcmErrorReason(err)),
+	))
+}
+
+func (service *MetricsService) observePhoneOnline(ctx context.Context, observer metric.Int64Observer) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	online, offline, err := service.monitorRepository.CountPhoneOnline(ctx)
+	if err != nil {
+		ctxLogger.Error(service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot count heartbeat monitors to observe the online status of phones")))
+		return nil
+	}
+
+	observer.Observe(online, metric.WithAttributes(attribute.Bool("online", true)))
+	observer.Observe(offline, metric.WithAttributes(attribute.Bool("online", false)))
+	return nil
+}
+
+func (service *MetricsService) fcmErrorReason(err error) string {
+	switch {
+	case messaging.IsRegistrationTokenNotRegistered(err):
+		return "registration_token_not_registered"
+	case messaging.IsInvalidArgument(err):
+		return "invalid_argument"
+	case messaging.IsMessageRateExceeded(err):
+		return "message_rate_exceeded"
+	case messaging.IsMismatchedCredential(err):
+		return "mismatched_credential"
+	case messaging.IsServerUnavailable(err):
+		return "server_unavailable"
+	case messaging.IsInternal(err):
+		return "internal"
+	default:
+		return "unknown"
+	}
+}
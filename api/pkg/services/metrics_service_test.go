@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type metricsHeartbeatMonitorRepositoryStub struct {
+	repositories.HeartbeatMonitorRepository
+	monitors []*entities.HeartbeatMonitor
+}
+
+func (stub *metricsHeartbeatMonitorRepositoryStub) CountPhoneOnline(context.Context) (online int64, offline int64, err error) {
+	for _, monitor := range stub.monitors {
+		if monitor.PhoneOnline {
+			online++
+		} else {
+			offline++
+		}
+	}
+	return online, offline, nil
+}
+
+func newTestMetricsService(t *testing.T, monitors ...*entities.HeartbeatMonitor) (*MetricsService, *metric.ManualReader) {
+	logger := &noopLogger{}
+	reader := metric.NewManualReader()
+	meter := metric.NewMeterProvider(metric.WithReader(reader)).Meter("test")
+
+	service, err := NewMetricsService(logger, telemetry.NewOtelLogger("test", logger), meter, &metricsHeartbeatMonitorRepositoryStub{monitors: monitors})
+	require.NoError(t, err)
+	return service, reader
+}
+
+func collectMetric(t *testing.T, reader *metric.ManualReader, name string) metricdata.Aggregation {
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	for _, scope := range data.ScopeMetrics {
+		for _, item := range scope.Metrics {
+			if item.Name == name {
+				return item.Data
+			}
+		}
+	}
+	t.Fatalf("metric [%s] was not collected", name)
+	return nil
+}
+
+func TestMetricsServiceRecordMessageStatus(t *testing.T) {
+	service, reader := newTestMetricsService(t)
+	ctx := context.Background()
+
+	service.RecordMessageStatus(ctx, "+18005550199", entities.MessageStatusDelivered)
+	service.RecordMessageStatus(ctx, "+18005550199", entities.MessageStatusDelivered)
+	service.RecordMessageStatus(ctx, "+18005550100", entities.MessageStatusFailed)
+
+	sum := collectMetric(t, reader, "httpsms.message.status").(metricdata.Sum[int64])
+	counts := map[attribute.Distinct]int64{}
+	for _, point := range sum.DataPoints {
+		counts[point.Attributes.Equivalent()] = point.Value
+	}
+
+	delivered := attribute.NewSet(attribute.String("owner", "+18005550199"), attribute.String("status", "delivered"))
+	failed := attribute.NewSet(attribute.String("owner", "+18005550100"), attribute.String("status", "failed"))
+	assert.Equal(t, map[attribute.Distinct]int64{delivered.Equivalent(): 2, failed.Equivalent(): 1}, counts)
+}
+
+func TestMetricsServiceRecordEnqueueCountsFailures(t *testing.T) {
+	service, reader := newTestMetricsService(t)
+	ctx := context.Background()
+
+	service.RecordEnqueue(ctx, "events", time.Now(), nil)
+	service.RecordEnqueue(ctx, "events", time.Now(), errors.New("deadline exceeded"))
+
+	histogram := collectMetric(t, reader, "httpsms.push_queue.enqueue.duration").(metricdata.Histogram[float64])
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, uint64(2), histogram.DataPoints[0].Count)
+
+	failures := collectMetric(t, reader, "httpsms.push_queue.enqueue.failures").(metricdata.Sum[int64])
+	require.Len(t, failures.DataPoints, 1)
+	assert.Equal(t, int64(1), failures.DataPoints[0].Value)
+}
+
+func TestMetricsServiceObservesPhoneOnline(t *testing.T) {
+	_, reader := newTestMetricsService(
+		t,
+		&entities.HeartbeatMonitor{PhoneID: uuid.New(), Owner: "+18005550199", PhoneOnline: true},
+		&entities.HeartbeatMonitor{PhoneID: uuid.New(), Owner: "+18005550198", PhoneOnline: true},
+		&entities.HeartbeatMonitor{PhoneID: uuid.New(), Owner: "+18005550100", PhoneOnline: false},
+	)
+
+	gauge := collectMetric(t, reader, "httpsms.heartbeat.phones").(metricdata.Gauge[int64])
+	values := map[bool]int64{}
+	for _, point := range gauge.DataPoints {
+		online, _ := point.Attributes.Value("online")
+		values[online.AsBool()] = point.Value
+	}
+
+	assert.Equal(t, map[bool]int64{true: 2, false: 1}, values)
+}
+
+func TestMetricsServiceFCMErrorReason(t *testing.T) {
+	service := &MetricsService{}
+	assert.Equal(t, "unknown", service.fcmErrorReason(errors.New("connection reset")))
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/emails"
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+)
+
+// OrganisationService manages the organisations which share the resources of the owner with the members
+type OrganisationService struct {
+	service
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	repository   repositories.OrganisationRepository
+	mailer       emails.Mailer
+	emailFactory emails.UserEmailFactory
+}
+
+// NewOrganisationService creates a new OrganisationService
+func NewOrganisationService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.OrganisationRepository,
+	mailer emails.Mailer,
+	emailFactory emails.UserEmailFactory,
+) (s *OrganisationService) {
+	return &OrganisationService{
+		logger:       logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:       tracer,
+		repository:   repository,
+		mailer:       mailer,
+		emailFactory: emailFactory,
+	}
+}
+
+// OrganisationStoreParams are parameters for creating an entities.Organisation
+type OrganisationStoreParams struct {
+	UserID entities.UserID
+	Email  string
+	Name   string
+}
+
+// Store creates an entities.Organisation which is owned by the user
+func (service *OrganisationService) Store(ctx context.Context, params *OrganisationStoreParams) (*entities.Organisation, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	now := time.Now().UTC()
+	organisation := &entities.Organisation{
+		ID:        uuid.New(),
+		Name:      params.Name,
+		OwnerID:   params.UserID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	owner := &entities.OrganisationMember{
+		ID:             uuid.New(),
+		OrganisationID: organisation.ID,
+		UserID:         &params.UserID,
+		Email:          params.Email,
+		Role:           entities.OrganisationRoleOwner,
+		InvitedBy:      params.UserID,
+		AcceptedAt:     &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := service.repository.Store(ctx, organisation, owner); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot store organisation for user [%s]", params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("created organisation [%s] for user [%s]", organisation.ID, params.UserID))
+	return organisation, nil
+}
+
+// Update changes the name of an entities.Organisation
+func (service *OrganisationService) Update(ctx context.Context, organisation *entities.Organisation, name string) (*entities.Organisation, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	organisation.Name = name
+	organisation.UpdatedAt = time.Now().UTC()
+	if err := service.repository.Update(ctx, organisation); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update organisation [%s]", organisation.ID))
+	}
+
+	return organisation, nil
+}
+
+// Load an entities.Organisation by ID
+func (service *OrganisationService) Load(ctx context.Context, organisationID uuid.UUID) (*entities.Organisation, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	organisation, err := service.repository.Load(ctx, organisationID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load organisation [%s]", organisationID))
+	}
+
+	return organisation, nil
+}
+
+// LoadByOwner loads the entities.Organisation which is owned by a user
+func (service *OrganisationService) LoadByOwner(ctx context.Context, ownerID entities.UserID) (*entities.Organisation, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	organisation, err := service.repository.LoadByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load organisation owned by user [%s]", ownerID))
+	}
+
+	return organisation, nil
+}
+
+// Delete an entities.Organisation, the resources of the owner are not deleted
+func (service *OrganisationService) Delete(ctx context.Context, organisationID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.Delete(ctx, organisationID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete organisation [%s]", organisationID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted organisation [%s]", organisationID))
+	return nil
+}
+
+// LoadMembership loads the entities.OrganisationMembership of a user who accepted the invitation to an organisation
+func (service *OrganisationService) LoadMembership(ctx context.Context, organisationID uuid.UUID, userID entities.UserID) (*entities.OrganisationMembership, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	membership, err := service.repository.LoadMembership(ctx, organisationID, userID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load membership of user [%s] in organisation [%s]", userID, organisationID))
+	}
+
+	return membership, nil
+}
+
+// FetchMemberships fetches the organisations of a user and the pending invitations to the email address of the user
+func (service *OrganisationService) FetchMemberships(ctx context.Context, userID entities.UserID, email string) ([]*entities.OrganisationMembership, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	memberships, err := service.repository.FetchMemberships(ctx, userID, email)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch memberships of user [%s]", userID))
+	}
+
+	return memberships, nil
+}
+
+// OrganisationInviteParams are parameters for inviting a user to an entities.Organisation
+type OrganisationInviteParams struct {
+	Organisation *entities.Organisation
+	InvitedBy    entities.UserID
+	InviterEmail string
+	Email        string
+	Role         entities.OrganisationRole
+}
+
+// Invite creates a pending entities.OrganisationMember and sends the invitation email
+func (service *OrganisationService) Invite(ctx context.Context, params *OrganisationInviteParams) (*entities.OrganisationMember, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	member := &entities.OrganisationMember{
+		ID:             uuid.New(),
+		OrganisationID: params.Organisation.ID,
+		Email:          params.Email,
+		Role:           params.Role,
+		InvitedBy:      params.InvitedBy,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
+	}
+
+	if err := service.repository.StoreMember(ctx, member); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot invite [%s] to organisation [%s]", params.Email, params.Organisation.ID))
+	}
+
+	// the invitation can still be accepted from the httpSMS settings page when the email cannot be sent
+	if err := service.sendInvitation(ctx, member, params); err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot send invitation email for member [%s] of organisation [%s]", member.ID, member.OrganisationID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("user [%s] invited member [%s] to organisation [%s] with role [%s]", params.InvitedBy, member.ID, member.OrganisationID, member.Role))
+	return member, nil
+}
+
+// Accept links the pending entities.OrganisationMember with the email address of the user to the user
+func (service *OrganisationService) Accept(ctx context.Context, organisationID uuid.UUID, userID entities.UserID, email string) (*entities.OrganisationMember, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	member, err := service.repository.LoadMemberByEmail(ctx, organisationID, email)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load invitation of [%s] to organisation [%s]", email, organisationID))
+	}
+
+	if !member.IsPending() {
+		return member, nil
+	}
+
+	now := time.Now().UTC()
+	member.UserID = &userID
+	member.AcceptedAt = &now
+	member.UpdatedAt = now
+	if err = service.repository.UpdateMember(ctx, member); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot accept invitation of member [%s] to organisation [%s]", member.ID, organisationID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("user [%s] accepted the invitation of member [%s] to organisation [%s]", userID, member.ID, organisationID))
+	return member, nil
+}
+
+// LoadMember loads an entities.OrganisationMember by ID
+func (service *OrganisationService) LoadMember(ctx context.Context, organisationID uuid.UUID, memberID uuid.UUID) (*entities.OrganisationMember, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	member, err := service.repository.LoadMember(ctx, organisationID, memberID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load member [%s] of organisation [%s]", memberID, organisationID))
+	}
+
+	return member, nil
+}
+
+// LoadMemberByEmail loads the entities.OrganisationMember which was invited with an email address
+func (service *OrganisationService) LoadMemberByEmail(ctx context.Context, organisationID uuid.UUID, email string) (*entities.OrganisationMember, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	member, err := service.repository.LoadMemberByEmail(ctx, organisationID, email)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load member with email [%s] in organisation [%s]", email, organisationID))
+	}
+
+	return member, nil
+}
+
+// IndexMembers fetches the entities.OrganisationMember of an organisation
+func (service *OrganisationService) IndexMembers(ctx context.Context, organisationID uuid.UUID, params repositories.IndexParams) ([]*entities.OrganisationMember, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	members, err := service.repository.IndexMembers(ctx, organisationID, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch members of organisation [%s]", organisationID))
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes the role of an entities.OrganisationMember
+func (service *OrganisationService) UpdateMemberRole(ctx context.Context, member *entities.OrganisationMember, role entities.OrganisationRole) (*entities.OrganisationMember, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	previous := member.Role
+	member.Role = role
+	member.UpdatedAt = time.Now().UTC()
+	if err := service.repository.UpdateMember(ctx, member); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update role of member [%s] in organisation [%s]", member.ID, member.OrganisationID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("changed role of member [%s] in organisation [%s] from [%s] to [%s]", member.ID, member.OrganisationID, previous, role))
+	return member, nil
+}
+
+// DeleteMember removes an entities.OrganisationMember from the organisation
+func (service *OrganisationService) DeleteMember(ctx context.Context, member *entities.OrganisationMember) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteMember(ctx, member.OrganisationID, member.ID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete member [%s] from organisation [%s]", member.ID, member.OrganisationID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted member [%s] from organisation [%s]", member.ID, member.OrganisationID))
+	return nil
+}
+
+// DeleteAllForUser deletes the organisations owned by a user and the memberships of the user
+func (service *OrganisationService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete organisations of user [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Organisation] and memberships for user [%s]", userID))
+	return nil
+}
+
+func (service *OrganisationService) sendInvitation(ctx context.Context, member *entities.OrganisationMember, params *OrganisationInviteParams) error {
+	email, err := service.emailFactory.OrganisationInvitation(member, params.Organisation, params.InviterEmail)
+	if err != nil {
+		return stacktrace.Propagatef(err, "cannot create invitation email for [%s]", member.Email)
+	}
+
+	if err = service.mailer.Send(ctx, email); err != nil {
+		return stacktrace.Propagatef(err, "cannot send invitation email to [%s]", member.Email)
+	}
+
+	return nil
+}
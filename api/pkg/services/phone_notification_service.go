@@ -26,8 +26,10 @@ type PhoneNotificationService struct {
 	phoneNotificationRepository   repositories.PhoneNotificationRepository
 	phoneRepository               repositories.PhoneRepository
 	messageSendScheduleRepository repositories.MessageSendScheduleRepository
+	messageRepository             repositories.MessageRepository
 	messagingClient               FCMClient
 	eventDispatcher               *EventDispatcher
+	metrics                       *MetricsService
 }
 
 // NewNotificationService creates a new PhoneNotificationService
@@ -38,7 +40,9 @@ func NewNotificationService(
 	phoneRepository repositories.PhoneRepository,
 	phoneNotificationRepository repositories.PhoneNotificationRepository,
 	messageSendScheduleRepository repositories.MessageSendScheduleRepository,
+	messageRepository repositories.MessageRepository,
 	dispatcher *EventDispatcher,
+	metrics *MetricsService,
 ) (s *PhoneNotificationService) {
 	return &PhoneNotificationService{
 		logger:                        logger.WithService(fmt.Sprintf("%T", &PhoneNotificationService{})),
@@ -47,7 +51,9 @@ func NewNotificationService(
 		phoneNotificationRepository:   phoneNotificationRepository,
 		phoneRepository:               phoneRepository,
 		messageSendScheduleRepository: messageSendScheduleRepository,
+		messageRepository:             messageRepository,
 		eventDispatcher:               dispatcher,
+		metrics:                       metrics,
 	}
 }
 
@@ -101,6 +107,7 @@ func (service *PhoneNotificationService) SendHeartbeatFCM(ctx context.Context, p
 		Token: *phone.FcmToken,
 	})
 	if err != nil {
+		service.metrics.RecordFCMSendError(ctx, "heartbeat", err)
 		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot send heartbeat FCM to phone with id [%s] for user [%s]", phone.ID, phone.UserID))
 		return nil
 	}
@@ -130,6 +137,16 @@ func (service *PhoneNotificationService) Send(ctx context.Context, params *Phone
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
 	defer span.End()
 
+	exists, err := service.phoneNotificationRepository.Exists(ctx, params.UserID, params.PhoneNotificationID)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot check if notification [%s] exists for message [%s]", params.PhoneNotificationID, params.MessageID))
+	}
+
+	if !exists {
+		ctxLogger.Info(fmt.Sprintf("notification [%s] for message [%s] has been deleted. skipping send", params.PhoneNotificationID, params.MessageID))
+		return nil
+	}
+
 	phone, err := service.phoneRepository.LoadByID(ctx, params.UserID, params.PhoneID)
 	if err != nil {
 		msg := fmt.Sprintf("cannot load phone with userID [%s] and phoneID [%s]", params.UserID, params.PhoneID)
@@ -153,6 +170,7 @@ func (service *PhoneNotificationService) Send(ctx context.Context, params *Phone
 		Token: *phone.FcmToken,
 	})
 	if err != nil {
+		service.metrics.RecordFCMSendError(ctx, "message", err)
 		ctxLogger.Warn(stacktrace.Propagatef(
 			err,
 
@@ -189,6 +207,25 @@ func (service *PhoneNotificationService) Schedule(ctx context.Context, params *P
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
+	message, err := service.messageRepository.Load(ctx, params.UserID, params.MessageID)
+	if err != nil && stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		ctxLogger.Info(fmt.Sprintf("message [%s] for user [%s] does not exist. skipping notification", params.MessageID, params.UserID))
+		return nil
+	}
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load message [%s] for user [%s]", params.MessageID, params.UserID))
+	}
+
+	if service.isStaleSchedule(message, params) {
+		ctxLogger.Info(fmt.Sprintf("message [%s] has status [%s] and send time [%s]. skipping stale notification", message.ID, message.Status, message.ScheduledSendTime))
+		return nil
+	}
+
+	if params.ExactSendTime && params.ScheduledSendTime != nil && isBeyondQueueHorizon(*params.ScheduledSendTime) {
+		ctxLogger.Info(fmt.Sprintf("message [%s] has send time [%s] beyond the queue horizon. notification will be scheduled by the sweeper", message.ID, params.ScheduledSendTime))
+		return nil
+	}
+
 	phone, err := service.phoneRepository.Load(ctx, params.UserID, params.Owner)
 	if err != nil {
 		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load phone with userID [%s] and phone [%s]", params.UserID, params.Owner))
@@ -243,6 +280,23 @@ func (service *PhoneNotificationService) Schedule(ctx context.Context, params *P
 	return nil
 }
 
+// isStaleSchedule checks if a queued task was made obsolete because the message was canceled, paused or rescheduled
+func (service *PhoneNotificationService) isStaleSchedule(message *entities.Message, params *PhoneNotificationScheduleParams) bool {
+	if message.IsCanceled() || message.IsPaused() {
+		return true
+	}
+
+	if message.ScheduledSendTime == nil {
+		return false
+	}
+
+	if params.ExactSendTime && params.ScheduledSendTime != nil {
+		return message.ScheduledSendTime.Sub(*params.ScheduledSendTime).Abs() > time.Second
+	}
+
+	return message.ScheduledSendTime.After(time.Now().UTC())
+}
+
 func (service *PhoneNotificationService) scheduleExact(
 	ctx context.Context,
 	span trace.Span,
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PhonePoolService manages the phone pools of a user and picks the phone which sends a message
+type PhonePoolService struct {
+	service
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	repository         repositories.PhonePoolRepository
+	phoneService       *PhoneService
+	monitorRepository  repositories.HeartbeatMonitorRepository
+	messageRepository  repositories.MessageRepository
+	suppressionService *SuppressionService
+}
+
+// NewPhonePoolService creates a new PhonePoolService
+func NewPhonePoolService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.PhonePoolRepository,
+	phoneService *PhoneService,
+	monitorRepository repositories.HeartbeatMonitorRepository,
+	messageRepository repositories.MessageRepository,
+	suppressionService *SuppressionService,
+) (s *PhonePoolService) {
+	return &PhonePoolService{
+		logger:             logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:             tracer,
+		repository:         repository,
+		phoneService:       phoneService,
+		monitorRepository:  monitorRepository,
+		messageRepository:  messageRepository,
+		suppressionService: suppressionService,
+	}
+}
+
+// PhonePoolUpsertParams are parameters for creating or updating an entities.PhonePool
+type PhonePoolUpsertParams struct {
+	UserID       entities.UserID
+	Name         string
+	PhoneNumbers []string
+}
+
+// Index fetches the entities.PhonePool of a user
+func (service *PhonePoolService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.PhonePool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	pools, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch phone pools for user [%s] with params [%+#v]", userID, params))
+	}
+
+	return pools, nil
+}
+
+// Load an entities.PhonePool of a user
+func (service *PhonePoolService) Load(ctx context.Context, userID entities.UserID, poolID uuid.UUID) (*entities.PhonePool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	pool, err := service.repository.Load(ctx, userID, poolID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load phone pool [%s] for user [%s]", poolID, userID))
+	}
+
+	return pool, nil
+}
+
+// Store a new entities.PhonePool
+func (service *PhonePoolService) Store(ctx context.Context, params *PhonePoolUpsertParams) (*entities.PhonePool, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	pool := &entities.PhonePool{
+		ID:           uuid.New(),
+		UserID:       params.UserID,
+		Name:         params.Name,
+		PhoneNumbers: pq.StringArray(params.PhoneNumbers),
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err := service.repository.Store(ctx, pool); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save phone pool for user [%s]", params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("phone pool saved with id [%s] for user [%s]", pool.ID, pool.UserID))
+	return pool, nil
+}
+
+// Update an existing entities.PhonePool
+func (service *PhonePoolService) Update(ctx context.Context, poolID uuid.UUID, params *PhonePoolUpsertParams) (*entities.PhonePool, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	pool, err := service.repository.Load(ctx, params.UserID, poolID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load phone pool [%s] for user [%s]", poolID, params.UserID))
+	}
+
+	pool.Name = params.Name
+	pool.PhoneNumbers = pq.StringArray(params.PhoneNumbers)
+	pool.UpdatedAt = time.Now().UTC()
+
+	if err = service.repository.Update(ctx, pool); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot update phone pool [%s] for user [%s]", poolID, params.UserID))
+	}
+
+	return pool, nil
+}
+
+// Delete an entities.PhonePool
+func (service *PhonePoolService) Delete(ctx context.Context, userID entities.UserID, poolID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, poolID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load phone pool [%s] for user [%s]", poolID, userID))
+	}
+
+	if err := service.repository.Delete(ctx, userID, poolID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete phone pool [%s] for user [%s]", poolID, userID))
+	}
+
+	return nil
+}
+
+// RemovePhone removes a phone number from all the entities.PhonePool of a user
+func (service *PhonePoolService) RemovePhone(ctx context.Context, userID entities.UserID, phoneNumber string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.repository.RemovePhone(ctx, userID, phoneNumber); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot remove phone [%s] from the phone pools of user [%s]", phoneNumber, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.PhonePool for an entities.UserID.
+func (service *PhonePoolService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.PhonePool] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.PhonePool] for user with ID [%s]", userID))
+	return nil
+}
+
+// phonePoolCandidate is a phone in an entities.PhonePool which can send a message
+type phonePoolCandidate struct {
+	Owner             string
+	Online            bool
+	QueueDepth        int
+	MessagesPerMinute uint
+}
+
+// load is the number of minutes needed by the phone to send its outstanding messages,
+// a phone without a rate limit sends its queue immediately so it has no load and ties are broken by the queue depth
+func (candidate phonePoolCandidate) load() float64 {
+	if candidate.MessagesPerMinute == 0 {
+		return 0
+	}
+	return float64(candidate.QueueDepth) / float64(candidate.MessagesPerMinute)
+}
+
+// SelectPhone picks the phone in an entities.PhonePool which should send a message to the contact, only the phones which can be used by the entities.AuthContext are selected
+func (service *PhonePoolService) SelectPhone(ctx context.Context, authUser entities.AuthContext, poolID uuid.UUID, contact string) (*entities.Phone, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	userID := authUser.ID
+
+	pool, err := service.repository.Load(ctx, userID, poolID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load phone pool [%s] for user [%s]", poolID, userID))
+	}
+
+	phones := make(map[string]*entities.Phone, len(pool.PhoneNumbers))
+	for _, phoneNumber := range pool.PhoneNumbers {
+		if !authUser.CanUsePhoneNumber(phoneNumber) {
+			ctxLogger.Info(fmt.Sprintf("skipping phone [%s] in pool [%s] because it cannot be used with the API key [%s]", phoneNumber, poolID, authUser.UserAPIKeyID))
+			continue
+		}
+
+		phone, err := service.phoneService.Load(ctx, userID, phoneNumber)
+		if err != nil {
+			ctxLogger.Warn(stacktrace.Propagatef(err, "cannot load phone [%s] in pool [%s] for user [%s]", phoneNumber, poolID, userID))
+			continue
+		}
+
+		suppressed, err := service.suppressionService.SuppressedContacts(ctx, userID, phone.PhoneNumber, []string{contact})
+		if err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot check if contact [%s] opted out of phone [%s] in pool [%s]", contact, phone.PhoneNumber, poolID))
+		}
+
+		if len(suppressed) > 0 {
+			ctxLogger.Info(fmt.Sprintf("skipping phone [%s] in pool [%s] because the contact [%s] opted out of receiving messages from it", phone.PhoneNumber, poolID, contact))
+			continue
+		}
+		phones[phone.PhoneNumber] = phone
+	}
+
+	if len(phones) == 0 {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "phone pool [%s] of user [%s] has no phones which can send messages to [%s]", poolID, userID, contact))
+	}
+
+	owners := make([]string, 0, len(phones))
+	for owner := range phones {
+		owners = append(owners, owner)
+	}
+
+	counts, err := service.messageRepository.CountOutstanding(ctx, userID, owners)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot count outstanding messages for phone pool [%s]", poolID))
+		counts = map[string]int{}
+	}
+
+	candidates := make([]phonePoolCandidate, 0, len(phones))
+	for owner, phone := range phones {
+		candidates = append(candidates, phonePoolCandidate{
+			Owner:             owner,
+			Online:            service.phoneOnline(ctx, ctxLogger, userID, owner),
+			QueueDepth:        counts[owner],
+			MessagesPerMinute: phone.MessagesPerMinute,
+		})
+	}
+
+	affinity := ""
+	if message, err := service.messageRepository.LastMessageWithOwners(ctx, userID, owners, contact); err == nil {
+		affinity = message.Owner
+	} else if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load the last message with contact [%s] for phone pool [%s]", contact, poolID))
+	}
+
+	owner := selectPoolPhone(candidates, affinity)
+	ctxLogger.Info(fmt.Sprintf("selected phone [%s] from pool [%s] to send a message to [%s] for user [%s]", owner, poolID, contact, userID))
+	return phones[owner], nil
+}
+
+// phoneOnline returns false when the phone has no heartbeat monitor because its status is unknown
+func (service *PhonePoolService) phoneOnline(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, owner string) bool {
+	monitor, err := service.monitorRepository.Load(ctx, userID, owner)
+	if err != nil {
+		if stacktrace.GetCode(err) != repositories.ErrCodeNotFound {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot load heartbeat monitor for phone [%s] of user [%s]", owner, userID))
+		}
+		return false
+	}
+	return monitor.PhoneOnline
+}
+
+// selectPoolPhone picks the owner which should send the message.
+// Online phones are preferred, the contact stays on the affinity phone while it can send within a minute,
+// otherwise the phone with the shortest queue relative to its messages per minute is selected.
+func selectPoolPhone(candidates []phonePoolCandidate, affinity string) string {
+	online := make([]phonePoolCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Online {
+			online = append(online, candidate)
+		}
+	}
+	if len(online) > 0 {
+		candidates = online
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Owner == affinity && candidate.load() < 1 {
+			return candidate.Owner
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].load() != candidates[j].load() {
+			return candidates[i].load() < candidates[j].load()
+		}
+		if candidates[i].QueueDepth != candidates[j].QueueDepth {
+			return candidates[i].QueueDepth < candidates[j].QueueDepth
+		}
+		return candidates[i].Owner < candidates[j].Owner
+	})
+
+	return candidates[0].Owner
+}
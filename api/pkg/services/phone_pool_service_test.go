@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type heartbeatMonitorRepositoryStub struct {
+	repositories.HeartbeatMonitorRepository
+	monitors map[string]*entities.HeartbeatMonitor
+}
+
+func (stub *heartbeatMonitorRepositoryStub) Load(_ context.Context, _ entities.UserID, owner string) (*entities.HeartbeatMonitor, error) {
+	if monitor, ok := stub.monitors[owner]; ok {
+		return monitor, nil
+	}
+	return nil, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "monitor not found")
+}
+
+func TestSelectPoolPhone_PrefersOnlinePhones(t *testing.T) {
+	candidates := []phonePoolCandidate{
+		{Owner: "+18005550100", Online: false, QueueDepth: 0, MessagesPerMinute: 10},
+		{Owner: "+18005550101", Online: true, QueueDepth: 50, MessagesPerMinute: 10},
+	}
+
+	assert.Equal(t, "+18005550101", selectPoolPhone(candidates, ""))
+}
+
+func TestSelectPoolPhone_UsesAllPhonesWhenNoneIsOnline(t *testing.T) {
+	candidates := []phonePoolCandidate{
+		{Owner: "+18005550100", Online: false, QueueDepth: 5, MessagesPerMinute: 10},
+		{Owner: "+18005550101", Online: false, QueueDepth: 1, MessagesPerMinute: 10},
+	}
+
+	assert.Equal(t, "+18005550101", selectPoolPhone(candidates, ""))
+}
+
+func TestSelectPoolPhone_KeepsContactAffinityWhileThePhoneHasHeadroom(t *testing.T) {
+	candidates := []phonePoolCandidate{
+		{Owner: "+18005550100", Online: true, QueueDepth: 0, MessagesPerMinute: 10},
+		{Owner: "+18005550101", Online: true, QueueDepth: 5, MessagesPerMinute: 10},
+	}
+
+	assert.Equal(t, "+18005550101", selectPoolPhone(candidates, "+18005550101"))
+}
+
+func TestSelectPoolPhone_BreaksAffinityWhenThePhoneIsSaturated(t *testing.T) {
+	candidates := []phonePoolCandidate{
+		{Owner: "+18005550100", Online: true, QueueDepth: 2, MessagesPerMinute: 10},
+		{Owner: "+18005550101", Online: true, QueueDepth: 30, MessagesPerMinute: 10},
+	}
+
+	assert.Equal(t, "+18005550100", selectPoolPhone(candidates, "+18005550101"))
+}
+
+func TestSelectPoolPhone_UsesMessagesPerMinuteHeadroom(t *testing.T) {
+	candidates := []phonePoolCandidate{
+		{Owner: "+18005550100", Online: true, QueueDepth: 10, MessagesPerMinute: 5},
+		{Owner: "+18005550101", Online: true, QueueDepth: 20, MessagesPerMinute: 40},
+	}
+
+	assert.Equal(t, "+18005550101", selectPoolPhone(candidates, ""))
+}
+
+func TestSelectPoolPhone_PrefersPhonesWithoutRateLimit(t *testing.T) {
+	candidates := []phonePoolCandidate{
+		{Owner: "+18005550100", Online: true, QueueDepth: 2, MessagesPerMinute: 60},
+		{Owner: "+18005550101", Online: true, QueueDepth: 10, MessagesPerMinute: 0},
+		{Owner: "+18005550102", Online: true, QueueDepth: 5, MessagesPerMinute: 0},
+	}
+
+	assert.Equal(t, "+18005550102", selectPoolPhone(candidates, ""))
+}
+
+type phonePoolRepositoryStub struct {
+	repositories.PhonePoolRepository
+	pool *entities.PhonePool
+}
+
+func (stub *phonePoolRepositoryStub) Load(_ context.Context, _ entities.UserID, _ uuid.UUID) (*entities.PhonePool, error) {
+	return stub.pool, nil
+}
+
+type phonePoolMessageRepositoryStub struct {
+	repositories.MessageRepository
+	counts map[string]int
+}
+
+func (stub *phonePoolMessageRepositoryStub) CountOutstanding(_ context.Context, _ entities.UserID, _ []string) (map[string]int, error) {
+	return stub.counts, nil
+}
+
+func (stub *phonePoolMessageRepositoryStub) LastMessageWithOwners(_ context.Context, _ entities.UserID, _ []string, _ string) (*entities.Message, error) {
+	return nil, stacktrace.NewErrorWithCodef(repositories.ErrCodeNotFound, "message not found")
+}
+
+func newPhonePoolServiceForTest(pool *entities.PhonePool, counts map[string]int) *PhonePoolService {
+	logger := &noopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	phones := &phoneRepositoryStub{phones: map[string]*entities.Phone{}}
+	for _, phoneNumber := range pool.PhoneNumbers {
+		phones.phones[phoneNumber] = &entities.Phone{PhoneNumber: phoneNumber, MessagesPerMinute: 10}
+	}
+
+	return NewPhonePoolService(
+		logger,
+		tracer,
+		&phonePoolRepositoryStub{pool: pool},
+		NewPhoneService(logger, tracer, phones, nil),
+		&heartbeatMonitorRepositoryStub{},
+		&phonePoolMessageRepositoryStub{counts: counts},
+		newSuppressionServiceForTest(&suppressionRepositoryStub{}),
+	)
+}
+
+func TestPhonePoolServiceSelectPhone_OnlySelectsThePhonesOfTheAPIKey(t *testing.T) {
+	apiKeyID := uuid.New()
+	pool := &entities.PhonePool{ID: uuid.New(), PhoneNumbers: pq.StringArray{"+18005550100", "+18005550101"}}
+	service := newPhonePoolServiceForTest(pool, map[string]int{"+18005550100": 0, "+18005550101": 50})
+	authUser := entities.AuthContext{ID: "user-id", UserAPIKeyID: &apiKeyID, PhoneNumbers: []string{"+18005550101"}}
+
+	phone, err := service.SelectPhone(context.Background(), authUser, pool.ID, "+18005550199")
+
+	require.NoError(t, err)
+	assert.Equal(t, "+18005550101", phone.PhoneNumber)
+}
+
+func TestPhonePoolServiceSelectPhone_ReturnsNotFoundWhenTheAPIKeyCannotUseThePool(t *testing.T) {
+	apiKeyID := uuid.New()
+	pool := &entities.PhonePool{ID: uuid.New(), PhoneNumbers: pq.StringArray{"+18005550100"}}
+	service := newPhonePoolServiceForTest(pool, map[string]int{})
+	authUser := entities.AuthContext{ID: "user-id", UserAPIKeyID: &apiKeyID, PhoneNumbers: []string{"+18005550101"}}
+
+	_, err := service.SelectPhone(context.Background(), authUser, pool.ID, "+18005550199")
+
+	assert.Equal(t, repositories.ErrCodeNotFound, stacktrace.GetCode(err))
+}
+
+func TestPhonePoolServicePhoneOnline_IsFalseWithoutHeartbeatMonitor(t *testing.T) {
+	logger := &noopLogger{}
+	service := &PhonePoolService{monitorRepository: &heartbeatMonitorRepositoryStub{
+		monitors: map[string]*entities.HeartbeatMonitor{"+18005550100": {PhoneOnline: true}},
+	}}
+
+	assert.True(t, service.phoneOnline(context.Background(), logger, "user-id", "+18005550100"))
+	assert.False(t, service.phoneOnline(context.Background(), logger, "user-id", "+18005550101"))
+}
@@ -75,13 +75,21 @@ func (service *PhoneService) Index(ctx context.Context, authUser entities.AuthCo
 
 	ctxLogger := service.tracer.CtxLogger(service.logger, span)
 
+	params.OrganisationID = authUser.OrganisationID
 	phones, err := service.repository.Index(ctx, authUser.ID, params)
 	if err != nil {
 		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch phones with parms [%+#v]", params))
 	}
 
-	ctxLogger.Info(fmt.Sprintf("fetched [%d] phones with prams [%+#v]", len(*phones), params))
-	return phones, nil
+	allowed := make([]entities.Phone, 0, len(*phones))
+	for _, phone := range *phones {
+		if authUser.CanUsePhoneNumber(phone.PhoneNumber) {
+			allowed = append(allowed, phone)
+		}
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] phones with prams [%+#v]", len(allowed), params))
+	return &allowed, nil
 }
 
 // Load a phone by userID and owner
@@ -92,6 +100,14 @@ func (service *PhoneService) Load(ctx context.Context, userID entities.UserID, o
 	return service.repository.Load(ctx, userID, owner)
 }
 
+// LoadByID a phone by userID and phoneID
+func (service *PhoneService) LoadByID(ctx context.Context, userID entities.UserID, phoneID uuid.UUID) (*entities.Phone, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	return service.repository.LoadByID(ctx, userID, phoneID)
+}
+
 // PhoneUpsertParams are parameters for creating a new entities.Phone
 type PhoneUpsertParams struct {
 	PhoneNumber               *phonenumbers.PhoneNumber
@@ -102,10 +118,12 @@ type PhoneUpsertParams struct {
 	MessageExpirationDuration *time.Duration
 	MissedCallAutoReply       *string
 	UnarchiveThread           *bool
+	FallbackPhoneNumbers      *[]string
 	SIM                       entities.SIM
 	MessageSendScheduleID     *uuid.UUID
 	Source                    string
 	UserID                    entities.UserID
+	ActorID                   *entities.UserID
 }
 
 // Upsert a new entities.Phone
@@ -125,6 +143,7 @@ func (service *PhoneService) Upsert(ctx context.Context, params *PhoneUpsertPara
 			FcmToken:              params.FcmToken,
 			SIM:                   params.SIM,
 			MessageSendScheduleID: params.MessageSendScheduleID,
+			ActorID:               params.ActorID,
 		})
 	}
 
@@ -215,6 +234,7 @@ type PhoneFCMTokenParams struct {
 	FcmToken              *string
 	SIM                   entities.SIM
 	MessageSendScheduleID *uuid.UUID
+	ActorID               *entities.UserID
 }
 
 // UpsertFCMToken the FCM token for an entities.Phone
@@ -258,6 +278,7 @@ func (service *PhoneService) createPhone(ctx context.Context, params *PhoneFCMTo
 		SIM:                      params.SIM,
 		MissedCallAutoReply:      nil,
 		MessageSendScheduleID:    params.MessageSendScheduleID,
+		ActorID:                  params.ActorID,
 		PhoneNumber:              phonenumbers.Format(params.PhoneNumber, phonenumbers.E164),
 		CreatedAt:                time.Now().UTC(),
 		UpdatedAt:                time.Now().UTC(),
@@ -303,8 +324,13 @@ func (service *PhoneService) update(phone *entities.Phone, params *PhoneUpsertPa
 		phone.UnarchiveThread = *params.UnarchiveThread
 	}
 
+	if params.FallbackPhoneNumbers != nil {
+		phone.FallbackPhoneNumbers = *params.FallbackPhoneNumbers
+	}
+
 	phone.SIM = params.SIM
 	phone.MessageSendScheduleID = params.MessageSendScheduleID
+	phone.ActorID = params.ActorID
 
 	return phone
 }
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+)
+
+const (
+	// retentionUserBatchSize is the number of entities.User with a retention setting which are loaded at the same time
+	retentionUserBatchSize = 100
+
+	// retentionPurgeBatchSize is the number of rows which are deleted in a single query
+	retentionPurgeBatchSize = 500
+)
+
+// RetentionService deletes the data of users which is older than their retention settings
+type RetentionService struct {
+	service
+	logger                      telemetry.Logger
+	tracer                      telemetry.Tracer
+	userRepository              repositories.UserRepository
+	messageRepository           repositories.MessageRepository
+	heartbeatRepository         repositories.HeartbeatRepository
+	phoneNotificationRepository repositories.PhoneNotificationRepository
+	attachmentRepository        repositories.AttachmentRepository
+	threadService               *MessageThreadService
+	apiBaseURL                  string
+}
+
+// NewRetentionService creates a new RetentionService
+func NewRetentionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	userRepository repositories.UserRepository,
+	messageRepository repositories.MessageRepository,
+	heartbeatRepository repositories.HeartbeatRepository,
+	phoneNotificationRepository repositories.PhoneNotificationRepository,
+	attachmentRepository repositories.AttachmentRepository,
+	threadService *MessageThreadService,
+	apiBaseURL string,
+) (s *RetentionService) {
+	return &RetentionService{
+		logger:                      logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:                      tracer,
+		userRepository:              userRepository,
+		messageRepository:           messageRepository,
+		heartbeatRepository:         heartbeatRepository,
+		phoneNotificationRepository: phoneNotificationRepository,
+		attachmentRepository:        attachmentRepository,
+		threadService:               threadService,
+		apiBaseURL:                  apiBaseURL,
+	}
+}
+
+// Purge deletes the data which is older than the retention settings of every entities.User
+func (service *RetentionService) Purge(ctx context.Context) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	afterID := entities.UserID("")
+	for {
+		users, err := service.userRepository.IndexWithRetention(ctx, afterID, retentionUserBatchSize)
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot fetch users with retention after ID [%s]", afterID))
+		}
+
+		for _, user := range users {
+			if err = service.PurgeUser(ctx, user, time.Now().UTC()); err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot purge the data of user [%s]", user.ID))
+			}
+		}
+
+		if len(users) < retentionUserBatchSize {
+			return nil
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// PurgeUser deletes the data of an entities.User which is older than the retention settings at the given time
+func (service *RetentionService) PurgeUser(ctx context.Context, user *entities.User, now time.Time) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if cutoff := user.DataRetentionCutoff(now); cutoff != nil {
+		count, err := service.deleteMessages(ctx, ctxLogger, user.ID, *cutoff)
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete messages before [%s] for user [%s]", cutoff, user.ID))
+		}
+
+		heartbeats, err := service.deleteInBatches(func() (int64, error) {
+			return service.heartbeatRepository.DeleteBefore(ctx, user.ID, *cutoff, retentionPurgeBatchSize)
+		})
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete heartbeats before [%s] for user [%s]", cutoff, user.ID))
+		}
+
+		notifications, err := service.deleteInBatches(func() (int64, error) {
+			return service.phoneNotificationRepository.DeleteBefore(ctx, user.ID, *cutoff, retentionPurgeBatchSize)
+		})
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete phone notifications before [%s] for user [%s]", cutoff, user.ID))
+		}
+
+		ctxLogger.Info(fmt.Sprintf("deleted [%d] messages, [%d] heartbeats and [%d] phone notifications before [%s] for user [%s]", count, heartbeats, notifications, cutoff, user.ID))
+	}
+
+	if cutoff := user.MessageContentRetentionCutoff(now); cutoff != nil {
+		count, err := service.removeMessageContent(ctx, ctxLogger, user.ID, *cutoff)
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot remove message content before [%s] for user [%s]", cutoff, user.ID))
+		}
+		ctxLogger.Info(fmt.Sprintf("removed the content of [%d] messages before [%s] for user [%s]", count, cutoff, user.ID))
+	}
+
+	return nil
+}
+
+func (service *RetentionService) deleteInBatches(deleteBatch func() (int64, error)) (int64, error) {
+	var total int64
+	for {
+		count, err := deleteBatch()
+		if err != nil {
+			return total, err
+		}
+
+		total += count
+		if count < retentionPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (service *RetentionService) deleteMessages(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, cutoff time.Time) (int, error) {
+	total := 0
+	for {
+		messages, err := service.messageRepository.FetchBefore(ctx, userID, cutoff, false, retentionPurgeBatchSize)
+		if err != nil {
+			return total, stacktrace.Propagatef(err, "cannot fetch messages before [%s]", cutoff)
+		}
+
+		if len(messages) == 0 {
+			return total, nil
+		}
+
+		service.deleteAttachments(ctx, ctxLogger, messages)
+		if err = service.messageRepository.DeleteByIDs(ctx, userID, service.messageIDs(messages)); err != nil {
+			return total, stacktrace.Propagatef(err, "cannot delete [%d] messages", len(messages))
+		}
+
+		for _, message := range service.lastMessagePerThread(messages) {
+			payload := &events.MessageAPIDeletedPayload{
+				MessageID: message.ID,
+				UserID:    message.UserID,
+				Owner:     message.Owner,
+				Contact:   message.Contact,
+				Timestamp: time.Now().UTC(),
+			}
+
+			if previous, err := service.messageRepository.LastMessage(ctx, userID, message.Owner, message.Contact); err == nil {
+				payload.PreviousMessageID = &previous.ID
+				payload.PreviousMessageStatus = &previous.Status
+				payload.PreviousMessageContent = &previous.Content
+			}
+
+			if err = service.threadService.UpdateAfterDeletedMessage(ctx, payload); err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot update thread with owner [%s] and contact [%s] after deleting message [%s]", message.Owner, message.Contact, message.ID))
+			}
+		}
+
+		total += len(messages)
+		if len(messages) < retentionPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (service *RetentionService) removeMessageContent(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, cutoff time.Time) (int, error) {
+	total := 0
+	for {
+		messages, err := service.messageRepository.FetchBefore(ctx, userID, cutoff, true, retentionPurgeBatchSize)
+		if err != nil {
+			return total, stacktrace.Propagatef(err, "cannot fetch messages with content before [%s]", cutoff)
+		}
+
+		if len(messages) == 0 {
+			return total, nil
+		}
+
+		service.deleteAttachments(ctx, ctxLogger, messages)
+		if err = service.messageRepository.RemoveContent(ctx, userID, service.messageIDs(messages)); err != nil {
+			return total, stacktrace.Propagatef(err, "cannot remove the content of [%d] messages", len(messages))
+		}
+
+		// the thread keeps a copy of the content of the last message
+		for _, message := range service.lastMessagePerThread(messages) {
+			content := ""
+			err = service.threadService.UpdateAfterDeletedMessage(ctx, &events.MessageAPIDeletedPayload{
+				MessageID:              message.ID,
+				UserID:                 message.UserID,
+				Owner:                  message.Owner,
+				Contact:                message.Contact,
+				Timestamp:              time.Now().UTC(),
+				PreviousMessageID:      &message.ID,
+				PreviousMessageStatus:  &message.Status,
+				PreviousMessageContent: &content,
+			})
+			if err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot update thread with owner [%s] and contact [%s] after removing the content of message [%s]", message.Owner, message.Contact, message.ID))
+			}
+		}
+
+		total += len(messages)
+		if len(messages) < retentionPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// deleteAttachments removes the attachments which were uploaded to the AttachmentRepository for the messages
+func (service *RetentionService) deleteAttachments(ctx context.Context, ctxLogger telemetry.Logger, messages []*entities.Message) {
+	prefix := service.apiBaseURL + "/v1/"
+	for _, message := range messages {
+		for _, attachment := range message.Attachments {
+			if !strings.HasPrefix(attachment, prefix) {
+				continue
+			}
+
+			path := strings.TrimPrefix(attachment, prefix)
+			if err := service.attachmentRepository.Delete(ctx, path); err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete attachment [%s] of message [%s]", path, message.ID))
+			}
+		}
+	}
+}
+
+// lastMessagePerThread returns the newest message for each owner and contact in a list of messages with the oldest message first
+func (service *RetentionService) lastMessagePerThread(messages []*entities.Message) map[string]*entities.Message {
+	result := make(map[string]*entities.Message)
+	for _, message := range messages {
+		result[message.Owner+"|"+message.Contact] = message
+	}
+	return result
+}
+
+func (service *RetentionService) messageIDs(messages []*entities.Message) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
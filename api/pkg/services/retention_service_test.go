@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionServiceDeleteAttachmentsOnlyDeletesUploadedFiles(t *testing.T) {
+	logger := &noopLogger{}
+	tracer := telemetry.NewOtelLogger("test", logger)
+	attachments := repositories.NewMemoryAttachmentRepository(logger, tracer)
+	service := NewRetentionService(logger, tracer, nil, nil, nil, nil, attachments, nil, "https://api.httpsms.com")
+
+	ctx := context.Background()
+	require.NoError(t, attachments.Upload(ctx, "attachments/user/message/0/photo.jpg", []byte("photo"), "image/jpeg"))
+	require.NoError(t, attachments.Upload(ctx, "external.jpg", []byte("external"), "image/jpeg"))
+
+	service.deleteAttachments(ctx, logger, []*entities.Message{{
+		ID: uuid.New(),
+		Attachments: []string{
+			"https://api.httpsms.com/v1/attachments/user/message/0/photo.jpg",
+			"https://example.com/external.jpg",
+		},
+	}})
+
+	_, err := attachments.Download(ctx, "attachments/user/message/0/photo.jpg")
+	assert.Error(t, err)
+
+	_, err = attachments.Download(ctx, "external.jpg")
+	assert.NoError(t, err)
+}
+
+func TestRetentionServiceLastMessagePerThread(t *testing.T) {
+	service := &RetentionService{}
+	older := &entities.Message{ID: uuid.New(), Owner: "+18005550199", Contact: "+18005550100"}
+	newer := &entities.Message{ID: uuid.New(), Owner: "+18005550199", Contact: "+18005550100"}
+	other := &entities.Message{ID: uuid.New(), Owner: "+18005550199", Contact: "+18005550101"}
+
+	result := service.lastMessagePerThread([]*entities.Message{older, other, newer})
+
+	assert.Len(t, result, 2)
+	assert.Equal(t, newer, result["+18005550199|+18005550100"])
+	assert.Equal(t, other, result["+18005550199|+18005550101"])
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	// messageQueueHorizon is the longest delay which is put directly on the push queue
+	messageQueueHorizon = 480 * time.Hour
+
+	// scheduledMessageSweepLimit is the number of entities.ScheduledMessage enqueued in a single batch
+	scheduledMessageSweepLimit = 100
+)
+
+// scheduledMessageContentEvents are the events which are persisted without the message content, the content is loaded
+// from the entities.Message when the event is enqueued so that it is not stored in plaintext.
+var scheduledMessageContentEvents = map[string]bool{
+	events.EventTypeMessageAPISent: true,
+	events.MessageAPIRescheduled:   true,
+}
+
+// ScheduledMessageService persists message events which are scheduled beyond the push queue horizon
+type ScheduledMessageService struct {
+	service
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	repository        repositories.ScheduledMessageRepository
+	messageRepository repositories.MessageRepository
+	eventDispatcher   *EventDispatcher
+}
+
+// NewScheduledMessageService creates a new ScheduledMessageService
+func NewScheduledMessageService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.ScheduledMessageRepository,
+	messageRepository repositories.MessageRepository,
+	eventDispatcher *EventDispatcher,
+) (s *ScheduledMessageService) {
+	return &ScheduledMessageService{
+		logger:            logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:            tracer,
+		repository:        repository,
+		messageRepository: messageRepository,
+		eventDispatcher:   eventDispatcher,
+	}
+}
+
+// ScheduledMessageDispatchParams are parameters for dispatching a message event at a time
+type ScheduledMessageDispatchParams struct {
+	UserID    entities.UserID
+	MessageID uuid.UUID
+	Event     cloudevents.Event
+	SendAt    time.Time
+	Timeout   time.Duration
+}
+
+// Dispatch enqueues the event with the timeout when the send time is inside the queue horizon, otherwise the event is persisted
+// as an entities.ScheduledMessage until the Sweep picks it up.
+func (service *ScheduledMessageService) Dispatch(ctx context.Context, params *ScheduledMessageDispatchParams) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if !isBeyondQueueHorizon(params.SendAt) {
+		if _, err := service.eventDispatcher.DispatchWithTimeout(ctx, params.Event, params.Timeout); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot dispatch event type [%s] and id [%s]", params.Event.Type(), params.Event.ID()))
+		}
+		return nil
+	}
+
+	event := params.Event
+	if scheduledMessageContentEvents[event.Type()] {
+		var err error
+		if event, err = setScheduledMessageContent(event, nil); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot remove content from event [%s] with id [%s]", params.Event.Type(), params.Event.ID()))
+		}
+	}
+
+	content, err := json.Marshal(event)
+	if err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot marshal event [%s] with id [%s]", params.Event.Type(), params.Event.ID()))
+	}
+
+	message := &entities.ScheduledMessage{
+		ID:        uuid.New(),
+		UserID:    params.UserID,
+		MessageID: params.MessageID,
+		EventType: params.Event.Type(),
+		Event:     string(content),
+		SendAt:    params.SendAt.UTC(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if err = service.repository.Store(ctx, message); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot store [%s] event for message [%s]", params.Event.Type(), params.MessageID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("event [%s] for message [%s] is scheduled at [%s] beyond the queue horizon", params.Event.Type(), params.MessageID, message.SendAt))
+	return nil
+}
+
+// Sweep enqueues the entities.ScheduledMessage which are now inside the queue horizon
+func (service *ScheduledMessageService) Sweep(ctx context.Context) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	count := 0
+	for {
+		messages, err := service.repository.Claim(ctx, time.Now().UTC().Add(messageQueueHorizon), scheduledMessageSweepLimit)
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot claim scheduled messages"))
+		}
+
+		for _, message := range messages {
+			if err = service.enqueue(ctx, message); err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot enqueue scheduled message [%s] for message [%s], it will be claimed again", message.ID, message.MessageID))
+				continue
+			}
+
+			if err = service.repository.Delete(ctx, message); err != nil {
+				ctxLogger.Error(stacktrace.Propagatef(err, "cannot delete enqueued scheduled message [%s] for message [%s]", message.ID, message.MessageID))
+			}
+			count++
+		}
+
+		if len(messages) < scheduledMessageSweepLimit {
+			break
+		}
+	}
+
+	if count > 0 {
+		ctxLogger.Info(fmt.Sprintf("enqueued [%d] scheduled messages", count))
+	}
+	return nil
+}
+
+func (service *ScheduledMessageService) enqueue(ctx context.Context, message *entities.ScheduledMessage) error {
+	event := cloudevents.NewEvent()
+	if err := json.Unmarshal([]byte(message.Event), &event); err != nil {
+		return stacktrace.Propagatef(err, "cannot unmarshal [%s] event for message [%s]", message.EventType, message.MessageID)
+	}
+
+	if scheduledMessageContentEvents[event.Type()] {
+		sent, err := service.messageRepository.Load(ctx, message.UserID, message.MessageID)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			service.logger.Info(fmt.Sprintf("message [%s] of [%s] event was deleted before the event was enqueued", message.MessageID, event.Type()))
+			return nil
+		}
+		if err != nil {
+			return stacktrace.Propagatef(err, "cannot load message [%s] for user [%s] of [%s] event", message.MessageID, message.UserID, event.Type())
+		}
+
+		if event, err = setScheduledMessageContent(event, &sent.Content); err != nil {
+			return stacktrace.Propagatef(err, "cannot set content of [%s] event for message [%s]", event.Type(), message.MessageID)
+		}
+	}
+
+	timeout := message.SendAt.Sub(time.Now().UTC())
+	if timeout <= 0 {
+		timeout = time.Nanosecond
+	}
+
+	if _, err := service.eventDispatcher.DispatchWithTimeout(ctx, event, timeout); err != nil {
+		return stacktrace.Propagatef(err, "cannot dispatch event [%s] with id [%s] for message [%s]", event.Type(), event.ID(), message.MessageID)
+	}
+	return nil
+}
+
+// DeleteByMessageID deletes the entities.ScheduledMessage of a message, all event types are deleted if eventType is empty
+func (service *ScheduledMessageService) DeleteByMessageID(ctx context.Context, userID entities.UserID, messageID uuid.UUID, eventType string) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if err := service.repository.DeleteByMessageID(ctx, userID, messageID, eventType); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete scheduled messages for message [%s] and user [%s]", messageID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.ScheduledMessage for an entities.UserID.
+func (service *ScheduledMessageService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.ScheduledMessage] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.ScheduledMessage] for user with ID [%s]", userID))
+	return nil
+}
+
+// isBeyondQueueHorizon checks if a send time is too far in the future to be put directly on the push queue
+func isBeyondQueueHorizon(sendAt time.Time) bool {
+	return sendAt.After(time.Now().UTC().Add(messageQueueHorizon))
+}
+
+// setScheduledMessageContent returns a copy of the event with the content field of the payload, the field is removed when content is nil
+func setScheduledMessageContent(event cloudevents.Event, content *string) (cloudevents.Event, error) {
+	payload := map[string]json.RawMessage{}
+	if err := event.DataAs(&payload); err != nil {
+		return event, stacktrace.Propagatef(err, "cannot decode payload of event [%s] with id [%s]", event.Type(), event.ID())
+	}
+
+	delete(payload, "content")
+	if content != nil {
+		value, err := json.Marshal(*content)
+		if err != nil {
+			return event, stacktrace.Propagatef(err, "cannot encode content of event [%s] with id [%s]", event.Type(), event.ID())
+		}
+		payload["content"] = value
+	}
+
+	event = event.Clone()
+	if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return event, stacktrace.Propagatef(err, "cannot encode payload of event [%s] with id [%s]", event.Type(), event.ID())
+	}
+	return event, nil
+}
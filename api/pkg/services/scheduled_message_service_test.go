@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBeyondQueueHorizon_InsideHorizon_ReturnsFalse(t *testing.T) {
+	assert.False(t, isBeyondQueueHorizon(time.Now().UTC().Add(24*time.Hour)))
+	assert.False(t, isBeyondQueueHorizon(time.Now().UTC().Add(-time.Hour)))
+}
+
+func TestIsBeyondQueueHorizon_OutsideHorizon_ReturnsTrue(t *testing.T) {
+	assert.True(t, isBeyondQueueHorizon(time.Now().UTC().Add(messageQueueHorizon+time.Hour)))
+	assert.True(t, isBeyondQueueHorizon(time.Now().UTC().AddDate(1, 0, 0)))
+}
+
+type scheduledMessageRepositoryStub struct {
+	repositories.ScheduledMessageRepository
+	claimable []*entities.ScheduledMessage
+	deleted   []*entities.ScheduledMessage
+	stored    []*entities.ScheduledMessage
+}
+
+func (stub *scheduledMessageRepositoryStub) Store(_ context.Context, message *entities.ScheduledMessage) error {
+	stub.stored = append(stub.stored, message)
+	return nil
+}
+
+func (stub *scheduledMessageRepositoryStub) Claim(_ context.Context, _ time.Time, _ int) ([]*entities.ScheduledMessage, error) {
+	messages := stub.claimable
+	stub.claimable = nil
+	return messages, nil
+}
+
+func (stub *scheduledMessageRepositoryStub) Delete(_ context.Context, message *entities.ScheduledMessage) error {
+	stub.deleted = append(stub.deleted, message)
+	return nil
+}
+
+func (stub *scheduledMessageRepositoryStub) DeleteByMessageID(_ context.Context, _ entities.UserID, _ uuid.UUID, _ string) error {
+	return nil
+}
+
+func TestScheduledMessageServiceSweep_DeletesOnlyTheEnqueuedMessages(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetSource("/v1/messages/send")
+	event.SetType(events.EventTypeMessageSendExpiredCheck)
+	content, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	enqueued := &entities.ScheduledMessage{ID: uuid.New(), MessageID: uuid.New(), Event: string(content), SendAt: time.Now().UTC().Add(time.Hour)}
+	invalid := &entities.ScheduledMessage{ID: uuid.New(), MessageID: uuid.New(), Event: "{", SendAt: time.Now().UTC().Add(time.Hour)}
+
+	queue := &pushQueueStub{}
+	repository := &scheduledMessageRepositoryStub{claimable: []*entities.ScheduledMessage{invalid, enqueued}}
+	logger := &noopLogger{}
+	service := NewScheduledMessageService(logger, telemetry.NewOtelLogger("test", logger), repository, &messageRepositoryStub{}, newEventDispatcherForTest(queue))
+
+	require.NoError(t, service.Sweep(context.Background()))
+
+	assert.Equal(t, []string{events.EventTypeMessageSendExpiredCheck}, queue.types())
+	assert.Equal(t, []*entities.ScheduledMessage{enqueued}, repository.deleted)
+}
+
+func TestScheduledMessageService_StoresTheEventWithoutTheContent(t *testing.T) {
+	message := &entities.Message{ID: uuid.New(), UserID: "user-id", Content: "Hello, world"}
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetSource("/v1/messages/send")
+	event.SetType(events.EventTypeMessageAPISent)
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, &events.MessageAPISentPayload{MessageID: message.ID, UserID: message.UserID, Content: message.Content, SIM: entities.SIM1}))
+
+	queue := &pushQueueStub{}
+	repository := &scheduledMessageRepositoryStub{}
+	logger := &noopLogger{}
+	service := NewScheduledMessageService(
+		logger,
+		telemetry.NewOtelLogger("test", logger),
+		repository,
+		&messageRepositoryStub{messages: map[uuid.UUID]*entities.Message{message.ID: message}},
+		newEventDispatcherForTest(queue),
+	)
+
+	require.NoError(t, service.Dispatch(context.Background(), &ScheduledMessageDispatchParams{
+		UserID:    message.UserID,
+		MessageID: message.ID,
+		Event:     event,
+		SendAt:    time.Now().UTC().Add(messageQueueHorizon + time.Hour),
+	}))
+
+	require.Len(t, repository.stored, 1)
+	assert.NotContains(t, repository.stored[0].Event, message.Content)
+	assert.Empty(t, queue.types())
+
+	require.NoError(t, service.enqueue(context.Background(), repository.stored[0]))
+
+	require.Equal(t, []string{events.EventTypeMessageAPISent}, queue.types())
+	payload := new(events.MessageAPISentPayload)
+	require.NoError(t, queue.events[0].DataAs(payload))
+	assert.Equal(t, message.Content, payload.Content)
+	assert.Equal(t, entities.SIM1, payload.SIM)
+}
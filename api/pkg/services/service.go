@@ -1,7 +1,9 @@
 package services
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
@@ -49,3 +51,12 @@ func (service *service) getFormattedNumber(ctxLogger telemetry.Logger, phoneNumb
 
 	return phonenumbers.Format(number, phonenumbers.INTERNATIONAL)
 }
+
+// getFormattedContact formats a phone number and prefixes it with the name of the contact when the name is known e.g "Jane Doe (+1 800-555-0100)"
+func (service *service) getFormattedContact(ctxLogger telemetry.Logger, phoneNumber string, name *string) string {
+	formatted := service.getFormattedNumber(ctxLogger, phoneNumber)
+	if name == nil || strings.TrimSpace(*name) == "" {
+		return formatted
+	}
+	return fmt.Sprintf("%s (%s)", strings.TrimSpace(*name), formatted)
+}
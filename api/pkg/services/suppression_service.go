@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+)
+
+// optOutKeywords are the inbound messages which add a contact to the suppression list
+var optOutKeywords = map[string]struct{}{
+	"STOP":        {},
+	"STOPALL":     {},
+	"STOP ALL":    {},
+	"UNSUBSCRIBE": {},
+	"CANCEL":      {},
+	"END":         {},
+	"QUIT":        {},
+	"OPTOUT":      {},
+	"OPT OUT":     {},
+	"REVOKE":      {},
+}
+
+// optInKeywords are the inbound messages which remove a contact from the suppression list
+var optInKeywords = map[string]struct{}{
+	"START":     {},
+	"UNSTOP":    {},
+	"SUBSCRIBE": {},
+}
+
+// SuppressionService manages the contacts which opted out of receiving messages
+type SuppressionService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.SuppressionRepository
+}
+
+// NewSuppressionService creates a new SuppressionService
+func NewSuppressionService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.SuppressionRepository,
+) (s *SuppressionService) {
+	return &SuppressionService{
+		logger:     logger.WithService(fmt.Sprintf("%T", s)),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// Index fetches the entities.Suppression for a user
+func (service *SuppressionService) Index(ctx context.Context, userID entities.UserID, owner string, params repositories.IndexParams) ([]*entities.Suppression, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	suppressions, err := service.repository.Index(ctx, userID, owner, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch suppressions with params [%+#v]", params))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] suppressions with params [%+#v]", len(suppressions), params))
+	return suppressions, nil
+}
+
+// SuppressionStoreParams are parameters for storing entities.Suppression
+type SuppressionStoreParams struct {
+	UserID   entities.UserID
+	Owner    string
+	Contacts []string
+	Source   entities.SuppressionSource
+	Keyword  *string
+}
+
+// Store adds the contacts to the suppression list of an owner
+func (service *SuppressionService) Store(ctx context.Context, params *SuppressionStoreParams) ([]*entities.Suppression, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	suppressions := make([]*entities.Suppression, 0, len(params.Contacts))
+	for _, contact := range params.Contacts {
+		suppression := &entities.Suppression{
+			ID:        uuid.New(),
+			UserID:    params.UserID,
+			Owner:     params.Owner,
+			Contact:   contact,
+			Source:    params.Source,
+			Keyword:   params.Keyword,
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+		}
+
+		if err := service.repository.Store(ctx, suppression); err != nil {
+			return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save suppression for user [%s] with owner [%s] and contact [%s]", params.UserID, params.Owner, contact))
+		}
+		suppressions = append(suppressions, suppression)
+	}
+
+	ctxLogger.Info(fmt.Sprintf("stored [%d] suppressions for user [%s] and owner [%s]", len(suppressions), params.UserID, params.Owner))
+	return suppressions, nil
+}
+
+// Delete an entities.Suppression
+func (service *SuppressionService) Delete(ctx context.Context, userID entities.UserID, suppressionID uuid.UUID) error {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	if _, err := service.repository.Load(ctx, userID, suppressionID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.PropagateWithCodef(err, stacktrace.GetCode(err), "cannot load suppression with userID [%s] and suppressionID [%s]", userID, suppressionID))
+	}
+
+	if err := service.repository.Delete(ctx, userID, suppressionID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete suppression with id [%s] and user id [%s]", suppressionID, userID))
+	}
+
+	return nil
+}
+
+// DeleteAllForUser deletes all entities.Suppression for an entities.UserID.
+func (service *SuppressionService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not delete [entities.Suppression] for user with ID [%s]", userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [entities.Suppression] for user with ID [%s]", userID))
+	return nil
+}
+
+// SuppressedContacts returns the contacts which are on the suppression list of an owner
+func (service *SuppressionService) SuppressedContacts(ctx context.Context, userID entities.UserID, owner string, contacts []string) ([]string, error) {
+	ctx, span := service.tracer.Start(ctx)
+	defer span.End()
+
+	suppressed, err := service.repository.FindContacts(ctx, userID, owner, contacts)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot find suppressed contacts for user [%s] and owner [%s]", userID, owner))
+	}
+
+	return suppressed, nil
+}
+
+// HandleKeyword updates the suppression list when an inbound message is an opt-out or opt-in keyword
+func (service *SuppressionService) HandleKeyword(ctx context.Context, message *entities.Message) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if message.Encrypted {
+		return nil
+	}
+
+	keyword := service.normalizeKeyword(message.Content)
+
+	if _, ok := optOutKeywords[keyword]; ok {
+		_, err := service.Store(ctx, &SuppressionStoreParams{
+			UserID:   message.UserID,
+			Owner:    message.Owner,
+			Contacts: []string{message.Contact},
+			Source:   entities.SuppressionSourceKeyword,
+			Keyword:  &keyword,
+		})
+		if err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot suppress contact [%s] for message [%s] with keyword [%s]", message.Contact, message.ID, keyword))
+		}
+		ctxLogger.Info(fmt.Sprintf("contact [%s] opted out of messages from [%s] with keyword [%s] in message [%s]", message.Contact, message.Owner, keyword, message.ID))
+		return nil
+	}
+
+	if _, ok := optInKeywords[keyword]; ok {
+		if err := service.repository.DeleteByOwnerAndContact(ctx, message.UserID, message.Owner, message.Contact); err != nil {
+			return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot remove suppression of contact [%s] for message [%s] with keyword [%s]", message.Contact, message.ID, keyword))
+		}
+		ctxLogger.Info(fmt.Sprintf("contact [%s] opted in to messages from [%s] with keyword [%s] in message [%s]", message.Contact, message.Owner, keyword, message.ID))
+	}
+
+	return nil
+}
+
+// IsKeyword checks if the content of an inbound message is an opt-out or opt-in keyword
+func (service *SuppressionService) IsKeyword(content string) bool {
+	keyword := service.normalizeKeyword(content)
+	if _, ok := optOutKeywords[keyword]; ok {
+		return true
+	}
+	_, ok := optInKeywords[keyword]
+	return ok
+}
+
+// normalizeKeyword converts the content of a message into an upper case keyword without punctuation e.g "Stop." becomes "STOP"
+func (service *SuppressionService) normalizeKeyword(content string) string {
+	content = strings.TrimFunc(content, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return strings.ToUpper(strings.Join(strings.Fields(content), " "))
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type suppressionRepositoryStub struct {
+	stored     []*entities.Suppression
+	deleted    []string
+	suppressed map[string][]string
+}
+
+func (stub *suppressionRepositoryStub) Store(_ context.Context, suppression *entities.Suppression) error {
+	stub.stored = append(stub.stored, suppression)
+	return nil
+}
+
+func (stub *suppressionRepositoryStub) Load(context.Context, entities.UserID, uuid.UUID) (*entities.Suppression, error) {
+	return nil, nil
+}
+
+func (stub *suppressionRepositoryStub) Index(context.Context, entities.UserID, string, repositories.IndexParams) ([]*entities.Suppression, error) {
+	return nil, nil
+}
+
+func (stub *suppressionRepositoryStub) FindContacts(_ context.Context, _ entities.UserID, owner string, _ []string) ([]string, error) {
+	return stub.suppressed[owner], nil
+}
+
+func (stub *suppressionRepositoryStub) Delete(context.Context, entities.UserID, uuid.UUID) error {
+	return nil
+}
+
+func (stub *suppressionRepositoryStub) DeleteByOwnerAndContact(_ context.Context, _ entities.UserID, _ string, contact string) error {
+	stub.deleted = append(stub.deleted, contact)
+	return nil
+}
+
+func (stub *suppressionRepositoryStub) DeleteAllForUser(context.Context, entities.UserID) error {
+	return nil
+}
+
+func newSuppressionServiceForTest(repository repositories.SuppressionRepository) *SuppressionService {
+	logger := &noopLogger{}
+	return NewSuppressionService(logger, telemetry.NewOtelLogger("test", logger), repository)
+}
+
+func TestSuppressionServiceNormalizeKeyword(t *testing.T) {
+	service := &SuppressionService{}
+
+	assert.Equal(t, "STOP", service.normalizeKeyword(" stop. "))
+	assert.Equal(t, "STOP ALL", service.normalizeKeyword("Stop   all!"))
+	assert.Equal(t, "PLEASE STOP TEXTING ME", service.normalizeKeyword("please stop texting me"))
+}
+
+func TestSuppressionServiceHandleKeywordOptOut(t *testing.T) {
+	repository := &suppressionRepositoryStub{}
+	service := newSuppressionServiceForTest(repository)
+
+	err := service.HandleKeyword(context.Background(), &entities.Message{
+		ID:      uuid.New(),
+		UserID:  entities.UserID("user-id"),
+		Owner:   "+18005550199",
+		Contact: "+18005550100",
+		Content: "Unsubscribe",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, repository.stored, 1)
+	assert.Equal(t, "+18005550100", repository.stored[0].Contact)
+	assert.Equal(t, entities.SuppressionSourceKeyword, repository.stored[0].Source)
+	assert.Equal(t, "UNSUBSCRIBE", *repository.stored[0].Keyword)
+}
+
+func TestSuppressionServiceHandleKeywordOptIn(t *testing.T) {
+	repository := &suppressionRepositoryStub{}
+	service := newSuppressionServiceForTest(repository)
+
+	err := service.HandleKeyword(context.Background(), &entities.Message{
+		ID:      uuid.New(),
+		UserID:  entities.UserID("user-id"),
+		Owner:   "+18005550199",
+		Contact: "+18005550100",
+		Content: "START",
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, repository.stored)
+	assert.Equal(t, []string{"+18005550100"}, repository.deleted)
+}
+
+func TestSuppressionServiceHandleKeywordIgnoresOtherMessages(t *testing.T) {
+	repository := &suppressionRepositoryStub{}
+	service := newSuppressionServiceForTest(repository)
+
+	for _, message := range []*entities.Message{
+		{ID: uuid.New(), Content: "please stop texting me"},
+		{ID: uuid.New(), Content: "STOP", Encrypted: true},
+	} {
+		require.NoError(t, service.HandleKeyword(context.Background(), message))
+	}
+
+	assert.Empty(t, repository.stored)
+	assert.Empty(t, repository.deleted)
+}
+
+func TestSuppressionServiceIsKeyword(t *testing.T) {
+	service := &SuppressionService{}
+
+	assert.True(t, service.IsKeyword("Stop."))
+	assert.True(t, service.IsKeyword(" start "))
+	assert.False(t, service.IsKeyword("HOURS"))
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UserAPIKeyService is responsible for managing entities.UserAPIKey
+type UserAPIKeyService struct {
+	service
+	logger     telemetry.Logger
+	tracer     telemetry.Tracer
+	repository repositories.UserAPIKeyRepository
+}
+
+// NewUserAPIKeyService creates a new UserAPIKeyService
+func NewUserAPIKeyService(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	repository repositories.UserAPIKeyRepository,
+) *UserAPIKeyService {
+	return &UserAPIKeyService{
+		logger:     logger.WithService(fmt.Sprintf("%T", &UserAPIKeyService{})),
+		tracer:     tracer,
+		repository: repository,
+	}
+}
+
+// Index fetches the entities.UserAPIKey for an entities.UserID
+func (service *UserAPIKeyService) Index(ctx context.Context, userID entities.UserID, params repositories.IndexParams) ([]*entities.UserAPIKey, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	userAPIKeys, err := service.repository.Index(ctx, userID, params)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not fetch user API Keys with params [%+#v]", params))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("fetched [%d] user API Keys with prams [%+#v]", len(userAPIKeys), params))
+	return userAPIKeys, nil
+}
+
+// UserAPIKeyCreateParams are parameters for creating an entities.UserAPIKey
+type UserAPIKeyCreateParams struct {
+	UserID       entities.UserID
+	UserEmail    string
+	Name         string
+	Scopes       []string
+	PhoneNumbers []string
+	ExpiresAt    *time.Time
+}
+
+// Create a new entities.UserAPIKey
+func (service *UserAPIKeyService) Create(ctx context.Context, params *UserAPIKeyCreateParams) (*entities.UserAPIKey, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	apiKey, err := service.generateAPIKey(64)
+	if err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot generate API key")
+	}
+
+	userAPIKey := &entities.UserAPIKey{
+		ID:           uuid.New(),
+		Name:         params.Name,
+		UserID:       params.UserID,
+		UserEmail:    params.UserEmail,
+		Scopes:       pq.StringArray(params.Scopes),
+		PhoneNumbers: pq.StringArray(params.PhoneNumbers),
+		APIKey:       "sk_" + apiKey,
+		ExpiresAt:    params.ExpiresAt,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	if err = service.repository.Create(ctx, userAPIKey); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot create UserAPIKey for user [%s]", params.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("created [%T] with ID [%s] and scopes [%v] for user ID [%s]", userAPIKey, userAPIKey.ID, params.Scopes, params.UserID))
+	return userAPIKey, nil
+}
+
+// Delete an entities.UserAPIKey
+func (service *UserAPIKeyService) Delete(ctx context.Context, userID entities.UserID, userAPIKeyID uuid.UUID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	userAPIKey, err := service.repository.Load(ctx, userID, userAPIKeyID)
+	if err != nil {
+		return stacktrace.Propagatef(err, "cannot load [%T] with ID [%s] for user [%s]", &entities.UserAPIKey{}, userAPIKeyID, userID.String())
+	}
+
+	if err = service.repository.Delete(ctx, userAPIKey); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete [%T] with ID [%s] for user [%s]", userAPIKey, userAPIKey.ID, userAPIKey.UserID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted [%T] with ID [%s] for user ID [%s]", userAPIKey, userAPIKey.ID, userID))
+	return nil
+}
+
+// DeleteAllForUser removes all entities.UserAPIKey for a user
+func (service *UserAPIKeyService) DeleteAllForUser(ctx context.Context, userID entities.UserID) error {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	if err := service.repository.DeleteAllForUser(ctx, userID); err != nil {
+		return service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot delete all [%T] for user ID [%s]", &entities.UserAPIKey{}, userID))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("deleted all [%T] for user ID [%s]", &entities.UserAPIKey{}, userID))
+	return nil
+}
+
+func (service *UserAPIKeyService) generateRandomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	// Note that err == nil only if we read len(b) bytes.
+	if _, err := rand.Read(b); err != nil {
+		return nil, stacktrace.Propagatef(err, "cannot generate [%d] random bytes", n)
+	}
+
+	return b, nil
+}
+
+func (service *UserAPIKeyService) generateAPIKey(n int) (string, error) {
+	b, err := service.generateRandomBytes(n)
+	return base64.URLEncoding.EncodeToString(b)[0:n], stacktrace.Propagatef(err, "cannot generate [%d] random bytes", n)
+}
@@ -239,6 +239,33 @@ func (service *UserService) UpdateNotificationSettings(ctx context.Context, user
 	return user, nil
 }
 
+// UserRetentionUpdateParams are parameters for updating the data retention settings of a user
+type UserRetentionUpdateParams struct {
+	MessageContentRetentionDays *uint
+	DataRetentionDays           *uint
+}
+
+// UpdateRetentionSettings for an entities.User
+func (service *UserService) UpdateRetentionSettings(ctx context.Context, userID entities.UserID, params *UserRetentionUpdateParams) (*entities.User, error) {
+	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
+	defer span.End()
+
+	user, err := service.repository.Load(ctx, userID)
+	if err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load [%T] with ID [%s]", user, userID))
+	}
+
+	user.MessageContentRetentionDays = params.MessageContentRetentionDays
+	user.DataRetentionDays = params.DataRetentionDays
+
+	if err = service.repository.Update(ctx, user); err != nil {
+		return nil, service.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot save user with id [%s] in [%T]", user.ID, service.repository))
+	}
+
+	ctxLogger.Info(fmt.Sprintf("updated retention settings for [%T] with ID [%s] in the [%T]", user, user.ID, service.repository))
+	return user, nil
+}
+
 // RotateAPIKey for an entities.User
 func (service *UserService) RotateAPIKey(ctx context.Context, source string, userID entities.UserID) (*entities.User, error) {
 	ctx, span, ctxLogger := service.tracer.StartWithLogger(ctx, service.logger)
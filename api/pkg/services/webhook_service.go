@@ -36,6 +36,7 @@ type WebhookService struct {
 	client     *http.Client
 	repository repositories.WebhookRepository
 	dispatcher *EventDispatcher
+	metrics    *MetricsService
 }
 
 // NewWebhookService creates a new WebhookService
@@ -45,6 +46,7 @@ func NewWebhookService(
 	client *http.Client,
 	repository repositories.WebhookRepository,
 	dispatcher *EventDispatcher,
+	metrics *MetricsService,
 ) (s *WebhookService) {
 	return &WebhookService{
 		logger:     logger.WithService(fmt.Sprintf("%T", s)),
@@ -52,6 +54,7 @@ func NewWebhookService(
 		client:     client,
 		dispatcher: dispatcher,
 		repository: repository,
+		metrics:    metrics,
 	}
 }
 
@@ -217,6 +220,7 @@ func (service *WebhookService) sendNotification(ctx context.Context, event cloud
 
 		response, err := service.client.Do(request)
 		if err != nil {
+			service.metrics.RecordWebhookDelivery(ctx, event.Type(), 0)
 			ctxLogger.Warn(stacktrace.Propagatef(err, "cannot send [%s] event to webhook [%s] for user [%s] after [%d] attempts", event.Type(), webhook.URL, webhook.UserID, attempts))
 			if attempts == 1 {
 				return err
@@ -232,6 +236,7 @@ func (service *WebhookService) sendNotification(ctx context.Context, event cloud
 			}
 		}()
 
+		service.metrics.RecordWebhookDelivery(ctx, event.Type(), response.StatusCode)
 		if response.StatusCode >= 400 {
 			ctxLogger.Info(fmt.Sprintf("cannot send [%s] event to webhook [%s] for user [%s] with response code [%d] after [%d] attempts", event.Type(), webhook.URL, webhook.UserID, response.StatusCode, attempts))
 			if attempts == 1 {
@@ -0,0 +1,171 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// maxAutoReplyCooldown is the longest cooldown of an entities.AutoReplyRule
+const maxAutoReplyCooldown = 30 * 24 * time.Hour
+
+// AutoReplyRuleHandlerValidator validates models used in handlers.AutoReplyRuleHandler
+type AutoReplyRuleHandlerValidator struct {
+	validator
+	logger            telemetry.Logger
+	tracer            telemetry.Tracer
+	phoneService      *services.PhoneService
+	scheduleValidator *MessageSendScheduleHandlerValidator
+}
+
+// NewAutoReplyRuleHandlerValidator creates a new handlers.AutoReplyRuleHandler validator
+func NewAutoReplyRuleHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+	scheduleValidator *MessageSendScheduleHandlerValidator,
+) (v *AutoReplyRuleHandlerValidator) {
+	return &AutoReplyRuleHandlerValidator{
+		logger:            logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:            tracer,
+		phoneService:      phoneService,
+		scheduleValidator: scheduleValidator,
+	}
+}
+
+// ValidateIndex validates the requests.AutoReplyRuleIndex request
+func (validator *AutoReplyRuleHandlerValidator) ValidateIndex(_ context.Context, request requests.AutoReplyRuleIndex) url.Values {
+	rules := govalidator.MapData{
+		"limit": []string{
+			"required",
+			"numeric",
+			"min:1",
+			"max:100",
+		},
+		"skip": []string{
+			"required",
+			"numeric",
+			"min:0",
+		},
+		"query": []string{
+			"max:100",
+		},
+	}
+
+	if request.Owner != "" {
+		rules["owner"] = []string{phoneNumberRule}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.AutoReplyRuleStore request
+func (validator *AutoReplyRuleHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.AutoReplyRuleStore) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	rules := govalidator.MapData{
+		"owner": []string{
+			"required",
+			phoneNumberRule,
+		},
+		"name": []string{
+			"required",
+			"min:1",
+			"max:100",
+		},
+		"trigger": []string{
+			"required",
+			fmt.Sprintf("in:%s,%s", entities.AutoReplyTriggerMessage, entities.AutoReplyTriggerMissedCall),
+		},
+		"match_type": []string{
+			"required",
+			fmt.Sprintf("in:%s,%s,%s,%s", entities.AutoReplyMatchTypeExact, entities.AutoReplyMatchTypePrefix, entities.AutoReplyMatchTypeRegex, entities.AutoReplyMatchTypeAny),
+		},
+		"content": []string{
+			"required",
+			"min:1",
+			"max:2048",
+		},
+	}
+
+	if request.MatchType != string(entities.AutoReplyMatchTypeAny) {
+		rules["pattern"] = []string{
+			"required",
+			"min:1",
+			"max:255",
+		}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := v.ValidateStruct()
+
+	if request.Priority < 0 || request.Priority > 1000 {
+		result.Add("priority", "The priority field must be between 0 and 1000")
+	}
+
+	if time.Duration(request.CooldownSeconds)*time.Second > maxAutoReplyCooldown {
+		result.Add("cooldown_seconds", fmt.Sprintf("The cooldown_seconds field must not be greater than %d", int(maxAutoReplyCooldown.Seconds())))
+	}
+
+	if request.MatchType == string(entities.AutoReplyMatchTypeRegex) && request.Pattern != "" {
+		if _, err := regexp.Compile(request.Pattern); err != nil {
+			result.Add("pattern", fmt.Sprintf("The pattern field must be a valid regular expression: %s", err.Error()))
+		}
+	}
+
+	if len(request.Windows) > 0 {
+		validator.validateWindows(result, request.Windows)
+		if _, err := time.LoadLocation(request.Timezone); request.Timezone == "" || err != nil {
+			result.Add("timezone", "The timezone must be a valid IANA timezone e.g Europe/London.")
+		}
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+
+	_, err := validator.phoneService.Load(ctx, userID, request.Owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("owner", fmt.Sprintf("no phone found with 'owner' number [%s]", request.Owner))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load phone for user [%s] and phone [%s]", userID, request.Owner)))
+		result.Add("owner", fmt.Sprintf("could not validate 'owner' number [%s], please try again later", request.Owner))
+	}
+
+	return result
+}
+
+// validateWindows validates the windows of an entities.AutoReplyRule, a window which ends before it starts crosses midnight
+func (validator *AutoReplyRuleHandlerValidator) validateWindows(result url.Values, windows []requests.MessageSendScheduleWindow) {
+	windowsPerDay := make(map[int]int)
+	for index, item := range windows {
+		validator.scheduleValidator.validateDayOfWeek(result, index, item, windowsPerDay)
+		validator.scheduleValidator.validateStartMinute(result, index, item)
+		validator.scheduleValidator.validateEndMinute(result, index, item)
+		if item.EndMinute == item.StartMinute {
+			result.Add("windows", fmt.Sprintf("windows[%d].end_minute must be different from start_minute", index))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/dustin/go-humanize"
+)
+
+// maxBulkJobFileSize is the maximum size in bytes of the file of a bulk job
+const maxBulkJobFileSize = 50 * 1000 * 1000
+
+// BulkJobHandlerValidator validates models used in handlers.BulkJobHandler
+type BulkJobHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewBulkJobHandlerValidator creates a new handlers.BulkJobHandler validator
+func NewBulkJobHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *BulkJobHandlerValidator) {
+	return &BulkJobHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateStore validates the uploaded file of a bulk job.
+// The rows of the file are validated in the background when the job is processed.
+func (v *BulkJobHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, header *multipart.FileHeader) url.Values {
+	_, span, ctxLogger := v.tracer.StartWithLogger(ctx, v.logger)
+	defer span.End()
+
+	result := url.Values{}
+	if !strings.HasSuffix(strings.ToLower(header.Filename), ".csv") && !strings.HasSuffix(strings.ToLower(header.Filename), ".xlsx") {
+		result.Add("document", fmt.Sprintf("The file [%s] is not a valid CSV or Excel file. The file name must end with .csv or .xlsx", header.Filename))
+		return result
+	}
+
+	if header.Size > maxBulkJobFileSize {
+		result.Add("document", fmt.Sprintf("The file must be less than %s the file you uploaded is [%s].", humanize.Bytes(maxBulkJobFileSize), humanize.Bytes(uint64(header.Size))))
+		return result
+	}
+
+	if header.Size == 0 {
+		ctxLogger.Warn(stacktrace.NewErrorf("file [%s] uploaded by user [%s] is empty", header.Filename, userID))
+		result.Add("document", fmt.Sprintf("The uploaded file [%s] is empty.", header.Filename))
+		return result
+	}
+
+	return result
+}
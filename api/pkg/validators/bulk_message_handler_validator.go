@@ -3,10 +3,12 @@ package validators
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -20,18 +22,22 @@ import (
 	"github.com/NdoleStudio/httpsms/pkg/telemetry"
 	"github.com/NdoleStudio/stacktrace"
 	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
 	"github.com/jszwec/csvutil"
 	"github.com/nyaruka/phonenumbers"
+	"github.com/thedevsaddam/govalidator"
 )
 
 // BulkMessageHandlerValidator validates models used in handlers.BillingHandler
 type BulkMessageHandlerValidator struct {
 	validator
-	phoneService *services.PhoneService
-	userService  *services.UserService
-	logger       telemetry.Logger
-	tracer       telemetry.Tracer
-	cache        cache.Cache
+	phoneService       *services.PhoneService
+	userService        *services.UserService
+	suppressionService *services.SuppressionService
+	templateService    *services.MessageTemplateService
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	cache              cache.Cache
 }
 
 // NewBulkMessageHandlerValidator creates a new handlers.BulkMessageHandlerValidator validator
@@ -40,14 +46,18 @@ func NewBulkMessageHandlerValidator(
 	tracer telemetry.Tracer,
 	phoneService *services.PhoneService,
 	userService *services.UserService,
+	suppressionService *services.SuppressionService,
+	templateService *services.MessageTemplateService,
 	appCache cache.Cache,
 ) (v *BulkMessageHandlerValidator) {
 	return &BulkMessageHandlerValidator{
-		logger:       logger.WithService(fmt.Sprintf("%T", v)),
-		tracer:       tracer,
-		userService:  userService,
-		phoneService: phoneService,
-		cache:        appCache,
+		logger:             logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:             tracer,
+		userService:        userService,
+		phoneService:       phoneService,
+		suppressionService: suppressionService,
+		templateService:    templateService,
+		cache:              appCache,
 	}
 }
 
@@ -75,7 +85,7 @@ func (v *BulkMessageHandlerValidator) ValidateStore(ctx context.Context, userID
 	}
 
 	if len(messages) > 1000 {
-		result.Add("document", "The uploaded file must contain less than 1000 records.")
+		result.Add("document", "The uploaded file must contain less than 1000 records. Upload larger files to the /v1/bulk-jobs endpoint to send them in the background.")
 		return messages, user.Location(), result
 	}
 
@@ -83,6 +93,11 @@ func (v *BulkMessageHandlerValidator) ValidateStore(ctx context.Context, userID
 		messages[index] = message.Sanitize()
 	}
 
+	result = v.renderTemplates(ctx, ctxLogger, userID, messages)
+	if len(result) != 0 {
+		return messages, user.Location(), result
+	}
+
 	result = v.validateMessages(ctx, messages, user.Location())
 	if len(result) != 0 {
 		return messages, user.Location(), result
@@ -93,9 +108,158 @@ func (v *BulkMessageHandlerValidator) ValidateStore(ctx context.Context, userID
 		return messages, user.Location(), result
 	}
 
+	result = v.validateSuppressions(ctx, ctxLogger, userID, messages)
+	if len(result) != 0 {
+		return messages, user.Location(), result
+	}
+
 	return messages, user.Location(), result
 }
 
+// ValidateDryRun runs the validation of ValidateStore on every row of a bulk SMS file without sending the messages.
+// File level errors are returned as url.Values and the errors of each row are in the entities.BulkMessageReport.
+func (v *BulkMessageHandlerValidator) ValidateDryRun(ctx context.Context, userID entities.UserID, header *multipart.FileHeader) (*entities.BulkMessageReport, url.Values) {
+	ctx, span, ctxLogger := v.tracer.StartWithLogger(ctx, v.logger)
+	defer span.End()
+
+	user, err := v.userService.GetByID(ctx, userID)
+	if err != nil {
+		result := url.Values{}
+		result.Add("document", "Cannot load your account. Please try again later or contact support.")
+		ctxLogger.Error(v.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "cannot load user [%s]", userID)))
+		return nil, result
+	}
+
+	messages, result := v.parseFile(ctxLogger, user, header)
+	if len(result) != 0 {
+		return nil, result
+	}
+
+	if len(messages) == 0 {
+		result.Add("document", "The uploaded file doesn't contain any valid records. Make sure you are using the official httpSMS template.")
+		return nil, result
+	}
+
+	if len(messages) > 1000 {
+		result.Add("document", "The uploaded file must contain less than 1000 records. Upload larger files to the /v1/bulk-jobs endpoint to send them in the background.")
+		return nil, result
+	}
+
+	rows := make([]*entities.BulkMessageReportRow, 0, len(messages))
+	templates := map[string]*entities.MessageTemplate{}
+	for index, message := range messages {
+		messages[index] = message.Sanitize()
+
+		row := &entities.BulkMessageReportRow{
+			Row:             index + 2,
+			FromPhoneNumber: message.FromPhoneNumber,
+			ToPhoneNumber:   message.ToPhoneNumber,
+			SendAt:          message.GetSendTime(user.Location()),
+			Errors:          []string{},
+		}
+
+		if err := v.renderTemplate(ctx, ctxLogger, userID, templates, message); err != "" {
+			row.Errors = append(row.Errors, err)
+		}
+		row.Errors = append(row.Errors, v.validateMessage(message, user.Location())...)
+
+		segments := entities.CalculateMessageSegments(message.Content)
+		row.Encoding, row.Segments = segments.Encoding, segments.Segments
+		rows = append(rows, row)
+	}
+
+	registered := map[string]bool{}
+	for _, row := range rows {
+		if _, ok := registered[row.FromPhoneNumber]; !ok {
+			registered[row.FromPhoneNumber] = v.isRegistered(ctx, userID, row.FromPhoneNumber)
+		}
+		if !registered[row.FromPhoneNumber] {
+			row.Errors = append(row.Errors, fmt.Sprintf("The FromPhoneNumber [%s] is not registered on your account", row.FromPhoneNumber))
+		}
+	}
+
+	for owner, contacts := range v.contactRows(messages) {
+		suppressed, err := v.suppressedContacts(ctx, ctxLogger, userID, owner, contacts)
+		for contact, numbers := range contacts {
+			for _, number := range numbers {
+				if err != "" {
+					rows[number-2].Errors = append(rows[number-2].Errors, err)
+				} else if slices.Contains(suppressed, contact) {
+					rows[number-2].Errors = append(rows[number-2].Errors, fmt.Sprintf("The ToPhoneNumber [%s] has opted out of receiving messages from [%s]", contact, owner))
+				}
+			}
+		}
+	}
+
+	return entities.NewBulkMessageReport(rows), url.Values{}
+}
+
+// ParseRow validates a row in the file of a bulk job with the same rules as ValidateStore and converts it into services.MessageSendParams.
+// The FromPhoneNumber and the suppressed contacts are validated by the services.BulkJobService for each batch of rows.
+func (v *BulkMessageHandlerValidator) ParseRow(ctx context.Context, user *entities.User, header []string, record []string, templates map[string]*entities.MessageTemplate) (*services.MessageSendParams, []string) {
+	ctx, span, ctxLogger := v.tracer.StartWithLogger(ctx, v.logger)
+	defer span.End()
+
+	message := v.bulkMessage(header, record).Sanitize()
+	if err := v.renderTemplate(ctx, ctxLogger, user.ID, templates, message); err != "" {
+		return nil, []string{err}
+	}
+
+	if errors := v.validateMessage(message, user.Location()); len(errors) != 0 {
+		return nil, errors
+	}
+
+	params := message.ToMessageSendParams(user.ID, "", "", 0, user.Location())
+	return &params, nil
+}
+
+// bulkMessage maps the cells of a row to a requests.BulkMessage using the column headers, the extra columns are template variables
+func (v *BulkMessageHandlerValidator) bulkMessage(header []string, record []string) *requests.BulkMessage {
+	message := new(requests.BulkMessage)
+	fields := map[string]*string{
+		"FromPhoneNumber":          &message.FromPhoneNumber,
+		"ToPhoneNumber":            &message.ToPhoneNumber,
+		"Content":                  &message.Content,
+		"SendTime(optional)":       &message.SendTime,
+		"AttachmentURLs(optional)": &message.AttachmentURLs,
+		"TemplateID(optional)":     &message.TemplateID,
+	}
+
+	var columns []int
+	for column, name := range header {
+		field, ok := fields[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))]
+		if !ok {
+			columns = append(columns, column)
+			continue
+		}
+		if column < len(record) {
+			*field = record[column]
+		}
+	}
+
+	message.Variables = v.templateVariables(header, record, columns)
+	return message
+}
+
+// ValidateExport validates the requests.BulkMessageExport request
+func (v *BulkMessageHandlerValidator) ValidateExport(_ context.Context, request requests.BulkMessageExport) url.Values {
+	validator := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"requestID": []string{
+				"required",
+				"regex:^bulk-",
+				"max:255",
+			},
+			"format": []string{
+				"required",
+				fmt.Sprintf("in:%s,%s", entities.MessageExportFormatCSV, entities.MessageExportFormatXLSX),
+			},
+		},
+	})
+	return validator.ValidateStruct()
+}
+
 func (v *BulkMessageHandlerValidator) parseFile(ctxLogger telemetry.Logger, user *entities.User, header *multipart.FileHeader) ([]*requests.BulkMessage, url.Values) {
 	if header.Header.Get("Content-Type") == "text/csv" || strings.HasSuffix(header.Filename, ".csv") {
 		return v.parseCSV(ctxLogger, user, header)
@@ -132,12 +296,29 @@ func (v *BulkMessageHandlerValidator) parseXlsx(ctxLogger telemetry.Logger, user
 		return nil, result
 	}
 
+	var columnNames []string
+	if len(rows) > 0 {
+		columnNames = rows[0]
+	}
+
 	var messages []*requests.BulkMessage
 	for index, row := range rows {
 		if len(row) < 3 || strings.TrimSpace(row[0]) == "" || index == 0 {
 			continue
 		}
 
+		var templateID string
+		var columns []int
+		for column := 5; column < len(columnNames); column++ {
+			if strings.TrimSpace(columnNames[column]) == "TemplateID(optional)" {
+				if column < len(row) {
+					templateID = strings.TrimSpace(row[column])
+				}
+				continue
+			}
+			columns = append(columns, column)
+		}
+
 		var sendTimeRaw string
 		if len(row) > 3 && strings.TrimSpace(row[3]) != "" {
 			ctxLogger.Info(fmt.Sprintf("excel time = [%s]", row[3]))
@@ -161,6 +342,8 @@ func (v *BulkMessageHandlerValidator) parseXlsx(ctxLogger telemetry.Logger, user
 			Content:         row[2],
 			SendTime:        sendTimeRaw,
 			AttachmentURLs:  attachmentURLs,
+			TemplateID:      templateID,
+			Variables:       v.templateVariables(columnNames, row, columns),
 		})
 	}
 
@@ -203,70 +386,175 @@ func (v *BulkMessageHandlerValidator) parseCSV(ctxLogger telemetry.Logger, user
 		return nil, result
 	}
 
-	var messages []*requests.BulkMessage
-	if err := csvutil.Unmarshal(content, &messages); err != nil {
-		ctxLogger.Error(stacktrace.Propagatef(err, "cannot unmarshall contents [%s] into type [%T] for file [%s] and user [%s]", content, messages, header.Filename, user.ID))
+	decoder, err := csvutil.NewDecoder(csv.NewReader(bytes.NewReader(content)))
+	if err == io.EOF {
+		return nil, url.Values{}
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot read header of file [%s] for user [%s]", header.Filename, user.ID))
 		result.Add("document", fmt.Sprintf("Cannot read the contents of the uploaded file [%s].", header.Filename))
 		return nil, result
 	}
 
+	var messages []*requests.BulkMessage
+	for {
+		message := new(requests.BulkMessage)
+		if err = decoder.Decode(message); err == io.EOF {
+			break
+		}
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot unmarshall contents [%s] into type [%T] for file [%s] and user [%s]", content, messages, header.Filename, user.ID))
+			result.Add("document", fmt.Sprintf("Cannot read the contents of the uploaded file [%s].", header.Filename))
+			return nil, result
+		}
+
+		message.Variables = v.templateVariables(decoder.Header(), decoder.Record(), decoder.Unused())
+		messages = append(messages, message)
+	}
+
 	return messages, url.Values{}
 }
 
-func (v *BulkMessageHandlerValidator) validateMessages(_ context.Context, messages []*requests.BulkMessage, location *time.Location) url.Values {
+// templateVariables maps the extra columns of a row to template variables using the column headers as names
+func (v *BulkMessageHandlerValidator) templateVariables(header []string, row []string, columns []int) map[string]string {
+	variables := map[string]string{}
+	for _, column := range columns {
+		if column >= len(header) || strings.TrimSpace(header[column]) == "" {
+			continue
+		}
+
+		value := ""
+		if column < len(row) {
+			value = strings.TrimSpace(row[column])
+		}
+		variables[strings.TrimSpace(header[column])] = value
+	}
+	return variables
+}
+
+// renderTemplates replaces the content of each row with its rendered message template.
+// Rows without a TemplateID are sent with the Content column as it is, even when they have extra columns.
+func (v *BulkMessageHandlerValidator) renderTemplates(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, messages []*requests.BulkMessage) url.Values {
 	result := url.Values{}
+	templates := map[string]*entities.MessageTemplate{}
+
 	for index, message := range messages {
+		if err := v.renderTemplate(ctx, ctxLogger, userID, templates, message); err != "" {
+			result.Add("document", fmt.Sprintf("Row [%d]: %s", index+2, err))
+		}
+	}
 
-		if message.AttachmentURLs != "" {
-			urls := strings.Split(message.AttachmentURLs, ",")
+	return result
+}
 
-			validAttachmentCount := 0
-			for _, u := range urls {
-				if strings.TrimSpace(u) != "" {
-					validAttachmentCount++
-				}
-			}
+// renderTemplate replaces the content of a row with its rendered message template and returns the error of the row
+func (v *BulkMessageHandlerValidator) renderTemplate(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, templates map[string]*entities.MessageTemplate, message *requests.BulkMessage) string {
+	if message.TemplateID == "" {
+		return ""
+	}
 
-			if validAttachmentCount > 10 {
-				result.Add("document", fmt.Sprintf("Row [%d]: You cannot attach more than 10 files per message.", index+2))
-			}
+	template, err := v.loadTemplate(ctx, ctxLogger, userID, templates, message.TemplateID)
+	if err != "" {
+		return err
+	}
 
-			for _, u := range urls {
-				cleanURL := strings.TrimSpace(u)
-				if cleanURL == "" {
-					continue
-				}
+	content, missing := template.Render(message.Variables)
+	if len(missing) > 0 {
+		return fmt.Sprintf("The template variables [%s] are missing. Add a column for each variable.", strings.Join(missing, ", "))
+	}
 
-				parsedURL, err := url.ParseRequestURI(cleanURL)
-				if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
-					result.Add("document", fmt.Sprintf("Row [%d]: The attachment URL [%s] has an invalid url format.", index+2, cleanURL))
-				} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-					result.Add("document", fmt.Sprintf("Row [%d]: The attachment URL [%s] must use http or https.", index+2, cleanURL))
-				}
-			}
+	message.Content = content
+	return ""
+}
+
+func (v *BulkMessageHandlerValidator) loadTemplate(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, templates map[string]*entities.MessageTemplate, templateID string) (*entities.MessageTemplate, string) {
+	if template, ok := templates[templateID]; ok {
+		if template == nil {
+			return nil, fmt.Sprintf("The TemplateID [%s] does not exist on your account.", templateID)
 		}
+		return template, ""
+	}
+
+	parsedID, err := uuid.Parse(templateID)
+	if err != nil {
+		return nil, fmt.Sprintf("The TemplateID [%s] is not a valid UUID.", templateID)
+	}
+
+	template, err := v.templateService.Load(ctx, userID, parsedID)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		templates[templateID] = nil
+		return nil, fmt.Sprintf("The TemplateID [%s] does not exist on your account.", templateID)
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load message template [%s] for user [%s]", templateID, userID))
+		return nil, fmt.Sprintf("Cannot load the TemplateID [%s]. Please try again later.", templateID)
+	}
+
+	templates[templateID] = template
+	return template, ""
+}
 
-		if _, err := phonenumbers.Parse(message.FromPhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
-			result.Add("document", fmt.Sprintf("Row [%d]: The FromPhoneNumber [%s] is not a valid E.164 phone number", index+2, message.FromPhoneNumber))
+func (v *BulkMessageHandlerValidator) validateMessages(_ context.Context, messages []*requests.BulkMessage, location *time.Location) url.Values {
+	result := url.Values{}
+	for index, message := range messages {
+		for _, err := range v.validateMessage(message, location) {
+			result.Add("document", fmt.Sprintf("Row [%d]: %s", index+2, err))
 		}
+	}
+	return result
+}
 
-		if _, err := phonenumbers.Parse(message.ToPhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
-			result.Add("document", fmt.Sprintf("Row [%d]: The ToPhoneNumber [%s] is not a valid E.164 phone number", index+2, message.ToPhoneNumber))
+// validateMessage returns the errors of a single row
+func (v *BulkMessageHandlerValidator) validateMessage(message *requests.BulkMessage, location *time.Location) []string {
+	var result []string
+	if message.AttachmentURLs != "" {
+		urls := strings.Split(message.AttachmentURLs, ",")
+
+		validAttachmentCount := 0
+		for _, u := range urls {
+			if strings.TrimSpace(u) != "" {
+				validAttachmentCount++
+			}
 		}
 
-		if len(message.Content) > 1024 {
-			result.Add("document", fmt.Sprintf("Row [%d]: The message content must be less than 1024 characters.", index+2))
+		if validAttachmentCount > 10 {
+			result = append(result, "You cannot attach more than 10 files per message.")
 		}
 
-		if strings.TrimSpace(message.SendTime) != "" {
-			sendTime := message.GetSendTime(location)
-			if sendTime == nil {
-				result.Add("document", fmt.Sprintf("Row [%d]: The SendTime [%s] is not a valid date format. Use RFC3339 (e.g. 2023-11-11T02:10:01Z) or YYYY-MM-DDTHH:MM:SS.", index+2, message.SendTime))
-			} else if sendTime.After(time.Now().Add(420 * time.Hour)) {
-				result.Add("document", fmt.Sprintf("Row [%d]: The SendTime [%s] cannot be more than 20 days (420 hours) in the future.", index+2, sendTime.Format(time.RFC3339)))
+		for _, u := range urls {
+			cleanURL := strings.TrimSpace(u)
+			if cleanURL == "" {
+				continue
+			}
+
+			parsedURL, err := url.ParseRequestURI(cleanURL)
+			if err != nil || parsedURL.Scheme == "" || parsedURL.Host == "" {
+				result = append(result, fmt.Sprintf("The attachment URL [%s] has an invalid url format.", cleanURL))
+			} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+				result = append(result, fmt.Sprintf("The attachment URL [%s] must use http or https.", cleanURL))
 			}
 		}
 	}
+
+	if _, err := phonenumbers.Parse(message.FromPhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
+		result = append(result, fmt.Sprintf("The FromPhoneNumber [%s] is not a valid E.164 phone number", message.FromPhoneNumber))
+	}
+
+	if _, err := phonenumbers.Parse(message.ToPhoneNumber, phonenumbers.UNKNOWN_REGION); err != nil {
+		result = append(result, fmt.Sprintf("The ToPhoneNumber [%s] is not a valid E.164 phone number", message.ToPhoneNumber))
+	}
+
+	if len(message.Content) > 1024 {
+		result = append(result, "The message content must be less than 1024 characters.")
+	}
+
+	if strings.TrimSpace(message.SendTime) != "" {
+		sendTime := message.GetSendTime(location)
+		if sendTime == nil {
+			result = append(result, fmt.Sprintf("The SendTime [%s] is not a valid date format. Use RFC3339 (e.g. 2023-11-11T02:10:01Z) or YYYY-MM-DDTHH:MM:SS.", message.SendTime))
+		}
+	}
 	return result
 }
 
@@ -278,14 +566,61 @@ func (v *BulkMessageHandlerValidator) validateOwners(ctx context.Context, userID
 
 	result := url.Values{}
 	for number, rows := range numbers {
-		_, err := v.phoneService.Load(ctx, userID, strings.TrimSpace(number))
-		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		if !v.isRegistered(ctx, userID, number) {
 			result.Add("document", fmt.Sprintf("Rows [%s]: The FromPhoneNumber [%s] is not registered on your account", v.toString(rows), number))
 		}
 	}
 	return result
 }
 
+// isRegistered checks that a phone number is not missing from the account of the user
+func (v *BulkMessageHandlerValidator) isRegistered(ctx context.Context, userID entities.UserID, number string) bool {
+	_, err := v.phoneService.Load(ctx, userID, strings.TrimSpace(number))
+	return stacktrace.GetCode(err) != repositories.ErrCodeNotFound
+}
+
+func (v *BulkMessageHandlerValidator) validateSuppressions(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, messages []*requests.BulkMessage) url.Values {
+	result := url.Values{}
+	for owner, rows := range v.contactRows(messages) {
+		suppressed, err := v.suppressedContacts(ctx, ctxLogger, userID, owner, rows)
+		if err != "" {
+			result.Add("document", err)
+			continue
+		}
+
+		for _, contact := range suppressed {
+			result.Add("document", fmt.Sprintf("Rows [%s]: The ToPhoneNumber [%s] has opted out of receiving messages from [%s]", v.toString(rows[contact]), contact, owner))
+		}
+	}
+	return result
+}
+
+// contactRows groups the row numbers of the messages by the FromPhoneNumber and the ToPhoneNumber
+func (v *BulkMessageHandlerValidator) contactRows(messages []*requests.BulkMessage) map[string]map[string][]int {
+	contacts := map[string]map[string][]int{}
+	for index, message := range messages {
+		if _, ok := contacts[message.FromPhoneNumber]; !ok {
+			contacts[message.FromPhoneNumber] = map[string][]int{}
+		}
+		contacts[message.FromPhoneNumber][message.ToPhoneNumber] = append(contacts[message.FromPhoneNumber][message.ToPhoneNumber], index+2)
+	}
+	return contacts
+}
+
+func (v *BulkMessageHandlerValidator) suppressedContacts(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, owner string, rows map[string][]int) ([]string, string) {
+	numbers := make([]string, 0, len(rows))
+	for contact := range rows {
+		numbers = append(numbers, contact)
+	}
+
+	suppressed, err := v.suppressionService.SuppressedContacts(ctx, userID, owner, numbers)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot load suppressed contacts for user [%s] and owner [%s]", userID, owner))
+		return nil, fmt.Sprintf("Cannot validate the ToPhoneNumber values for the FromPhoneNumber [%s]. Please try again later.", owner)
+	}
+	return suppressed, ""
+}
+
 func (v *BulkMessageHandlerValidator) toString(value []int) string {
 	result := strings.Builder{}
 	for index, row := range value {
@@ -0,0 +1,67 @@
+package validators
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkMessageValidateMessageReturnsAllRowErrors(t *testing.T) {
+	validator := &BulkMessageHandlerValidator{}
+	message := &requests.BulkMessage{
+		FromPhoneNumber: "+18005550199",
+		ToPhoneNumber:   "invalid",
+		Content:         "Hello",
+		SendTime:        "tomorrow",
+		AttachmentURLs:  "ftp://example.com/a.png",
+	}
+
+	errors := validator.validateMessage(message, time.UTC)
+
+	assert.Equal(t, []string{
+		"The attachment URL [ftp://example.com/a.png] must use http or https.",
+		"The ToPhoneNumber [invalid] is not a valid E.164 phone number",
+		"The SendTime [tomorrow] is not a valid date format. Use RFC3339 (e.g. 2023-11-11T02:10:01Z) or YYYY-MM-DDTHH:MM:SS.",
+	}, errors)
+}
+
+func TestBulkMessageValidateMessagesPrefixesRowNumbers(t *testing.T) {
+	validator := &BulkMessageHandlerValidator{}
+	messages := []*requests.BulkMessage{
+		{FromPhoneNumber: "+18005550199", ToPhoneNumber: "+18005550100", Content: "Hello"},
+		{FromPhoneNumber: "+18005550199", ToPhoneNumber: "invalid", Content: "Hello"},
+	}
+
+	errors := validator.validateMessages(context.Background(), messages, time.UTC)
+
+	assert.Equal(t, []string{"Row [3]: The ToPhoneNumber [invalid] is not a valid E.164 phone number"}, errors["document"])
+}
+
+func TestBulkMessageRenderTemplatesIgnoresExtraColumnsWithoutTemplateID(t *testing.T) {
+	validator := &BulkMessageHandlerValidator{}
+	messages := []*requests.BulkMessage{
+		{FromPhoneNumber: "+18005550199", ToPhoneNumber: "+18005550100", Content: "Hello {{name}}", Variables: map[string]string{"name": "John"}},
+	}
+
+	errors := validator.renderTemplates(context.Background(), nil, "user-id", messages)
+
+	assert.Empty(t, errors)
+	assert.Equal(t, "Hello {{name}}", messages[0].Content)
+}
+
+func TestBulkMessageBulkMessageMapsTheColumnsByName(t *testing.T) {
+	validator := &BulkMessageHandlerValidator{}
+	header := []string{"\ufeffToPhoneNumber", "FromPhoneNumber", "name", "Content", "TemplateID(optional)"}
+
+	message := validator.bulkMessage(header, []string{"+18005550100", "+18005550199", " John ", "Hello {{name}}"})
+
+	assert.Equal(t, &requests.BulkMessage{
+		FromPhoneNumber: "+18005550199",
+		ToPhoneNumber:   "+18005550100",
+		Content:         "Hello {{name}}",
+		Variables:       map[string]string{"name": "John"},
+	}, message)
+}
@@ -0,0 +1,69 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// CampaignHandlerValidator validates models used in handlers.CampaignHandler
+type CampaignHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewCampaignHandlerValidator creates a new handlers.CampaignHandler validator
+func NewCampaignHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *CampaignHandlerValidator) {
+	return &CampaignHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateIndex validates the requests.CampaignIndex request
+func (validator *CampaignHandlerValidator) ValidateIndex(_ context.Context, request requests.CampaignIndex) url.Values {
+	rules := govalidator.MapData{
+		"limit": []string{
+			"required",
+			"numeric",
+			"min:1",
+			"max:100",
+		},
+		"skip": []string{
+			"required",
+			"numeric",
+			"min:0",
+		},
+		"query": []string{
+			"max:100",
+		},
+	}
+
+	if request.Status != "" {
+		rules["status"] = []string{
+			fmt.Sprintf(
+				"in:%s,%s,%s,%s,%s",
+				entities.CampaignStatusDraft,
+				entities.CampaignStatusRunning,
+				entities.CampaignStatusPaused,
+				entities.CampaignStatusCompleted,
+				entities.CampaignStatusCanceled,
+			),
+		}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return v.ValidateStruct()
+}
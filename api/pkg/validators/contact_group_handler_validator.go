@@ -0,0 +1,161 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// ContactGroupHandlerValidator validates models used in handlers.ContactGroupHandler
+type ContactGroupHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewContactGroupHandlerValidator creates a new handlers.ContactGroupHandler validator
+func NewContactGroupHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *ContactGroupHandlerValidator) {
+	return &ContactGroupHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateIndex validates the requests.ContactGroupIndex request
+func (validator *ContactGroupHandlerValidator) ValidateIndex(_ context.Context, request requests.ContactGroupIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.ContactGroupStore request
+func (validator *ContactGroupHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.ContactGroupStore) url.Values {
+	rules := govalidator.MapData{
+		"name": []string{
+			"required",
+			"min:1",
+			"max:100",
+		},
+		"type": []string{
+			"required",
+			fmt.Sprintf("in:%s,%s", entities.ContactGroupTypeStatic, entities.ContactGroupTypeDynamic),
+		},
+	}
+
+	if request.Type == string(entities.ContactGroupTypeDynamic) {
+		rules["owner"] = []string{
+			"required",
+			phoneNumberRule,
+		}
+		rules["direction"] = []string{
+			"required",
+			fmt.Sprintf("in:%s,%s,%s", entities.ContactGroupDirectionReceived, entities.ContactGroupDirectionSent, entities.ContactGroupDirectionAny),
+		}
+	} else {
+		rules["members"] = []string{
+			"required",
+			"min:1",
+			fmt.Sprintf("max:%d", services.MaxContactGroupRecipients),
+			multiplePhoneNumberRule,
+		}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+
+	result := v.ValidateStruct()
+	if request.Type == string(entities.ContactGroupTypeDynamic) && (request.WithinDays < 1 || request.WithinDays > 365) {
+		result.Add("within_days", "The within_days field must be between 1 and 365")
+	}
+
+	if len(result) != 0 || request.Type != string(entities.ContactGroupTypeDynamic) {
+		return result
+	}
+
+	validator.validateOwner(ctx, userID, "owner", request.Owner, result)
+	return result
+}
+
+// ValidateSend validates the requests.ContactGroupSend request
+func (validator *ContactGroupHandlerValidator) ValidateSend(ctx context.Context, userID entities.UserID, request requests.ContactGroupSend) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"from": []string{
+				"required",
+				phoneNumberRule,
+			},
+			"content": []string{
+				"required",
+				"min:1",
+				"max:2048",
+			},
+			"attachments": []string{
+				"max:10",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if request.SendAt != nil && request.SendAt.Before(time.Now().Add(-time.Minute)) {
+		result.Add("send_at", "The send_at field must be a time in the future")
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+
+	validator.validateOwner(ctx, userID, "from", request.From, result)
+	return result
+}
+
+// validateOwner checks that the phone number belongs to a phone of the user
+func (validator *ContactGroupHandlerValidator) validateOwner(ctx context.Context, userID entities.UserID, field string, owner string, result url.Values) {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	_, err := validator.phoneService.Load(ctx, userID, owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add(field, fmt.Sprintf("no phone found with '%s' number [%s]", field, owner))
+		return
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load phone for user [%s] and phone [%s]", userID, owner)))
+		result.Add(field, fmt.Sprintf("could not validate '%s' number [%s], please try again later", field, owner))
+	}
+}
@@ -0,0 +1,246 @@
+package validators
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	"github.com/jszwec/csvutil"
+	"github.com/thedevsaddam/govalidator"
+)
+
+const (
+	maxContactAttributes  = 50
+	maxContactTags        = 20
+	maxContactImportRows  = 10000
+	maxContactImportBytes = 5000000
+)
+
+// contactPhoneNumberRegex matches the phone number of a contact, it is the same as the contactPhoneNumberRule
+var contactPhoneNumberRegex = regexp.MustCompile(`^\+?[0-9]\d{1,14}$`)
+
+// ContactHandlerValidator validates models used in handlers.ContactHandler
+type ContactHandlerValidator struct {
+	validator
+	logger  telemetry.Logger
+	tracer  telemetry.Tracer
+	service *services.ContactService
+}
+
+// NewContactHandlerValidator creates a new handlers.ContactHandler validator
+func NewContactHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	service *services.ContactService,
+) (v *ContactHandlerValidator) {
+	return &ContactHandlerValidator{
+		logger:  logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:  tracer,
+		service: service,
+	}
+}
+
+// ValidateIndex validates the requests.ContactIndex request
+func (validator *ContactHandlerValidator) ValidateIndex(_ context.Context, request requests.ContactIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+			"tag": []string{
+				"max:50",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.ContactStore request.
+// The contactID is nil when a new contact is created.
+func (validator *ContactHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, contactID *uuid.UUID, request requests.ContactStore) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"phone_number": []string{
+				"required",
+				contactPhoneNumberRule,
+			},
+			"name": []string{
+				"max:100",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	validator.validateTags(result, "tags", request.Tags)
+
+	if len(request.Attributes) > maxContactAttributes {
+		result.Add("attributes", fmt.Sprintf("The attributes field cannot contain more than %d fields", maxContactAttributes))
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+
+	contact, err := validator.service.FindByPhoneNumber(ctx, userID, request.PhoneNumber)
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not find contact for user [%s] and phone number [%s]", userID, request.PhoneNumber)))
+		result.Add("phone_number", fmt.Sprintf("could not validate the phone number [%s], please try again later", request.PhoneNumber))
+		return result
+	}
+
+	if contact != nil && (contactID == nil || contact.ID != *contactID) {
+		result.Add("phone_number", fmt.Sprintf("a contact with the phone number [%s] already exists", request.PhoneNumber))
+	}
+
+	return result
+}
+
+// ValidateImport validates the CSV file of contacts and returns the contacts in the file
+func (validator *ContactHandlerValidator) ValidateImport(ctx context.Context, userID entities.UserID, header *multipart.FileHeader) ([]*requests.ContactImport, url.Values) {
+	_, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	if header.Header.Get("Content-Type") != "text/csv" && !strings.HasSuffix(header.Filename, ".csv") {
+		result := url.Values{}
+		result.Add("document", fmt.Sprintf("The file [%s] is not a valid CSV file.", header.Filename))
+		return nil, result
+	}
+
+	contacts, result := validator.parseCSV(ctxLogger, userID, header)
+	if len(result) != 0 {
+		return nil, result
+	}
+
+	if len(contacts) == 0 {
+		result.Add("document", "The uploaded file doesn't contain any contacts. The file must have the PhoneNumber and Name columns.")
+		return nil, result
+	}
+
+	if len(contacts) > maxContactImportRows {
+		result.Add("document", fmt.Sprintf("The uploaded file must contain less than %d contacts.", maxContactImportRows))
+		return nil, result
+	}
+
+	for index, contact := range contacts {
+		contact.Sanitize()
+		if !contactPhoneNumberRegex.MatchString(contact.PhoneNumber) {
+			result.Add("document", fmt.Sprintf("Row [%d]: The PhoneNumber [%s] is not a valid phone number", index+2, contact.PhoneNumber))
+		}
+		if len(contact.Name) > 100 {
+			result.Add("document", fmt.Sprintf("Row [%d]: The Name must be less than 100 characters", index+2))
+		}
+		validator.validateTags(result, "document", strings.Split(contact.Tags, ","))
+		if len(contact.Attributes) > maxContactAttributes {
+			result.Add("document", fmt.Sprintf("Row [%d]: A contact cannot have more than %d extra columns", index+2, maxContactAttributes))
+		}
+	}
+
+	return contacts, result
+}
+
+func (validator *ContactHandlerValidator) validateTags(result url.Values, field string, tags []string) {
+	if len(tags) > maxContactTags {
+		result.Add(field, fmt.Sprintf("A contact cannot have more than %d tags", maxContactTags))
+	}
+	for _, tag := range tags {
+		if len(tag) > 50 {
+			result.Add(field, fmt.Sprintf("The tag [%s] must be less than 50 characters", tag))
+		}
+	}
+}
+
+func (validator *ContactHandlerValidator) parseCSV(ctxLogger telemetry.Logger, userID entities.UserID, header *multipart.FileHeader) ([]*requests.ContactImport, url.Values) {
+	result := url.Values{}
+	if header.Size >= maxContactImportBytes {
+		result.Add("document", fmt.Sprintf("The CSV file must be less than %s the file you uploaded is [%s].", humanize.Bytes(maxContactImportBytes), humanize.Bytes(uint64(header.Size))))
+		return nil, result
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot open file [%s] for reading for user [%s]", header.Filename, userID))
+		result.Add("document", fmt.Sprintf("Cannot open the uploaded file with name [%s].", header.Filename))
+		return nil, result
+	}
+	defer func() {
+		if e := file.Close(); e != nil {
+			ctxLogger.Error(stacktrace.Propagatef(e, "cannot close file [%s] for user [%s]", header.Filename, userID))
+		}
+	}()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot read file [%s] for user [%s]", header.Filename, userID))
+		result.Add("document", fmt.Sprintf("Cannot read the contents of the uploaded file [%s].", header.Filename))
+		return nil, result
+	}
+
+	decoder, err := csvutil.NewDecoder(csv.NewReader(bytes.NewReader(content)))
+	if err == io.EOF {
+		return nil, result
+	}
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot read header of file [%s] for user [%s]", header.Filename, userID))
+		result.Add("document", fmt.Sprintf("Cannot read the contents of the uploaded file [%s].", header.Filename))
+		return nil, result
+	}
+
+	var contacts []*requests.ContactImport
+	for {
+		contact := new(requests.ContactImport)
+		if err = decoder.Decode(contact); err == io.EOF {
+			break
+		}
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "cannot unmarshall row [%d] into type [%T] for file [%s] and user [%s]", len(contacts)+2, contact, header.Filename, userID))
+			result.Add("document", fmt.Sprintf("Cannot read the contents of the uploaded file [%s].", header.Filename))
+			return nil, result
+		}
+
+		contact.Attributes = validator.attributes(decoder.Header(), decoder.Record(), decoder.Unused())
+		contacts = append(contacts, contact)
+	}
+
+	return contacts, result
+}
+
+// attributes maps the extra columns of a row to contact attributes using the column headers as names
+func (validator *ContactHandlerValidator) attributes(header []string, row []string, columns []int) map[string]any {
+	attributes := map[string]any{}
+	for _, column := range columns {
+		if column >= len(header) || strings.TrimSpace(header[column]) == "" || column >= len(row) || strings.TrimSpace(row[column]) == "" {
+			continue
+		}
+		attributes[strings.TrimSpace(header[column])] = strings.TrimSpace(row[column])
+	}
+	return attributes
+}
@@ -45,6 +45,9 @@ func (validator *HeartbeatHandlerValidator) ValidateIndex(_ context.Context, req
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"query": []string{
 				"max:100",
 			},
@@ -54,7 +57,7 @@ func (validator *HeartbeatHandlerValidator) ValidateIndex(_ context.Context, req
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validateCursor(v.ValidateStruct(), request.Cursor, request.Skip)
 }
 
 // ValidateStore validates the requests.HeartbeatStore request
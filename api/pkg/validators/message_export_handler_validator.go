@@ -0,0 +1,78 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// MessageExportHandlerValidator validates models used in handlers.MessageExportHandler
+type MessageExportHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewMessageExportHandlerValidator creates a new handlers.MessageExportHandler validator
+func NewMessageExportHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *MessageExportHandlerValidator) {
+	return &MessageExportHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateStore validates the requests.MessageExportStore request
+func (validator *MessageExportHandlerValidator) ValidateStore(_ context.Context, request requests.MessageExportStore) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"format": []string{
+				"required",
+				fmt.Sprintf("in:%s,%s,%s", entities.MessageExportFormatCSV, entities.MessageExportFormatNDJSON, entities.MessageExportFormatXLSX),
+			},
+			"owners": []string{
+				multipleContactPhoneNumberRule,
+			},
+			"contacts": []string{
+				multipleContactPhoneNumberRule,
+			},
+			"types": []string{
+				multipleInRule + ":" + strings.Join([]string{
+					entities.MessageTypeCallMissed,
+					entities.MessageTypeMobileOriginated,
+					entities.MessageTypeMobileTerminated,
+				}, ","),
+			},
+			"statuses": []string{
+				multipleInRule + ":" + strings.Join([]string{
+					entities.MessageStatusPending,
+					entities.MessageStatusScheduled,
+					entities.MessageStatusSending,
+					entities.MessageStatusSent,
+					entities.MessageStatusDelivered,
+					entities.MessageStatusFailed,
+					entities.MessageStatusExpired,
+					entities.MessageStatusReceived,
+					entities.MessageStatusCanceled,
+					entities.MessageStatusPaused,
+				}, ","),
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if request.Since != nil && request.Until != nil && !request.Since.Before(*request.Until) {
+		result.Add("until", "The until time must be after the since time")
+	}
+
+	return result
+}
@@ -1,17 +1,26 @@
 package validators
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/xml"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf16"
 
 	"github.com/NdoleStudio/httpsms/pkg/cache"
 	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/services"
 	"github.com/NdoleStudio/stacktrace"
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
 
 	"github.com/NdoleStudio/httpsms/pkg/entities"
 
@@ -23,11 +32,15 @@ import (
 // MessageHandlerValidator validates models used in handlers.MessageHandler
 type MessageHandlerValidator struct {
 	validator
-	logger         telemetry.Logger
-	tracer         telemetry.Tracer
-	phoneService   *services.PhoneService
-	tokenValidator *TurnstileTokenValidator
-	cache          cache.Cache
+	logger             telemetry.Logger
+	tracer             telemetry.Tracer
+	phoneService       *services.PhoneService
+	suppressionService *services.SuppressionService
+	templateService    *services.MessageTemplateService
+	poolService        *services.PhonePoolService
+	tokenValidator     *TurnstileTokenValidator
+	cache              cache.Cache
+	contentEncrypted   bool
 }
 
 // NewMessageHandlerValidator creates a new handlers.MessageHandler validator
@@ -35,15 +48,23 @@ func NewMessageHandlerValidator(
 	logger telemetry.Logger,
 	tracer telemetry.Tracer,
 	phoneService *services.PhoneService,
+	suppressionService *services.SuppressionService,
+	templateService *services.MessageTemplateService,
+	poolService *services.PhonePoolService,
 	tokenValidator *TurnstileTokenValidator,
 	appCache cache.Cache,
+	contentEncrypted bool,
 ) (v *MessageHandlerValidator) {
 	return &MessageHandlerValidator{
-		logger:         logger.WithService(fmt.Sprintf("%T", v)),
-		tracer:         tracer,
-		phoneService:   phoneService,
-		tokenValidator: tokenValidator,
-		cache:          appCache,
+		logger:             logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:             tracer,
+		phoneService:       phoneService,
+		suppressionService: suppressionService,
+		templateService:    templateService,
+		poolService:        poolService,
+		tokenValidator:     tokenValidator,
+		cache:              appCache,
+		contentEncrypted:   contentEncrypted,
 	}
 }
 
@@ -131,7 +152,7 @@ func (validator MessageHandlerValidator) validateAttachments(attachments []reque
 	return errors
 }
 
-// ValidateMessageSend validates the requests.MessageSend request
+// ValidateMessageSend validates the requests.MessageSend request, the message template is rendered to check its variables and length
 func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context, userID entities.UserID, request requests.MessageSend) url.Values {
 	ctx, span := validator.tracer.Start(ctx)
 	defer span.End()
@@ -148,29 +169,53 @@ func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context
 			"request_id": []string{
 				"max:255",
 			},
-			"from": []string{
-				"required",
-				phoneNumberRule,
-			},
+			"from": func() []string {
+				if request.PoolID != "" {
+					return []string{}
+				}
+				return []string{"required", phoneNumberRule}
+			}(),
+			"pool_id": func() []string {
+				if request.PoolID != "" {
+					return []string{"uuid"}
+				}
+				return []string{}
+			}(),
 			"attachments": []string{
 				"max:10",
 				multipleAttachmentURLRule,
 			},
-			"content": []string{
-				"required",
-				"min:1",
-				"max:2048",
-			},
+			"content": func() []string {
+				if request.TemplateID != "" {
+					return []string{"max:2048"}
+				}
+				return []string{"required", "min:1", "max:2048"}
+			}(),
+			"template_id": func() []string {
+				if request.TemplateID != "" {
+					return []string{"uuid"}
+				}
+				return []string{}
+			}(),
 		},
 	})
 
 	result := v.ValidateStruct()
+	if request.PoolID != "" && request.From != "" {
+		result.Add("from", "the 'from' number cannot be set when sending the message with a 'pool_id'")
+	}
+
 	if len(result) != 0 {
 		return result
 	}
 
-	if request.SendAt != nil && request.SendAt.After(time.Now().Add(480*time.Hour)) {
-		result.Add("send_at", "the scheduled time cannot be more than 20 days (480 hours) in the future")
+	if request.TemplateID != "" {
+		validator.validateTemplate(ctx, ctxLogger, userID, request, result)
+	}
+
+	if request.PoolID != "" {
+		validator.validatePool(ctx, ctxLogger, userID, request, result)
+		return result
 	}
 
 	_, err := validator.phoneService.Load(ctx, userID, request.From)
@@ -183,6 +228,7 @@ func (validator MessageHandlerValidator) ValidateMessageSend(ctx context.Context
 		result.Add("from", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
 	}
 
+	validator.validateSuppressions(ctx, ctxLogger, userID, request.From, []string{request.To}, result)
 	return result
 }
 
@@ -233,9 +279,84 @@ func (validator MessageHandlerValidator) ValidateMessageBulkSend(ctx context.Con
 		result.Add("from", fmt.Sprintf("could not validate 'from' number [%s], please try again later", request.From))
 	}
 
+	validator.validateSuppressions(ctx, ctxLogger, userID, request.From, request.To, result)
 	return result
 }
 
+// validateTemplate checks that the message template exists and that all of its variables are provided
+func (validator MessageHandlerValidator) validateTemplate(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, request requests.MessageSend, result url.Values) {
+	template, err := validator.templateService.Load(ctx, userID, uuid.MustParse(request.TemplateID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("template_id", fmt.Sprintf("no message template found with ID [%s]", request.TemplateID))
+		return
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "could not load message template [%s] for user [%s]", request.TemplateID, userID))
+		result.Add("template_id", fmt.Sprintf("could not validate the message template with ID [%s], please try again later", request.TemplateID))
+		return
+	}
+
+	content, missing := template.Render(request.Variables)
+	length := len([]rune(content))
+	for _, name := range missing {
+		result.Add("variables", fmt.Sprintf("the variable [%s] is required by the message template [%s]", name, template.Name))
+	}
+
+	if len(missing) == 0 && (length == 0 || length > 2048) {
+		result.Add("variables", fmt.Sprintf("the rendered message template must be between 1 and 2048 characters but it has [%d] characters", length))
+	}
+}
+
+// validatePool checks that the phone pool exists and that the contact did not opt out of every phone in the pool
+func (validator MessageHandlerValidator) validatePool(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, request requests.MessageSend, result url.Values) {
+	pool, err := validator.poolService.Load(ctx, userID, uuid.MustParse(request.PoolID))
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("pool_id", fmt.Sprintf("no phone pool found with ID [%s]", request.PoolID))
+		return
+	}
+
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "could not load phone pool [%s] for user [%s]", request.PoolID, userID))
+		result.Add("pool_id", fmt.Sprintf("could not validate the phone pool with ID [%s], please try again later", request.PoolID))
+		return
+	}
+
+	if len(pool.PhoneNumbers) == 0 {
+		result.Add("pool_id", fmt.Sprintf("the phone pool [%s] does not have any phones", pool.Name))
+		return
+	}
+
+	for _, owner := range pool.PhoneNumbers {
+		suppressed, err := validator.suppressionService.SuppressedContacts(ctx, userID, owner, []string{request.To})
+		if err != nil {
+			ctxLogger.Error(stacktrace.Propagatef(err, "could not load suppressed contacts for user [%s] and owner [%s]", userID, owner))
+			result.Add("to", "could not validate the 'to' phone number, please try again later")
+			return
+		}
+
+		if len(suppressed) == 0 {
+			return
+		}
+	}
+
+	result.Add("to", fmt.Sprintf("the contact [%s] has opted out of receiving messages from all the phones in the pool [%s]", request.To, pool.Name))
+}
+
+// validateSuppressions adds an error for every contact which opted out of receiving messages from the owner
+func (validator MessageHandlerValidator) validateSuppressions(ctx context.Context, ctxLogger telemetry.Logger, userID entities.UserID, owner string, contacts []string, result url.Values) {
+	suppressed, err := validator.suppressionService.SuppressedContacts(ctx, userID, owner, contacts)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "could not load suppressed contacts for user [%s] and owner [%s]", userID, owner))
+		result.Add("to", "could not validate the 'to' phone numbers, please try again later")
+		return
+	}
+
+	for _, contact := range suppressed {
+		result.Add("to", fmt.Sprintf("the contact [%s] has opted out of receiving messages from [%s]", contact, owner))
+	}
+}
+
 // ValidateMessageOutstanding validates the requests.MessageOutstanding request
 func (validator MessageHandlerValidator) ValidateMessageOutstanding(_ context.Context, request requests.MessageOutstanding) url.Values {
 	v := govalidator.New(govalidator.Options{
@@ -266,6 +387,9 @@ func (validator MessageHandlerValidator) ValidateMessageIndex(_ context.Context,
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"contact": []string{
 				"required",
 				"min:1",
@@ -279,7 +403,15 @@ func (validator MessageHandlerValidator) ValidateMessageIndex(_ context.Context,
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validateCursor(validator.validateQuery(v.ValidateStruct(), request.Query), request.Cursor, request.Skip)
+}
+
+// validateQuery rejects queries when the content is encrypted at rest because the database can only match the encrypted content
+func (validator MessageHandlerValidator) validateQuery(errors url.Values, query string) url.Values {
+	if validator.contentEncrypted && strings.TrimSpace(query) != "" {
+		errors.Add("query", "The message content is encrypted so messages cannot be searched with a query. Use the contacts, types, statuses or date filters instead.")
+	}
+	return errors
 }
 
 // ValidateMessageSearch validates the requests.MessageSearch request
@@ -300,11 +432,15 @@ func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Conte
 			"statuses": []string{
 				multipleInRule + ":" + strings.Join([]string{
 					entities.MessageStatusPending,
+					entities.MessageStatusScheduled,
+					entities.MessageStatusSending,
 					entities.MessageStatusSent,
 					entities.MessageStatusDelivered,
 					entities.MessageStatusFailed,
 					entities.MessageStatusExpired,
 					entities.MessageStatusReceived,
+					entities.MessageStatusCanceled,
+					entities.MessageStatusPaused,
 				}, ","),
 			},
 			"sort_by": []string{
@@ -327,8 +463,11 @@ func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Conte
 				"numeric",
 				"min:0",
 			},
+			"contacts": []string{
+				multipleContactPhoneNumberRule,
+			},
 			"query": []string{
-				"max:50",
+				"max:100",
 			},
 			"token": []string{
 				"required",
@@ -336,7 +475,16 @@ func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Conte
 		},
 	})
 
-	errors := v.ValidateStruct()
+	errors := validator.validateQuery(v.ValidateStruct(), request.Query)
+	if request.From != "" && request.FromTime() == nil {
+		errors.Add("from", "The from field must be a date like 2024-01-31 or an RFC3339 timestamp like 2024-01-31T09:00:00Z")
+	}
+	if request.To != "" && request.ToTime() == nil {
+		errors.Add("to", "The to field must be a date like 2024-01-31 or an RFC3339 timestamp like 2024-01-31T18:00:00Z")
+	}
+	if from, to := request.FromTime(), request.ToTime(); from != nil && to != nil && !from.Before(*to) {
+		errors.Add("to", "The to field must be after the from field")
+	}
 	if len(errors) > 0 {
 		return errors
 	}
@@ -348,6 +496,57 @@ func (validator MessageHandlerValidator) ValidateMessageSearch(ctx context.Conte
 	return errors
 }
 
+const (
+	// maxMessageAnalyticsRange is the longest time range of the message analytics
+	maxMessageAnalyticsRange = 366 * 24 * time.Hour
+
+	// maxMessageAnalyticsHourlyRange is the longest time range of the message analytics which are grouped by hour
+	maxMessageAnalyticsHourlyRange = 31 * 24 * time.Hour
+)
+
+// ValidateMessageAnalytics validates the requests.MessageAnalytics request
+func (validator MessageHandlerValidator) ValidateMessageAnalytics(_ context.Context, request requests.MessageAnalytics, now time.Time) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				phoneNumberRule,
+			},
+			"granularity": []string{
+				"required",
+				"in:" + strings.Join([]string{
+					string(entities.MessageAnalyticsGranularityHour),
+					string(entities.MessageAnalyticsGranularityDay),
+					string(entities.MessageAnalyticsGranularityWeek),
+					string(entities.MessageAnalyticsGranularityMonth),
+				}, ","),
+			},
+		},
+	})
+
+	errors := v.ValidateStruct()
+	if request.From != "" && request.FromTime() == nil {
+		errors.Add("from", "The from field must be a date like 2024-01-31 or an RFC3339 timestamp like 2024-01-31T09:00:00Z")
+	}
+	if request.To != "" && request.ToTime() == nil {
+		errors.Add("to", "The to field must be a date like 2024-01-31 or an RFC3339 timestamp like 2024-01-31T18:00:00Z")
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+
+	from, to := request.TimeRange(now)
+	if !from.Before(to) {
+		errors.Add("to", "The to field must be after the from field")
+	} else if to.Sub(from) > maxMessageAnalyticsRange {
+		errors.Add("from", "The time range between the from and the to fields cannot be longer than 366 days")
+	} else if request.Granularity == string(entities.MessageAnalyticsGranularityHour) && to.Sub(from) > maxMessageAnalyticsHourlyRange {
+		errors.Add("granularity", "The hour granularity can only be used for a time range of up to 31 days")
+	}
+
+	return errors
+}
+
 // ValidateMessageEvent validates the requests.MessageEvent request
 func (validator MessageHandlerValidator) ValidateMessageEvent(_ context.Context, request requests.MessageEvent) url.Values {
 	v := govalidator.New(govalidator.Options{
@@ -370,6 +569,47 @@ func (validator MessageHandlerValidator) ValidateMessageEvent(_ context.Context,
 	return v.ValidateStruct()
 }
 
+// ValidateMessageReschedule validates the requests.MessageReschedule request
+func (validator MessageHandlerValidator) ValidateMessageReschedule(_ context.Context, request requests.MessageReschedule) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"messageID": []string{
+				"required",
+				"uuid",
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if result == nil {
+		result = url.Values{}
+	}
+
+	if request.SendAt == nil {
+		result.Add("send_at", "the send_at field is required")
+	} else if request.SendAt.Before(time.Now()) {
+		result.Add("send_at", "the scheduled time must be in the future")
+	}
+
+	return result
+}
+
+// ValidateMessageSegments validates the requests.MessageSegments request
+func (validator MessageHandlerValidator) ValidateMessageSegments(_ context.Context, request requests.MessageSegments) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"content": []string{
+				"required",
+				"min:1",
+				"max:2048",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
 // ValidateCallMissed validates the requests.MessageCallMissed request
 func (validator MessageHandlerValidator) ValidateCallMissed(_ context.Context, request requests.MessageCallMissed) url.Values {
 	v := govalidator.New(govalidator.Options{
@@ -394,3 +634,164 @@ func (validator MessageHandlerValidator) ValidateCallMissed(_ context.Context, r
 
 	return v.ValidateStruct()
 }
+
+const (
+	maxMessageImportRows  = 100_000
+	maxMessageImportBytes = 50 * 1000 * 1000
+)
+
+// messageImportCharacterReferences matches consecutive numeric character references in an XML file
+var messageImportCharacterReferences = regexp.MustCompile(`(?:&#[0-9]+;)+`)
+
+// ValidateImport validates an "SMS Backup & Restore" XML file and returns the messages which can be imported
+func (validator MessageHandlerValidator) ValidateImport(ctx context.Context, userID entities.UserID, request requests.MessageImportStore, header *multipart.FileHeader) ([]*requests.MessageImport, url.Values) {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				"required",
+				phoneNumberRule,
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return nil, result
+	}
+	owner := request.Owner
+
+	if !strings.HasSuffix(strings.ToLower(header.Filename), ".xml") {
+		result.Add("document", fmt.Sprintf("The file [%s] is not a valid XML file. The file name must end with .xml", header.Filename))
+		return nil, result
+	}
+
+	if header.Size >= maxMessageImportBytes {
+		result.Add("document", fmt.Sprintf("The XML file must be less than %s the file you uploaded is [%s].", humanize.Bytes(maxMessageImportBytes), humanize.Bytes(uint64(header.Size))))
+		return nil, result
+	}
+
+	_, err := validator.phoneService.Load(ctx, userID, owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("owner", fmt.Sprintf("no phone found with 'owner' number [%s]", owner))
+		return nil, result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load phone for user [%s] and phone [%s]", userID, owner)))
+		result.Add("owner", fmt.Sprintf("could not validate 'owner' number [%s], please try again later", owner))
+		return nil, result
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot open file [%s] for reading for user [%s]", header.Filename, userID))
+		result.Add("document", fmt.Sprintf("Cannot open the uploaded file with name [%s].", header.Filename))
+		return nil, result
+	}
+	defer func() {
+		if e := file.Close(); e != nil {
+			ctxLogger.Error(stacktrace.Propagatef(e, "cannot close file [%s] for user [%s]", header.Filename, userID))
+		}
+	}()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		ctxLogger.Error(stacktrace.Propagatef(err, "cannot read file [%s] for user [%s]", header.Filename, userID))
+		result.Add("document", fmt.Sprintf("Cannot read the contents of the uploaded file [%s].", header.Filename))
+		return nil, result
+	}
+
+	messages, err := validator.parseMessageImport(content)
+	if err != nil {
+		ctxLogger.Warn(stacktrace.Propagatef(err, "cannot parse XML file [%s] for user [%s]", header.Filename, userID))
+		result.Add("document", fmt.Sprintf("The file [%s] is not a valid SMS Backup & Restore XML file.", header.Filename))
+		return nil, result
+	}
+
+	if len(messages) == 0 {
+		result.Add("document", "The uploaded file doesn't contain any sent or received SMS messages.")
+		return nil, result
+	}
+
+	if len(messages) > maxMessageImportRows {
+		result.Add("document", fmt.Sprintf("The uploaded file must contain less than %d messages.", maxMessageImportRows))
+		return nil, result
+	}
+
+	for index, message := range messages {
+		message.Sanitize(owner)
+		if message.Address == "" {
+			result.Add("document", fmt.Sprintf("Message [%d]: The address is empty", index+1))
+		}
+		if _, ok := message.Timestamp(); !ok {
+			result.Add("document", fmt.Sprintf("Message [%d]: The date [%s] is not a valid timestamp in milliseconds", index+1, message.Date))
+		}
+	}
+
+	return messages, result
+}
+
+// parseMessageImport reads the <sms> elements of an "SMS Backup & Restore" XML file which can be imported
+func (validator MessageHandlerValidator) parseMessageImport(content []byte) ([]*requests.MessageImport, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(validator.combineSurrogateReferences(content)))
+
+	var messages []*requests.MessageImport
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return messages, nil
+		}
+		if err != nil {
+			return nil, stacktrace.Propagatef(err, "cannot read XML token")
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "sms" {
+			continue
+		}
+
+		message := new(requests.MessageImport)
+		if err = decoder.DecodeElement(message, &element); err != nil {
+			return nil, stacktrace.Propagatef(err, "cannot decode element [%d] into [%T]", len(messages)+1, message)
+		}
+
+		if message.IsImportable() {
+			messages = append(messages, message)
+		}
+	}
+}
+
+// combineSurrogateReferences replaces the UTF-16 surrogate pairs which "SMS Backup & Restore" writes for emojis
+// (e.g. "&#55357;&#56832;") with a single character reference because the XML decoder rejects surrogates.
+func (validator MessageHandlerValidator) combineSurrogateReferences(content []byte) []byte {
+	return messageImportCharacterReferences.ReplaceAllFunc(content, func(match []byte) []byte {
+		var runes []rune
+		var pending []uint16
+		for _, reference := range strings.Split(strings.TrimSuffix(string(match), ";"), ";") {
+			value, err := strconv.ParseUint(strings.TrimPrefix(reference, "&#"), 10, 32)
+			if err != nil {
+				return match
+			}
+
+			if value <= 0xFFFF {
+				pending = append(pending, uint16(value))
+				continue
+			}
+
+			runes = append(runes, utf16.Decode(pending)...)
+			runes = append(runes, rune(value))
+			pending = nil
+		}
+		runes = append(runes, utf16.Decode(pending)...)
+
+		result := strings.Builder{}
+		for _, character := range runes {
+			result.WriteString(fmt.Sprintf("&#%d;", character))
+		}
+		return []byte(result.String())
+	})
+}
@@ -0,0 +1,73 @@
+package validators
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageHandlerValidatorParseMessageImport(t *testing.T) {
+	validator := MessageHandlerValidator{}
+	content := []byte(`<?xml version='1.0' encoding='UTF-8' standalone='yes' ?>
+<smses count="4">
+  <sms protocol="0" address="+18005550100" date="1704110400000" type="1" body="Hello &#55357;&#56832;" status="-1" readable_date="Jan 1, 2024 12:00:00 PM" />
+  <sms protocol="0" address="(800) 555-0100" date="1704110460000" type="2" body="Line one&#10;Line two" status="0" />
+  <sms protocol="0" address="+18005550100" date="1704110520000" type="3" body="Draft" status="-1" />
+  <mms date="1704110580000" msg_box="1" address="+18005550100"><parts /></mms>
+</smses>`)
+
+	messages, err := validator.parseMessageImport(content)
+
+	assert.Nil(t, err)
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "Hello 😀", messages[0].Body)
+	assert.Equal(t, "1", messages[0].Type)
+	assert.Equal(t, "Line one\nLine two", messages[1].Body)
+	assert.Equal(t, "(800) 555-0100", messages[1].Address)
+}
+
+func TestMessageHandlerValidatorParseMessageImportInvalidXML(t *testing.T) {
+	validator := MessageHandlerValidator{}
+
+	_, err := validator.parseMessageImport([]byte(`<smses><sms address="+18005550100"`))
+
+	assert.NotNil(t, err)
+}
+
+func TestMessageHandlerValidatorCombineSurrogateReferences(t *testing.T) {
+	validator := MessageHandlerValidator{}
+
+	assert.Equal(t, "&#128512;&#72;", string(validator.combineSurrogateReferences([]byte("&#55357;&#56832;&#72;"))))
+	assert.Equal(t, "a&#65533;b", string(validator.combineSurrogateReferences([]byte("a&#55357;b"))))
+	assert.Equal(t, "&#233;", string(validator.combineSurrogateReferences([]byte("&#233;"))))
+}
+
+func TestMessageHandlerValidatorValidateMessageAnalytics(t *testing.T) {
+	validator := MessageHandlerValidator{}
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	validate := func(request requests.MessageAnalytics) url.Values {
+		return validator.ValidateMessageAnalytics(context.Background(), request.Sanitize(), now)
+	}
+
+	assert.Empty(t, validate(requests.MessageAnalytics{}))
+	assert.Empty(t, validate(requests.MessageAnalytics{From: "2024-06-01", Owner: "+18005550199", Granularity: "hour"}))
+	assert.Contains(t, validate(requests.MessageAnalytics{Granularity: "year"}), "granularity")
+	assert.Contains(t, validate(requests.MessageAnalytics{From: "last week"}), "from")
+	assert.Contains(t, validate(requests.MessageAnalytics{From: "2024-06-30", To: "2024-06-01"}), "to")
+	assert.Contains(t, validate(requests.MessageAnalytics{From: "2022-01-01"}), "from")
+	assert.Contains(t, validate(requests.MessageAnalytics{From: "2024-01-01", Granularity: "hour"}), "granularity")
+}
+
+func TestMessageHandlerValidatorValidateMessageIndexRejectsQueriesWhenTheContentIsEncrypted(t *testing.T) {
+	request := requests.MessageIndex{Owner: "+18005550199", Contact: "+18005550100", Query: "order", Limit: "20", Skip: "0"}
+
+	assert.Empty(t, MessageHandlerValidator{}.ValidateMessageIndex(context.Background(), request))
+	assert.Equal(t, url.Values{"query": []string{"The message content is encrypted so messages cannot be searched with a query. Use the contacts, types, statuses or date filters instead."}}, MessageHandlerValidator{contentEncrypted: true}.ValidateMessageIndex(context.Background(), request))
+
+	request.Query = ""
+	assert.Empty(t, MessageHandlerValidator{contentEncrypted: true}.ValidateMessageIndex(context.Background(), request))
+}
@@ -44,6 +44,9 @@ func (validator *MessageThreadHandlerValidator) ValidateMessageThreadIndex(_ con
 				"numeric",
 				"min:0",
 			},
+			"cursor": []string{
+				cursorRule,
+			},
 			"is_archived": []string{
 				"required",
 				"in:true,false",
@@ -57,7 +60,7 @@ func (validator *MessageThreadHandlerValidator) ValidateMessageThreadIndex(_ con
 			},
 		},
 	})
-	return v.ValidateStruct()
+	return validator.validateCursor(v.ValidateStruct(), request.Cursor, request.Skip)
 }
 
 // ValidateUpdate validates requests.UserUpdate
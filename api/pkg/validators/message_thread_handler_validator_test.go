@@ -3,7 +3,9 @@ package validators
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 	"github.com/NdoleStudio/httpsms/pkg/requests"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -32,3 +34,17 @@ func TestValidateUpdateAcceptsReadOnlyUpdate(t *testing.T) {
 
 	assert.Empty(t, errors)
 }
+
+func TestValidateMessageThreadIndexWithCursor(t *testing.T) {
+	validator := &MessageThreadHandlerValidator{}
+	cursor := repositories.Cursor{Timestamp: time.Date(2026, 7, 18, 7, 0, 0, 0, time.UTC), ID: uuid.New()}
+	request := requests.MessageThreadIndex{Owner: "+18005550199", Cursor: cursor.Encode()}
+
+	assert.Empty(t, validator.ValidateMessageThreadIndex(context.Background(), request.Sanitize()))
+
+	request.Skip = "20"
+	assert.NotEmpty(t, validator.ValidateMessageThreadIndex(context.Background(), request.Sanitize()).Get("cursor"))
+
+	request = requests.MessageThreadIndex{Owner: "+18005550199", Cursor: "invalid"}
+	assert.NotEmpty(t, validator.ValidateMessageThreadIndex(context.Background(), request.Sanitize()).Get("cursor"))
+}
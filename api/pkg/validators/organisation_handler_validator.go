@@ -0,0 +1,102 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// OrganisationHandlerValidator validates models used in handlers.OrganisationHandler
+type OrganisationHandlerValidator struct {
+	validator
+	logger telemetry.Logger
+	tracer telemetry.Tracer
+}
+
+// NewOrganisationHandlerValidator creates a new handlers.OrganisationHandler validator
+func NewOrganisationHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+) (v *OrganisationHandlerValidator) {
+	return &OrganisationHandlerValidator{
+		logger: logger.WithService(fmt.Sprintf("%T", v)),
+		tracer: tracer,
+	}
+}
+
+// ValidateStore validates the requests.OrganisationStore request
+func (validator *OrganisationHandlerValidator) ValidateStore(_ context.Context, request requests.OrganisationStore) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"name": []string{
+				"required",
+				"min:1",
+				"max:60",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateMemberIndex validates the requests.OrganisationMemberIndex request
+func (validator *OrganisationHandlerValidator) ValidateMemberIndex(_ context.Context, request requests.OrganisationMemberIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateMemberStore validates the requests.OrganisationMemberStore request
+func (validator *OrganisationHandlerValidator) ValidateMemberStore(_ context.Context, request requests.OrganisationMemberStore) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"email": []string{
+				"required",
+				"email",
+				"max:255",
+			},
+			"role": []string{
+				"required",
+				fmt.Sprintf("in:%s,%s,%s", entities.OrganisationRoleAdmin, entities.OrganisationRoleAgent, entities.OrganisationRoleReadOnly),
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateMemberUpdate validates the requests.OrganisationMemberUpdate request
+func (validator *OrganisationHandlerValidator) ValidateMemberUpdate(_ context.Context, request requests.OrganisationMemberUpdate) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"role": []string{
+				"required",
+				fmt.Sprintf("in:%s,%s,%s", entities.OrganisationRoleAdmin, entities.OrganisationRoleAgent, entities.OrganisationRoleReadOnly),
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
@@ -95,6 +95,14 @@ func (validator *PhoneHandlerValidator) ValidateUpsert(ctx context.Context, user
 	})
 
 	result := v.ValidateStruct()
+	if len(request.FallbackPhoneNumbers) > 0 {
+		for key, values := range validator.validateFallbackPhoneNumbers(request) {
+			for _, value := range values {
+				result.Add(key, value)
+			}
+		}
+	}
+
 	if request.MaxSendAttempts > 0 && request.MessageExpirationSeconds == 0 {
 		result.Add("message_expiration_seconds", "message_expiration_seconds cannot be 0 when max_send_attempts is greater than 0")
 	}
@@ -113,6 +121,27 @@ func (validator *PhoneHandlerValidator) ValidateUpsert(ctx context.Context, user
 	return result
 }
 
+func (validator *PhoneHandlerValidator) validateFallbackPhoneNumbers(request requests.PhoneUpsert) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"fallback_phone_numbers": []string{
+				"max:5",
+				multiplePhoneNumberRule,
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	for _, phoneNumber := range request.FallbackPhoneNumbers {
+		if phoneNumber == request.PhoneNumber {
+			result.Add("fallback_phone_numbers", fmt.Sprintf("the phone [%s] cannot be a fallback for itself", phoneNumber))
+		}
+	}
+
+	return result
+}
+
 // ValidateFCMToken validates requests.PhoneFCMToken
 func (validator *PhoneHandlerValidator) ValidateFCMToken(_ context.Context, request requests.PhoneFCMToken) url.Values {
 	v := govalidator.New(govalidator.Options{
@@ -0,0 +1,103 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// PhonePoolHandlerValidator validates models used in handlers.PhonePoolHandler
+type PhonePoolHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewPhonePoolHandlerValidator creates a new handlers.PhonePoolHandler validator
+func NewPhonePoolHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *PhonePoolHandlerValidator) {
+	return &PhonePoolHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateIndex validates the requests.PhonePoolIndex request
+func (validator *PhonePoolHandlerValidator) ValidateIndex(_ context.Context, request requests.PhonePoolIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.PhonePoolStore request
+func (validator *PhonePoolHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.PhonePoolStore) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"name": []string{
+				"required",
+				"min:1",
+				"max:100",
+			},
+			"phone_numbers": []string{
+				"required",
+				"min:1",
+				"max:50",
+				multiplePhoneNumberRule,
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	for _, phoneNumber := range request.PhoneNumbers {
+		_, err := validator.phoneService.Load(ctx, userID, phoneNumber)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			result.Add("phone_numbers", fmt.Sprintf("no phone found with number [%s]. install the android app on your phone to add it to a pool", phoneNumber))
+			continue
+		}
+
+		if err != nil {
+			ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load phone for user [%s] and phone [%s]", userID, phoneNumber)))
+			result.Add("phone_numbers", fmt.Sprintf("could not validate phone number [%s], please try again later", phoneNumber))
+		}
+	}
+
+	return result
+}
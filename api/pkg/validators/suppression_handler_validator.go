@@ -0,0 +1,106 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// SuppressionHandlerValidator validates models used in handlers.SuppressionHandler
+type SuppressionHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewSuppressionHandlerValidator creates a new handlers.SuppressionHandler validator
+func NewSuppressionHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *SuppressionHandlerValidator) {
+	return &SuppressionHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateIndex validates the requests.SuppressionIndex request
+func (validator *SuppressionHandlerValidator) ValidateIndex(_ context.Context, request requests.SuppressionIndex) url.Values {
+	rules := govalidator.MapData{
+		"limit": []string{
+			"required",
+			"numeric",
+			"min:1",
+			"max:1000",
+		},
+		"skip": []string{
+			"required",
+			"numeric",
+			"min:0",
+		},
+		"query": []string{
+			"max:100",
+		},
+	}
+
+	if request.Owner != "" {
+		rules["owner"] = []string{phoneNumberRule}
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data:  &request,
+		Rules: rules,
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.SuppressionStore request
+func (validator *SuppressionHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.SuppressionStore) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"owner": []string{
+				"required",
+				phoneNumberRule,
+			},
+			"contacts": []string{
+				"required",
+				"min:1",
+				"max:1000",
+				multipleContactPhoneNumberRule,
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if len(result) != 0 {
+		return result
+	}
+
+	_, err := validator.phoneService.Load(ctx, userID, request.Owner)
+	if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+		result.Add("owner", fmt.Sprintf("no phone found with 'owner' number [%s]", request.Owner))
+		return result
+	}
+
+	if err != nil {
+		ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load phone for user [%s] and phone [%s]", userID, request.Owner)))
+		result.Add("owner", fmt.Sprintf("could not validate 'owner' number [%s], please try again later", request.Owner))
+	}
+
+	return result
+}
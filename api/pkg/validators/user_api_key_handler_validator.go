@@ -0,0 +1,121 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/NdoleStudio/httpsms/pkg/entities"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
+	"github.com/NdoleStudio/httpsms/pkg/requests"
+	"github.com/NdoleStudio/httpsms/pkg/services"
+	"github.com/NdoleStudio/httpsms/pkg/telemetry"
+	"github.com/NdoleStudio/stacktrace"
+	"github.com/thedevsaddam/govalidator"
+)
+
+// UserAPIKeyHandlerValidator validates models used in handlers.UserAPIKeyHandler
+type UserAPIKeyHandlerValidator struct {
+	validator
+	logger       telemetry.Logger
+	tracer       telemetry.Tracer
+	phoneService *services.PhoneService
+}
+
+// NewUserAPIKeyHandlerValidator creates a new handlers.UserAPIKeyHandler validator
+func NewUserAPIKeyHandlerValidator(
+	logger telemetry.Logger,
+	tracer telemetry.Tracer,
+	phoneService *services.PhoneService,
+) (v *UserAPIKeyHandlerValidator) {
+	return &UserAPIKeyHandlerValidator{
+		logger:       logger.WithService(fmt.Sprintf("%T", v)),
+		tracer:       tracer,
+		phoneService: phoneService,
+	}
+}
+
+// ValidateIndex validates the requests.UserAPIKeyIndex request
+func (validator *UserAPIKeyHandlerValidator) ValidateIndex(_ context.Context, request requests.UserAPIKeyIndex) url.Values {
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"limit": []string{
+				"required",
+				"numeric",
+				"min:1",
+				"max:100",
+			},
+			"skip": []string{
+				"required",
+				"numeric",
+				"min:0",
+			},
+			"query": []string{
+				"max:100",
+			},
+		},
+	})
+	return v.ValidateStruct()
+}
+
+// ValidateStore validates the requests.UserAPIKeyStore request
+func (validator *UserAPIKeyHandlerValidator) ValidateStore(ctx context.Context, userID entities.UserID, request requests.UserAPIKeyStore) url.Values {
+	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
+	defer span.End()
+
+	var scopes []string
+	for _, scope := range entities.UserAPIKeyScopes() {
+		scopes = append(scopes, string(scope))
+	}
+
+	v := govalidator.New(govalidator.Options{
+		Data: &request,
+		Rules: govalidator.MapData{
+			"name": []string{
+				"required",
+				"min:1",
+				"max:60",
+			},
+			"scopes": []string{
+				"required",
+				"min:1",
+				multipleInRule + ":" + strings.Join(scopes, ","),
+			},
+			"phone_numbers": []string{
+				"max:50",
+				multiplePhoneNumberRule,
+			},
+		},
+	})
+
+	result := v.ValidateStruct()
+	if result == nil {
+		result = url.Values{}
+	}
+
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now()) {
+		result.Add("expires_at", "the expiry time must be in the future")
+	}
+
+	if len(result) != 0 {
+		return result
+	}
+
+	for _, phoneNumber := range request.PhoneNumbers {
+		_, err := validator.phoneService.Load(ctx, userID, phoneNumber)
+		if stacktrace.GetCode(err) == repositories.ErrCodeNotFound {
+			result.Add("phone_numbers", fmt.Sprintf("no phone found with number [%s]. install the android app on your phone to add it to the API key", phoneNumber))
+			continue
+		}
+
+		if err != nil {
+			ctxLogger.Error(validator.tracer.WrapErrorSpan(span, stacktrace.Propagatef(err, "could not load phone for user [%s] and phone [%s]", userID, phoneNumber)))
+			result.Add("phone_numbers", fmt.Sprintf("could not validate phone number [%s], please try again later", phoneNumber))
+		}
+	}
+
+	return result
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/thedevsaddam/govalidator"
 )
 
+// maxRetentionDays is the longest retention period which can be set by a user
+const maxRetentionDays = 3650
+
 // UserHandlerValidator validates models used in handlers.UserHandler
 type UserHandlerValidator struct {
 	validator
@@ -48,6 +51,20 @@ func (validator *UserHandlerValidator) ValidateUpdate(_ context.Context, request
 	return v.ValidateStruct()
 }
 
+// ValidateRetentionUpdate validates requests.UserRetentionUpdate
+func (validator *UserHandlerValidator) ValidateRetentionUpdate(_ context.Context, request requests.UserRetentionUpdate) url.Values {
+	result := url.Values{}
+	for field, days := range map[string]*uint{
+		"message_content_retention_days": request.MessageContentRetentionDays,
+		"data_retention_days":            request.DataRetentionDays,
+	} {
+		if days != nil && (*days < 1 || *days > maxRetentionDays) {
+			result.Add(field, fmt.Sprintf("The %s field must be between 1 and %d days", field, maxRetentionDays))
+		}
+	}
+	return result
+}
+
 // ValidatePaymentInvoice validates the requests.UserPaymentInvoice request
 func (validator *UserHandlerValidator) ValidatePaymentInvoice(ctx context.Context, userID entities.UserID, request requests.UserPaymentInvoice) url.Values {
 	ctx, span, ctxLogger := validator.tracer.StartWithLogger(ctx, validator.logger)
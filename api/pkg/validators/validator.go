@@ -12,6 +12,7 @@ import (
 
 	"github.com/NdoleStudio/httpsms/pkg/cache"
 	"github.com/NdoleStudio/httpsms/pkg/events"
+	"github.com/NdoleStudio/httpsms/pkg/repositories"
 
 	"github.com/nyaruka/phonenumbers"
 	"github.com/thedevsaddam/govalidator"
@@ -27,6 +28,7 @@ const (
 	multipleAttachmentURLRule      = "multipleAttachmentURL"
 	multipleInRule                 = "multipleIn"
 	webhookEventsRule              = "webhookEvents"
+	cursorRule                     = "cursor"
 )
 
 func init() {
@@ -148,6 +150,7 @@ func init() {
 			events.EventTypeMessagePhoneDelivered: true,
 			events.EventTypeMessageSendFailed:     true,
 			events.EventTypeMessageSendExpired:    true,
+			events.EventTypeMessageSendRerouted:   true,
 			events.EventTypePhoneHeartbeatOnline:  true,
 			events.EventTypePhoneHeartbeatOffline: true,
 			events.MessageCallMissed:              true,
@@ -161,6 +164,31 @@ func init() {
 
 		return nil
 	})
+
+	govalidator.AddCustomRule(cursorRule, func(field string, rule string, message string, value interface{}) error {
+		cursor, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("The %s field must be a string", field)
+		}
+
+		if cursor == "" {
+			return nil
+		}
+
+		if _, err := repositories.DecodeCursor(cursor); err != nil {
+			return fmt.Errorf("The %s field must be a cursor returned in the next_cursor or prev_cursor of a previous response", field)
+		}
+
+		return nil
+	})
+}
+
+// validateCursor checks that the cursor is not combined with the offset of a paginated request
+func (validator *validator) validateCursor(result url.Values, cursor string, skip string) url.Values {
+	if cursor != "" && skip != "0" {
+		result.Add("cursor", "The cursor field cannot be used together with the skip field")
+	}
+	return result
 }
 
 // ValidateUUID that the payload is a UUID